@@ -1,17 +1,24 @@
 package main
 
 import (
-	"better-uptime/common/cloudinary"
+	"better-uptime/common/audit"
+	"better-uptime/common/devauth"
 	"better-uptime/common/firebase"
+	"better-uptime/common/identity"
+	"better-uptime/common/plans"
+	"better-uptime/common/secrets"
+	"better-uptime/common/storage"
 	"better-uptime/config"
 	"better-uptime/internal/api"
 	"better-uptime/internal/api/worker"
 	db "better-uptime/internal/db/sqlc"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -33,40 +40,58 @@ func main() {
 	}
 	defer pool.Close()
 
-	// Initialize Firebase Auth
 	// Initialize Firebase Auth
 	if cfg.FIREBASE_SERVICE_ACCOUNT != "" || cfg.FIREBASE_SERVICE_ACCOUNT_JSON != "" {
 		if err := firebase.InitFirebaseAuth(cfg.FIREBASE_SERVICE_ACCOUNT, cfg.FIREBASE_SERVICE_ACCOUNT_JSON); err != nil {
 			log.Printf("⚠️  Warning: Firebase Auth initialization failed: %v", err)
 			log.Println("   Firebase token verification will not work. Real Firebase tokens will be rejected.")
-			log.Println("   The bypass token 'frontend' will still work for testing.")
 		} else {
 			fmt.Println("✅ Firebase Auth initialized successfully")
 		}
 	} else {
 		log.Println("⚠️  FIREBASE credentials not set in .env")
 		log.Println("   Firebase token verification is disabled.")
-		log.Println("   Only the bypass token 'frontend' will work for testing.")
 		log.Println("   To enable: Set FIREBASE_SERVICE_ACCOUNT (path) or FIREBASE_SERVICE_ACCOUNT_JSON (content)")
 	}
 
-	// Initialize Cloudinary
-	cloudinaryUploader, err := cloudinary.NewImageUploader(
-		cfg.CLOUDINARY_CLOUD_NAME,
-		cfg.CLOUDINARY_API_KEY,
-		cfg.CLOUDINARY_API_SECRET,
-	)
-	if err != nil {
-		log.Printf("Warning: Cloudinary initialization failed: %v", err)
+	// Development auth only runs in an explicit development setup
+	if err := devauth.Init(*cfg); err != nil {
+		log.Fatalf("Cannot start dev auth: %v", err)
+	}
+	if devauth.Enabled() {
+		log.Printf("⚠️  Dev auth is enabled (APP_ENV=%s): test identities can sign in without Firebase", cfg.APP_ENV)
+	}
+
+	// Auth providers tried by TokenMiddleware, in order
+	if err := identity.Init(*cfg); err != nil {
+		log.Fatalf("Cannot configure auth providers: %v", err)
+	}
+	fmt.Printf("Auth providers: %s\n", strings.Join(identity.Names(), ", "))
+
+	audit.Init(*cfg)
+
+	if err := plans.Init(*cfg); err != nil {
+		log.Fatalf("Invalid plan configuration: %v", err)
+	}
+
+	// Initialize artifact storage (screenshots)
+	var artifacts storage.Storage
+	if backend, err := storage.New(*cfg); err != nil {
+		log.Printf("Warning: %s storage initialization failed, screenshots are disabled: %v", cfg.STORAGE_DRIVER, err)
 	} else {
-		fmt.Println("Cloudinary initialized successfully")
+		artifacts = backend
+		fmt.Printf("Artifact storage initialized (%s)\n", cfg.STORAGE_DRIVER)
 	}
 
 	// Create store
 	store := db.NewStore(pool)
 
+	rollupWorker := worker.NewRollupWorker(store)
+	retentionWorker := worker.NewRetentionWorker(store, cfg, artifacts)
+	secretsWorker, secretsErr := worker.NewSecretsRotationWorker(store, cfg)
+
 	// ✅ ADD THIS: Start Monitor Worker
-	worker := worker.NewMonitorWorker(store, cfg)
+	worker := worker.NewMonitorWorker(store, cfg, artifacts)
 
 	// Create background context for the worker
 	workerCtx, cancelWorker := context.WithCancel(context.Background())
@@ -76,8 +101,21 @@ func main() {
 	go worker.Start(workerCtx)
 	fmt.Println("🚀 Monitor worker started - checking monitors every minute")
 
+	// Aggregate monitor_logs into 1m/1h/1d rollups for charts and stats
+	go rollupWorker.Start(workerCtx)
+
+	// Create monitor_logs partitions and prune logs past the retention horizon
+	go retentionWorker.Start(workerCtx)
+
+	// Move monitor secrets onto the current SECRETS_KEY after a rotation
+	if secretsErr == nil {
+		go secretsWorker.Start(workerCtx)
+	} else if !errors.Is(secretsErr, secrets.ErrNoKey) {
+		log.Printf("Warning: secrets rotation is disabled: %v", secretsErr)
+	}
+
 	// Start server
-	server := api.NewServer(store, cfg, cloudinaryUploader)
+	server := api.NewServer(store, cfg, artifacts)
 
 	// Channel for graceful shutdown
 	// SIGINT = Ctrl+C, SIGTERM = Termination request (like from Docker/K8s)
@@ -0,0 +1,37 @@
+// Command devtoken mints a self-signed JWT that the API accepts while dev auth
+// is enabled with the same DEV_AUTH_JWT_SECRET, for integration tests.
+package main
+
+import (
+	"better-uptime/common/devauth"
+	"better-uptime/config"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the test user (required)")
+	name := flag.String("name", "", "full name of the test user")
+	ttl := flag.Duration("ttl", time.Hour, "how long the token stays valid")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	if !cfg.IsDevelopment() {
+		log.Fatal("devtoken can only be used when APP_ENV is development")
+	}
+	if cfg.DEV_AUTH_JWT_SECRET == "" {
+		log.Fatal("DEV_AUTH_JWT_SECRET is not set")
+	}
+	if *email == "" {
+		flag.Usage()
+		log.Fatal("-email is required")
+	}
+
+	token, err := devauth.SignToken(cfg.DEV_AUTH_JWT_SECRET, *email, *name, *ttl)
+	if err != nil {
+		log.Fatalf("Cannot sign token: %v", err)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// runCheck checks a URL from the server, so results match what its monitors
+// would see. A target found down exits with status 2, for use in CI.
+func runCheck(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("check", "[flags] <url>")
+	kind := fs.String("type", "http", "check type: http, api or grpc")
+	method := fs.String("method", "GET", "HTTP method")
+	configPath := fs.String("config", "", "file with the type-specific config as JSON, or - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected one URL")
+	}
+	config, err := readConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]any{
+		"url":    fs.Arg(0),
+		"method": strings.ToUpper(*method),
+		"type":   *kind,
+	}
+	if config != nil {
+		body["config"] = config
+	}
+	var result CheckResult
+	if err := cli.client.JSON(ctx, http.MethodPost, "/monitor/check-url", nil, body, &result); err != nil {
+		return err
+	}
+
+	err = cli.out.Print(result, func(t *Table) {
+		t.Row("URL", result.Url)
+		t.Row("Result", checkSummary(result))
+		t.Row("DNS", yesNo(result.DnsOk))
+		t.Row("SSL", yesNo(result.SslOk))
+		if result.Timings != nil {
+			t.Row("DNS lookup", optional(result.Timings.DNSMs, "%.0fms"))
+			t.Row("Connect", optional(result.Timings.ConnectMs, "%.0fms"))
+			t.Row("TLS", optional(result.Timings.TLSMs, "%.0fms"))
+			t.Row("First byte", optional(result.Timings.TTFBMs, "%.0fms"))
+			t.Row("Transfer", optional(result.Timings.TransferMs, "%.0fms"))
+		}
+		for i, step := range result.Steps {
+			status := "ok"
+			if !step.OK {
+				status = "failed: " + orDash(step.Error)
+			}
+			t.Row(fmt.Sprintf("Step %d", i+1), fmt.Sprintf("%s %s %s  %d in %.0fms  %s", orDash(step.Name), step.Method, step.URL, step.StatusCode, step.ResponseTime, status))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if result.Status != "up" {
+		return errDown
+	}
+	return nil
+}
+
+// checkSummary puts the outcome of a check on one line
+func checkSummary(r CheckResult) string {
+	summary := fmt.Sprintf("%s, %d in %.0fms", r.Status, r.StatusCode, r.ResponseTime)
+	if r.Error != "" {
+		summary += ": " + r.Error
+	}
+	return summary
+}
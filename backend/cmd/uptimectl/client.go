@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the /v1 API with an API key
+type Client struct {
+	baseURL string
+	apiKey  string
+	org     string
+	http    *http.Client
+}
+
+func NewClient(baseURL, apiKey, org string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		org:     org,
+		// Ad-hoc checks and applying large documents take a while
+		http: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// APIError is an error response of the API
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.Status)
+}
+
+// JSON sends body as JSON, when not nil, and decodes the response into out,
+// when not nil
+func (c *Client) JSON(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	data, err := c.Do(ctx, method, path, query, "application/json", reader)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unexpected response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// Do sends a request and returns the body of a successful response
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader) ([]byte, error) {
+	target := c.baseURL + "/v1" + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.org != "" {
+		req.Header.Set("X-Org-ID", c.org)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, apiError(resp.StatusCode, data)
+	}
+	return data, nil
+}
+
+// apiError reads the message of a JSON error response, falling back to the
+// body of endpoints that answer in plain text
+func apiError(status int, body []byte) error {
+	var payload struct {
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Message != "" {
+		message = payload.Message
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &APIError{Status: status, Message: message}
+}
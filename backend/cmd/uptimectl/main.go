@@ -0,0 +1,131 @@
+// Command uptimectl drives the REST API from terminals and CI with an API
+// key: it manages monitors, tails checks and alerts, shows SLA reports, runs
+// ad-hoc checks and applies monitors-as-code documents.
+//
+// The server and key come from -server and -api-key or, so that keys stay
+// out of shell history and process lists, UPTIMECTL_SERVER and
+// UPTIMECTL_API_KEY.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+const usage = `Usage: uptimectl [flags] <command> [arguments]
+
+Commands:
+  monitors list|get|create|update|pause|resume|delete
+                 manage monitors
+  logs <id>      show a monitor's recent checks, -f to follow
+  alerts         show recent alerts, -f to follow
+  sla            show an SLA report
+  check <url>    run one check through the server's checker
+  export         print the monitors-as-code document
+  plan <file>    show what applying a document would change
+  apply <file>   apply a document
+
+Run "uptimectl <command> -h" for a command's flags.
+
+Flags:
+`
+
+// errDown makes a command exit with status 2 without printing an error, for
+// checks that ran but found the target down
+var errDown = errors.New("down")
+
+type command func(ctx context.Context, cli *CLI, args []string) error
+
+var commands = map[string]command{
+	"monitors": runMonitors,
+	"logs":     runLogs,
+	"alerts":   runAlerts,
+	"sla":      runSLA,
+	"check":    runCheck,
+	"export":   runExport,
+	"plan":     runPlan,
+	"apply":    runApply,
+}
+
+// CLI holds what every command needs
+type CLI struct {
+	client *Client
+	out    *Printer
+}
+
+func main() {
+	server := flag.String("server", envOr("UPTIMECTL_SERVER", "http://localhost:8080"), "API base URL")
+	apiKey := flag.String("api-key", os.Getenv("UPTIMECTL_API_KEY"), "API key, starting with bu_")
+	org := flag.String("org", os.Getenv("UPTIMECTL_ORG"), "organization ID, for keys of users in several organizations")
+	output := flag.String("o", "table", "output format: table or json")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	run, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "uptimectl: unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output != "table" && *output != "json" {
+		fatal(fmt.Errorf("-o must be table or json, not %q", *output))
+	}
+	if *apiKey == "" {
+		fatal(errors.New("no API key: set UPTIMECTL_API_KEY or pass -api-key"))
+	}
+	if !strings.HasPrefix(*apiKey, "bu_") {
+		fatal(errors.New("API keys start with bu_"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cli := &CLI{
+		client: NewClient(*server, *apiKey, *org),
+		out:    NewPrinter(os.Stdout, *output == "json"),
+	}
+	err := run(ctx, cli, flag.Args()[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errDown):
+		os.Exit(2)
+	case ctx.Err() != nil:
+		// Interrupted while following
+	default:
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "uptimectl: %v\n", err)
+	os.Exit(1)
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newFlagSet makes the flags of a command, which print their own usage on -h
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: uptimectl %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ApplyResponse is the response of POST /manifest/plan and /manifest/apply
+type ApplyResponse struct {
+	DryRun  bool `json:"dry_run"`
+	Prune   bool `json:"prune"`
+	Summary struct {
+		Create    int `json:"create"`
+		Update    int `json:"update"`
+		Delete    int `json:"delete"`
+		Unchanged int `json:"unchanged"`
+		Unmanaged int `json:"unmanaged"`
+		Unlisted  int `json:"unlisted"`
+	} `json:"summary"`
+	Changes []struct {
+		Kind       string `json:"kind"`
+		ExternalID string `json:"external_id"`
+		Action     string `json:"action"`
+		ID         int32  `json:"id"`
+		Adopted    bool   `json:"adopted"`
+		Changes    []struct {
+			Field  string          `json:"field"`
+			Before json.RawMessage `json:"before"`
+			After  json.RawMessage `json:"after"`
+		} `json:"changes"`
+	} `json:"changes"`
+}
+
+func runExport(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("export", "[flags]")
+	format := fs.String("format", "yaml", "document format: yaml or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "yaml" && *format != "json" {
+		return fmt.Errorf("-format must be yaml or json, not %q", *format)
+	}
+
+	data, err := cli.client.Do(ctx, http.MethodGet, "/manifest", url.Values{"format": {*format}}, "", nil)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runPlan(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("plan", "[flags] <file|->")
+	prune := fs.Bool("prune", false, "include deleting resources left out of the document")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return sendDocument(ctx, cli, fs.Args(), "/manifest/plan", url.Values{"prune": {strconv.FormatBool(*prune)}})
+}
+
+func runApply(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("apply", "[flags] <file|->")
+	prune := fs.Bool("prune", false, "delete resources left out of the document")
+	dryRun := fs.Bool("dry-run", false, "show the changes without making them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query := url.Values{
+		"prune":   {strconv.FormatBool(*prune)},
+		"dry_run": {strconv.FormatBool(*dryRun)},
+	}
+	return sendDocument(ctx, cli, fs.Args(), "/manifest/apply", query)
+}
+
+// sendDocument posts a document file as is, so the server reports problems
+// against what was written
+func sendDocument(ctx context.Context, cli *CLI, args []string, path string, query url.Values) error {
+	if len(args) != 1 {
+		return errors.New("expected one document file, or - for stdin")
+	}
+	doc, err := readInput(args[0])
+	if err != nil {
+		return err
+	}
+	contentType := "application/yaml"
+	if strings.EqualFold(filepath.Ext(args[0]), ".json") {
+		contentType = "application/json"
+	}
+
+	data, err := cli.client.Do(ctx, http.MethodPost, path, query, contentType, bytes.NewReader(doc))
+	if err != nil {
+		return err
+	}
+	var resp ApplyResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("unexpected response from %s: %w", path, err)
+	}
+
+	err = cli.out.Print(resp, func(t *Table) {
+		t.Row("ACTION", "KIND", "EXTERNAL ID", "ID", "FIELDS")
+		for _, c := range resp.Changes {
+			action := c.Action
+			if c.Adopted {
+				action += " (adopt)"
+			}
+			id := "-"
+			if c.ID != 0 {
+				id = strconv.Itoa(int(c.ID))
+			}
+			fields := make([]string, len(c.Changes))
+			for i, f := range c.Changes {
+				fields[i] = f.Field
+			}
+			t.Row(action, c.Kind, c.ExternalID, id, orDash(strings.Join(fields, ", ")))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s := resp.Summary
+	result := "Applied"
+	if resp.DryRun {
+		result = "Plan"
+	}
+	cli.out.Printf("\n%s: %d create, %d update, %d delete, %d unchanged, %d unmanaged, %d unlisted\n",
+		result, s.Create, s.Update, s.Delete, s.Unchanged, s.Unmanaged, s.Unlisted)
+	if s.Unlisted > 0 && !resp.Prune {
+		cli.out.Printf("Unlisted resources were applied from a document before; pass -prune to delete them.\n")
+	}
+	return nil
+}
@@ -0,0 +1,452 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MonitorStats is a row of GET /monitor/monitors/stats
+type MonitorStats struct {
+	ID               int32    `json:"id"`
+	Url              string   `json:"url"`
+	Type             string   `json:"type"`
+	Interval         int32    `json:"interval"`
+	Status           string   `json:"status"`
+	IsActive         bool     `json:"is_active"`
+	AvgResponseTime  string   `json:"avg_response_time"`
+	UptimePercentage string   `json:"uptime_percentage"`
+	LastCheck        string   `json:"last_check"`
+	GroupID          *int32   `json:"group_id"`
+	Tags             []string `json:"tags"`
+}
+
+// Monitor is a monitor as the API returns it
+type Monitor struct {
+	ID       int32   `json:"id"`
+	Url      string  `json:"url"`
+	Method   *string `json:"method"`
+	Type     *string `json:"type"`
+	Interval int32   `json:"interval"`
+	Status   struct {
+		MonitorStatus string `json:"monitor_status"`
+	} `json:"status"`
+	IsActive   *bool           `json:"is_active"`
+	GroupID    *int32          `json:"group_id"`
+	Tags       []string        `json:"tags"`
+	Config     json.RawMessage `json:"config"`
+	ExternalID *string         `json:"external_id"`
+	CreatedAt  *string         `json:"created_at"`
+	UpdatedAt  *string         `json:"updated_at"`
+	Secrets    []struct {
+		Name string `json:"name"`
+		Hint string `json:"hint"`
+	} `json:"secrets"`
+}
+
+// CheckResult is the result of one check
+type CheckResult struct {
+	Url          string  `json:"url"`
+	StatusCode   int32   `json:"status_code"`
+	ResponseTime float64 `json:"response_time"`
+	Status       string  `json:"status"`
+	DnsOk        bool    `json:"dns_ok"`
+	SslOk        bool    `json:"ssl_ok"`
+	Error        string  `json:"error,omitempty"`
+	Timings      *struct {
+		DNSMs      *float64 `json:"dns_ms"`
+		ConnectMs  *float64 `json:"connect_ms"`
+		TLSMs      *float64 `json:"tls_ms"`
+		TTFBMs     *float64 `json:"ttfb_ms"`
+		TransferMs *float64 `json:"transfer_ms"`
+	} `json:"timings,omitempty"`
+	Steps []struct {
+		Name         string  `json:"name"`
+		Method       string  `json:"method"`
+		URL          string  `json:"url"`
+		StatusCode   int32   `json:"status_code"`
+		ResponseTime float64 `json:"response_time"`
+		OK           bool    `json:"ok"`
+		Error        string  `json:"error,omitempty"`
+	} `json:"steps,omitempty"`
+}
+
+const monitorsUsage = "list|get|create|update|pause|resume|delete [flags] [id...]"
+
+func runMonitors(ctx context.Context, cli *CLI, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: uptimectl monitors %s", monitorsUsage)
+	}
+	switch args[0] {
+	case "list", "ls":
+		return listMonitors(ctx, cli, args[1:])
+	case "get":
+		return getMonitor(ctx, cli, args[1:])
+	case "create":
+		return createMonitor(ctx, cli, args[1:])
+	case "update":
+		return updateMonitor(ctx, cli, args[1:])
+	case "pause":
+		return toggleMonitors(ctx, cli, args[1:], false)
+	case "resume":
+		return toggleMonitors(ctx, cli, args[1:], true)
+	case "delete", "rm":
+		return deleteMonitors(ctx, cli, args[1:])
+	default:
+		return fmt.Errorf("unknown monitors command %q, want one of %s", args[0], monitorsUsage)
+	}
+}
+
+func listMonitors(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("monitors list", "[flags]")
+	var tags stringList
+	fs.Var(&tags, "tag", "only monitors with this tag, repeatable")
+	status := fs.String("status", "", "only monitors that are up, down, pending or paused")
+	group := fs.Int("group", 0, "only monitors in this group")
+	search := fs.String("q", "", "only monitors whose URL contains this text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+	if *status != "" {
+		query.Set("status", *status)
+	}
+	if *group != 0 {
+		query.Set("group_id", strconv.Itoa(*group))
+	}
+	if *search != "" {
+		query.Set("q", *search)
+	}
+
+	var monitors []MonitorStats
+	if err := cli.client.JSON(ctx, http.MethodGet, "/monitor/monitors/stats", query, nil, &monitors); err != nil {
+		return err
+	}
+	return cli.out.Print(monitors, func(t *Table) {
+		t.Row("ID", "URL", "TYPE", "INTERVAL", "STATUS", "UPTIME", "AVG", "LAST CHECK", "TAGS")
+		for _, m := range monitors {
+			status := m.Status
+			if !m.IsActive {
+				status = "paused"
+			}
+			t.Row(m.ID, m.Url, orDash(m.Type), fmt.Sprintf("%ds", m.Interval), status,
+				m.UptimePercentage, orDash(m.AvgResponseTime), m.LastCheck, orDash(strings.Join(m.Tags, ",")))
+		}
+	})
+}
+
+func getMonitor(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("monitors get", "<id>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args(), 1)
+	if err != nil {
+		return err
+	}
+
+	monitor, err := fetchMonitor(ctx, cli, ids[0])
+	if err != nil {
+		return err
+	}
+	return cli.out.Print(monitor, func(t *Table) {
+		printMonitor(t, monitor)
+	})
+}
+
+func fetchMonitor(ctx context.Context, cli *CLI, id int32) (Monitor, error) {
+	var monitor Monitor
+	err := cli.client.JSON(ctx, http.MethodGet, fmt.Sprintf("/monitor/get-monitor/%d", id), nil, nil, &monitor)
+	return monitor, err
+}
+
+func printMonitor(t *Table, m Monitor) {
+	t.Row("ID", m.ID)
+	t.Row("URL", m.Url)
+	t.Row("Type", orDash(deref(m.Type)))
+	t.Row("Method", orDash(deref(m.Method)))
+	t.Row("Interval", fmt.Sprintf("%ds", m.Interval))
+	status := orDash(m.Status.MonitorStatus)
+	if m.IsActive != nil && !*m.IsActive {
+		status = "paused"
+	}
+	t.Row("Status", status)
+	t.Row("Group", optional(m.GroupID, "%d"))
+	t.Row("Tags", orDash(strings.Join(m.Tags, ",")))
+	t.Row("External ID", orDash(deref(m.ExternalID)))
+	if len(m.Config) > 0 && string(m.Config) != "null" {
+		t.Row("Config", string(m.Config))
+	}
+	for _, s := range m.Secrets {
+		t.Row("Secret "+s.Name, s.Hint)
+	}
+	t.Row("Updated", orDash(deref(m.UpdatedAt)))
+}
+
+// monitorFlags are the settings create and update share
+type monitorFlags struct {
+	url      *string
+	method   *string
+	kind     *string
+	interval *int
+	group    *int
+	tags     *string
+	config   *string
+}
+
+func addMonitorFlags(fs *flag.FlagSet) monitorFlags {
+	return monitorFlags{
+		url:      fs.String("url", "", "URL, or host:port for grpc monitors"),
+		method:   fs.String("method", "GET", "HTTP method"),
+		kind:     fs.String("type", "http", "monitor type: http, api, browser, grpc, postgres, mysql or redis"),
+		interval: fs.Int("interval", 60, "seconds between checks"),
+		group:    fs.Int("group", 0, "monitor group ID, 0 for none"),
+		tags:     fs.String("tags", "", "comma-separated tags"),
+		config:   fs.String("config", "", "file with the type-specific config as JSON, - for stdin"),
+	}
+}
+
+func splitTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func readConfig(path string) (json.RawMessage, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", path)
+	}
+	return data, nil
+}
+
+func createMonitor(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("monitors create", "-url <url> [flags]")
+	flags := addMonitorFlags(fs)
+	paused := fs.Bool("paused", false, "create the monitor paused")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *flags.url == "" {
+		return fmt.Errorf("-url is required")
+	}
+	config, err := readConfig(*flags.config)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]any{
+		"url":       *flags.url,
+		"method":    strings.ToUpper(*flags.method),
+		"type":      *flags.kind,
+		"interval":  *flags.interval,
+		"status":    "pending",
+		"is_active": !*paused,
+		"tags":      splitTags(*flags.tags),
+	}
+	if config != nil {
+		body["config"] = config
+	}
+	if *flags.group != 0 {
+		body["group_id"] = *flags.group
+	}
+
+	var created struct {
+		Monitor    Monitor      `json:"monitor"`
+		FirstCheck *CheckResult `json:"first_check"`
+		Message    string       `json:"message"`
+	}
+	if err := cli.client.JSON(ctx, http.MethodPost, "/monitor/create-monitor", nil, body, &created); err != nil {
+		return err
+	}
+	return cli.out.Print(created, func(t *Table) {
+		printMonitor(t, created.Monitor)
+		if created.FirstCheck != nil {
+			t.Row("First check", checkSummary(*created.FirstCheck))
+		}
+	})
+}
+
+func updateMonitor(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("monitors update", "[flags] <id>")
+	flags := addMonitorFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args(), 1)
+	if err != nil {
+		return err
+	}
+
+	// The endpoint replaces the URL, method, type and interval, so unchanged
+	// ones are sent as they are
+	existing, err := fetchMonitor(ctx, cli, ids[0])
+	if err != nil {
+		return err
+	}
+	body := map[string]any{
+		"id":       existing.ID,
+		"url":      existing.Url,
+		"method":   deref(existing.Method),
+		"type":     deref(existing.Type),
+		"interval": existing.Interval,
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if len(set) == 0 {
+		return fmt.Errorf("nothing to update, pass at least one of -url, -method, -type, -interval, -group, -tags or -config")
+	}
+	if set["url"] {
+		body["url"] = *flags.url
+	}
+	if set["method"] {
+		body["method"] = strings.ToUpper(*flags.method)
+	}
+	if set["type"] {
+		body["type"] = *flags.kind
+	}
+	if set["interval"] {
+		body["interval"] = *flags.interval
+	}
+	if set["group"] {
+		body["group_id"] = *flags.group
+	}
+	if set["tags"] {
+		body["tags"] = splitTags(*flags.tags)
+	}
+	if set["config"] {
+		config, err := readConfig(*flags.config)
+		if err != nil {
+			return err
+		}
+		body["config"] = config
+	}
+
+	var updated Monitor
+	if err := cli.client.JSON(ctx, http.MethodPut, "/monitor/update-monitor", nil, body, &updated); err != nil {
+		return err
+	}
+	return cli.out.Print(updated, func(t *Table) {
+		printMonitor(t, updated)
+	})
+}
+
+func toggleMonitors(ctx context.Context, cli *CLI, args []string, active bool) error {
+	name := "monitors pause"
+	if active {
+		name = "monitors resume"
+	}
+	fs := newFlagSet(name, "<id>...")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args(), -1)
+	if err != nil {
+		return err
+	}
+
+	var toggled []Monitor
+	for _, id := range ids {
+		var monitor Monitor
+		body := map[string]any{"id": id, "is_active": active}
+		if err := cli.client.JSON(ctx, http.MethodPost, "/monitor/toggle-monitor", nil, body, &monitor); err != nil {
+			return fmt.Errorf("monitor %d: %w", id, err)
+		}
+		toggled = append(toggled, monitor)
+	}
+	return cli.out.Print(toggled, func(t *Table) {
+		t.Row("ID", "URL", "ACTIVE")
+		for _, m := range toggled {
+			t.Row(m.ID, m.Url, m.IsActive != nil && *m.IsActive)
+		}
+	})
+}
+
+func deleteMonitors(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("monitors delete", "<id>...")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args(), -1)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := cli.client.JSON(ctx, http.MethodDelete, fmt.Sprintf("/monitor/delete-monitor/%d", id), nil, nil, nil); err != nil {
+			return fmt.Errorf("monitor %d: %w", id, err)
+		}
+	}
+	return cli.out.Print(map[string]any{"deleted": ids}, func(t *Table) {
+		for _, id := range ids {
+			t.Row("Deleted monitor", id)
+		}
+	})
+}
+
+// parseIDs reads monitor IDs from arguments, exactly n of them or, when n is
+// negative, at least one
+func parseIDs(args []string, n int) ([]int32, error) {
+	if n >= 0 && len(args) != n || n < 0 && len(args) == 0 {
+		return nil, fmt.Errorf("expected a monitor ID")
+	}
+	ids := make([]int32, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid monitor ID %q", arg)
+		}
+		ids = append(ids, int32(id))
+	}
+	return ids, nil
+}
+
+// readInput reads a file, or stdin for "-"
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
+	}
+	return *v
+}
+
+// stringList is a flag that may be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
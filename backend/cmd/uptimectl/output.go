@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Printer writes results as aligned tables or, for scripts, as JSON
+type Printer struct {
+	w    io.Writer
+	json bool
+}
+
+func NewPrinter(w io.Writer, asJSON bool) *Printer {
+	return &Printer{w: w, json: asJSON}
+}
+
+// JSON reports whether results are printed as JSON
+func (p *Printer) JSON() bool {
+	return p.json
+}
+
+// Print writes v as indented JSON in JSON mode, otherwise calls table
+func (p *Printer) Print(v any, table func(t *Table)) error {
+	if p.json {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	t := p.Table()
+	table(t)
+	return t.Flush()
+}
+
+// Line writes v as one line of JSON, for streams of results
+func (p *Printer) Line(v any) error {
+	return json.NewEncoder(p.w).Encode(v)
+}
+
+// Printf writes text meant for people, which JSON output leaves out
+func (p *Printer) Printf(format string, args ...any) {
+	if !p.json {
+		fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// Table starts a table, which is written on Flush
+func (p *Printer) Table() *Table {
+	return &Table{w: tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)}
+}
+
+type Table struct {
+	w *tabwriter.Writer
+}
+
+func (t *Table) Row(cells ...any) {
+	columns := make([]string, len(cells))
+	for i, cell := range cells {
+		columns[i] = strings.ReplaceAll(fmt.Sprint(cell), "\t", " ")
+	}
+	fmt.Fprintln(t.w, strings.Join(columns, "\t"))
+}
+
+func (t *Table) Flush() error {
+	return t.w.Flush()
+}
+
+// orDash shows empty values as a dash, so columns stay readable
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// optional formats a value the API may leave out
+func optional[T any](v *T, format string) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf(format, *v)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SLAReport is the response of GET /analytics/sla
+type SLAReport struct {
+	Scope       string   `json:"scope"`
+	Name        string   `json:"name"`
+	PeriodStart string   `json:"period_start"`
+	PeriodEnd   string   `json:"period_end"`
+	Timezone    string   `json:"timezone"`
+	SLOTarget   float64  `json:"slo_target"`
+	Summary     SLAStats `json:"summary"`
+	Monitors    []struct {
+		MonitorID int32  `json:"monitor_id"`
+		Name      string `json:"name"`
+		SLAStats
+	} `json:"monitors"`
+}
+
+type SLAStats struct {
+	MeasuredSeconds             int64    `json:"measured_seconds"`
+	UptimeSeconds               int64    `json:"uptime_seconds"`
+	DowntimeSeconds             int64    `json:"downtime_seconds"`
+	MaintenanceSeconds          int64    `json:"maintenance_seconds"`
+	NoDataSeconds               int64    `json:"no_data_seconds"`
+	UptimePercent               *float64 `json:"uptime_percent"`
+	Incidents                   int      `json:"incidents"`
+	MTTRSeconds                 *int64   `json:"mttr_seconds"`
+	MTBFSeconds                 *int64   `json:"mtbf_seconds"`
+	ErrorBudgetSeconds          int64    `json:"error_budget_seconds"`
+	ErrorBudgetRemainingSeconds int64    `json:"error_budget_remaining_seconds"`
+	ErrorBudgetRemainingPercent *float64 `json:"error_budget_remaining_percent"`
+	SLOMet                      bool     `json:"slo_met"`
+}
+
+func runSLA(ctx context.Context, cli *CLI, args []string) error {
+	fs := newFlagSet("sla", "-monitor <id> | -group <id> | -status-page <id> [flags]")
+	monitorID := fs.Int("monitor", 0, "report on a monitor")
+	groupID := fs.Int("group", 0, "report on a monitor group")
+	pageID := fs.Int("status-page", 0, "report on the monitors of a status page")
+	month := fs.String("month", "", "calendar month, as YYYY-MM; the current month by default")
+	from := fs.String("from", "", "start of a custom period, as YYYY-MM-DD")
+	to := fs.String("to", "", "end of a custom period, as YYYY-MM-DD")
+	tz := fs.String("tz", "", "timezone of the period, like Europe/Berlin; UTC by default")
+	slo := fs.Float64("slo", 0, "SLO target in percent; the server default when unset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	scopes := 0
+	for name, id := range map[string]int{"monitor_id": *monitorID, "group_id": *groupID, "status_page_id": *pageID} {
+		if id != 0 {
+			query.Set(name, strconv.Itoa(id))
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		return errors.New("pass exactly one of -monitor, -group and -status-page")
+	}
+	for name, value := range map[string]string{"month": *month, "from": *from, "to": *to, "tz": *tz} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if *slo != 0 {
+		query.Set("slo", strconv.FormatFloat(*slo, 'f', -1, 64))
+	}
+
+	var report SLAReport
+	if err := cli.client.JSON(ctx, http.MethodGet, "/analytics/sla", query, nil, &report); err != nil {
+		return err
+	}
+	if cli.out.JSON() {
+		return cli.out.Print(report, nil)
+	}
+
+	t := cli.out.Table()
+	t.Row(report.Scope, report.Name)
+	t.Row("Period", fmt.Sprintf("%s to %s (%s)", report.PeriodStart, report.PeriodEnd, report.Timezone))
+	t.Row("SLO", fmt.Sprintf("%g%%", report.SLOTarget))
+	t.Row("")
+	if err := t.Flush(); err != nil {
+		return err
+	}
+
+	t = cli.out.Table()
+	t.Row("ID", "MONITOR", "UPTIME", "DOWNTIME", "INCIDENTS", "MTTR", "MTBF", "BUDGET LEFT", "SLO MET")
+	for _, m := range report.Monitors {
+		slaRow(t, strconv.Itoa(int(m.MonitorID)), m.Name, m.SLAStats)
+	}
+	slaRow(t, "", "total", report.Summary)
+	return t.Flush()
+}
+
+func slaRow(t *Table, id, name string, s SLAStats) {
+	t.Row(
+		orDash(id),
+		name,
+		optional(s.UptimePercent, "%.3f%%"),
+		seconds(&s.DowntimeSeconds),
+		s.Incidents,
+		seconds(s.MTTRSeconds),
+		seconds(s.MTBFSeconds),
+		fmt.Sprintf("%s (%s)", seconds(&s.ErrorBudgetRemainingSeconds), optional(s.ErrorBudgetRemainingPercent, "%.1f%%")),
+		yesNo(s.SLOMet),
+	)
+}
+
+// seconds shows a duration in seconds the way Go prints durations, like 1h2m3s
+func seconds(s *int64) string {
+	if s == nil {
+		return "-"
+	}
+	return (time.Duration(*s) * time.Second).String()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
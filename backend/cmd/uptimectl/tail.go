@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CheckLog is a row of GET /monitor/monitor/{id}/logs
+type CheckLog struct {
+	ID           int32    `json:"id"`
+	StatusCode   *int32   `json:"status_code"`
+	ResponseTime *float64 `json:"response_time"`
+	DnsOk        *bool    `json:"dns_ok"`
+	SslOk        *bool    `json:"ssl_ok"`
+	ErrorType    *string  `json:"error_type"`
+	ErrorMessage *string  `json:"error_message"`
+	CheckedAt    string   `json:"checked_at"`
+}
+
+// Alert is a row of GET /alert/recent
+type Alert struct {
+	ID        int32  `json:"id"`
+	MonitorID int32  `json:"monitor_id"`
+	URL       string `json:"url"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+}
+
+// maxTailLimit is the most rows the endpoints return at once
+const maxTailLimit = 100
+
+type tailFlags struct {
+	follow *bool
+	lines  *int
+	every  *time.Duration
+}
+
+func newTailFlagSet(name, args string) (*flag.FlagSet, tailFlags) {
+	fs := newFlagSet(name, args)
+	return fs, tailFlags{
+		follow: fs.Bool("f", false, "keep polling and print new rows as they arrive"),
+		lines:  fs.Int("n", 20, "how many recent rows to show first"),
+		every:  fs.Duration("every", 5*time.Second, "how often to poll with -f"),
+	}
+}
+
+func runLogs(ctx context.Context, cli *CLI, args []string) error {
+	fs, flags := newTailFlagSet("logs", "[flags] <monitor-id>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args(), 1)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/monitor/monitor/%d/logs", ids[0])
+
+	fetch := func(limit int) ([]CheckLog, error) {
+		var page struct {
+			Logs []CheckLog `json:"logs"`
+		}
+		query := url.Values{"limit": {strconv.Itoa(limit)}}
+		err := cli.client.JSON(ctx, http.MethodGet, path, query, nil, &page)
+		return page.Logs, err
+	}
+	header := []any{"CHECKED AT", "STATUS", "CODE", "TIME", "ERROR"}
+	row := func(t *Table, l CheckLog) {
+		status := "up"
+		if l.ErrorType != nil && *l.ErrorType != "" || l.StatusCode == nil || *l.StatusCode < 200 || *l.StatusCode >= 400 {
+			status = "down"
+		}
+		t.Row(l.CheckedAt, status, optional(l.StatusCode, "%d"), optional(l.ResponseTime, "%.0fms"), orDash(deref(l.ErrorMessage)))
+	}
+	id := func(l CheckLog) int32 { return l.ID }
+	return tail(ctx, cli, flags, fetch, id, header, row)
+}
+
+func runAlerts(ctx context.Context, cli *CLI, args []string) error {
+	fs, flags := newTailFlagSet("alerts", "[flags]")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fetch := func(limit int) ([]Alert, error) {
+		var alerts []Alert
+		query := url.Values{"limit": {strconv.Itoa(limit)}}
+		err := cli.client.JSON(ctx, http.MethodGet, "/alert/recent", query, nil, &alerts)
+		return alerts, err
+	}
+	header := []any{"TIME", "MONITOR", "TYPE", "URL", "MESSAGE"}
+	row := func(t *Table, a Alert) {
+		t.Row(a.Timestamp, a.MonitorID, a.Type, a.URL, a.Message)
+	}
+	id := func(a Alert) int32 { return a.ID }
+	return tail(ctx, cli, flags, fetch, id, header, row)
+}
+
+// tail prints the latest rows oldest first and, when following, polls for
+// rows newer than the last one printed. fetch returns rows newest first.
+// Followed JSON output has one object per line so it can be piped.
+func tail[T any](ctx context.Context, cli *CLI, flags tailFlags, fetch func(limit int) ([]T, error), id func(T) int32, header []any, row func(*Table, T)) error {
+	limit := min(max(*flags.lines, 1), maxTailLimit)
+	rows, err := fetch(limit)
+	if err != nil {
+		return err
+	}
+	reverse(rows)
+
+	if !*flags.follow {
+		return cli.out.Print(rows, func(t *Table) {
+			t.Row(header...)
+			for _, r := range rows {
+				row(t, r)
+			}
+		})
+	}
+
+	var last int32
+	printRows := func(rows []T, withHeader bool) error {
+		if cli.out.JSON() {
+			for _, r := range rows {
+				if err := cli.out.Line(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		t := cli.out.Table()
+		if withHeader {
+			t.Row(header...)
+		}
+		for _, r := range rows {
+			row(t, r)
+		}
+		return t.Flush()
+	}
+	for _, r := range rows {
+		last = max(last, id(r))
+	}
+	if err := printRows(rows, true); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(*flags.every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		latest, err := fetch(maxTailLimit)
+		if err != nil {
+			return err
+		}
+		var fresh []T
+		for _, r := range latest {
+			if id(r) > last {
+				fresh = append(fresh, r)
+			}
+		}
+		reverse(fresh)
+		for _, r := range fresh {
+			last = max(last, id(r))
+		}
+		if err := printRows(fresh, false); err != nil {
+			return err
+		}
+	}
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
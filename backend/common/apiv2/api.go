@@ -0,0 +1,95 @@
+package apiv2
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// API is the /v2 router. Handle mounts an operation and adds it to the
+// OpenAPI document served at /openapi.json in one step, so the contract
+// cannot drift from the routes.
+type API struct {
+	root   *chi.Mux
+	router chi.Router
+	spec   *spec
+	store  db.Store
+}
+
+// Operation describes an endpoint for the router and the OpenAPI document
+type Operation struct {
+	ID          string
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	Query       []Param
+	// Paginated adds the limit and cursor query parameters
+	Paginated bool
+	// Body and Response are zero values of the request and response types
+	Body     any
+	Response any
+	// Status is the success status, 200 when zero
+	Status int
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Description string
+	// Type is a JSON schema type, string when empty
+	Type     string
+	Repeated bool
+}
+
+func (op Operation) status() int {
+	if op.Status == 0 {
+		return http.StatusOK
+	}
+	return op.Status
+}
+
+func New(store db.Store) *API {
+	root := routes.DefaultRouter(Envelope)
+	root.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, ErrNotFound)
+	})
+	root.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, ErrMethodNotAllowed)
+	})
+
+	s := newSpec()
+	root.Get("/openapi.json", s.serve)
+
+	return &API{
+		root:   root,
+		router: root.With(middleware.TokenMiddleware(store)),
+		spec:   s,
+		store:  store,
+	}
+}
+
+// With returns an API whose operations also run the given middlewares
+func (a *API) With(middlewares ...func(http.Handler) http.Handler) *API {
+	scoped := *a
+	scoped.router = a.router.With(middlewares...)
+	return &scoped
+}
+
+// Handle mounts handler for op. POSTs honour the Idempotency-Key header.
+func (a *API) Handle(op Operation, handler http.HandlerFunc) {
+	var h http.Handler = handler
+	if op.Method == http.MethodPost {
+		h = Idempotent(a.store)(h)
+	}
+	a.router.Method(op.Method, op.Path, h)
+	a.spec.add(op)
+}
+
+func (a *API) Handler() http.Handler {
+	return a.root
+}
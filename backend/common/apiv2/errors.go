@@ -0,0 +1,207 @@
+package apiv2
+
+import (
+	"better-uptime/common/util"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Error is how every v2 error is reported, as {"error": {...}}. Code is
+// stable for clients to branch on; Message is for people.
+type Error struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+type errorEnvelope struct {
+	Error *Error `json:"error"`
+}
+
+// Error codes besides the ones derived from the status (see codeForStatus)
+const (
+	CodeInvalidJSON          = "invalid_json"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidCursor        = "invalid_cursor"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodeIdempotencyKeyInUse  = "idempotency_key_in_use"
+)
+
+var (
+	ErrNotFound         = &Error{Status: http.StatusNotFound, Code: "not_found", Message: "resource not found"}
+	ErrMethodNotAllowed = &Error{Status: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "method not allowed"}
+	ErrInvalidCursor    = &Error{Status: http.StatusBadRequest, Code: CodeInvalidCursor, Message: "invalid cursor, pass next_cursor of the previous page as is"}
+	ErrInvalidID        = &Error{Status: http.StatusBadRequest, Code: "invalid_request", Message: "invalid id"}
+)
+
+// Errorf makes an error with the code of its status
+func Errorf(status int, format string, args ...any) *Error {
+	return &Error{Status: status, Code: codeForStatus(status), Message: fmt.Sprintf(format, args...)}
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "invalid_request"
+	case http.StatusUnauthorized:
+		return "unauthenticated"
+	case http.StatusPaymentRequired:
+		return "plan_limit_reached"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusRequestEntityTooLarge:
+		return "request_too_large"
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusServiceUnavailable:
+		return "service_unavailable"
+	}
+	if status >= 500 {
+		return "internal_error"
+	}
+	return "invalid_request"
+}
+
+// AsError classifies any error the handlers return. Errors of the util
+// catalogue keep their status; database failures are logged and reported
+// without their details.
+func AsError(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		return Errorf(http.StatusRequestEntityTooLarge, "the request body is larger than %d bytes", maxBytes.Limit)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return Errorf(http.StatusConflict, "a resource with these values already exists")
+		case "23503":
+			return Errorf(http.StatusConflict, "a referenced resource does not exist")
+		}
+		log.Printf("❌ v2 database error: %v", err)
+		return Errorf(http.StatusInternalServerError, "internal error")
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return Errorf(http.StatusServiceUnavailable, "the request was cancelled or timed out")
+	}
+	for known, status := range util.CustomErrorType {
+		if errors.Is(err, known) {
+			return Errorf(status, "%s", err.Error())
+		}
+	}
+	return Errorf(http.StatusBadRequest, "%s", err.Error())
+}
+
+// WriteError writes err in the error envelope
+func WriteError(w http.ResponseWriter, err error) {
+	apiErr := AsError(err)
+	util.WriteJson(w, apiErr.Status, errorEnvelope{Error: apiErr})
+}
+
+// Envelope rewrites error responses that were not written by WriteError, like
+// those of the shared auth middleware or http.Error, into the error envelope,
+// so every v2 error has the same shape.
+func Envelope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ew := &envelopeWriter{ResponseWriter: w}
+		next.ServeHTTP(ew, r)
+		ew.finish()
+	})
+}
+
+// envelopeWriter passes successful responses through and holds back the body
+// of errors until the handler is done
+type envelopeWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *envelopeWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	if status < 400 {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *envelopeWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.status < 400 {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.body.Write(p)
+}
+
+func (w *envelopeWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && w.status < 400 {
+		f.Flush()
+	}
+}
+
+func (w *envelopeWriter) finish() {
+	if w.status < 400 {
+		return
+	}
+
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+	body := w.body.Bytes()
+	if json.Unmarshal(body, &envelope) == nil && bytes.HasPrefix(bytes.TrimSpace(envelope.Error), []byte("{")) {
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.Write(body)
+		return
+	}
+
+	// The v1 shape {"error": true, "message": ...} or plain text
+	var v1 struct {
+		Message string `json:"message"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &v1) == nil && v1.Message != "" {
+		message = v1.Message
+	}
+	if message == "" {
+		message = strings.ToLower(http.StatusText(w.status))
+	}
+
+	header := w.ResponseWriter.Header()
+	header.Del("Content-Length")
+	header.Del("X-Content-Type-Options")
+	util.WriteJson(w.ResponseWriter, w.status, errorEnvelope{Error: Errorf(w.status, "%s", message)})
+}
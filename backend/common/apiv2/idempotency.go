@@ -0,0 +1,153 @@
+package apiv2
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/common/middleware"
+	db "better-uptime/internal/db/sqlc"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// IdempotencyKeyHeader makes a POST safe to retry: a retry with the same
+	// key within 24 hours gets the first response instead of running again
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a replayed response
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotent stores the response of requests sent with an Idempotency-Key
+// per organization and caller and replays it for retries. A key sent with a
+// different request is rejected, as is a retry while the first request still
+// runs. Server errors free the key, since nothing was created.
+func Idempotent(store db.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				WriteError(w, Errorf(http.StatusBadRequest, "%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength))
+				return
+			}
+
+			ctx := r.Context()
+			payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+			if err != nil {
+				WriteError(w, Errorf(http.StatusUnauthorized, "unauthenticated"))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := sha256.New()
+			io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+			hash.Write(body)
+			requestHash := hex.EncodeToString(hash.Sum(nil))
+
+			id := db.GetIdempotencyKeyParams{OrgID: payload.OrgId, Principal: principal(payload), Key: key}
+			_, err = store.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+				OrgID:       id.OrgID,
+				Principal:   id.Principal,
+				Key:         id.Key,
+				RequestHash: requestHash,
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
+				replay(w, r, store, id, requestHash)
+				return
+			}
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// Keep the outcome even if the client went away meanwhile
+			ctx = context.WithoutCancel(ctx)
+			if rec.status >= 500 {
+				err = store.ReleaseIdempotencyKey(ctx, db.ReleaseIdempotencyKeyParams(id))
+			} else {
+				err = store.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+					OrgID:        id.OrgID,
+					Principal:    id.Principal,
+					Key:          id.Key,
+					StatusCode:   pgtype.Int4{Int32: int32(rec.status), Valid: true},
+					ResponseBody: rec.body.Bytes(),
+				})
+			}
+			if err != nil {
+				log.Printf("❌ Failed to store the outcome of idempotency key %q: %v", key, err)
+			}
+		})
+	}
+}
+
+// principal is who sent the request; one caller's keys never match another's
+func principal(payload firebase.FirebasePayload) string {
+	if payload.APIKeyID != 0 {
+		return "api_key:" + strconv.Itoa(int(payload.APIKeyID))
+	}
+	return "user:" + payload.UserId.String()
+}
+
+func replay(w http.ResponseWriter, r *http.Request, store db.Store, id db.GetIdempotencyKeyParams, requestHash string) {
+	stored, err := store.GetIdempotencyKey(r.Context(), id)
+	if err != nil {
+		// Released or expired between the claim and now
+		WriteError(w, &Error{Status: http.StatusConflict, Code: CodeIdempotencyKeyInUse, Message: "the request with this idempotency key just finished, retry"})
+		return
+	}
+	switch {
+	case stored.RequestHash != requestHash:
+		WriteError(w, &Error{Status: http.StatusUnprocessableEntity, Code: CodeIdempotencyKeyReused, Message: "this idempotency key was used for a different request"})
+	case !stored.StatusCode.Valid:
+		WriteError(w, &Error{Status: http.StatusConflict, Code: CodeIdempotencyKeyInUse, Message: "a request with this idempotency key is still running"})
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(int(stored.StatusCode.Int32))
+		w.Write(stored.ResponseBody)
+	}
+}
+
+// recorder keeps a copy of the response as it is written
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
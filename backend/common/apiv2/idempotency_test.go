@@ -0,0 +1,148 @@
+package apiv2
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/common/middleware"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// keyStore keeps idempotency keys in memory; expiry is not simulated
+type keyStore struct {
+	db.Store
+	mu   sync.Mutex
+	keys map[db.GetIdempotencyKeyParams]db.IdempotencyKey
+}
+
+func newKeyStore() *keyStore {
+	return &keyStore{keys: make(map[db.GetIdempotencyKeyParams]db.IdempotencyKey)}
+}
+
+func (s *keyStore) ClaimIdempotencyKey(_ context.Context, arg db.ClaimIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := db.GetIdempotencyKeyParams{OrgID: arg.OrgID, Principal: arg.Principal, Key: arg.Key}
+	if _, ok := s.keys[id]; ok {
+		return db.IdempotencyKey{}, pgx.ErrNoRows
+	}
+	row := db.IdempotencyKey{OrgID: arg.OrgID, Principal: arg.Principal, Key: arg.Key, RequestHash: arg.RequestHash}
+	s.keys[id] = row
+	return row, nil
+}
+
+func (s *keyStore) GetIdempotencyKey(_ context.Context, arg db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row, ok := s.keys[arg]
+	if !ok {
+		return db.IdempotencyKey{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (s *keyStore) CompleteIdempotencyKey(_ context.Context, arg db.CompleteIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := db.GetIdempotencyKeyParams{OrgID: arg.OrgID, Principal: arg.Principal, Key: arg.Key}
+	row := s.keys[id]
+	row.StatusCode, row.ResponseBody = arg.StatusCode, arg.ResponseBody
+	s.keys[id] = row
+	return nil
+}
+
+func (s *keyStore) ReleaseIdempotencyKey(_ context.Context, arg db.ReleaseIdempotencyKeyParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, db.GetIdempotencyKeyParams(arg))
+	return nil
+}
+
+// countingHandler answers 201 with the number of times it ran
+func countingHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call":` + strconv.Itoa(*calls) + `}`))
+	})
+}
+
+func idempotentRequest(payload firebase.FirebasePayload, path, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	r.Header.Set(IdempotencyKeyHeader, "retry-1")
+	return r.WithContext(context.WithValue(r.Context(), middleware.TokenPayloadKey, payload))
+}
+
+func serve(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	calls := 0
+	handler := Idempotent(newKeyStore())(countingHandler(&calls))
+	user := firebase.FirebasePayload{OrgId: uuid.New(), UserId: uuid.New()}
+
+	first := serve(handler, idempotentRequest(user, "/v2/monitors", `{"name":"api"}`))
+	retry := serve(handler, idempotentRequest(user, "/v2/monitors", `{"name":"api"}`))
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want once", calls)
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry got %d %s, want the first response %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("retry is missing the %s header", IdempotentReplayedHeader)
+	}
+}
+
+func TestIdempotentKeyReusedForDifferentRequest(t *testing.T) {
+	calls := 0
+	handler := Idempotent(newKeyStore())(countingHandler(&calls))
+	user := firebase.FirebasePayload{OrgId: uuid.New(), UserId: uuid.New()}
+
+	serve(handler, idempotentRequest(user, "/v2/monitors", `{"name":"api"}`))
+
+	for name, r := range map[string]*http.Request{
+		"other body":  idempotentRequest(user, "/v2/monitors", `{"name":"web"}`),
+		"other path":  idempotentRequest(user, "/v2/status-pages", `{"name":"api"}`),
+		"other query": idempotentRequest(user, "/v2/monitors?dry_run=true", `{"name":"api"}`),
+	} {
+		w := serve(handler, r)
+		if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), CodeIdempotencyKeyReused) {
+			t.Errorf("%s: got %d %s, want 422 %s", name, w.Code, w.Body, CodeIdempotencyKeyReused)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want once", calls)
+	}
+}
+
+func TestIdempotentKeysArePerPrincipal(t *testing.T) {
+	calls := 0
+	handler := Idempotent(newKeyStore())(countingHandler(&calls))
+	orgID := uuid.New()
+	alice := firebase.FirebasePayload{OrgId: orgID, UserId: uuid.New()}
+	bob := firebase.FirebasePayload{OrgId: orgID, UserId: uuid.New()}
+	apiKey := firebase.FirebasePayload{OrgId: orgID, UserId: alice.UserId, APIKeyID: 7}
+
+	for _, payload := range []firebase.FirebasePayload{alice, bob, apiKey} {
+		w := serve(handler, idempotentRequest(payload, "/v2/monitors", `{"name":"api"}`))
+		if w.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("%s got another caller's response", principal(payload))
+		}
+	}
+	if calls != 3 {
+		t.Errorf("handler ran %d times, want once per caller", calls)
+	}
+}
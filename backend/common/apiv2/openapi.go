@@ -0,0 +1,443 @@
+package apiv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The OpenAPI 3.0 document is generated from the operations as they are
+// mounted and from the Go types of their bodies, so it always matches what
+// the server does. Schemas follow the json tags: omitempty fields are
+// optional and the others required, pointers without omitempty are nullable,
+// and the validate tags become constraints. A doc tag describes a field.
+
+const (
+	openAPIVersion = "3.0.3"
+	specVersion    = "2.0.0"
+)
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*schema          `json:"allOf,omitempty"`
+}
+
+type document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       info                  `json:"info"`
+	Servers    []server              `json:"servers"`
+	Security   []map[string][]string `json:"security"`
+	Tags       []tag                 `json:"tags"`
+	Paths      map[string]pathItem   `json:"paths"`
+	Components components            `json:"components"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type server struct {
+	URL string `json:"url"`
+}
+
+type tag struct {
+	Name string `json:"name"`
+}
+
+// pathItem maps lowercase methods to operations
+type pathItem map[string]*operationObject
+
+type operationObject struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Parameters  []parameter               `json:"parameters,omitempty"`
+	RequestBody *requestBody              `json:"requestBody,omitempty"`
+	Responses   map[string]responseObject `json:"responses"`
+}
+
+type parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *schema `json:"schema,omitempty"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type responseObject struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type components struct {
+	Schemas         map[string]*schema        `json:"schemas"`
+	Parameters      map[string]parameter      `json:"parameters"`
+	Responses       map[string]responseObject `json:"responses"`
+	SecuritySchemes map[string]any            `json:"securitySchemes"`
+}
+
+// spec collects the operations and the schemas of their types
+type spec struct {
+	doc   document
+	types map[reflect.Type]string
+	tags  map[string]bool
+}
+
+func newSpec() *spec {
+	s := &spec{
+		doc: document{
+			OpenAPI: openAPIVersion,
+			Info: info{
+				Title:   "Better Uptime API",
+				Version: specVersion,
+				Description: "Resource-oriented API for monitors, monitor groups, alert contacts and status pages. " +
+					"Authenticate with an API key (bu_...) or a session token as a bearer token. " +
+					"Lists are paginated with cursors and errors share one envelope. " +
+					"POST requests accept an Idempotency-Key header to make retries safe.",
+			},
+			Servers:  []server{{URL: "/v2"}},
+			Security: []map[string][]string{{"bearerAuth": {}}},
+			Tags:     []tag{},
+			Paths:    map[string]pathItem{},
+			Components: components{
+				Schemas: map[string]*schema{},
+				Parameters: map[string]parameter{
+					"OrgID": {
+						Name:        "X-Org-ID",
+						In:          "header",
+						Description: "Organization to act in, for users in several; defaults to the key's or user's organization",
+						Schema:      &schema{Type: "string", Format: "uuid"},
+					},
+					"IdempotencyKey": {
+						Name:        IdempotencyKeyHeader,
+						In:          "header",
+						Description: "Makes the request safe to retry: a retry with the same key within 24 hours gets the first response, marked with Idempotent-Replayed",
+						Schema:      &schema{Type: "string", MaxLength: intPtr(maxIdempotencyKeyLength)},
+					},
+					"Limit": {
+						Name:        "limit",
+						In:          "query",
+						Description: fmt.Sprintf("Page size, %d by default", DefaultPageSize),
+						Schema:      &schema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(MaxPageSize)},
+					},
+					"Cursor": {
+						Name:        "cursor",
+						In:          "query",
+						Description: "next_cursor of the previous page",
+						Schema:      &schema{Type: "string"},
+					},
+				},
+				Responses: map[string]responseObject{},
+				SecuritySchemes: map[string]any{
+					"bearerAuth": map[string]string{"type": "http", "scheme": "bearer"},
+				},
+			},
+		},
+		types: map[reflect.Type]string{},
+		tags:  map[string]bool{},
+	}
+	// The envelope always holds an error, which its pointer field can't tell
+	s.doc.Components.Schemas["ErrorEnvelope"] = &schema{
+		Type:       "object",
+		Properties: map[string]*schema{"error": s.schemaOf(reflect.TypeOf(Error{}))},
+		Required:   []string{"error"},
+	}
+	s.doc.Components.Responses["Error"] = responseObject{
+		Description: "Error",
+		Content:     map[string]mediaType{"application/json": {Schema: &schema{Ref: "#/components/schemas/ErrorEnvelope"}}},
+	}
+	return s
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+func (s *spec) add(op Operation) {
+	o := &operationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Responses:   map[string]responseObject{"default": {Ref: "#/components/responses/Error"}},
+	}
+	if op.Tag != "" {
+		o.Tags = []string{op.Tag}
+		if !s.tags[op.Tag] {
+			s.tags[op.Tag] = true
+			s.doc.Tags = append(s.doc.Tags, tag{Name: op.Tag})
+		}
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(op.Path, -1) {
+		o.Parameters = append(o.Parameters, parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &schema{Type: "integer", Format: "int32"},
+		})
+	}
+	for _, p := range op.Query {
+		param := parameter{Name: p.Name, In: "query", Description: p.Description, Schema: &schema{Type: p.Type}}
+		if p.Type == "" {
+			param.Schema.Type = "string"
+		}
+		if p.Repeated {
+			param.Schema = &schema{Type: "array", Items: param.Schema}
+		}
+		o.Parameters = append(o.Parameters, param)
+	}
+	if op.Paginated {
+		o.Parameters = append(o.Parameters,
+			parameter{Ref: "#/components/parameters/Limit"},
+			parameter{Ref: "#/components/parameters/Cursor"})
+	}
+	o.Parameters = append(o.Parameters, parameter{Ref: "#/components/parameters/OrgID"})
+	if op.Method == http.MethodPost {
+		o.Parameters = append(o.Parameters, parameter{Ref: "#/components/parameters/IdempotencyKey"})
+	}
+
+	if op.Body != nil {
+		o.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"application/json": {Schema: s.schemaOf(reflect.TypeOf(op.Body))}},
+		}
+	}
+
+	status := op.status()
+	response := responseObject{Description: http.StatusText(status)}
+	if op.Response != nil {
+		response.Content = map[string]mediaType{"application/json": {Schema: s.schemaOf(reflect.TypeOf(op.Response))}}
+	}
+	o.Responses[strconv.Itoa(status)] = response
+
+	item, ok := s.doc.Paths[op.Path]
+	if !ok {
+		item = pathItem{}
+		s.doc.Paths[op.Path] = item
+	}
+	item[strings.ToLower(op.Method)] = o
+}
+
+func (s *spec) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(s.doc)
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	genericName    = regexp.MustCompile(`^(\w+)\[(?:.*\.)?(\w+)\]$`)
+)
+
+// schemaOf describes t, adding named structs to the components
+func (s *spec) schemaOf(t reflect.Type) *schema {
+	switch t {
+	case timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &schema{Description: "Any JSON value"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(s.schemaOf(t.Elem()))
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &schema{Type: "array", Items: s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return s.namedSchema(t)
+	}
+	return &schema{}
+}
+
+func (s *spec) namedSchema(t reflect.Type) *schema {
+	name, ok := s.types[t]
+	if !ok {
+		name = t.Name()
+		if m := genericName.FindStringSubmatch(name); m != nil {
+			name = m[2] + m[1]
+		}
+		name = strings.ToUpper(name[:1]) + name[1:]
+		if _, taken := s.doc.Components.Schemas[name]; taken {
+			panic(fmt.Sprintf("apiv2: two types are named %s in the OpenAPI document", name))
+		}
+		s.types[t] = name
+		// Registered before its fields so types can refer to themselves
+		s.doc.Components.Schemas[name] = &schema{}
+		*s.doc.Components.Schemas[name] = *s.structSchema(t)
+	}
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+func (s *spec) structSchema(t reflect.Type) *schema {
+	obj := &schema{Type: "object", Properties: map[string]*schema{}}
+	s.addFields(obj, t)
+	return obj
+}
+
+func (s *spec) addFields(obj *schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addFields(obj, field.Type)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+
+		var prop *schema
+		if field.Type.Kind() == reflect.Pointer && omitempty {
+			// Optional rather than nullable: leaving it out keeps the value
+			prop = s.schemaOf(field.Type.Elem())
+		} else {
+			prop = s.schemaOf(field.Type)
+		}
+		if doc := field.Tag.Get("doc"); doc != "" || field.Tag.Get("validate") != "" {
+			prop = inline(prop)
+			prop.Description = doc
+			constrain(prop, field.Tag.Get("validate"))
+		}
+
+		obj.Properties[name] = prop
+		if !omitempty {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+}
+
+// constrain turns validate tags into schema constraints. Those after dive
+// apply to elements and are left out.
+func constrain(prop *schema, validate string) {
+	target := prop
+	if len(prop.AllOf) > 0 {
+		return
+	}
+	for _, rule := range strings.Split(validate, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			return
+		case "min", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			switch target.Type {
+			case "string":
+				if key == "min" {
+					target.MinLength = intPtr(int(n))
+				} else {
+					target.MaxLength = intPtr(int(n))
+				}
+			case "array":
+				if key == "min" {
+					target.MinItems = intPtr(int(n))
+				} else {
+					target.MaxItems = intPtr(int(n))
+				}
+			case "integer", "number":
+				if key == "min" {
+					target.Minimum = &n
+				} else {
+					target.Maximum = &n
+				}
+			}
+		case "oneof":
+			target.Enum = strings.Fields(value)
+		case "url":
+			target.Format = "uri"
+		case "email":
+			target.Format = "email"
+		case "fqdn":
+			target.Format = "hostname"
+		}
+	}
+}
+
+// inline makes a schema that can carry a description and constraints, which
+// sit beside a $ref only inside allOf
+func inline(s *schema) *schema {
+	if s.Ref != "" {
+		return &schema{AllOf: []*schema{s}}
+	}
+	copied := *s
+	return &copied
+}
+
+func nullable(s *schema) *schema {
+	s = inline(s)
+	s.Nullable = true
+	return s
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func floatPtr(n float64) *float64 {
+	return &n
+}
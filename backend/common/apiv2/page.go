@@ -0,0 +1,70 @@
+package apiv2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Page sizes of list endpoints
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Page is one page of a list. NextCursor is passed as ?cursor= to get the
+// next page and is null on the last one.
+type Page[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// ReadPage reads ?limit= and decodes ?cursor=, when given, into after, which
+// holds the position of the last item of the previous page. Lists query one
+// more item than the limit to tell whether there is a next page.
+func ReadPage(r *http.Request, after any) (int32, error) {
+	query := r.URL.Query()
+
+	limit := DefaultPageSize
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > MaxPageSize {
+			return 0, Errorf(http.StatusBadRequest, "limit must be between 1 and %d", MaxPageSize)
+		}
+		limit = parsed
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		data, err := base64.RawURLEncoding.DecodeString(raw)
+		if err != nil || json.Unmarshal(data, after) != nil {
+			return 0, ErrInvalidCursor
+		}
+	}
+	return int32(limit), nil
+}
+
+// NewPage makes a page of up to limit items out of rows queried with a
+// limit one higher. cursor gives the position of an item to continue after.
+func NewPage[R, T any](rows []R, limit int32, convert func(R) T, cursor func(R) any) Page[T] {
+	page := Page[T]{Data: make([]T, 0, min(len(rows), int(limit)))}
+	if len(rows) > int(limit) {
+		rows = rows[:limit]
+		page.HasMore = true
+	}
+	for _, row := range rows {
+		page.Data = append(page.Data, convert(row))
+	}
+	if page.HasMore {
+		data, _ := json.Marshal(cursor(rows[len(rows)-1]))
+		next := base64.RawURLEncoding.EncodeToString(data)
+		page.NextCursor = &next
+	}
+	return page
+}
+
+// IDCursor is the position in lists ordered by id
+type IDCursor struct {
+	ID int32 `json:"id"`
+}
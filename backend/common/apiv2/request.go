@@ -0,0 +1,46 @@
+package apiv2
+
+import (
+	"better-uptime/common/validation"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxBodyBytes bounds request bodies, as in v1
+const maxBodyBytes = 1 << 20
+
+// ReadJSON decodes the body into v, rejecting fields v doesn't have so typos
+// don't pass silently, then checks v's validate tags
+func ReadJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			return err
+		}
+		return &Error{Status: http.StatusBadRequest, Code: CodeInvalidJSON, Message: err.Error()}
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return &Error{Status: http.StatusBadRequest, Code: CodeInvalidJSON, Message: "body must only have a single JSON value"}
+	}
+
+	if err := validation.ValidateRequest(v); err != nil {
+		return &Error{Status: http.StatusUnprocessableEntity, Code: CodeValidationFailed, Message: err.Error()}
+	}
+	return nil
+}
+
+// PathID reads an integer id from the path
+func PathID(r *http.Request, name string) (int32, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, name), 10, 32)
+	if err != nil || id < 1 {
+		return 0, ErrInvalidID
+	}
+	return int32(id), nil
+}
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"better-uptime/common/logger"
+	"better-uptime/common/middleware"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionPause  = "pause"
+	ActionResume = "resume"
+)
+
+// Resource types
+const (
+	ResourceMonitor      = "monitor"
+	ResourceAlertContact = "alert_contact"
+	ResourceStatusPage   = "status_page"
+	ResourceNotice       = "status_page_notice"
+	ResourceBadge        = "badge"
+	ResourceOrganization = "organization"
+	ResourceMember       = "org_member"
+	ResourceInvite       = "org_invite"
+	ResourceAPIKey       = "api_key"
+	ResourceMonitorGroup = "monitor_group"
+)
+
+// Entry is one change to record. Before is nil for creations and After for
+// deletions. Both are stored as JSON, so secrets must already be masked.
+type Entry struct {
+	Action       string
+	ResourceType string
+	ResourceID   any
+	Before       any
+	After        any
+	// OrgID defaults to the active organization of the request
+	OrgID uuid.UUID
+}
+
+// trustProxyHeaders takes the client IP from X-Forwarded-For or X-Real-IP,
+// which is only safe behind a proxy that sets them
+var trustProxyHeaders bool
+
+func Init(cfg config.Config) {
+	trustProxyHeaders = cfg.TRUST_PROXY_HEADERS
+}
+
+// Record appends an entry on behalf of the user or API key that made the
+// request. The change it describes is already made, so a failure to record
+// it is logged rather than returned.
+func Record(r *http.Request, store db.Store, entry Entry) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		logger.Error("Audit log skipped for %s %s: %v", entry.ResourceType, entry.Action, err)
+		return
+	}
+
+	orgID := entry.OrgID
+	if orgID == uuid.Nil {
+		orgID = payload.OrgId
+	}
+
+	before, err := marshal(entry.Before)
+	if err != nil {
+		logger.Error("Audit log skipped for %s %s: %v", entry.ResourceType, entry.Action, err)
+		return
+	}
+	after, err := marshal(entry.After)
+	if err != nil {
+		logger.Error("Audit log skipped for %s %s: %v", entry.ResourceType, entry.Action, err)
+		return
+	}
+
+	err = store.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		OrgID:        orgID,
+		UserID:       pgtype.UUID{Bytes: payload.UserId, Valid: payload.UserId != uuid.Nil},
+		ActorEmail:   payload.Email,
+		ApiKeyID:     pgtype.Int4{Int32: payload.APIKeyID, Valid: payload.APIKeyID != 0},
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   fmt.Sprint(entry.ResourceID),
+		Before:       before,
+		After:        after,
+		Ip:           ClientIP(r),
+		UserAgent:    r.UserAgent(),
+	})
+	if err != nil {
+		logger.Error("Failed to record audit log for %s %v %s: %v", entry.ResourceType, entry.ResourceID, entry.Action, err)
+	}
+}
+
+func marshal(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// ClientIP is the address the request came from
+func ClientIP(r *http.Request) string {
+	if trustProxyHeaders {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return strings.TrimSpace(realIP)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
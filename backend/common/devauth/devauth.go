@@ -0,0 +1,182 @@
+package devauth
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/config"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenPrefix marks bearer tokens naming a configured test identity, e.g.
+// "dev:alice@example.com"
+const TokenPrefix = "dev:"
+
+// Issuer is the iss claim of self-signed test JWTs
+const Issuer = "better-uptime-dev"
+
+const minSecretLength = 32
+
+var (
+	ErrNotDevelopment  = errors.New("DEV_AUTH_ENABLED requires APP_ENV=development")
+	ErrUnknownIdentity = errors.New("unknown dev auth identity")
+)
+
+// Identity is a test user that can sign in without Firebase
+type Identity struct {
+	Email    string
+	Fullname string
+}
+
+// provider is nil unless dev auth is enabled
+var provider *Provider
+
+type Provider struct {
+	identities map[string]Identity
+	jwtSecret  []byte
+}
+
+// Init enables dev auth when DEV_AUTH_ENABLED is set. It fails in production,
+// which callers should treat as fatal.
+func Init(cfg config.Config) error {
+	if !cfg.DEV_AUTH_ENABLED {
+		return nil
+	}
+	if !cfg.IsDevelopment() {
+		return ErrNotDevelopment
+	}
+
+	p, err := New(cfg.DEV_AUTH_USERS, cfg.DEV_AUTH_JWT_SECRET)
+	if err != nil {
+		return err
+	}
+	provider = p
+	return nil
+}
+
+// New parses identities as comma-separated "email" or "email=Full Name"
+// entries. An empty jwtSecret disables self-signed JWTs.
+func New(identities string, jwtSecret string) (*Provider, error) {
+	p := &Provider{identities: make(map[string]Identity)}
+
+	for _, entry := range strings.Split(identities, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		email, name, _ := strings.Cut(entry, "=")
+		email = strings.ToLower(strings.TrimSpace(email))
+		if !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("DEV_AUTH_USERS: %q is not an email address", email)
+		}
+		p.identities[email] = Identity{Email: email, Fullname: strings.TrimSpace(name)}
+	}
+
+	if jwtSecret != "" {
+		if len(jwtSecret) < minSecretLength {
+			return nil, fmt.Errorf("DEV_AUTH_JWT_SECRET must be at least %d characters", minSecretLength)
+		}
+		p.jwtSecret = []byte(jwtSecret)
+	}
+
+	if len(p.identities) == 0 && p.jwtSecret == nil {
+		return nil, errors.New("dev auth is enabled but neither DEV_AUTH_USERS nor DEV_AUTH_JWT_SECRET is set")
+	}
+	return p, nil
+}
+
+// Enabled reports whether dev auth accepts tokens
+func Enabled() bool {
+	return provider != nil
+}
+
+// Verify checks a bearer token against dev auth. ok is false when dev auth is
+// disabled or the token is not a dev token, so the caller can try Firebase.
+func Verify(token string) (payload firebase.FirebasePayload, ok bool, err error) {
+	if provider == nil {
+		return firebase.FirebasePayload{}, false, nil
+	}
+	return provider.Verify(token)
+}
+
+func (p *Provider) Verify(token string) (firebase.FirebasePayload, bool, error) {
+	if email, found := strings.CutPrefix(token, TokenPrefix); found {
+		identity, known := p.identities[strings.ToLower(email)]
+		if !known {
+			return firebase.FirebasePayload{}, true, ErrUnknownIdentity
+		}
+		return identity.payload(), true, nil
+	}
+
+	if p.jwtSecret == nil || !isDevJWT(token) {
+		return firebase.FirebasePayload{}, false, nil
+	}
+
+	claims := &devClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return p.jwtSecret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return firebase.FirebasePayload{}, true, fmt.Errorf("invalid dev token: %w", err)
+	}
+	if !strings.Contains(claims.Email, "@") {
+		return firebase.FirebasePayload{}, true, errors.New("invalid dev token: missing email claim")
+	}
+
+	return Identity{Email: strings.ToLower(claims.Email), Fullname: claims.Name}.payload(), true, nil
+}
+
+type devClaims struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// isDevJWT tells self-signed test tokens from Firebase ID tokens before
+// verifying anything
+func isDevJWT(token string) bool {
+	claims := jwt.MapClaims{}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil {
+		return false
+	}
+	iss, _ := claims.GetIssuer()
+	return parsed.Method.Alg() == jwt.SigningMethodHS256.Alg() && iss == Issuer
+}
+
+// SignToken issues a self-signed test JWT for email, accepted while dev auth
+// runs with the same DEV_AUTH_JWT_SECRET
+func SignToken(secret string, email string, fullname string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, devClaims{
+		Email: email,
+		Name:  fullname,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   email,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	})
+	return token.SignedString([]byte(secret))
+}
+
+func (i Identity) payload() firebase.FirebasePayload {
+	fullname := i.Fullname
+	if fullname == "" {
+		fullname, _, _ = strings.Cut(i.Email, "@")
+	}
+	return firebase.FirebasePayload{
+		UID:      TokenPrefix + i.Email,
+		Email:    i.Email,
+		Fullname: fullname,
+		Provider: "password",
+	}
+}
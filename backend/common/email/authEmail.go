@@ -0,0 +1,31 @@
+package email
+
+import (
+	"fmt"
+	"html"
+)
+
+// SendEmailVerification asks someone who signed up with a password to confirm
+// their address
+func SendEmailVerification(to string, verifyURL string, validFor string) error {
+	subject := "Confirm your email address for Better Uptime"
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>%s
+</head>
+<body>
+	<div class="container">
+		<h2>✉️ Confirm your email address</h2>
+		<p>Hello,</p>
+		<p>Someone (hopefully you) signed up for Better Uptime with this email address.</p>
+		<p><a class="button" href="%s">Confirm email address</a></p>
+		<p>The link expires in %s. If you did not sign up, you can safely ignore this email.</p>
+		<div class="footer">Powered by <strong>Better Uptime Monitor</strong></div>
+	</div>
+</body>
+</html>`, statusPageEmailStyle, html.EscapeString(verifyURL), html.EscapeString(validFor))
+
+	return SendHTMLEmail(to, subject, htmlBody)
+}
@@ -0,0 +1,36 @@
+package email
+
+import (
+	"fmt"
+	"html"
+)
+
+// SendOrgInvite invites someone to join an organization with the given role
+func SendOrgInvite(to string, orgName string, inviterName string, role string, acceptURL string) error {
+	subject := fmt.Sprintf("You're invited to join %s on Better Uptime", orgName)
+
+	inviter := "A teammate"
+	if inviterName != "" {
+		inviter = inviterName
+	}
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>%s
+</head>
+<body>
+	<div class="container">
+		<h2>👋 Join %s</h2>
+		<p>Hello,</p>
+		<p>%s invited you to join <strong>%s</strong> as <strong>%s</strong>, to share its monitors, alert contacts and status pages.</p>
+		<p><a class="button" href="%s">Accept invitation</a></p>
+		<p>Sign in with this email address to accept. The invitation expires in 7 days.</p>
+		<div class="footer">Powered by <strong>Better Uptime Monitor</strong></div>
+	</div>
+</body>
+</html>`, statusPageEmailStyle, html.EscapeString(orgName), html.EscapeString(inviter), html.EscapeString(orgName),
+		html.EscapeString(role), html.EscapeString(acceptURL))
+
+	return SendHTMLEmail(to, subject, htmlBody)
+}
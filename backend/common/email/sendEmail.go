@@ -3,6 +3,7 @@ package email
 import (
 	"better-uptime/config"
 	"fmt"
+	"html"
 	"net/smtp"
 )
 
@@ -109,6 +110,15 @@ func SendTestEmail(to string) error {
 
 // SendStatusAlert sends a website status alert email
 func SendStatusAlert(to string, websiteURL string, isUp bool, responseTime string, lastChecked string) error {
+	return sendStatusAlert(to, websiteURL, isUp, responseTime, lastChecked, "")
+}
+
+// SendStatusAlertWithScreenshot sends a status alert email linking to a screenshot of the page
+func SendStatusAlertWithScreenshot(to string, websiteURL string, isUp bool, responseTime string, lastChecked string, screenshotURL string) error {
+	return sendStatusAlert(to, websiteURL, isUp, responseTime, lastChecked, screenshotURL)
+}
+
+func sendStatusAlert(to string, websiteURL string, isUp bool, responseTime string, lastChecked string, screenshotURL string) error {
 	status := "UP"
 	statusClass := "status-up"
 	if !isUp {
@@ -160,14 +170,24 @@ func SendStatusAlert(to string, websiteURL string, isUp bool, responseTime strin
 		<p>Your website <strong>%s</strong> is currently <span class="%s">%s</span>.</p>
 		<p>Response time: <strong>%s</strong></p>
 		<p>Last checked: <strong>%s</strong></p>
+		%s
 		<div class="footer">Powered by <strong>Better Uptime Monitor</strong></div>
 	</div>
 </body>
-</html>`, statusClass, getStatusColor(isUp), websiteURL, statusClass, status, responseTime, lastChecked)
+</html>`, statusClass, getStatusColor(isUp), websiteURL, statusClass, status, responseTime, lastChecked, screenshotHTML(screenshotURL))
 
 	return SendHTMLEmail(to, subject, htmlBody)
 }
 
+func screenshotHTML(screenshotURL string) string {
+	if screenshotURL == "" {
+		return ""
+	}
+	escaped := html.EscapeString(screenshotURL)
+	return fmt.Sprintf(`<p>Screenshot at failure:</p>
+		<p><a href="%s"><img src="%s" alt="Screenshot" style="max-width: 100%%; border: 1px solid #eee;"></a></p>`, escaped, escaped)
+}
+
 func getStatusColor(isUp bool) string {
 	if isUp {
 		return "green"
@@ -0,0 +1,93 @@
+package email
+
+import (
+	"fmt"
+	"html"
+)
+
+const statusPageEmailStyle = `
+	<style>
+		body {
+			font-family: Arial, sans-serif;
+			background-color: #f9f9f9;
+			color: #333;
+			padding: 20px;
+		}
+		.container {
+			background: white;
+			padding: 20px;
+			border-radius: 10px;
+			box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+			max-width: 600px;
+			margin: auto;
+		}
+		h2 {
+			color: #007BFF;
+		}
+		.button {
+			display: inline-block;
+			padding: 10px 18px;
+			background: #007BFF;
+			color: white;
+			border-radius: 6px;
+			text-decoration: none;
+		}
+		.footer {
+			margin-top: 20px;
+			font-size: 12px;
+			color: #777;
+			text-align: center;
+		}
+	</style>`
+
+// SendSubscriptionConfirmation asks a status page visitor to confirm their subscription
+func SendSubscriptionConfirmation(to string, pageTitle string, confirmURL string) error {
+	subject := fmt.Sprintf("Confirm your subscription to %s", pageTitle)
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>%s
+</head>
+<body>
+	<div class="container">
+		<h2>📬 Confirm your subscription</h2>
+		<p>Hello,</p>
+		<p>Someone (hopefully you) asked to receive incident and maintenance updates for <strong>%s</strong>.</p>
+		<p><a class="button" href="%s">Confirm subscription</a></p>
+		<p>The link works for 48 hours. If you did not request this, you can safely ignore this email.</p>
+		<div class="footer">Powered by <strong>Better Uptime Monitor</strong></div>
+	</div>
+</body>
+</html>`, statusPageEmailStyle, html.EscapeString(pageTitle), html.EscapeString(confirmURL))
+
+	return SendHTMLEmail(to, subject, htmlBody)
+}
+
+// SendNoticeUpdate sends an incident or maintenance update to a confirmed subscriber
+func SendNoticeUpdate(to string, pageTitle string, noticeTitle string, status string, message string, pageURL string, unsubscribeURL string) error {
+	subject := fmt.Sprintf("[%s] %s - %s", pageTitle, noticeTitle, status)
+
+	htmlBody := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>%s
+</head>
+<body>
+	<div class="container">
+		<h2>📢 %s</h2>
+		<p>Status: <strong>%s</strong></p>
+		<p>%s</p>
+		<p><a class="button" href="%s">View status page</a></p>
+		<div class="footer">
+			You are receiving this because you subscribed to %s.
+			<a href="%s">Unsubscribe</a><br>
+			Powered by <strong>Better Uptime Monitor</strong>
+		</div>
+	</div>
+</body>
+</html>`, statusPageEmailStyle, html.EscapeString(noticeTitle), html.EscapeString(status), html.EscapeString(message),
+		html.EscapeString(pageURL), html.EscapeString(pageTitle), html.EscapeString(unsubscribeURL))
+
+	return SendHTMLEmail(to, subject, htmlBody)
+}
@@ -27,6 +27,13 @@ type FirebasePayload struct {
 	UserId   uuid.UUID // Your internal DB UUID (populated later)
 	Provider string    // Firebase sign-in provider (e.g., password, google, phone)
 	Role     string    // Your internal DB role
+	OrgId    uuid.UUID // Active organization, resolved by TokenMiddleware
+	OrgRole  string    // The user's role in OrgId (owner, admin, editor, viewer)
+	APIKeyID int32     // Set when the request authenticated with an API key
+	Scopes   []string  // Scopes of the API key, nil for signed-in users
+
+	// EmailVerified is the token's email_verified claim, only set for Firebase tokens
+	EmailVerified bool
 }
 
 // Initialize Firebase app and client once
@@ -76,6 +83,8 @@ func VerifyFirebaseIDToken(ctx context.Context, idToken string) (FirebasePayload
 		Fullname: getClaimString(claims, "name"),
 		Phone:    getClaimString(claims, "phone_number"),
 		Provider: getSignInProvider(claims),
+
+		EmailVerified: claims["email_verified"] == true,
 	}, nil
 }
 
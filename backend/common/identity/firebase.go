@@ -0,0 +1,47 @@
+package identity
+
+import (
+	"better-uptime/common/devauth"
+	"better-uptime/common/firebase"
+	"context"
+	"errors"
+	"strings"
+)
+
+// Firebase ID tokens are issued by https://securetoken.google.com/<project-id>
+const firebaseIssuerPrefix = "https://securetoken.google.com/"
+
+type firebaseProvider struct{}
+
+func (firebaseProvider) Name() string { return "firebase" }
+
+func (firebaseProvider) Verify(ctx context.Context, token string) (firebase.FirebasePayload, error) {
+	if !strings.HasPrefix(unverifiedIssuer(token), firebaseIssuerPrefix) {
+		return firebase.FirebasePayload{}, ErrNotHandled
+	}
+	payload, err := firebase.VerifyFirebaseIDToken(ctx, token)
+	if err != nil {
+		return firebase.FirebasePayload{}, err
+	}
+	// Users are matched by email, so an unverified address could take over
+	// the account (and org invites) of whoever owns it. Phone sign-ins have
+	// no email at all.
+	if payload.Email != "" && !payload.EmailVerified {
+		return firebase.FirebasePayload{}, errors.New("email is not verified by the issuer")
+	}
+	return payload, nil
+}
+
+// devProvider adapts devauth, which decides on its own which tokens are dev
+// tokens
+type devProvider struct{}
+
+func (devProvider) Name() string { return "dev" }
+
+func (devProvider) Verify(_ context.Context, token string) (firebase.FirebasePayload, error) {
+	payload, ok, err := devauth.Verify(token)
+	if !ok {
+		return firebase.FirebasePayload{}, ErrNotHandled
+	}
+	return payload, err
+}
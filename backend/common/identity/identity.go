@@ -0,0 +1,132 @@
+package identity
+
+import (
+	"better-uptime/common/devauth"
+	"better-uptime/common/firebase"
+	"better-uptime/config"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrNotHandled is returned by a Provider for tokens it did not issue, so
+	// the next provider can try
+	ErrNotHandled = errors.New("token not issued by this provider")
+	// ErrNoProvider means no configured provider accepts the token
+	ErrNoProvider = errors.New("no auth provider accepts this token")
+)
+
+// Provider verifies bearer tokens of one identity provider. Verify returns
+// ErrNotHandled without verifying anything when the token is not its own.
+type Provider interface {
+	Name() string
+	Verify(ctx context.Context, token string) (firebase.FirebasePayload, error)
+}
+
+// Chain tries providers in order until one handles the token
+type Chain []Provider
+
+func (c Chain) Verify(ctx context.Context, token string) (firebase.FirebasePayload, error) {
+	for _, p := range c {
+		payload, err := p.Verify(ctx, token)
+		if errors.Is(err, ErrNotHandled) {
+			continue
+		}
+		if err != nil {
+			return firebase.FirebasePayload{}, fmt.Errorf("%s: %w", p.Name(), err)
+		}
+		return payload, nil
+	}
+	return firebase.FirebasePayload{}, ErrNoProvider
+}
+
+var (
+	chain Chain = Chain{firebaseProvider{}}
+	local *LocalProvider
+)
+
+// Init builds the provider chain from AUTH_PROVIDERS, a comma-separated list of
+// firebase, oidc and local. Dev auth goes first when enabled, so devauth.Init
+// must run before.
+func Init(cfg config.Config) error {
+	var c Chain
+	if devauth.Enabled() {
+		c = append(c, devProvider{})
+	}
+
+	var l *LocalProvider
+	for _, name := range strings.Split(cfg.AUTH_PROVIDERS, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "firebase":
+			c = append(c, firebaseProvider{})
+		case "oidc":
+			p, err := NewOIDCProvider(cfg.OIDC_ISSUER_URL, cfg.OIDC_AUDIENCE, cfg.OIDC_TRUST_UNVERIFIED_EMAIL)
+			if err != nil {
+				return err
+			}
+			c = append(c, p)
+		case "local":
+			p, err := NewLocalProvider(
+				cfg.AUTH_JWT_SIGNING_KEY,
+				cfg.AUTH_JWT_ISSUER,
+				time.Duration(cfg.AUTH_ACCESS_TOKEN_TTL_MINUTES)*time.Minute,
+				time.Duration(cfg.AUTH_REFRESH_TOKEN_TTL_DAYS)*24*time.Hour,
+			)
+			if err != nil {
+				return err
+			}
+			l = p
+			c = append(c, p)
+		default:
+			return fmt.Errorf("AUTH_PROVIDERS: unknown provider %q", name)
+		}
+	}
+
+	chain, local = c, l
+	return nil
+}
+
+// Verify checks a bearer token against the configured providers
+func Verify(ctx context.Context, token string) (firebase.FirebasePayload, error) {
+	return chain.Verify(ctx, token)
+}
+
+// Names lists the configured providers in the order they are tried
+func Names() []string {
+	names := make([]string, len(chain))
+	for i, p := range chain {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Local returns the local email/password provider, or nil when it is not
+// configured
+func Local() *LocalProvider {
+	return local
+}
+
+// HashToken is how refresh tokens are stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// unverifiedIssuer reads the iss claim of a JWT before verifying it, to route
+// the token to its provider. It is empty for anything that isn't a JWT.
+func unverifiedIssuer(token string) string {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return ""
+	}
+	iss, _ := claims.GetIssuer()
+	return iss
+}
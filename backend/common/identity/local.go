@@ -0,0 +1,109 @@
+package identity
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/common/util"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const minSigningKeyLength = 32
+
+// LocalProvider issues and verifies short-lived access tokens for users who
+// sign in with email and password. Refresh tokens are opaque and kept in the
+// database by the auth handlers.
+type LocalProvider struct {
+	key        []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewLocalProvider(signingKey string, issuer string, accessTTL, refreshTTL time.Duration) (*LocalProvider, error) {
+	if len(signingKey) < minSigningKeyLength {
+		return nil, fmt.Errorf("AUTH_JWT_SIGNING_KEY must be at least %d characters", minSigningKeyLength)
+	}
+	if issuer == "" {
+		return nil, errors.New("AUTH_JWT_ISSUER is empty")
+	}
+	if accessTTL <= 0 || refreshTTL <= 0 {
+		return nil, errors.New("AUTH_ACCESS_TOKEN_TTL_MINUTES and AUTH_REFRESH_TOKEN_TTL_DAYS must be positive")
+	}
+	return &LocalProvider{
+		key:        []byte(signingKey),
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}, nil
+}
+
+func (p *LocalProvider) Name() string { return "local" }
+
+type localClaims struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IssueAccessToken signs an access token for a user, returning it with its
+// expiry
+func (p *LocalProvider) IssueAccessToken(userID uuid.UUID, email string, fullname string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(p.accessTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, localClaims{
+		Email: email,
+		Name:  fullname,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signed, err := token.SignedString(p.key)
+	return signed, expiresAt, err
+}
+
+// NewRefreshToken returns a random refresh token, the hash to store and when
+// it expires
+func (p *LocalProvider) NewRefreshToken() (token string, hash string, expiresAt time.Time, err error) {
+	token, err = util.RandomToken(32)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return token, HashToken(token), time.Now().Add(p.refreshTTL), nil
+}
+
+func (p *LocalProvider) Verify(_ context.Context, token string) (firebase.FirebasePayload, error) {
+	if unverifiedIssuer(token) != p.issuer {
+		return firebase.FirebasePayload{}, ErrNotHandled
+	}
+
+	claims := &localClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return p.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return firebase.FirebasePayload{}, err
+	}
+	if !strings.Contains(claims.Email, "@") {
+		return firebase.FirebasePayload{}, errors.New("missing email claim")
+	}
+
+	return firebase.FirebasePayload{
+		UID:      claims.Subject,
+		Email:    claims.Email,
+		Fullname: claims.Name,
+		Provider: "password",
+	}, nil
+}
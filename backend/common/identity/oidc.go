@@ -0,0 +1,267 @@
+package identity
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/common/util"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// jwksTTL is how long fetched signing keys are trusted before refetching
+	jwksTTL = time.Hour
+	// jwksMinRefresh limits refetches caused by tokens with an unknown kid
+	jwksMinRefresh = time.Minute
+)
+
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OIDCProvider verifies ID tokens of a generic OpenID Connect issuer. The
+// JWKS location comes from the issuer's discovery document and the keys are
+// cached, refetched when they expire or a token names a key not seen yet.
+type OIDCProvider struct {
+	issuer   string
+	audience string
+	// trustEmail accepts tokens that leave out email_verified
+	trustEmail bool
+	client     *http.Client
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+// NewOIDCProvider verifies tokens of issuer for audience. trustEmail accepts
+// tokens without an email_verified claim, for issuers that only hand out
+// verified addresses and don't send it.
+func NewOIDCProvider(issuer string, audience string, trustEmail bool) (*OIDCProvider, error) {
+	if issuer == "" {
+		return nil, errors.New("OIDC_ISSUER_URL is required for the oidc auth provider")
+	}
+	if audience == "" {
+		return nil, errors.New("OIDC_AUDIENCE is required for the oidc auth provider")
+	}
+	return &OIDCProvider{
+		issuer:     issuer,
+		audience:   audience,
+		trustEmail: trustEmail,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *OIDCProvider) Name() string { return "oidc" }
+
+type oidcClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	PhoneNumber       string `json:"phone_number"`
+	jwt.RegisteredClaims
+}
+
+func (p *OIDCProvider) Verify(ctx context.Context, token string) (firebase.FirebasePayload, error) {
+	if unverifiedIssuer(token) != p.issuer {
+		return firebase.FirebasePayload{}, ErrNotHandled
+	}
+
+	claims := &oidcClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+	if err != nil {
+		return firebase.FirebasePayload{}, err
+	}
+
+	// Users are matched by email, so an address the issuer has not verified
+	// could take over someone else's account
+	if !strings.Contains(claims.Email, "@") {
+		return firebase.FirebasePayload{}, errors.New("missing email claim")
+	}
+	if !emailVerified(claims.EmailVerified, p.trustEmail) {
+		return firebase.FirebasePayload{}, errors.New("email is not verified by the issuer")
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.PreferredUsername
+	}
+	return firebase.FirebasePayload{
+		UID:      claims.Subject,
+		Email:    strings.ToLower(claims.Email),
+		Fullname: name,
+		Phone:    claims.PhoneNumber,
+		Provider: "oidc",
+	}, nil
+}
+
+// emailVerified accepts the claim as a boolean or, as some issuers send it,
+// a string. A missing claim counts as unverified unless the issuer is trusted
+// to only hand out verified addresses.
+func emailVerified(claim any, trustMissing bool) bool {
+	switch v := claim.(type) {
+	case nil:
+		return trustMissing
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
+
+// key returns the signing key for kid, refreshing the cached JWKS when it is
+// stale or does not know kid
+func (p *OIDCProvider) key(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, known := p.keys[kid]
+	age := time.Since(p.fetchedAt)
+	if known && age < jwksTTL {
+		return key, nil
+	}
+
+	if p.keys == nil || age >= jwksMinRefresh {
+		if err := p.refresh(ctx); err != nil {
+			// Keep verifying with the keys we have while the issuer is unreachable
+			if known {
+				return key, nil
+			}
+			return nil, fmt.Errorf("fetching signing keys: %w", err)
+		}
+		key, known = p.keys[kid]
+	}
+
+	if !known {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (p *OIDCProvider) refresh(ctx context.Context) error {
+	if p.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		url := strings.TrimSuffix(p.issuer, "/") + "/.well-known/openid-configuration"
+		if err := p.getJSON(ctx, url, &discovery); err != nil {
+			return err
+		}
+		if discovery.Issuer != p.issuer {
+			return fmt.Errorf("discovery document is for issuer %q, not %q", discovery.Issuer, p.issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("discovery document has no jwks_uri")
+		}
+		p.jwksURI = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(ctx, p.jwksURI, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// Skip keys of types we can't use rather than failing the whole set
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("no usable signing keys in JWKS")
+	}
+
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	return nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jwk is a JSON Web Key as served in a JWKS
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		return util.ParseRSAPublicKeyFromApple(k.N, k.E)
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -0,0 +1,53 @@
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Change is a top-level field that differs between before and after
+type Change struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Diff lists the fields added, removed or changed between two JSON objects.
+// Creations and deletions list every field of the one side there is.
+func Diff(before, after json.RawMessage) []Change {
+	var b, a map[string]json.RawMessage
+	_ = json.Unmarshal(before, &b)
+	_ = json.Unmarshal(after, &a)
+
+	fields := make(map[string]bool, len(a)+len(b))
+	for field := range b {
+		fields[field] = true
+	}
+	for field := range a {
+		fields[field] = true
+	}
+
+	changes := []Change{}
+	for field := range fields {
+		if !Equal(b[field], a[field]) {
+			changes = append(changes, Change{Field: field, Before: b[field], After: a[field]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// Equal compares values regardless of formatting and key order
+func Equal(x, y json.RawMessage) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	var vx, vy any
+	if json.Unmarshal(x, &vx) != nil || json.Unmarshal(y, &vy) != nil {
+		return bytes.Equal(x, y)
+	}
+	cx, _ := json.Marshal(vx)
+	cy, _ := json.Marshal(vy)
+	return bytes.Equal(cx, cy)
+}
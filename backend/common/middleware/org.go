@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// OrgHeader selects the active organization; without it the user's first
+// organization is used
+const OrgHeader = "X-Org-ID"
+
+var roleRank = map[db.OrgRole]int{
+	db.OrgRoleViewer: 1,
+	db.OrgRoleEditor: 2,
+	db.OrgRoleAdmin:  3,
+	db.OrgRoleOwner:  4,
+}
+
+// RoleAtLeast reports whether role grants everything min does
+func RoleAtLeast(role, min db.OrgRole) bool {
+	return roleRank[role] >= roleRank[min]
+}
+
+// RequireRole rejects requests whose role in the active organization is below min.
+// It must run after TokenMiddleware.
+func RequireRole(min db.OrgRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, err := GetFirebasePayloadFromContext(r.Context())
+			if err != nil {
+				util.ErrorJson(w, util.ErrUnauthorized)
+				return
+			}
+			if !RoleAtLeast(db.OrgRole(payload.OrgRole), min) {
+				util.ErrorJson(w, util.ErrInsufficientRole)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRoleForWrites lets every member read and requires min for anything
+// other than GET, HEAD and OPTIONS
+func RequireRoleForWrites(min db.OrgRole) func(http.Handler) http.Handler {
+	requireRole := RequireRole(min)
+	return func(next http.Handler) http.Handler {
+		guarded := requireRole(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+			default:
+				guarded.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// resolveOrg sets the active organization and role on the payload. A user
+// without any membership gets a personal organization.
+func resolveOrg(ctx context.Context, store db.Store, r *http.Request, payload *firebase.FirebasePayload) error {
+	var membership db.OrgMembership
+	var err error
+
+	if header := r.Header.Get(OrgHeader); header != "" {
+		orgId, parseErr := uuid.Parse(header)
+		if parseErr != nil {
+			return util.ErrInvalidUuid
+		}
+		membership, err = store.GetOrgMembership(ctx, db.GetOrgMembershipParams{OrgID: orgId, UserID: payload.UserId})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return util.ErrNotOrgMember
+		}
+	} else {
+		membership, err = ensureDefaultOrg(ctx, store, payload)
+	}
+	if err != nil {
+		return err
+	}
+
+	payload.OrgId = membership.OrgID
+	payload.OrgRole = string(membership.Role)
+	return nil
+}
+
+// ensureDefaultOrg returns the user's first membership, creating a personal
+// organization owned by the user if there is none
+func ensureDefaultOrg(ctx context.Context, store db.Store, payload *firebase.FirebasePayload) (db.OrgMembership, error) {
+	membership, err := store.GetDefaultOrgMembership(ctx, payload.UserId)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return membership, err
+	}
+
+	err = store.ExecTx(ctx, func(q *db.Queries) error {
+		// Concurrent first requests would otherwise each create an organization
+		if err := q.LockUserOrganizations(ctx, payload.UserId); err != nil {
+			return err
+		}
+		membership, err = q.GetDefaultOrgMembership(ctx, payload.UserId)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		name := "Personal"
+		if payload.Fullname != "" {
+			name = payload.Fullname + "'s workspace"
+		}
+		org, err := q.CreateOrganization(ctx, db.CreateOrganizationParams{
+			Name:      name,
+			CreatedBy: pgtype.UUID{Bytes: payload.UserId, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		membership, err = q.AddOrgMember(ctx, db.AddOrgMemberParams{
+			OrgID:  org.ID,
+			UserID: payload.UserId,
+			Role:   db.OrgRoleOwner,
+		})
+		return err
+	})
+	return membership, err
+}
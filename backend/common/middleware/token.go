@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"better-uptime/common/firebase"
+	"better-uptime/common/identity"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
 
@@ -10,7 +11,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/sirupsen/logrus"
 )
@@ -19,7 +19,8 @@ type tokenPayloadKeyType string
 
 const TokenPayloadKey tokenPayloadKeyType = "auth-payload"
 
-// TokenMiddleware verifies Firebase token, upserts user, and sets payload in context
+// TokenMiddleware verifies an auth provider token or API key, upserts user, resolves the
+// active organization and sets payload in context
 func TokenMiddleware(store db.Store) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -30,8 +31,6 @@ func TokenMiddleware(store db.Store) func(http.Handler) http.Handler {
 				return
 			}
 
-			logrus.Info("Authorization header:", authHeader)
-
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
 				util.ErrorJson(w, util.ErrInvalidToken)
@@ -42,31 +41,28 @@ func TokenMiddleware(store db.Store) func(http.Handler) http.Handler {
 			idToken := parts[1]
 
 			ctx := r.Context()
-			var payload firebase.FirebasePayload
-
-			// Dev mode: frontend bypass
-			if idToken == "frontend" {
-				payload = firebase.FirebasePayload{
-					Email:  "test@example.com",
-					UID:    "0b927d97-782a-4c82-b9d2-e4e06774ed37",
-					UserId: uuid.MustParse("0b927d97-782a-4c82-b9d2-e4e06774ed37"),
-					Role:   "tester",
+
+			// API keys for scripts and CI
+			if strings.HasPrefix(idToken, APIKeyPrefix) {
+				keyPayload, err := authenticateAPIKey(ctx, store, r, idToken)
+				if err != nil {
+					util.ErrorJson(w, err)
+					logrus.WithError(err).Error("Failed to authenticate API key")
+					return
 				}
-				logrus.Info("Frontend bypass: using test user payload")
-				ctx = context.WithValue(ctx, TokenPayloadKey, payload)
+				ctx = context.WithValue(ctx, TokenPayloadKey, keyPayload)
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
 
-			// Verify Firebase token
-			fbPayload, err := firebase.VerifyFirebaseIDToken(ctx, idToken)
+			// Firebase, OIDC, local or dev auth tokens, whichever is configured
+			fbPayload, err := identity.Verify(ctx, idToken)
 			if err != nil {
 				util.ErrorJson(w, util.ErrInvalidToken)
-				logrus.WithError(err).Error("Failed to verify Firebase ID token")
+				logrus.WithError(err).Error("Failed to verify auth token")
 				return
 			}
-
-			logrus.Infof("Firebase token verified. Email: %s, UID: %s", fbPayload.Email, fbPayload.UID)
+			logrus.Infof("Auth token verified. Email: %s, UID: %s", fbPayload.Email, fbPayload.UID)
 
 			// Set defaults for provider and fullname
 			if fbPayload.Provider == "" {
@@ -102,6 +98,13 @@ func TokenMiddleware(store db.Store) func(http.Handler) http.Handler {
 
 			logrus.Infof("User found/created: %v", user.ID)
 
+			// Resolve the active organization and the user's role in it
+			if err := resolveOrg(ctx, store, r, &fbPayload); err != nil {
+				util.ErrorJson(w, err)
+				logrus.WithError(err).Error("Failed to resolve organization")
+				return
+			}
+
 			// Set payload in request context
 			ctx = context.WithValue(ctx, TokenPayloadKey, fbPayload)
 
@@ -0,0 +1,172 @@
+package plans
+
+import (
+	"better-uptime/common/util"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Plan names
+const (
+	Free      = "free"
+	Pro       = "pro"
+	Business  = "business"
+	Unlimited = "unlimited"
+)
+
+// Plan is what an organization may use. Zero limits mean no limit.
+type Plan struct {
+	Name               string `json:"name"`
+	MaxMonitors        int64  `json:"max_monitors"`
+	MinIntervalSeconds int32  `json:"min_interval_seconds"`
+	// RetentionDays is how long raw check logs are kept; rollups outlive them
+	RetentionDays    int   `json:"retention_days"`
+	MaxAlertContacts int64 `json:"max_alert_contacts"`
+	MaxStatusPages   int64 `json:"max_status_pages"`
+	// Paid plans are sold through Stripe
+	Paid bool `json:"paid"`
+}
+
+var all = []Plan{
+	{Name: Free, MaxMonitors: 5, MinIntervalSeconds: 120, RetentionDays: 30, MaxAlertContacts: 1, MaxStatusPages: 1},
+	{Name: Pro, MaxMonitors: 50, MinIntervalSeconds: 60, RetentionDays: 90, MaxAlertContacts: 10, MaxStatusPages: 5, Paid: true},
+	{Name: Business, MaxMonitors: 500, MinIntervalSeconds: 60, RetentionDays: 395, MaxAlertContacts: 100, MaxStatusPages: 50, Paid: true},
+	// For self-hosted installs that don't sell plans
+	{Name: Unlimited, RetentionDays: 395},
+}
+
+// All lists every plan
+func All() []Plan {
+	return append([]Plan(nil), all...)
+}
+
+func Lookup(name string) (Plan, bool) {
+	for _, p := range all {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Plan{}, false
+}
+
+// Default is the plan of organizations without an active subscription
+func Default(cfg config.Config) Plan {
+	if p, ok := Lookup(cfg.DEFAULT_PLAN); ok {
+		return p
+	}
+	p, _ := Lookup(Unlimited)
+	return p
+}
+
+// Init checks the plan configuration and applies the operator's
+// LOG_RETENTION_DAYS overrides. Call it once at startup.
+func Init(cfg config.Config) error {
+	if _, ok := Lookup(cfg.DEFAULT_PLAN); !ok {
+		return fmt.Errorf("DEFAULT_PLAN: unknown plan %q", cfg.DEFAULT_PLAN)
+	}
+	overrides, err := parseRetention(cfg.LOG_RETENTION_DAYS)
+	if err != nil {
+		return err
+	}
+	for i := range all {
+		if days, ok := overrides[all[i].Name]; ok {
+			all[i].RetentionDays = days
+		}
+	}
+	return nil
+}
+
+// parseRetention parses comma-separated "plan=days" entries, 0 keeps logs forever
+func parseRetention(value string) (map[string]int, error) {
+	overrides := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, rawDays, _ := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("LOG_RETENTION_DAYS: unknown plan %q", name)
+		}
+		days, err := strconv.Atoi(strings.TrimSpace(rawDays))
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("LOG_RETENTION_DAYS: %q is not a number of days", rawDays)
+		}
+		overrides[name] = days
+	}
+	return overrides, nil
+}
+
+// ForOrg returns the plan the organization is on
+func ForOrg(ctx context.Context, store db.Store, cfg config.Config, orgId uuid.UUID) (Plan, error) {
+	sub, err := store.GetOrgSubscription(ctx, orgId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Default(cfg), nil
+	}
+	if err != nil {
+		return Plan{}, err
+	}
+	if p, ok := Lookup(sub.Plan); ok {
+		return p, nil
+	}
+	return Default(cfg), nil
+}
+
+// CheckInterval rejects check intervals more frequent than the plan allows
+func (p Plan) CheckInterval(seconds int32) error {
+	if p.MinIntervalSeconds > 0 && seconds < p.MinIntervalSeconds {
+		return util.ErrPlanIntervalTooShort
+	}
+	return nil
+}
+
+// CheckNewMonitor rejects a new monitor once the organization has as many as
+// the plan allows
+func (p Plan) CheckNewMonitor(ctx context.Context, store db.Store, orgId uuid.UUID) error {
+	return checkLimit(ctx, p.MaxMonitors, store.CountOrgMonitors, orgId, util.ErrPlanMonitorLimit)
+}
+
+func (p Plan) CheckNewAlertContact(ctx context.Context, store db.Store, orgId uuid.UUID) error {
+	return checkLimit(ctx, p.MaxAlertContacts, store.CountOrgAlertContacts, orgId, util.ErrPlanAlertContactLimit)
+}
+
+func (p Plan) CheckNewStatusPage(ctx context.Context, store db.Store, orgId uuid.UUID) error {
+	return checkLimit(ctx, p.MaxStatusPages, store.CountOrgStatusPages, orgId, util.ErrPlanStatusPageLimit)
+}
+
+// CheckTotals rejects resource totals beyond the plan, for changes that add
+// and remove many resources at once
+func (p Plan) CheckTotals(monitors, alertContacts, statusPages int64) error {
+	switch {
+	case p.MaxMonitors > 0 && monitors > p.MaxMonitors:
+		return util.ErrPlanMonitorLimit
+	case p.MaxAlertContacts > 0 && alertContacts > p.MaxAlertContacts:
+		return util.ErrPlanAlertContactLimit
+	case p.MaxStatusPages > 0 && statusPages > p.MaxStatusPages:
+		return util.ErrPlanStatusPageLimit
+	}
+	return nil
+}
+
+func checkLimit(ctx context.Context, max int64, count func(context.Context, uuid.UUID) (int64, error), orgId uuid.UUID, limitErr error) error {
+	if max <= 0 {
+		return nil
+	}
+	n, err := count(ctx, orgId)
+	if err != nil {
+		return err
+	}
+	if n >= max {
+		return limitErr
+	}
+	return nil
+}
@@ -27,8 +27,8 @@ func DefaultRouter(middlewares ...func(http.Handler) http.Handler) *chi.Mux {
 		cors.Handler(cors.Options{
 			AllowedOrigins:   []string{"https://*", "http://*"},
 			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", util.VIN_HEADER},
-			ExposedHeaders:   []string{"Link"},
+			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Org-ID", "Idempotency-Key", util.VIN_HEADER},
+			ExposedHeaders:   []string{"Link", "Idempotent-Replayed"},
 			AllowCredentials: true,
 			MaxAge:           300,
 		}),
@@ -56,7 +56,7 @@ func loggerMiddleware(h http.Handler) http.Handler {
 				r.Method, scheme, r.Host, r.RequestURI, r.Proto,
 				ww.Status(), ww.BytesWritten(), time.Since(t1),
 			)
-			logger.Info(message)
+			logger.Info("%s", message)
 		}()
 
 		h.ServeHTTP(ww, r)
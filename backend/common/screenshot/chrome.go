@@ -0,0 +1,56 @@
+package screenshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ChromeProvider captures pages with headless Chrome over the DevTools protocol.
+// With a devtoolsURL (e.g. ws://chrome:9222) it drives that browser, otherwise
+// it starts a local Chrome for every capture.
+type ChromeProvider struct {
+	devtoolsURL string
+}
+
+func NewChromeProvider(devtoolsURL string) *ChromeProvider {
+	return &ChromeProvider{devtoolsURL: devtoolsURL}
+}
+
+func (p *ChromeProvider) Capture(ctx context.Context, url string) ([]byte, error) {
+	browserCtx, cancel := NewChromeContext(ctx, p.devtoolsURL)
+	defer cancel()
+
+	var png []byte
+	// Quality 100 keeps the capture a PNG
+	if err := chromedp.Run(browserCtx,
+		chromedp.Navigate(url),
+		chromedp.FullScreenshot(&png, 100),
+	); err != nil {
+		return nil, fmt.Errorf("failed to take screenshot: %w", err)
+	}
+
+	return png, nil
+}
+
+// NewChromeContext returns a chromedp context on a new tab of the browser at
+// devtoolsURL, or of a freshly started local headless Chrome when it is empty.
+// cancel closes the tab and stops a local browser.
+func NewChromeContext(ctx context.Context, devtoolsURL string) (context.Context, context.CancelFunc) {
+	var allocCtx context.Context
+	var cancelAlloc context.CancelFunc
+	if devtoolsURL != "" {
+		allocCtx, cancelAlloc = chromedp.NewRemoteAllocator(ctx, devtoolsURL)
+	} else {
+		allocCtx, cancelAlloc = chromedp.NewExecAllocator(ctx, append(chromedp.DefaultExecAllocatorOptions[:],
+			chromedp.WindowSize(1280, 800),
+		)...)
+	}
+
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	return browserCtx, func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+}
@@ -0,0 +1,28 @@
+package screenshot
+
+import (
+	"better-uptime/config"
+	"context"
+	"log"
+)
+
+// Provider captures a PNG of the page at url
+type Provider interface {
+	Capture(ctx context.Context, url string) ([]byte, error)
+}
+
+// NewProvider picks the provider named by SCREENSHOT_PROVIDER:
+// "screenshotone" (default), "chrome" or "noop".
+func NewProvider(cfg config.Config) Provider {
+	switch cfg.SCREENSHOT_PROVIDER {
+	case "chrome":
+		return NewChromeProvider(cfg.CHROME_DEVTOOLS_URL)
+	case "noop":
+		return NoopProvider{}
+	case "", "screenshotone":
+		return NewScreenshotOneProvider(cfg.SCREENSHOTONE_KEY, cfg.SCREENSHOTONE_SECRET)
+	default:
+		log.Printf("Unknown SCREENSHOT_PROVIDER %q, screenshots are disabled", cfg.SCREENSHOT_PROVIDER)
+		return NoopProvider{}
+	}
+}
@@ -0,0 +1,43 @@
+package screenshot
+
+import (
+	"context"
+	"fmt"
+
+	screenshots "github.com/screenshotone/gosdk"
+)
+
+// ScreenshotOneProvider captures pages through the screenshotone.com API
+type ScreenshotOneProvider struct {
+	key    string
+	secret string
+}
+
+func NewScreenshotOneProvider(key, secret string) *ScreenshotOneProvider {
+	return &ScreenshotOneProvider{key: key, secret: secret}
+}
+
+func (p *ScreenshotOneProvider) Capture(ctx context.Context, url string) ([]byte, error) {
+	if p.key == "" || p.secret == "" {
+		return nil, fmt.Errorf("screenshot API credentials are missing")
+	}
+
+	client, err := screenshots.NewClient(p.key, p.secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create screenshot client: %w", err)
+	}
+
+	options := screenshots.NewTakeOptions(url).
+		Format("png").
+		FullPage(true).
+		DeviceScaleFactor(2).
+		BlockAds(true).
+		BlockTrackers(true)
+
+	imgBytes, _, err := client.Take(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take screenshot: %w", err)
+	}
+
+	return imgBytes, nil
+}
@@ -0,0 +1,229 @@
+package secrets
+
+import (
+	"better-uptime/config"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// versionEnvelope secrets are sealed with their own data key, which is
+	// wrapped by a master key: version || key id || wrapped data key || nonce || ciphertext.
+	// Version 1 was never stored and is not accepted.
+	versionEnvelope byte = 2
+
+	keyIDSize      = 4
+	dataKeySize    = 32
+	nonceSize      = 12
+	tagSize        = 16
+	wrappedKeySize = nonceSize + dataKeySize + tagSize
+)
+
+var (
+	ErrNoKey         = errors.New("SECRETS_KEY is not set")
+	ErrInvalidSecret = errors.New("secret cannot be decrypted")
+	ErrUnknownKey    = errors.New("secret was sealed with a key that is no longer configured")
+)
+
+// Box encrypts small secrets, such as monitor credentials, for storage at rest
+// with AES-256-GCM envelope encryption. New secrets are sealed under the active
+// master key; previous keys are kept only to open and rewrap older secrets.
+type Box struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewFromConfig builds a Box from SECRETS_KEY and SECRETS_PREVIOUS_KEYS
+func NewFromConfig(cfg config.Config) (*Box, error) {
+	var previous []string
+	for _, key := range strings.Split(cfg.SECRETS_PREVIOUS_KEYS, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			previous = append(previous, key)
+		}
+	}
+	return New(cfg.SECRETS_KEY, previous...)
+}
+
+// New builds a Box from base64-encoded 32-byte master keys. activeKey seals new
+// secrets, previousKeys only open existing ones.
+func New(activeKey string, previousKeys ...string) (*Box, error) {
+	if activeKey == "" {
+		return nil, ErrNoKey
+	}
+
+	b := &Box{keys: make(map[string]cipher.AEAD)}
+	for i, key := range append([]string{activeKey}, previousKeys...) {
+		id, aead, err := parseKey(key)
+		if err != nil {
+			if i == 0 {
+				return nil, fmt.Errorf("SECRETS_KEY: %w", err)
+			}
+			return nil, fmt.Errorf("SECRETS_PREVIOUS_KEYS[%d]: %w", i-1, err)
+		}
+		if i == 0 {
+			b.active = id
+		}
+		b.keys[id] = aead
+	}
+
+	return b, nil
+}
+
+func parseKey(key string) (string, cipher.AEAD, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return "", nil, fmt.Errorf("key is not valid base64: %w", err)
+	}
+	if len(raw) != 32 {
+		return "", nil, fmt.Errorf("key must be 32 bytes, got %d", len(raw))
+	}
+
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:keyIDSize]), aead, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ActiveKeyID identifies the master key new secrets are sealed with
+func (b *Box) ActiveKeyID() string {
+	return b.active
+}
+
+// Seal encrypts plaintext under a fresh data key. aad binds the secret to its
+// owner (e.g. the monitor and secret name), so a ciphertext copied onto another
+// row does not decrypt.
+func (b *Box) Seal(plaintext, aad []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, _ := hex.DecodeString(b.active)
+	out := make([]byte, 0, 1+keyIDSize+wrappedKeySize+nonceSize+len(plaintext)+tagSize)
+	out = append(out, versionEnvelope)
+	out = append(out, keyID...)
+
+	out, err = seal(b.keys[b.active], out, dataKey, aad)
+	if err != nil {
+		return nil, err
+	}
+	return seal(dataAEAD, out, plaintext, aad)
+}
+
+// Open decrypts a secret made by Seal with the same aad
+func (b *Box) Open(sealed, aad []byte) ([]byte, error) {
+	dataKey, rest, err := b.unwrap(sealed, aad)
+	if err != nil {
+		return nil, err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataAEAD, rest, aad)
+}
+
+// KeyID returns the id of the master key a sealed secret depends on, "" when
+// sealed is not a secret made by Seal
+func KeyID(sealed []byte) string {
+	if len(sealed) < 1+keyIDSize || sealed[0] != versionEnvelope {
+		return ""
+	}
+	return hex.EncodeToString(sealed[1 : 1+keyIDSize])
+}
+
+// Rewrap moves a secret onto the active master key by re-encrypting only its
+// data key. It returns sealed unchanged when it already uses the active key.
+func (b *Box) Rewrap(sealed, aad []byte) ([]byte, error) {
+	if KeyID(sealed) == b.active {
+		return sealed, nil
+	}
+
+	dataKey, rest, err := b.unwrap(sealed, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, _ := hex.DecodeString(b.active)
+	out := make([]byte, 0, len(sealed))
+	out = append(out, versionEnvelope)
+	out = append(out, keyID...)
+	out, err = seal(b.keys[b.active], out, dataKey, aad)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, rest...), nil
+}
+
+// unwrap decrypts the data key of an envelope secret and returns it with the
+// remaining nonce || ciphertext
+func (b *Box) unwrap(sealed, aad []byte) ([]byte, []byte, error) {
+	if len(sealed) < 1+keyIDSize+wrappedKeySize+nonceSize+tagSize || sealed[0] != versionEnvelope {
+		return nil, nil, ErrInvalidSecret
+	}
+
+	kek, ok := b.keys[KeyID(sealed)]
+	if !ok {
+		return nil, nil, ErrUnknownKey
+	}
+	wrapped := sealed[1+keyIDSize : 1+keyIDSize+wrappedKeySize]
+	dataKey, err := open(kek, wrapped, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, sealed[1+keyIDSize+wrappedKeySize:], nil
+}
+
+// seal appends nonce || ciphertext of plaintext to out
+func seal(aead cipher.AEAD, out, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// open decrypts nonce || ciphertext
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < nonceSize+tagSize {
+		return nil, ErrInvalidSecret
+	}
+	plaintext, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], aad)
+	if err != nil {
+		return nil, ErrInvalidSecret
+	}
+	return plaintext, nil
+}
+
+// Hint masks a secret for display, keeping the last characters of long ones
+// so users can tell which value is stored
+func Hint(secret string) string {
+	const mask = "••••"
+	runes := []rune(secret)
+	if len(runes) < 12 {
+		return mask
+	}
+	return mask + string(runes[len(runes)-4:])
+}
@@ -0,0 +1,166 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func newBox(t *testing.T, activeKey string, previousKeys ...string) *Box {
+	t.Helper()
+	box, err := New(activeKey, previousKeys...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return box
+}
+
+func TestSealOpen(t *testing.T) {
+	box := newBox(t, newKey(t))
+	aad := []byte("monitor:7:password")
+
+	sealed, err := box.Seal([]byte("hunter2"), aad)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("hunter2")) {
+		t.Fatal("sealed secret contains the plaintext")
+	}
+	if KeyID(sealed) != box.ActiveKeyID() {
+		t.Errorf("KeyID = %q, want the active key %q", KeyID(sealed), box.ActiveKeyID())
+	}
+
+	plaintext, err := box.Open(sealed, aad)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if string(plaintext) != "hunter2" {
+		t.Errorf("Open = %q, want hunter2", plaintext)
+	}
+
+	again, err := box.Seal([]byte("hunter2"), aad)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if bytes.Equal(sealed, again) {
+		t.Error("sealing the same secret twice gave the same ciphertext")
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	box := newBox(t, newKey(t))
+	aad := []byte("monitor:7:password")
+	sealed, err := box.Seal([]byte("hunter2"), aad)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	// Flip a bit in the wrapped data key, the nonce and the ciphertext
+	for _, i := range []int{1 + keyIDSize + 3, 1 + keyIDSize + wrappedKeySize + 1, len(sealed) - 1} {
+		tampered := bytes.Clone(sealed)
+		tampered[i] ^= 0x01
+		if _, err := box.Open(tampered, aad); !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("byte %d flipped: Open error = %v, want ErrInvalidSecret", i, err)
+		}
+	}
+
+	if _, err := box.Open(sealed[:len(sealed)-1], aad); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("truncated: Open error = %v, want ErrInvalidSecret", err)
+	}
+	if _, err := box.Open(nil, aad); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("empty: Open error = %v, want ErrInvalidSecret", err)
+	}
+
+	unknownVersion := bytes.Clone(sealed)
+	unknownVersion[0] = 9
+	if _, err := box.Open(unknownVersion, aad); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("unknown version: Open error = %v, want ErrInvalidSecret", err)
+	}
+}
+
+func TestOpenWrongAAD(t *testing.T) {
+	box := newBox(t, newKey(t))
+	sealed, err := box.Seal([]byte("hunter2"), []byte("monitor:7:password"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	for _, aad := range []string{"monitor:8:password", "monitor:7:username", ""} {
+		if _, err := box.Open(sealed, []byte(aad)); !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("aad %q: Open error = %v, want ErrInvalidSecret", aad, err)
+		}
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldMaster, newMaster := newKey(t), newKey(t)
+	aad := []byte("monitor:7:password")
+
+	sealed, err := newBox(t, oldMaster).Seal([]byte("hunter2"), aad)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	if _, err := newBox(t, newMaster).Open(sealed, aad); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("without the old key: Open error = %v, want ErrUnknownKey", err)
+	}
+
+	rotated := newBox(t, newMaster, oldMaster)
+	if plaintext, err := rotated.Open(sealed, aad); err != nil || string(plaintext) != "hunter2" {
+		t.Fatalf("with the old key as previous: Open = %q, %v", plaintext, err)
+	}
+
+	rewrapped, err := rotated.Rewrap(sealed, aad)
+	if err != nil {
+		t.Fatalf("Rewrap: %v", err)
+	}
+	if KeyID(rewrapped) != rotated.ActiveKeyID() {
+		t.Errorf("rewrapped KeyID = %q, want the active key %q", KeyID(rewrapped), rotated.ActiveKeyID())
+	}
+	if plaintext, err := newBox(t, newMaster).Open(rewrapped, aad); err != nil || string(plaintext) != "hunter2" {
+		t.Errorf("rewrapped secret with only the new key: Open = %q, %v", plaintext, err)
+	}
+	if _, err := rotated.Rewrap(sealed, []byte("monitor:8:password")); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("Rewrap with the wrong aad: error = %v, want ErrInvalidSecret", err)
+	}
+
+	again, err := rotated.Rewrap(rewrapped, aad)
+	if err != nil || !bytes.Equal(again, rewrapped) {
+		t.Errorf("Rewrap of a secret on the active key changed it (err %v)", err)
+	}
+}
+
+func TestNewRejectsBadKeys(t *testing.T) {
+	if _, err := New(""); !errors.Is(err, ErrNoKey) {
+		t.Errorf("empty key: error = %v, want ErrNoKey", err)
+	}
+	if _, err := New("not base64!"); err == nil {
+		t.Error("invalid base64 key was accepted")
+	}
+	if _, err := New(base64.StdEncoding.EncodeToString(make([]byte, 16))); err == nil {
+		t.Error("16-byte key was accepted")
+	}
+	if _, err := New(newKey(t), "short"); err == nil {
+		t.Error("invalid previous key was accepted")
+	}
+}
+
+func TestHint(t *testing.T) {
+	if got := Hint("hunter2"); got != "••••" {
+		t.Errorf("Hint of a short secret = %q, want only the mask", got)
+	}
+	if got := Hint("sk_live_1234567890abcd"); got != "••••abcd" {
+		t.Errorf("Hint = %q, want ••••abcd", got)
+	}
+}
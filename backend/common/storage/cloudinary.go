@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+)
+
+// Cloudinary stores artifacts as private Cloudinary images
+type Cloudinary struct {
+	cld *cloudinary.Cloudinary
+}
+
+func NewCloudinary(cloudName, apiKey, apiSecret string) (*Cloudinary, error) {
+	if cloudName == "" || apiKey == "" || apiSecret == "" {
+		return nil, fmt.Errorf("cloudinary credentials are missing")
+	}
+
+	cld, err := cloudinary.NewFromParams(cloudName, apiKey, apiSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &Cloudinary{cld: cld}, nil
+}
+
+// splitKey turns "a/b.png" into the public ID "a/b" and the format "png"
+func splitKey(key string) (string, string) {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext), strings.TrimPrefix(ext, ".")
+}
+
+func (c *Cloudinary) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	publicID, format := splitKey(key)
+
+	_, err := c.cld.Upload.Upload(ctx, body, uploader.UploadParams{
+		PublicID:     publicID,
+		Format:       format,
+		ResourceType: "image",
+		Type:         api.DeliveryType(api.Private),
+	})
+	if err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+	return nil
+}
+
+func (c *Cloudinary) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	publicID, format := splitKey(key)
+	expiresAt := time.Now().Add(ttl)
+
+	return c.cld.Upload.PrivateDownloadURL(uploader.PrivateDownloadURLParams{
+		PublicID:     publicID,
+		Format:       format,
+		DeliveryType: api.Private,
+		ExpiresAt:    &expiresAt,
+		ResourceType: api.Image,
+	})
+}
+
+func (c *Cloudinary) Delete(ctx context.Context, key string) error {
+	publicID, _ := splitKey(key)
+
+	_, err := c.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     publicID,
+		Type:         api.Private,
+		ResourceType: "image",
+	})
+	return err
+}
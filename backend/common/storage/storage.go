@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"better-uptime/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+var (
+	ErrInvalidKey       = errors.New("invalid artifact key")
+	ErrInvalidSignature = errors.New("invalid or expired artifact signature")
+)
+
+// Storage keeps artifacts such as failure screenshots. Keys are slash-separated
+// paths with a file extension, e.g. "monitors/12/down/1700000000.png". Artifacts
+// are private; they are read through signed URLs that expire.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the backend named by STORAGE_DRIVER: "cloudinary" (default), "s3" or "local"
+func New(cfg config.Config) (Storage, error) {
+	switch cfg.STORAGE_DRIVER {
+	case "", "cloudinary":
+		return NewCloudinary(cfg.CLOUDINARY_CLOUD_NAME, cfg.CLOUDINARY_API_KEY, cfg.CLOUDINARY_API_SECRET)
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:  cfg.S3_ENDPOINT,
+			Region:    cfg.S3_REGION,
+			Bucket:    cfg.S3_BUCKET,
+			AccessKey: cfg.S3_ACCESS_KEY,
+			SecretKey: cfg.S3_SECRET_KEY,
+			UseSSL:    cfg.S3_USE_SSL,
+		})
+	case "local":
+		return NewLocal(cfg.STORAGE_LOCAL_DIR, cfg.STORAGE_SIGNING_KEY, cfg.PUBLIC_BASE_URL+"/v1/public/artifacts")
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", cfg.STORAGE_DRIVER)
+	}
+}
+
+// DeleteAll deletes every key, logging the ones that fail, and returns how many
+// were deleted. A nil backend deletes nothing.
+func DeleteAll(ctx context.Context, s Storage, keys []string) int {
+	if s == nil {
+		return 0
+	}
+	deleted := 0
+	for _, key := range keys {
+		if err := s.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete artifact %s: %v", key, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
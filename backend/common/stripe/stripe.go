@@ -0,0 +1,143 @@
+package stripe
+
+import (
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	"better-uptime/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client calls the parts of the Stripe API billing needs. The API base is
+// configurable so a local stub can stand in for Stripe.
+type Client struct {
+	secretKey string
+	apiBase   string
+	prices    map[string]string
+	http      *http.Client
+}
+
+// NewClient returns util.ErrBillingNotConfigured without STRIPE_SECRET_KEY
+func NewClient(cfg config.Config) (*Client, error) {
+	if cfg.STRIPE_SECRET_KEY == "" {
+		return nil, util.ErrBillingNotConfigured
+	}
+	prices := map[string]string{}
+	if cfg.STRIPE_PRICE_PRO != "" {
+		prices[plans.Pro] = cfg.STRIPE_PRICE_PRO
+	}
+	if cfg.STRIPE_PRICE_BUSINESS != "" {
+		prices[plans.Business] = cfg.STRIPE_PRICE_BUSINESS
+	}
+	return &Client{
+		secretKey: cfg.STRIPE_SECRET_KEY,
+		apiBase:   strings.TrimRight(cfg.STRIPE_API_BASE, "/"),
+		prices:    prices,
+		http:      &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// PriceForPlan is the Stripe price a plan is sold at
+func (c *Client) PriceForPlan(plan string) (string, bool) {
+	price, ok := c.prices[plan]
+	return price, ok
+}
+
+// PlanForPrice maps a Stripe price back to its plan
+func (c *Client) PlanForPrice(price string) (string, bool) {
+	for plan, p := range c.prices {
+		if p == price {
+			return plan, true
+		}
+	}
+	return "", false
+}
+
+type CheckoutParams struct {
+	Price string
+	// Customer reuses an existing Stripe customer; otherwise CustomerEmail
+	// prefills a new one
+	Customer      string
+	CustomerEmail string
+	// ClientReferenceID identifies the organization in the webhook
+	ClientReferenceID string
+	Metadata          map[string]string
+	SuccessURL        string
+	CancelURL         string
+}
+
+type CheckoutSession struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// CreateCheckoutSession starts a subscription checkout hosted by Stripe
+func (c *Client) CreateCheckoutSession(ctx context.Context, params CheckoutParams) (CheckoutSession, error) {
+	form := url.Values{}
+	form.Set("mode", "subscription")
+	form.Set("line_items[0][price]", params.Price)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("success_url", params.SuccessURL)
+	form.Set("cancel_url", params.CancelURL)
+	form.Set("client_reference_id", params.ClientReferenceID)
+	if params.Customer != "" {
+		form.Set("customer", params.Customer)
+	} else if params.CustomerEmail != "" {
+		form.Set("customer_email", params.CustomerEmail)
+	}
+	for k, v := range params.Metadata {
+		// On the session for checkout.session.completed, and on the
+		// subscription for the customer.subscription.* events
+		form.Set("metadata["+k+"]", v)
+		form.Set("subscription_data[metadata]["+k+"]", v)
+	}
+
+	var session CheckoutSession
+	err := c.post(ctx, "/v1/checkout/sessions", form, &session)
+	return session, err
+}
+
+// GetSubscription fetches a subscription as it is now, shaped like the object
+// of customer.subscription.* events
+func (c *Client) GetSubscription(ctx context.Context, id string) (json.RawMessage, error) {
+	var sub json.RawMessage
+	err := c.do(ctx, http.MethodGet, "/v1/subscriptions/"+url.PathEscape(id), nil, &sub)
+	return sub, err
+}
+
+func (c *Client) post(ctx context.Context, path string, form url.Values, v any) error {
+	return c.do(ctx, http.MethodPost, path, form, v)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.apiBase+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("stripe %s: %s: %s", path, resp.Status, apiErr.Error.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
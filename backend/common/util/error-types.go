@@ -130,6 +130,42 @@ var (
 	ErrUnauthorizedForAppointment            = errors.New("unauthorized to reschedule this appointment")
 	ErrTrainerNotAssigned                    = errors.New("trainer is not assigned to this membership")
 	ErrInvalidRescheduledByValue             = errors.New("invalid 'rescheduled_by' value. Must be 'CLIENT' or 'TRAINER'")
+	ErrStatusPageNotFound                    = errors.New("status page not found")
+	ErrInvalidSlug                           = errors.New("slug may only contain lowercase letters, numbers and hyphens")
+	ErrNoticeNotFound                        = errors.New("notice not found")
+	ErrInvalidNoticeStatus                   = errors.New("status is not valid for this notice kind")
+	ErrSubscriptionNotFound                  = errors.New("subscription not found")
+	ErrMonitorNotFound                       = errors.New("monitor not found")
+	ErrBadgeNotFound                         = errors.New("badge not found")
+	ErrMonitorLogNotFound                    = errors.New("monitor log not found")
+	ErrSecretsNotConfigured                  = errors.New("credential encryption is not configured")
+	ErrNotOrgMember                          = errors.New("not a member of this organization")
+	ErrInsufficientRole                      = errors.New("your role in this organization does not allow this")
+	ErrLastOwner                             = errors.New("an organization needs at least one owner")
+	ErrMemberNotFound                        = errors.New("member not found")
+	ErrInviteNotFound                        = errors.New("invite not found or expired")
+	ErrInviteEmailMismatch                   = errors.New("this invite was sent to a different email address")
+	ErrAPIKeyNotFound                        = errors.New("api key not found")
+	ErrInsufficientScope                     = errors.New("api key lacks the scope for this request")
+	ErrSessionRequired                       = errors.New("sign in to do this, api keys are not accepted")
+	ErrInvalidScope                          = errors.New("unknown api key scope")
+	ErrInvalidCredentials                    = errors.New("invalid email or password")
+	ErrSignupDisabled                        = errors.New("sign up is disabled, ask an administrator for an account")
+	ErrPlanMonitorLimit                      = errors.New("your plan's monitor limit is reached, upgrade to add more")
+	ErrPlanIntervalTooShort                  = errors.New("your plan does not allow checks this frequent, upgrade or choose a longer interval")
+	ErrPlanAlertContactLimit                 = errors.New("your plan's alert contact limit is reached, upgrade to add more")
+	ErrPlanStatusPageLimit                   = errors.New("your plan's status page limit is reached, upgrade to add more")
+	ErrUnknownPlan                           = errors.New("unknown plan")
+	ErrBillingNotConfigured                  = errors.New("billing is not configured")
+	ErrAlreadySubscribed                     = errors.New("the organization already has an active subscription")
+	ErrInvalidWebhookSignature               = errors.New("invalid webhook signature")
+	ErrMonitorGroupNotFound                  = errors.New("monitor group not found")
+	ErrMonitorGroupExists                    = errors.New("a monitor group with this name already exists")
+	ErrInvalidTag                            = errors.New("tags may only contain letters, numbers and . _ : / - and be at most 64 characters")
+	ErrTooManyTags                           = errors.New("a monitor can have at most 20 tags")
+	ErrInvalidBulkAction                     = errors.New("unknown bulk action")
+	ErrTooManyBulkMonitors                   = errors.New("bulk operations take at most 500 monitors")
+	ErrInvalidVerificationLink               = errors.New("this verification link is invalid or has expired, sign up again for a new one")
 )
 
 var CustomErrorType = map[error]int{
@@ -205,4 +241,40 @@ var CustomErrorType = map[error]int{
 	ErrNutritionPlanUploadNotAllowed:         http.StatusBadRequest,
 	ErrPlanNotReady:                          http.StatusBadRequest,
 	ErrTrainerNotAssigned:                    http.StatusBadRequest,
+	ErrStatusPageNotFound:                    http.StatusNotFound,
+	ErrInvalidSlug:                           http.StatusBadRequest,
+	ErrNoticeNotFound:                        http.StatusNotFound,
+	ErrInvalidNoticeStatus:                   http.StatusBadRequest,
+	ErrSubscriptionNotFound:                  http.StatusNotFound,
+	ErrMonitorNotFound:                       http.StatusNotFound,
+	ErrBadgeNotFound:                         http.StatusNotFound,
+	ErrMonitorLogNotFound:                    http.StatusNotFound,
+	ErrSecretsNotConfigured:                  http.StatusServiceUnavailable,
+	ErrNotOrgMember:                          http.StatusForbidden,
+	ErrInsufficientRole:                      http.StatusForbidden,
+	ErrLastOwner:                             http.StatusConflict,
+	ErrMemberNotFound:                        http.StatusNotFound,
+	ErrInviteNotFound:                        http.StatusNotFound,
+	ErrInviteEmailMismatch:                   http.StatusForbidden,
+	ErrAPIKeyNotFound:                        http.StatusNotFound,
+	ErrInsufficientScope:                     http.StatusForbidden,
+	ErrSessionRequired:                       http.StatusForbidden,
+	ErrInvalidScope:                          http.StatusBadRequest,
+	ErrInvalidCredentials:                    http.StatusUnauthorized,
+	ErrSignupDisabled:                        http.StatusForbidden,
+	ErrPlanMonitorLimit:                      http.StatusPaymentRequired,
+	ErrPlanIntervalTooShort:                  http.StatusPaymentRequired,
+	ErrPlanAlertContactLimit:                 http.StatusPaymentRequired,
+	ErrPlanStatusPageLimit:                   http.StatusPaymentRequired,
+	ErrUnknownPlan:                           http.StatusBadRequest,
+	ErrBillingNotConfigured:                  http.StatusServiceUnavailable,
+	ErrAlreadySubscribed:                     http.StatusConflict,
+	ErrInvalidWebhookSignature:               http.StatusBadRequest,
+	ErrMonitorGroupNotFound:                  http.StatusNotFound,
+	ErrMonitorGroupExists:                    http.StatusConflict,
+	ErrInvalidTag:                            http.StatusBadRequest,
+	ErrTooManyTags:                           http.StatusBadRequest,
+	ErrInvalidBulkAction:                     http.StatusBadRequest,
+	ErrTooManyBulkMonitors:                   http.StatusBadRequest,
+	ErrInvalidVerificationLink:               http.StatusBadRequest,
 }
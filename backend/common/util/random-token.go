@@ -0,0 +1,15 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RandomToken returns a hex encoded random token of n bytes
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
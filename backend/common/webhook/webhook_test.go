@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestPostRejectsLoopback(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	err := Post(context.Background(), server.URL, map[string]string{"event": "test"})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("Post to %s: error = %v, want ErrForbiddenAddress", server.URL, err)
+	}
+	if hit {
+		t.Error("the loopback server received the webhook")
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.ip)); got != tt.public {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
@@ -3,22 +3,61 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	PORT                      string
+	APP_ENV                   string
 	POSTGRES_CONNECTION       string
 	CLOUDINARY_CLOUD_NAME     string
 	CLOUDINARY_API_KEY        string
 	CLOUDINARY_API_SECRET      string
 	SCREENSHOTONE_KEY         string
 	SCREENSHOTONE_SECRET      string
+	SCREENSHOT_PROVIDER       string
+	CHROME_DEVTOOLS_URL       string
+	STORAGE_DRIVER            string
+	STORAGE_LOCAL_DIR         string
+	STORAGE_SIGNING_KEY       string
+	S3_ENDPOINT               string
+	S3_REGION                 string
+	S3_BUCKET                 string
+	S3_ACCESS_KEY             string
+	S3_SECRET_KEY             string
+	S3_USE_SSL                bool
+	SECRETS_KEY               string
+	SECRETS_PREVIOUS_KEYS     string
 	SMTP_EMAIL                string
 	SMTP_PASSWORD             string
 	FIREBASE_SERVICE_ACCOUNT      string
 	FIREBASE_SERVICE_ACCOUNT_JSON string
+	DEV_AUTH_ENABLED              bool
+	DEV_AUTH_USERS                string
+	DEV_AUTH_JWT_SECRET           string
+	AUTH_PROVIDERS                string
+	OIDC_ISSUER_URL               string
+	OIDC_AUDIENCE                 string
+	OIDC_TRUST_UNVERIFIED_EMAIL   bool
+	AUTH_JWT_SIGNING_KEY          string
+	AUTH_JWT_ISSUER               string
+	AUTH_ACCESS_TOKEN_TTL_MINUTES int
+	AUTH_REFRESH_TOKEN_TTL_DAYS   int
+	AUTH_LOCAL_SIGNUP             bool
+	PUBLIC_BASE_URL               string
+	APP_BASE_URL                  string
+	TRUST_PROXY_HEADERS           bool
+	DEFAULT_PLAN                  string
+	STRIPE_SECRET_KEY             string
+	STRIPE_WEBHOOK_SECRET         string
+	STRIPE_API_BASE               string
+	STRIPE_PRICE_PRO              string
+	STRIPE_PRICE_BUSINESS         string
+	ROLLUP_1M_RETENTION_DAYS      int
+	LOG_RETENTION_DAYS            string
 }
 
 func LoadConfig() *Config {
@@ -28,22 +67,78 @@ func LoadConfig() *Config {
 
 	return &Config{
 		PORT:                          getEnv("PORT", "8080"),
+		APP_ENV:                       getEnv("APP_ENV", "production"),
 		POSTGRES_CONNECTION:           getEnv("POSTGRES_CONNECTION", ""),
 		CLOUDINARY_CLOUD_NAME:         getEnv("CLOUDINARY_CLOUD_NAME", ""),
 		CLOUDINARY_API_KEY:            getEnv("CLOUDINARY_API_KEY", ""),
 		CLOUDINARY_API_SECRET:         getEnv("CLOUDINARY_API_SECRET", ""),
 		SCREENSHOTONE_KEY:             getEnv("SCREENSHOTONE_KEY", ""),
 		SCREENSHOTONE_SECRET:          getEnv("SCREENSHOTONE_SECRET", ""),
+		SCREENSHOT_PROVIDER:           getEnv("SCREENSHOT_PROVIDER", "screenshotone"),
+		CHROME_DEVTOOLS_URL:           getEnv("CHROME_DEVTOOLS_URL", ""),
+		STORAGE_DRIVER:                getEnv("STORAGE_DRIVER", "cloudinary"),
+		STORAGE_LOCAL_DIR:             getEnv("STORAGE_LOCAL_DIR", "./data/artifacts"),
+		STORAGE_SIGNING_KEY:           getEnv("STORAGE_SIGNING_KEY", ""),
+		S3_ENDPOINT:                   getEnv("S3_ENDPOINT", ""),
+		S3_REGION:                     getEnv("S3_REGION", ""),
+		S3_BUCKET:                     getEnv("S3_BUCKET", ""),
+		S3_ACCESS_KEY:                 getEnv("S3_ACCESS_KEY", ""),
+		S3_SECRET_KEY:                 getEnv("S3_SECRET_KEY", ""),
+		S3_USE_SSL:                    getEnv("S3_USE_SSL", "true") == "true",
+		SECRETS_KEY:                   getEnv("SECRETS_KEY", ""),
+		SECRETS_PREVIOUS_KEYS:         getEnv("SECRETS_PREVIOUS_KEYS", ""),
 		SMTP_EMAIL:                    getEnv("SMTP_EMAIL", ""),
 		SMTP_PASSWORD:                 getEnv("SMTP_PASSWORD", ""),
 		FIREBASE_SERVICE_ACCOUNT:      getEnv("FIREBASE_SERVICE_ACCOUNT", ""),
 		FIREBASE_SERVICE_ACCOUNT_JSON: getEnv("FIREBASE_SERVICE_ACCOUNT_JSON", ""),
+		DEV_AUTH_ENABLED:              getEnv("DEV_AUTH_ENABLED", "false") == "true",
+		DEV_AUTH_USERS:                getEnv("DEV_AUTH_USERS", ""),
+		DEV_AUTH_JWT_SECRET:           getEnv("DEV_AUTH_JWT_SECRET", ""),
+		AUTH_PROVIDERS:                getEnv("AUTH_PROVIDERS", "firebase"),
+		OIDC_ISSUER_URL:               getEnv("OIDC_ISSUER_URL", ""),
+		OIDC_AUDIENCE:                 getEnv("OIDC_AUDIENCE", ""),
+		OIDC_TRUST_UNVERIFIED_EMAIL:   getEnv("OIDC_TRUST_UNVERIFIED_EMAIL", "false") == "true",
+		AUTH_JWT_SIGNING_KEY:          getEnv("AUTH_JWT_SIGNING_KEY", ""),
+		AUTH_JWT_ISSUER:               getEnv("AUTH_JWT_ISSUER", "better-uptime"),
+		AUTH_ACCESS_TOKEN_TTL_MINUTES: getEnvInt("AUTH_ACCESS_TOKEN_TTL_MINUTES", 15),
+		AUTH_REFRESH_TOKEN_TTL_DAYS:   getEnvInt("AUTH_REFRESH_TOKEN_TTL_DAYS", 30),
+		AUTH_LOCAL_SIGNUP:             getEnv("AUTH_LOCAL_SIGNUP", "true") == "true",
+		PUBLIC_BASE_URL:               getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+		APP_BASE_URL:                  getEnv("APP_BASE_URL", "http://localhost:3000"),
+		TRUST_PROXY_HEADERS:           getEnv("TRUST_PROXY_HEADERS", "false") == "true",
+		DEFAULT_PLAN:                  getEnv("DEFAULT_PLAN", "unlimited"),
+		STRIPE_SECRET_KEY:             getEnv("STRIPE_SECRET_KEY", ""),
+		STRIPE_WEBHOOK_SECRET:         getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		STRIPE_API_BASE:               getEnv("STRIPE_API_BASE", "https://api.stripe.com"),
+		STRIPE_PRICE_PRO:              getEnv("STRIPE_PRICE_PRO", ""),
+		STRIPE_PRICE_BUSINESS:         getEnv("STRIPE_PRICE_BUSINESS", ""),
+		ROLLUP_1M_RETENTION_DAYS:      getEnvInt("ROLLUP_1M_RETENTION_DAYS", 14),
+		LOG_RETENTION_DAYS:            getEnv("LOG_RETENTION_DAYS", ""),
 	}
 }
 
+// IsDevelopment reports whether APP_ENV explicitly marks a development setup,
+// the only place development-only features run. Unset means production.
+func (c Config) IsDevelopment() bool {
+	return strings.EqualFold(c.APP_ENV, "development")
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer for %s, using %d", key, fallback)
+		return fallback
+	}
+	return parsed
+}
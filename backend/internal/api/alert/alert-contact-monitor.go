@@ -11,6 +11,9 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// screenshotWait is how long a down alert waits for the failure screenshot
+const screenshotWait = 60 * time.Second
+
 type TestURLResponse struct {
 	Url          string  `json:"url"`
 	StatusCode   int32   `json:"status_code"`
@@ -25,6 +28,53 @@ func (h *Handler) GetAlertContactsForMonitor(ctx context.Context, monitorID int3
 	return h.store.GetAlertContactsByMonitor(ctx, pgtype.Int4{Int32: monitorID, Valid: true})
 }
 
+// alertRecipients returns the emails to alert about a monitor going up or down:
+// the org's alert contacts configured on the monitor for that change or, when
+// none are configured, the org's current members. The monitor's creator may
+// have left the org since.
+func (h *Handler) alertRecipients(ctx context.Context, monitor db.Monitor, isUp bool) ([]string, error) {
+	configs, err := h.store.GetMonitorAlertConfigs(ctx, pgtype.Int4{Int32: monitor.ID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(configs) == 0 {
+		members, err := h.store.ListOrgMembers(ctx, monitor.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		recipients := make([]string, 0, len(members))
+		for _, member := range members {
+			recipients = append(recipients, member.Email)
+		}
+		return recipients, nil
+	}
+
+	contacts, err := h.GetAlertContactsForMonitor(ctx, monitor.ID)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[int32]bool, len(configs))
+	for _, config := range configs {
+		if isUp {
+			wanted[config.AlertContactID.Int32] = config.AlertOnUp.Bool
+		} else {
+			wanted[config.AlertContactID.Int32] = config.AlertOnDown.Bool
+		}
+	}
+
+	recipients := make([]string, 0, len(contacts))
+	seen := make(map[string]bool, len(contacts))
+	for _, contact := range contacts {
+		if contact.OrgID != monitor.OrgID || !wanted[contact.ID] || seen[contact.Email] {
+			continue
+		}
+		seen[contact.Email] = true
+		recipients = append(recipients, contact.Email)
+	}
+	return recipients, nil
+}
+
 func (h *Handler) CheckAndSendAlerts(ctx context.Context, monitor db.Monitor, checkResult *monitor.TestURLResponse) error {
 
 
@@ -52,6 +102,15 @@ func (h *Handler) CheckAndSendAlerts(ctx context.Context, monitor db.Monitor, ch
 		checkResult.ResponseTime,
 	)
 
+	// A down transition starts a screenshot, give it a moment to land in the alert
+	screenshotURL := ""
+	if !isUp {
+		screenshotURL = checkResult.WaitForScreenshot(ctx, screenshotWait)
+	}
+	if screenshotURL != "" {
+		message += fmt.Sprintf(". Screenshot: %s", screenshotURL)
+	}
+
 	previous := "unknown"
 	if monitor.LastStatus.Valid {
 		previous = string(monitor.LastStatus.MonitorStatus)
@@ -61,23 +120,23 @@ func (h *Handler) CheckAndSendAlerts(ctx context.Context, monitor db.Monitor, ch
 	if previous == newStatus {
 		return nil
 	}
-	user, err := h.store.GetUserByID(
-		ctx,
-		monitor.UserID.Bytes,
-	)
+	recipients, err := h.alertRecipients(ctx, monitor, isUp)
 	if err != nil {
 		return err
 	}
 
 	// 5. Send email to all contacts
-	if err := email.SendStatusAlert(
-		user.Email,
-		monitor.Url,
-		isUp,
-		fmt.Sprintf("%.0fms", checkResult.ResponseTime),
-		time.Now().Format("2006-01-02 15:04:05"),
-	); err != nil {
-		fmt.Println("email failed:", err)
+	for _, recipient := range recipients {
+		if err := email.SendStatusAlertWithScreenshot(
+			recipient,
+			monitor.Url,
+			isUp,
+			fmt.Sprintf("%.0fms", checkResult.ResponseTime),
+			time.Now().Format("2006-01-02 15:04:05"),
+			screenshotURL,
+		); err != nil {
+			fmt.Println("email failed:", err)
+		}
 	}
 
 	// Save the alert log once
@@ -1,7 +1,9 @@
 package alert
 
 import (
+	"better-uptime/common/audit"
 	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
 	"encoding/json"
@@ -37,8 +39,19 @@ func (h *Handler) CreateAlertContact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	plan, err := plans.ForOrg(ctx, h.store, *h.config, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if err := plan.CheckNewAlertContact(ctx, h.store, payload.OrgId); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
 	contact, err := h.store.CreateAlertContact(ctx, db.CreateAlertContactParams{
 		UserID: pgtype.UUID{Bytes: userId, Valid: true},
+		OrgID:  payload.OrgId,
 		Name:   req.Name,
 		Email:  req.Email,
 	})
@@ -69,5 +82,12 @@ func (h *Handler) CreateAlertContact(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:  createdAt,
 	}
 
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceAlertContact,
+		ResourceID:   contact.ID,
+		After:        response,
+	})
+
 	util.WriteJson(w, http.StatusCreated, response)
 }
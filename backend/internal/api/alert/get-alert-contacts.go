@@ -5,8 +5,6 @@ import (
 	"better-uptime/common/util"
 	"encoding/json"
 	"net/http"
-
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // GetAlertContacts returns the user's alert contacts
@@ -18,9 +16,9 @@ func (h *Handler) GetAlertContacts(w http.ResponseWriter, r *http.Request) {
 		util.ErrorJson(w, util.ErrUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
-	contacts, err := h.store.GetAlertContactsByUserID(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	contacts, err := h.store.GetAlertContactsByOrg(ctx, orgId)
 	if err != nil {
 		util.ErrorJson(w, err)
 		return
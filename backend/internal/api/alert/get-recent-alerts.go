@@ -7,8 +7,6 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
-
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // GetRecentAlerts returns the user's recent alerts
@@ -20,7 +18,7 @@ func (h *Handler) GetRecentAlerts(w http.ResponseWriter, r *http.Request) {
 		util.ErrorJson(w, util.ErrUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	// Get limit from query params, default to 50
 	limit := int32(50)
@@ -31,8 +29,8 @@ func (h *Handler) GetRecentAlerts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	alerts, err := h.store.GetRecentAlerts(ctx, db.GetRecentAlertsParams{
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
-		Limit:  limit,
+		OrgID: orgId,
+		Limit: limit,
 	})
 	if err != nil {
 		util.ErrorJson(w, err)
@@ -31,6 +31,8 @@ func (h *Handler) Routes() *chi.Mux {
 
 	router.Group(func(r chi.Router) {
 		r.Use(middleware.TokenMiddleware(h.store))
+		r.Use(middleware.RequireRoleForWrites(db.OrgRoleEditor))
+		r.Use(middleware.RequireScopeForWrites(middleware.ScopeAlertsWrite))
 
 		// Alert endpoints
 		r.Get("/recent", h.GetRecentAlerts)
@@ -0,0 +1,266 @@
+package alert
+
+import (
+	"better-uptime/common/apiv2"
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AlertContact is an alert contact in the v2 API
+type AlertContact struct {
+	ID         int32     `json:"id"`
+	ExternalID *string   `json:"external_id" doc:"Key of the contact in a manifest, null when it is not managed by one"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Verified   bool      `json:"verified"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AlertContactInput struct {
+	Name  string `json:"name" validate:"required,max=120"`
+	Email string `json:"email" validate:"required,email"`
+}
+
+// AlertContactPatch updates the fields it sets and keeps the others
+type AlertContactPatch struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=120"`
+	Email *string `json:"email,omitempty" validate:"omitempty,email"`
+}
+
+// V2Routes adds the alert contact resource to the v2 API
+func (h *Handler) V2Routes(api *apiv2.API) {
+	api = api.With(
+		middleware.RequireRoleForWrites(db.OrgRoleEditor),
+		middleware.RequireScopeForWrites(middleware.ScopeAlertsWrite),
+	)
+
+	api.Handle(apiv2.Operation{
+		ID: "listAlertContacts", Method: http.MethodGet, Path: "/alert-contacts", Tag: "Alert contacts",
+		Summary: "List alert contacts", Paginated: true, Response: apiv2.Page[AlertContact]{},
+	}, h.ListAlertContactsV2)
+	api.Handle(apiv2.Operation{
+		ID: "createAlertContact", Method: http.MethodPost, Path: "/alert-contacts", Tag: "Alert contacts",
+		Summary: "Create an alert contact", Body: AlertContactInput{},
+		Status: http.StatusCreated, Response: AlertContact{},
+	}, h.CreateAlertContactV2)
+	api.Handle(apiv2.Operation{
+		ID: "getAlertContact", Method: http.MethodGet, Path: "/alert-contacts/{id}", Tag: "Alert contacts",
+		Summary: "Get an alert contact", Response: AlertContact{},
+	}, h.GetAlertContactV2)
+	api.Handle(apiv2.Operation{
+		ID: "updateAlertContact", Method: http.MethodPatch, Path: "/alert-contacts/{id}", Tag: "Alert contacts",
+		Summary: "Update an alert contact", Body: AlertContactPatch{}, Response: AlertContact{},
+	}, h.UpdateAlertContactV2)
+	api.Handle(apiv2.Operation{
+		ID: "deleteAlertContact", Method: http.MethodDelete, Path: "/alert-contacts/{id}", Tag: "Alert contacts",
+		Summary:     "Delete an alert contact",
+		Description: "Monitors stop alerting the contact.",
+		Status:      http.StatusNoContent,
+	}, h.DeleteAlertContactV2)
+}
+
+func (h *Handler) ListAlertContactsV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var after apiv2.IDCursor
+	limit, err := apiv2.ReadPage(r, &after)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	contacts, err := h.store.ListOrgAlertContactsPage(ctx, db.ListOrgAlertContactsPageParams{
+		OrgID:    payload.OrgId,
+		AfterID:  after.ID,
+		PageSize: limit + 1,
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, apiv2.NewPage(contacts, limit, toV2AlertContact,
+		func(contact db.AlertContact) any { return apiv2.IDCursor{ID: contact.ID} }))
+}
+
+func (h *Handler) GetAlertContactV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	contact, err := h.store.GetOrgAlertContact(ctx, db.GetOrgAlertContactParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, toV2AlertContact(contact))
+}
+
+func (h *Handler) CreateAlertContactV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req AlertContactInput
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	plan, err := plans.ForOrg(ctx, h.store, *h.config, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if err := plan.CheckNewAlertContact(ctx, h.store, payload.OrgId); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	contact, err := h.store.CreateAlertContact(ctx, db.CreateAlertContactParams{
+		UserID: pgtype.UUID{Bytes: payload.UserId, Valid: true},
+		OrgID:  payload.OrgId,
+		Name:   req.Name,
+		Email:  req.Email,
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	resp := toV2AlertContact(contact)
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceAlertContact,
+		ResourceID:   contact.ID,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) UpdateAlertContactV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	var req AlertContactPatch
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.store.GetOrgAlertContact(ctx, db.GetOrgAlertContactParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	params := db.UpdateAlertContactParams{
+		ID:    existing.ID,
+		OrgID: payload.OrgId,
+		Name:  existing.Name,
+		Email: existing.Email,
+	}
+	if req.Name != nil {
+		params.Name = *req.Name
+	}
+	if req.Email != nil {
+		params.Email = *req.Email
+	}
+	contact, err := h.store.UpdateAlertContact(ctx, params)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	resp := toV2AlertContact(contact)
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceAlertContact,
+		ResourceID:   contact.ID,
+		Before:       toV2AlertContact(existing),
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteAlertContactV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.store.GetOrgAlertContact(ctx, db.GetOrgAlertContactParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if err := h.store.DeleteAlertContact(ctx, db.DeleteAlertContactParams{ID: id, OrgID: payload.OrgId}); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceAlertContact,
+		ResourceID:   id,
+		Before:       toV2AlertContact(existing),
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toV2AlertContact(contact db.AlertContact) AlertContact {
+	resp := AlertContact{
+		ID:        contact.ID,
+		Name:      contact.Name,
+		Email:     contact.Email,
+		Verified:  contact.IsVerified.Bool,
+		CreatedAt: contact.CreatedAt.Time,
+	}
+	if contact.ExternalID.Valid {
+		resp.ExternalID = &contact.ExternalID.String
+	}
+	return resp
+}
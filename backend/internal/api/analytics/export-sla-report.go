@@ -0,0 +1,210 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+var slaCSVHeader = []string{
+	"monitor_id", "name", "period_start", "period_end", "slo_target",
+	"uptime_percent", "measured_seconds", "downtime_seconds", "maintenance_seconds",
+	"no_data_seconds", "incidents", "mttr_seconds", "mtbf_seconds",
+	"error_budget_seconds", "error_budget_remaining_seconds", "slo_met",
+}
+
+func writeSLACSV(w http.ResponseWriter, report *SLAReport) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+slaFilename(report, "csv")+`"`)
+
+	writer := csv.NewWriter(w)
+	rows := [][]string{slaCSVHeader}
+	for _, m := range report.Monitors {
+		rows = append(rows, slaCSVRow(report, strconv.Itoa(int(m.MonitorID)), m.Name, m.SLAStats))
+	}
+	if report.Scope == "group" {
+		rows = append(rows, slaCSVRow(report, "total", report.Name, report.Summary))
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		log.Printf("failed to write SLA csv: %v", err)
+	}
+}
+
+func slaCSVRow(report *SLAReport, id, name string, s SLAStats) []string {
+	return []string{
+		id,
+		name,
+		report.PeriodStart,
+		report.PeriodEnd,
+		strconv.FormatFloat(report.SLOTarget, 'f', -1, 64),
+		formatOptionalPercent(s.UptimePercent, 4),
+		strconv.FormatInt(s.MeasuredSeconds, 10),
+		strconv.FormatInt(s.DowntimeSeconds, 10),
+		strconv.FormatInt(s.MaintenanceSeconds, 10),
+		strconv.FormatInt(s.NoDataSeconds, 10),
+		strconv.Itoa(s.Incidents),
+		formatOptionalSeconds(s.MTTRSeconds),
+		formatOptionalSeconds(s.MTBFSeconds),
+		strconv.FormatInt(s.ErrorBudgetSeconds, 10),
+		strconv.FormatInt(s.ErrorBudgetRemainingSeconds, 10),
+		strconv.FormatBool(s.SLOMet),
+	}
+}
+
+func writeSLAPDF(w http.ResponseWriter, report *SLAReport) {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("SLA report - "+report.Name, true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr("SLA report: "+report.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("Period %s to %s (%s)", report.PeriodStart, report.PeriodEnd, report.Timezone)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("SLO target %s%%", strconv.FormatFloat(report.SLOTarget, 'f', -1, 64)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	summary := report.Summary
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Summary", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range []string{
+		"Uptime: " + formatOptionalPercent(summary.UptimePercent, 3) + "%",
+		"SLO met: " + strconv.FormatBool(summary.SLOMet),
+		"Downtime: " + formatDuration(summary.DowntimeSeconds),
+		"Excluded maintenance: " + formatDuration(summary.MaintenanceSeconds),
+		"No data: " + formatDuration(summary.NoDataSeconds),
+		"Incidents: " + strconv.Itoa(summary.Incidents),
+		"MTTR: " + formatOptionalDuration(summary.MTTRSeconds) + "   MTBF: " + formatOptionalDuration(summary.MTBFSeconds),
+		"Error budget: " + formatDuration(summary.ErrorBudgetSeconds) + ", remaining " + formatSignedDuration(summary.ErrorBudgetRemainingSeconds),
+	} {
+		pdf.CellFormat(0, 6, line, "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	headers := []string{"Monitor", "Uptime %", "Downtime", "Maintenance", "Incidents", "MTTR", "MTBF", "Budget left", "SLO met"}
+	widths := []float64{80, 22, 26, 26, 20, 24, 24, 26, 18}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	for i, header := range headers {
+		pdf.CellFormat(widths[i], 7, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, m := range report.Monitors {
+		cells := []string{
+			tr(truncate(m.Name, 48)),
+			formatOptionalPercent(m.UptimePercent, 3),
+			formatDuration(m.DowntimeSeconds),
+			formatDuration(m.MaintenanceSeconds),
+			strconv.Itoa(m.Incidents),
+			formatOptionalDuration(m.MTTRSeconds),
+			formatOptionalDuration(m.MTBFSeconds),
+			formatSignedDuration(m.ErrorBudgetRemainingSeconds),
+			strconv.FormatBool(m.SLOMet),
+		}
+		for i, cell := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 6, cell, "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.MultiCell(0, 4, "Uptime is time-weighted from check state transitions. Scheduled maintenance and periods without check data are excluded from the measured time.", "", "L", false)
+	pdf.CellFormat(0, 4, "Generated "+time.Now().UTC().Format(time.RFC3339)+" by Better Uptime Monitor", "", 1, "L", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		log.Printf("failed to build SLA pdf: %v", err)
+		http.Error(w, "Could not generate PDF", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+slaFilename(report, "pdf")+`"`)
+	if err := pdf.Output(w); err != nil {
+		log.Printf("failed to write SLA pdf: %v", err)
+	}
+}
+
+func slaFilename(report *SLAReport, ext string) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(report.Name, "-"), "-")
+	if name == "" {
+		name = report.Scope
+	}
+	return fmt.Sprintf("sla-%s-%s.%s", name, report.PeriodStart[:10], ext)
+}
+
+func formatOptionalPercent(v *float64, precision int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', precision, 64)
+}
+
+func formatOptionalSeconds(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+func formatOptionalDuration(v *int64) string {
+	if v == nil {
+		return "-"
+	}
+	return formatDuration(*v)
+}
+
+func formatSignedDuration(seconds int64) string {
+	if seconds < 0 {
+		return "-" + formatDuration(-seconds)
+	}
+	return formatDuration(seconds)
+}
+
+// formatDuration renders seconds as e.g. "1d 2h 3m 4s"
+func formatDuration(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days := int64(d / (24 * time.Hour))
+	d -= time.Duration(days) * 24 * time.Hour
+
+	parts := make([]string, 0, 4)
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if h := int64(d / time.Hour); h > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", h))
+	}
+	if m := int64(d/time.Minute) % 60; m > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", m))
+	}
+	if s := int64(d/time.Second) % 60; s > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%ds", s))
+	}
+	return strings.Join(parts, " ")
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -5,7 +5,6 @@ import (
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"time"
 
@@ -31,10 +30,10 @@ func (h *Handler) GetOverview(w http.ResponseWriter, r *http.Request) {
 		util.ErrorJson(w, util.ErrUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	// Get all monitors for this user
-	monitors, err := h.store.GetUserMonitors(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	monitors, err := h.store.GetOrgMonitors(ctx, orgId)
 	if err != nil {
 		util.ErrorJson(w, err)
 		return
@@ -51,6 +50,26 @@ func (h *Handler) GetOverview(w http.ResponseWriter, r *http.Request) {
 
 	now := time.Now()
 
+	monitorIDs := make([]int32, 0, len(monitors))
+	for _, m := range monitors {
+		monitorIDs = append(monitorIDs, m.ID)
+	}
+
+	totals, err := h.store.GetRollupTotalsForMonitors(ctx, db.GetRollupTotalsForMonitorsParams{
+		MonitorIds: monitorIDs,
+		Resolution: db.RollupResolution1m,
+		Since:      pgtype.Timestamp{Time: now.UTC().Add(-24 * time.Hour), Valid: true},
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	totalsByMonitor := make(map[int32]db.GetRollupTotalsForMonitorsRow, len(totals))
+	for _, t := range totals {
+		totalsByMonitor[t.MonitorID] = t
+	}
+
 	for _, m := range monitors {
 		// Count active monitors
 		if m.IsActive.Bool {
@@ -64,41 +83,18 @@ func (h *Handler) GetOverview(w http.ResponseWriter, r *http.Request) {
 			monitorsDown++
 		}
 
-		// Get recent logs for this monitor to calculate stats
-		logs, err := h.store.GetMonitorLogs(ctx, db.GetMonitorLogsParams{
-			MonitorID: pgtype.Int4{Int32: m.ID, Valid: true},
-			Limit:     100,
-		})
-		if err != nil || len(logs) == 0 {
+		// Stats for the last 24 hours come from the 1-minute rollups
+		t, ok := totalsByMonitor[m.ID]
+		if !ok || t.TotalChecks == 0 {
 			continue
 		}
 
-		// Calculate stats from logs (same logic as monitor handler)
-		var monitorResponseTime float64
-		var successfulChecks int64
-		var responseTimeCount int64
-
-		for _, log := range logs {
-			if log.ResponseTime.Valid && log.ResponseTime.Float64 > 0 {
-				monitorResponseTime += log.ResponseTime.Float64
-				responseTimeCount++
-			}
-			if log.StatusCode.Valid && log.StatusCode.Int32 >= 200 && log.StatusCode.Int32 < 400 {
-				successfulChecks++
-			}
-		}
-
-		if len(logs) > 0 {
-			monitorUptime := float64(successfulChecks) / float64(len(logs)) * 100
-			totalUptime += monitorUptime
-			monitorsWithData++
-			fmt.Printf("Monitor %d: uptime=%.2f%%, checks=%d\n", m.ID, monitorUptime, len(logs))
-		}
+		monitorUptime := float64(t.SuccessfulChecks) / float64(t.TotalChecks) * 100
+		totalUptime += monitorUptime
+		monitorsWithData++
 
-		if responseTimeCount > 0 {
-			avgResp := monitorResponseTime / float64(responseTimeCount)
-			totalResponseTime += avgResp
-			fmt.Printf("Monitor %d: avg_response=%.2fms\n", m.ID, avgResp)
+		if t.AvgResponseTime > 0 {
+			totalResponseTime += t.AvgResponseTime
 		}
 	}
 
@@ -109,14 +105,12 @@ func (h *Handler) GetOverview(w http.ResponseWriter, r *http.Request) {
 		avgUptime = totalUptime / float64(monitorsWithData)
 		avgResponseTime = totalResponseTime / float64(monitorsWithData)
 	}
-	fmt.Printf("Analytics: monitors=%d, withData=%d, avgUptime=%.2f%%, avgResponse=%.2fms\n",
-		totalMonitors, monitorsWithData, avgUptime, avgResponseTime)
 
 	// Get today's alerts count
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	alerts, err := h.store.GetRecentAlerts(ctx, db.GetRecentAlertsParams{
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
-		Limit:  100,
+		OrgID: orgId,
+		Limit: 100,
 	})
 	alertsToday := 0
 	if err == nil {
@@ -0,0 +1,388 @@
+package analytics
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultSLOTarget = 99.9
+	maxSLAPeriod     = 366 * 24 * time.Hour
+)
+
+type SLAReport struct {
+	Scope       string       `json:"scope"`
+	Name        string       `json:"name"`
+	PeriodStart string       `json:"period_start"`
+	PeriodEnd   string       `json:"period_end"`
+	Timezone    string       `json:"timezone"`
+	SLOTarget   float64      `json:"slo_target"`
+	Summary     SLAStats     `json:"summary"`
+	Monitors    []MonitorSLA `json:"monitors"`
+}
+
+type MonitorSLA struct {
+	MonitorID int32  `json:"monitor_id"`
+	Name      string `json:"name"`
+	SLAStats
+}
+
+// SLAStats are time-weighted; all durations are in seconds.
+// Maintenance and no-data time are excluded from the measured time.
+type SLAStats struct {
+	MeasuredSeconds             int64    `json:"measured_seconds"`
+	UptimeSeconds               int64    `json:"uptime_seconds"`
+	DowntimeSeconds             int64    `json:"downtime_seconds"`
+	MaintenanceSeconds          int64    `json:"maintenance_seconds"`
+	NoDataSeconds               int64    `json:"no_data_seconds"`
+	UptimePercent               *float64 `json:"uptime_percent"`
+	Incidents                   int      `json:"incidents"`
+	MTTRSeconds                 *int64   `json:"mttr_seconds"`
+	MTBFSeconds                 *int64   `json:"mtbf_seconds"`
+	ErrorBudgetSeconds          int64    `json:"error_budget_seconds"`
+	ErrorBudgetRemainingSeconds int64    `json:"error_budget_remaining_seconds"`
+	ErrorBudgetRemainingPercent *float64 `json:"error_budget_remaining_percent"`
+	SLOMet                      bool     `json:"slo_met"`
+}
+
+type slaPeriod struct {
+	from     time.Time
+	to       time.Time
+	location *time.Location
+}
+
+// GetSLAReport returns the SLA of one monitor (?monitor_id=), of every monitor in
+// a monitor group (?group_id=) or on a status page (?status_page_id=) for a
+// calendar period.
+//
+// The period is either ?month=2025-09 or ?from=2025-09-01&to=2025-09-30 (inclusive),
+// interpreted in ?tz= (default UTC). ?slo= sets the target (default 99.9) and
+// ?format=csv|pdf exports the report instead of returning JSON.
+func (h *Handler) GetSLAReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	period, err := parseSLAPeriod(query.Get("month"), query.Get("from"), query.Get("to"), query.Get("tz"))
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	slo := defaultSLOTarget
+	if raw := query.Get("slo"); raw != "" {
+		slo, err = strconv.ParseFloat(raw, 64)
+		if err != nil || slo <= 0 || slo >= 100 {
+			util.ErrorJson(w, util.ErrInvalidQueryParams)
+			return
+		}
+	}
+
+	var report *SLAReport
+	switch {
+	case query.Get("monitor_id") != "":
+		report, err = h.monitorSLAReport(ctx, payload.OrgId, query.Get("monitor_id"), period, slo)
+	case query.Get("group_id") != "":
+		report, err = h.monitorGroupSLAReport(ctx, payload.OrgId, query.Get("group_id"), period, slo)
+	case query.Get("status_page_id") != "":
+		report, err = h.statusPageSLAReport(ctx, payload.OrgId, query.Get("status_page_id"), period, slo)
+	default:
+		err = util.ErrRequiredInputMissing("monitor_id, group_id or status_page_id")
+	}
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	switch query.Get("format") {
+	case "", "json":
+		util.WriteJson(w, http.StatusOK, report)
+	case "csv":
+		writeSLACSV(w, report)
+	case "pdf":
+		writeSLAPDF(w, report)
+	default:
+		util.ErrorJson(w, util.ErrInvalidQueryParams)
+	}
+}
+
+func parseSLAPeriod(month, from, to, tz string) (slaPeriod, error) {
+	location := time.UTC
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return slaPeriod{}, util.ErrInvalidQueryParams
+		}
+		location = loc
+	}
+
+	var period slaPeriod
+	switch {
+	case month != "":
+		start, err := time.ParseInLocation("2006-01", month, location)
+		if err != nil {
+			return slaPeriod{}, util.ErrInvalidTimeFormat
+		}
+		period = slaPeriod{from: start, to: start.AddDate(0, 1, 0)}
+	case from != "" && to != "":
+		start, err := time.ParseInLocation("2006-01-02", from, location)
+		if err != nil {
+			return slaPeriod{}, util.ErrInvalidTimeFormat
+		}
+		end, err := time.ParseInLocation("2006-01-02", to, location)
+		if err != nil {
+			return slaPeriod{}, util.ErrInvalidTimeFormat
+		}
+		period = slaPeriod{from: start, to: end.AddDate(0, 0, 1)}
+	default:
+		now := time.Now().In(location)
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+		period = slaPeriod{from: start, to: start.AddDate(0, 1, 0)}
+	}
+
+	if !period.to.After(period.from) {
+		return slaPeriod{}, util.ErrTimingError
+	}
+	if period.to.Sub(period.from) > maxSLAPeriod {
+		return slaPeriod{}, util.ErrInvalidQueryParams
+	}
+
+	period.location = location
+	return period, nil
+}
+
+func (h *Handler) monitorSLAReport(ctx context.Context, orgId uuid.UUID, rawID string, period slaPeriod, slo float64) (*SLAReport, error) {
+	monitorID, err := strconv.ParseInt(rawID, 10, 32)
+	if err != nil {
+		return nil, util.ErrNotValidRequest
+	}
+
+	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+		ID:    int32(monitorID),
+		OrgID: orgId,
+	})
+	if err != nil {
+		return nil, util.ErrMonitorNotFound
+	}
+
+	totals, err := h.monitorSLATotals(ctx, monitor.ID, monitor.Interval, period)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := buildSLAStats(totals, slo)
+	return &SLAReport{
+		Scope:       "monitor",
+		Name:        monitor.Url,
+		PeriodStart: period.from.Format(time.RFC3339),
+		PeriodEnd:   period.to.Format(time.RFC3339),
+		Timezone:    period.location.String(),
+		SLOTarget:   slo,
+		Summary:     stats,
+		Monitors:    []MonitorSLA{{MonitorID: monitor.ID, Name: monitor.Url, SLAStats: stats}},
+	}, nil
+}
+
+// statusPageSLAReport reports every monitor shown on a status page, under
+// the names the page gives them
+func (h *Handler) statusPageSLAReport(ctx context.Context, orgId uuid.UUID, rawID string, period slaPeriod, slo float64) (*SLAReport, error) {
+	pageID, err := strconv.ParseInt(rawID, 10, 32)
+	if err != nil {
+		return nil, util.ErrNotValidRequest
+	}
+
+	page, err := h.store.GetStatusPageByID(ctx, db.GetStatusPageByIDParams{
+		ID:    int32(pageID),
+		OrgID: orgId,
+	})
+	if err != nil {
+		return nil, util.ErrStatusPageNotFound
+	}
+
+	pageMonitors, err := h.store.GetStatusPageMonitors(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	monitors := make([]db.Monitor, 0, len(pageMonitors))
+	names := make(map[int32]string, len(pageMonitors))
+	for _, pm := range pageMonitors {
+		monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+			ID:    pm.MonitorID,
+			OrgID: orgId,
+		})
+		if err != nil {
+			continue
+		}
+		monitors = append(monitors, monitor)
+		names[monitor.ID] = pm.DisplayName
+	}
+
+	return h.groupSLAReport(ctx, page.Title, monitors, names, period, slo)
+}
+
+// monitorGroupSLAReport reports every monitor of a monitor group
+func (h *Handler) monitorGroupSLAReport(ctx context.Context, orgId uuid.UUID, rawID string, period slaPeriod, slo float64) (*SLAReport, error) {
+	groupID, err := strconv.ParseInt(rawID, 10, 32)
+	if err != nil {
+		return nil, util.ErrNotValidRequest
+	}
+
+	group, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{
+		ID:    int32(groupID),
+		OrgID: orgId,
+	})
+	if err != nil {
+		return nil, util.ErrMonitorGroupNotFound
+	}
+
+	monitors, err := h.store.SearchOrgMonitors(ctx, db.SearchOrgMonitorsParams{
+		OrgID:   orgId,
+		GroupID: pgtype.Int4{Int32: group.ID, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.groupSLAReport(ctx, group.Name, monitors, nil, period, slo)
+}
+
+// groupSLAReport reports a set of monitors, named by names or else by URL.
+// The summary is time-weighted across monitors, not an average of their
+// percentages.
+func (h *Handler) groupSLAReport(ctx context.Context, name string, monitors []db.Monitor, names map[int32]string, period slaPeriod, slo float64) (*SLAReport, error) {
+	var total slaTotals
+	rows := make([]MonitorSLA, 0, len(monitors))
+	for _, monitor := range monitors {
+		totals, err := h.monitorSLATotals(ctx, monitor.ID, monitor.Interval, period)
+		if err != nil {
+			return nil, err
+		}
+		total.add(totals)
+
+		monitorName := names[monitor.ID]
+		if monitorName == "" {
+			monitorName = monitor.Url
+		}
+		rows = append(rows, MonitorSLA{
+			MonitorID: monitor.ID,
+			Name:      monitorName,
+			SLAStats:  buildSLAStats(totals, slo),
+		})
+	}
+
+	return &SLAReport{
+		Scope:       "group",
+		Name:        name,
+		PeriodStart: period.from.Format(time.RFC3339),
+		PeriodEnd:   period.to.Format(time.RFC3339),
+		Timezone:    period.location.String(),
+		SLOTarget:   slo,
+		Summary:     buildSLAStats(total, slo),
+		Monitors:    rows,
+	}, nil
+}
+
+// monitorSLATotals loads the daily rollups of the period, the incidents opened
+// in it and the maintenance windows, then does the accounting.
+// Time after now is not accounted at all.
+func (h *Handler) monitorSLATotals(ctx context.Context, monitorID int32, interval int32, period slaPeriod) (slaTotals, error) {
+	now := time.Now().UTC()
+	from := period.from.UTC()
+	to := period.to.UTC()
+	if to.After(now) {
+		to = now
+	}
+	if !to.After(from) {
+		return slaTotals{}, nil
+	}
+
+	rollups, err := h.store.GetMonitorRollups(ctx, db.GetMonitorRollupsParams{
+		MonitorID:  monitorID,
+		Resolution: db.RollupResolution1d,
+		Since:      pgtype.Timestamp{Time: from.Truncate(slaDayLength), Valid: true},
+		Until:      pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		return slaTotals{}, err
+	}
+
+	starts, err := h.store.GetIncidentStartsForMonitor(ctx, db.GetIncidentStartsForMonitorParams{
+		MonitorID:   monitorID,
+		PeriodStart: pgtype.Timestamp{Time: from, Valid: true},
+		PeriodEnd:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		return slaTotals{}, err
+	}
+
+	incidents := make([]time.Time, 0, len(starts))
+	for _, start := range starts {
+		incidents = append(incidents, start.Time)
+	}
+
+	windows, err := h.store.GetMaintenanceWindowsForMonitor(ctx, db.GetMaintenanceWindowsForMonitorParams{
+		MonitorID:   monitorID,
+		PeriodStart: pgtype.Timestamp{Time: from, Valid: true},
+		PeriodEnd:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		return slaTotals{}, err
+	}
+
+	maintenance := make([]timeWindow, 0, len(windows))
+	for _, win := range windows {
+		maintenance = append(maintenance, timeWindow{start: win.WindowStart.Time, end: win.WindowEnd.Time})
+	}
+
+	return computeSLA(toSLADays(rollups), incidents, maintenance, from, to, now, time.Duration(interval)*time.Second), nil
+}
+
+func buildSLAStats(t slaTotals, slo float64) SLAStats {
+	measured := t.up + t.down
+	stats := SLAStats{
+		MeasuredSeconds:    int64(measured.Seconds()),
+		UptimeSeconds:      int64(t.up.Seconds()),
+		DowntimeSeconds:    int64(t.down.Seconds()),
+		MaintenanceSeconds: int64(t.maintenance.Seconds()),
+		NoDataSeconds:      int64(t.noData.Seconds()),
+		Incidents:          t.incidents,
+	}
+
+	if measured > 0 {
+		uptime := t.up.Seconds() / measured.Seconds() * 100
+		stats.UptimePercent = &uptime
+		stats.SLOMet = uptime >= slo
+	}
+
+	if t.incidents > 0 {
+		mttr := int64(t.down.Seconds()) / int64(t.incidents)
+		mtbf := int64(t.up.Seconds()) / int64(t.incidents)
+		stats.MTTRSeconds = &mttr
+		stats.MTBFSeconds = &mtbf
+	}
+
+	budget := measured.Seconds() * (100 - slo) / 100
+	stats.ErrorBudgetSeconds = int64(budget)
+	stats.ErrorBudgetRemainingSeconds = int64(budget - t.down.Seconds())
+	if budget > 0 {
+		remaining := (budget - t.down.Seconds()) / budget * 100
+		stats.ErrorBudgetRemainingPercent = &remaining
+	}
+
+	return stats
+}
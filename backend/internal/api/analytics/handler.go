@@ -28,6 +28,7 @@ func (h *Handler) Routes() *chi.Mux {
 		r.Use(middleware.TokenMiddleware(h.store))
 
 		r.Get("/overview", h.GetOverview)
+		r.Get("/sla", h.GetSLAReport)
 	})
 
 	return router
@@ -0,0 +1,175 @@
+package analytics
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"math"
+	"sort"
+	"time"
+)
+
+// slaDayLength is the resolution of the rollups the SLA is computed from
+const slaDayLength = 24 * time.Hour
+
+type timeWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// slaDay is the daily rollup of one monitor
+type slaDay struct {
+	start   time.Time
+	checks  int32
+	success int32
+}
+
+// slaTotals is the raw, time-weighted accounting of one monitor over a period
+type slaTotals struct {
+	up          time.Duration
+	down        time.Duration
+	maintenance time.Duration
+	noData      time.Duration
+	incidents   int
+}
+
+func (t *slaTotals) add(o slaTotals) {
+	t.up += o.up
+	t.down += o.down
+	t.maintenance += o.maintenance
+	t.noData += o.noData
+	t.incidents += o.incidents
+}
+
+func toSLADays(rows []db.MonitorRollup) []slaDay {
+	days := make([]slaDay, 0, len(rows))
+	for _, row := range rows {
+		days = append(days, slaDay{
+			start:   row.BucketStart.Time,
+			checks:  row.CheckCount,
+			success: row.SuccessCount,
+		})
+	}
+	return days
+}
+
+// computeSLA attributes every instant of [from, to) to exactly one of up, down,
+// maintenance or no data, one daily rollup at a time. The checks of a day are
+// taken as evenly spread: the share of the day they cover (checks × interval)
+// is split between up and down by the day's success ratio, the rest has no data.
+// Incidents are the ones opened in the period outside maintenance.
+func computeSLA(days []slaDay, incidents []time.Time, maintenance []timeWindow, from, to, now time.Time, interval time.Duration) slaTotals {
+	var totals slaTotals
+	if !to.After(from) {
+		return totals
+	}
+
+	maintenance = mergeWindows(maintenance)
+
+	byStart := make(map[time.Time]slaDay, len(days))
+	for _, d := range days {
+		byStart[d.start] = d
+	}
+
+	for dayStart := from.Truncate(slaDayLength); dayStart.Before(to); dayStart = dayStart.Add(slaDayLength) {
+		dayEnd := dayStart.Add(slaDayLength)
+		start, end := dayStart, dayEnd
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		inMaintenance := overlap(start, end, maintenance)
+		totals.maintenance += inMaintenance
+		effective := end.Sub(start) - inMaintenance
+
+		day, ok := byStart[dayStart]
+		if !ok || day.checks == 0 {
+			totals.noData += effective
+			continue
+		}
+
+		// Today's checks only cover the part of it that has passed
+		elapsed := slaDayLength
+		if now.Before(dayEnd) {
+			elapsed = now.Sub(dayStart)
+		}
+		covered := 1.0
+		if elapsed > 0 {
+			covered = math.Min(1, float64(day.checks)*float64(interval)/float64(elapsed))
+		}
+
+		measured := time.Duration(float64(effective) * covered)
+		up := time.Duration(float64(measured) * float64(day.success) / float64(day.checks))
+		totals.up += up
+		totals.down += measured - up
+		totals.noData += effective - measured
+	}
+
+	for _, at := range incidents {
+		if !at.Before(from) && at.Before(to) && !inWindows(at, maintenance) {
+			totals.incidents++
+		}
+	}
+
+	return totals
+}
+
+// inWindows reports whether t falls inside one of the windows
+func inWindows(t time.Time, windows []timeWindow) bool {
+	for _, w := range windows {
+		if !t.Before(w.start) && t.Before(w.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeWindows sorts windows and joins the ones that overlap
+func mergeWindows(windows []timeWindow) []timeWindow {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	sorted := make([]timeWindow, 0, len(windows))
+	for _, w := range windows {
+		if w.end.After(w.start) {
+			sorted = append(sorted, w)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	merged := make([]timeWindow, 0, len(sorted))
+	for _, w := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && !w.start.After(merged[last].end) {
+			if w.end.After(merged[last].end) {
+				merged[last].end = w.end
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// overlap returns how much of [start, end) is covered by the merged windows
+func overlap(start, end time.Time, windows []timeWindow) time.Duration {
+	var total time.Duration
+	for _, w := range windows {
+		s, e := w.start, w.end
+		if s.Before(start) {
+			s = start
+		}
+		if e.After(end) {
+			e = end
+		}
+		if e.After(s) {
+			total += e.Sub(s)
+		}
+	}
+	return total
+}
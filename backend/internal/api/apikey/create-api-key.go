@@ -0,0 +1,93 @@
+package apikey
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateAPIKey issues a key for the user. The key is only returned here; the
+// database keeps its hash.
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var scopes []string
+	for _, scope := range req.Scopes {
+		if !slices.Contains(middleware.Scopes, scope) {
+			util.ErrorJson(w, util.ErrInvalidScope)
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	var orgId pgtype.UUID
+	if req.OrgScoped {
+		if !middleware.RoleAtLeast(db.OrgRole(payload.OrgRole), db.OrgRoleAdmin) {
+			util.ErrorJson(w, util.ErrInsufficientRole)
+			return
+		}
+		orgId = pgtype.UUID{Bytes: payload.OrgId, Valid: true}
+	}
+
+	secret, err := util.RandomToken(32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrTokenGenError)
+		return
+	}
+	key := middleware.APIKeyPrefix + secret
+
+	var expiresAt pgtype.Timestamp
+	if req.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamp{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+
+	apiKey, err := h.store.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		UserID:    payload.UserId,
+		OrgID:     orgId,
+		Name:      req.Name,
+		Prefix:    key[:len(middleware.APIKeyPrefix)+8],
+		KeyHash:   middleware.HashAPIKey(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceAPIKey,
+		ResourceID:   apiKey.ID,
+		After:        apiKeyResponse(apiKey),
+	})
+
+	util.WriteJson(w, http.StatusCreated, CreateAPIKeyResponse{
+		APIKeyResponse: apiKeyResponse(apiKey),
+		Key:            key,
+	})
+}
@@ -0,0 +1,39 @@
+package apikey
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetAPIKeys lists the user's active keys and, for admins, the org-scoped keys
+// of the active organization
+func (h *Handler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	keys, err := h.store.ListAPIKeys(ctx, db.ListAPIKeysParams{
+		UserID:     payload.UserId,
+		IncludeOrg: middleware.RoleAtLeast(db.OrgRole(payload.OrgRole), db.OrgRoleAdmin),
+		OrgID:      pgtype.UUID{Bytes: payload.OrgId, Valid: true},
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	resp := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, apiKeyResponse(key))
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
@@ -0,0 +1,39 @@
+package apikey
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config *config.Config
+	store  db.Store
+}
+
+func NewHandler(config *config.Config, store db.Store) *Handler {
+	return &Handler{
+		config: config,
+		store:  store,
+	}
+}
+
+// Routes manage API keys. They need a signed-in user: a key cannot mint or
+// revoke keys.
+func (h *Handler) Routes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenMiddleware(h.store))
+		r.Use(middleware.RequireSession)
+
+		r.Post("/", h.CreateAPIKey)
+		r.Get("/", h.GetAPIKeys)
+		r.Delete("/{id}", h.RevokeAPIKey)
+	})
+
+	return router
+}
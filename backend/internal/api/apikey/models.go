@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=120"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,required"`
+	// ExpiresAt is optional, keys without it work until revoked
+	ExpiresAt *time.Time `json:"expires_at"`
+	// OrgScoped pins the key to the active organization; otherwise it acts in
+	// any organization of the user, chosen with the X-Org-ID header
+	OrgScoped bool `json:"org_scoped"`
+}
+
+type APIKeyResponse struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	OrgID      *uuid.UUID `json:"org_id"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is the only time the key itself is returned
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+func apiKeyResponse(key db.ApiKey) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt.Time,
+	}
+	if key.OrgID.Valid {
+		orgId := uuid.UUID(key.OrgID.Bytes)
+		resp.OrgID = &orgId
+	}
+	if key.ExpiresAt.Valid {
+		resp.ExpiresAt = &key.ExpiresAt.Time
+	}
+	if key.LastUsedAt.Valid {
+		resp.LastUsedAt = &key.LastUsedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,57 @@
+package apikey
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RevokeAPIKey disables a key for good. Users revoke their own keys; admins
+// also revoke the org-scoped keys of their organization.
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	key, err := h.store.GetAPIKey(ctx, int32(id))
+	if err != nil {
+		util.ErrorJson(w, util.ErrAPIKeyNotFound)
+		return
+	}
+
+	orgAdmin := key.OrgID.Valid && key.OrgID.Bytes == payload.OrgId &&
+		middleware.RoleAtLeast(db.OrgRole(payload.OrgRole), db.OrgRoleAdmin)
+	if key.UserID != payload.UserId && !orgAdmin {
+		util.ErrorJson(w, util.ErrAPIKeyNotFound)
+		return
+	}
+
+	if err := h.store.RevokeAPIKey(ctx, key.ID); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceAPIKey,
+		ResourceID:   key.ID,
+		Before:       apiKeyResponse(key),
+	})
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
@@ -0,0 +1,34 @@
+package artifact
+
+import (
+	"better-uptime/common/routes"
+	"better-uptime/common/storage"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config    *config.Config
+	store     db.Store
+	artifacts storage.Storage
+}
+
+func NewHandler(config *config.Config, store db.Store, artifacts storage.Storage) *Handler {
+	return &Handler{
+		config:    config,
+		store:     store,
+		artifacts: artifacts,
+	}
+}
+
+// PublicRoutes serve artifacts kept by the local storage driver. The signature
+// in the query string is the only credential.
+func (h *Handler) PublicRoutes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Get("/*", h.ServeArtifact)
+
+	return router
+}
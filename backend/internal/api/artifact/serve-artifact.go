@@ -0,0 +1,44 @@
+package artifact
+
+import (
+	"better-uptime/common/storage"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ServeArtifact streams a locally stored artifact for a valid, unexpired signed URL
+func (h *Handler) ServeArtifact(w http.ResponseWriter, r *http.Request) {
+	local, ok := h.artifacts.(*storage.Local)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	key := chi.URLParam(r, "*")
+	file, err := local.Open(key, r.URL.Query().Get("expires"), r.URL.Query().Get("sig"))
+	switch {
+	case errors.Is(err, storage.ErrInvalidSignature):
+		http.Error(w, "Link expired or invalid", http.StatusForbidden)
+		return
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, storage.ErrInvalidKey):
+		http.NotFound(w, r)
+		return
+	case err != nil:
+		http.Error(w, "Artifact unavailable", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "Artifact unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	http.ServeContent(w, r, path.Base(key), info.ModTime(), file)
+}
@@ -0,0 +1,128 @@
+package auditlog
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// GetAuditLogs lists audit log entries of the active organization, newest
+// first. Filters: resource_type, resource_id, action, user_id, api_key_id and
+// from/to as RFC 3339 times or dates; paginated with limit and offset.
+func (h *Handler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	params := db.ListAuditLogsParams{
+		OrgID:        payload.OrgId,
+		ResourceType: optionalText(query.Get("resource_type")),
+		ResourceID:   optionalText(query.Get("resource_id")),
+		Action:       optionalText(query.Get("action")),
+		RowLimit:     defaultLimit,
+	}
+
+	if v := query.Get("user_id"); v != "" {
+		userId, err := uuid.Parse(v)
+		if err != nil {
+			util.ErrorJson(w, util.ErrInvalidUuid)
+			return
+		}
+		params.UserID = pgtype.UUID{Bytes: userId, Valid: true}
+	}
+	if v := query.Get("api_key_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			util.ErrorJson(w, util.ErrNotValidRequest)
+			return
+		}
+		params.ApiKeyID = pgtype.Int4{Int32: int32(id), Valid: true}
+	}
+	if params.FromTime, err = parseTime(query.Get("from"), false); err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+	if params.ToTime, err = parseTime(query.Get("to"), true); err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			util.ErrorJson(w, util.ErrNotValidRequest)
+			return
+		}
+		params.RowLimit = int32(min(limit, maxLimit))
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || offset < 0 {
+			util.ErrorJson(w, util.ErrNotValidRequest)
+			return
+		}
+		params.RowOffset = int32(offset)
+	}
+
+	// One extra row tells whether there is another page
+	limit := params.RowLimit
+	params.RowLimit++
+	entries, err := h.store.ListAuditLogs(ctx, params)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	hasMore := len(entries) > int(limit)
+	if hasMore {
+		entries = entries[:limit]
+	}
+
+	resp := AuditLogsResponse{
+		Entries:    make([]AuditLogResponse, 0, len(entries)),
+		Pagination: Pagination{Limit: limit, Offset: params.RowOffset, HasMore: hasMore},
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, auditLogResponse(entry))
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func optionalText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+// parseTime accepts RFC 3339 times and plain dates. With endOfDay a date
+// includes the whole day, so that from and to can both be the same date.
+func parseTime(s string, endOfDay bool) (pgtype.Timestamp, error) {
+	if s == "" {
+		return pgtype.Timestamp{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return pgtype.Timestamp{Time: t.UTC(), Valid: true}, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return pgtype.Timestamp{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return pgtype.Timestamp{Time: t, Valid: true}, nil
+}
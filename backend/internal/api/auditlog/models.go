@@ -0,0 +1,61 @@
+package auditlog
+
+import (
+	"better-uptime/common/jsondiff"
+	db "better-uptime/internal/db/sqlc"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogResponse struct {
+	ID           int64             `json:"id"`
+	Action       string            `json:"action"`
+	ResourceType string            `json:"resource_type"`
+	ResourceID   string            `json:"resource_id"`
+	UserID       *uuid.UUID        `json:"user_id"`
+	ActorEmail   string            `json:"actor_email"`
+	APIKeyID     *int32            `json:"api_key_id"`
+	IP           string            `json:"ip"`
+	UserAgent    string            `json:"user_agent"`
+	Before       json.RawMessage   `json:"before"`
+	After        json.RawMessage   `json:"after"`
+	Changes      []jsondiff.Change `json:"changes"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+type Pagination struct {
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+	HasMore bool  `json:"has_more"`
+}
+
+type AuditLogsResponse struct {
+	Entries    []AuditLogResponse `json:"entries"`
+	Pagination Pagination         `json:"pagination"`
+}
+
+func auditLogResponse(entry db.AuditLog) AuditLogResponse {
+	resp := AuditLogResponse{
+		ID:           entry.ID,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		ActorEmail:   entry.ActorEmail,
+		IP:           entry.Ip,
+		UserAgent:    entry.UserAgent,
+		Before:       entry.Before,
+		After:        entry.After,
+		Changes:      jsondiff.Diff(entry.Before, entry.After),
+		CreatedAt:    entry.CreatedAt.Time,
+	}
+	if entry.UserID.Valid {
+		userId := uuid.UUID(entry.UserID.Bytes)
+		resp.UserID = &userId
+	}
+	if entry.ApiKeyID.Valid {
+		resp.APIKeyID = &entry.ApiKeyID.Int32
+	}
+	return resp
+}
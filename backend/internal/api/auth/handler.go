@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"better-uptime/common/identity"
 	"better-uptime/common/middleware"
 	"better-uptime/common/routes"
 	"better-uptime/config"
@@ -32,6 +33,17 @@ func (h *Handler) Routes() *chi.Mux {
 	router := routes.DefaultRouter()
 	router.Post("/login", h.Login)
 
+	// Email/password sign-in, when the local auth provider is configured
+	if identity.Local() != nil {
+		router.Route("/local", func(r chi.Router) {
+			r.Post("/register", h.LocalRegister)
+			r.Post("/verify-email", h.VerifyEmail)
+			r.Post("/login", h.LocalLogin)
+			r.Post("/refresh", h.RefreshToken)
+			r.Post("/logout", h.Logout)
+		})
+	}
+
 	router.Group(func(r chi.Router) {
 		r.Use(middleware.TokenMiddleware(h.store))
 		r.Get("/user-details", h.UserDetails)
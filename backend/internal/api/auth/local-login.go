@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"better-uptime/common/util"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash is compared against when the email is unknown, so response times
+// don't tell which accounts exist
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("better-uptime"), bcrypt.DefaultCost)
+
+// LocalLogin signs in with email and password, returning an access token and
+// a refresh token
+func (h *Handler) LocalLogin(w http.ResponseWriter, r *http.Request) {
+	var req localLoginRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.store.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil || !user.PasswordHash.Valid {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(req.Password))
+		util.ErrorJson(w, util.ErrInvalidCredentials)
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash.String), []byte(req.Password)); err != nil {
+		util.ErrorJson(w, util.ErrInvalidCredentials)
+		return
+	}
+	if !user.EmailVerified {
+		util.ErrorJson(w, util.ErrEmailNotVerified)
+		return
+	}
+
+	tokens, err := h.issueTokens(ctx, user.ID, user.Email, user.Fullname, uuid.New())
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, tokens)
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"better-uptime/common/email"
+	"better-uptime/common/identity"
+	"better-uptime/common/logger"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// emailVerificationTTL is how long the link in the verification email works
+const emailVerificationTTL = 24 * time.Hour
+
+// LocalRegister creates an email/password account and emails a link to
+// confirm the address. The account can't sign in until it is confirmed, and
+// until then signing up again with the email replaces it.
+func (h *Handler) LocalRegister(w http.ResponseWriter, r *http.Request) {
+	if !h.config.AUTH_LOCAL_SIGNUP {
+		util.ErrorJson(w, util.ErrSignupDisabled)
+		return
+	}
+
+	var req localRegisterRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	address := strings.ToLower(strings.TrimSpace(req.Email))
+	fullname := strings.TrimSpace(req.FullName)
+	if fullname == "" {
+		fullname, _, _ = strings.Cut(address, "@")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	token, err := util.RandomToken(32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrTokenGenError)
+		return
+	}
+
+	ctx := r.Context()
+	// Verified accounts, from any provider, are never taken over by signing up
+	user, err := h.store.CreateLocalUser(ctx, db.CreateLocalUserParams{
+		Email:                      address,
+		Fullname:                   fullname,
+		PasswordHash:               pgtype.Text{String: string(hash), Valid: true},
+		EmailVerificationHash:      pgtype.Text{String: identity.HashToken(token), Valid: true},
+		EmailVerificationExpiresAt: pgtype.Timestamp{Time: time.Now().Add(emailVerificationTTL), Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		util.ErrorJson(w, util.ErrUserAlreadyExsists)
+		return
+	}
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	verifyURL := strings.TrimRight(h.config.APP_BASE_URL, "/") + "/verify-email?token=" + url.QueryEscape(token)
+	go func() {
+		if err := email.SendEmailVerification(user.Email, verifyURL, "24 hours"); err != nil {
+			logger.Error("Failed to send verification email to user %s: %v", user.ID, err)
+		}
+	}()
+
+	util.WriteJson(w, http.StatusAccepted, map[string]string{"message": "Check your email to confirm your address"})
+}
+
+// VerifyEmail confirms the address of a local sign-up with the token from the
+// verification email and signs it in
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req verifyEmailRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	user, err := h.store.VerifyUserEmail(ctx, pgtype.Text{String: identity.HashToken(req.Token), Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		util.ErrorJson(w, util.ErrInvalidVerificationLink)
+		return
+	}
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	tokens, err := h.issueTokens(ctx, user.ID, user.Email, user.Fullname, uuid.New())
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, tokens)
+}
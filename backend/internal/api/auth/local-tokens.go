@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"better-uptime/common/identity"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// issueTokens signs an access token and stores a new refresh token in family
+func (h *Handler) issueTokens(ctx context.Context, userID uuid.UUID, email string, fullname string, family uuid.UUID) (tokenResponse, error) {
+	local := identity.Local()
+
+	accessToken, expiresAt, err := local.IssueAccessToken(userID, email, fullname)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+
+	refreshToken, hash, refreshExpiresAt, err := local.NewRefreshToken()
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	if _, err := h.store.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:    userID,
+		FamilyID:  family,
+		TokenHash: hash,
+		ExpiresAt: pgtype.Timestamp{Time: refreshExpiresAt, Valid: true},
+	}); err != nil {
+		return tokenResponse{}, err
+	}
+
+	return tokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(expiresAt).Seconds()),
+	}, nil
+}
@@ -1,16 +1,16 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
-	"better-uptime/common/firebase"
+	"better-uptime/common/identity"
 	"better-uptime/common/logger"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -30,33 +30,19 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	idToken := parts[1]
 
 	ctx := r.Context()
-	var payload firebase.FirebasePayload
-	var err error
-	// var userID uuid.UUID
-	// For frontend testing bypass
-	if idToken == "frontend" {
-		payload = firebase.FirebasePayload{
-			Email:    "test@example.com",
-			UserId:   uuid.MustParse("0b927d97-782a-4c82-b9d2-e4e06774ed37"),
-			UID:      "0b927d97-782a-4c82-b9d2-e4e06774ed37",
-			Phone:    "9876543210",
-			Provider: "password",
-		}
-		// userID = payload.UserId
-	} else {
-		payload, err = firebase.VerifyFirebaseIDToken(ctx, idToken)
-		if err != nil {
-			logger.Error("Firebase token verification failed: %v", err)
-			if err.Error() == "firebase auth client not initialized" {
-				util.ErrorJson(w, fmt.Errorf("authentication service not configured. Please contact administrator"))
-			} else {
-				util.ErrorJson(w, fmt.Errorf("invalid or expired token: %v", err))
-			}
-			return
+	payload, err := identity.Verify(ctx, idToken)
+	if err != nil {
+		logger.Error("Auth token verification failed: %v", err)
+		if errors.Is(err, identity.ErrNoProvider) {
+			util.ErrorJson(w, util.ErrInvalidToken)
+		} else if strings.HasSuffix(err.Error(), "firebase auth client not initialized") {
+			util.ErrorJson(w, fmt.Errorf("authentication service not configured. Please contact administrator"))
+		} else {
+			util.ErrorJson(w, fmt.Errorf("invalid or expired token: %v", err))
 		}
-		// userID = uuid.Nil
-		logger.Info("Firebase token verified successfully for email: %s", payload.Email)
+		return
 	}
+	logger.Info("Auth token verified successfully for email: %s", payload.Email)
 
 	var req authRequest
 	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
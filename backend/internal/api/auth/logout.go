@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"better-uptime/common/identity"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// Logout revokes the refresh token and every token rotated from it. Access
+// tokens already issued stay valid until they expire.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req refreshTokenRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	token, err := h.store.GetRefreshTokenByHash(ctx, identity.HashToken(req.RefreshToken))
+	if err != nil {
+		util.ErrorJson(w, util.ErrInvalidToken)
+		return
+	}
+
+	if err := h.store.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Signed out"})
+}
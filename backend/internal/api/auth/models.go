@@ -16,3 +16,29 @@ type authResponse struct {
 	// Phone            string `json:"phone"`
 	// ActiveMembership bool `json:"active_membership"`
 }
+
+type localRegisterRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	FullName string `json:"full_name"`
+}
+
+type localLoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"better-uptime/common/identity"
+	"better-uptime/common/logger"
+	"better-uptime/common/util"
+	"net/http"
+	"time"
+)
+
+// RefreshToken exchanges a refresh token for a new access token and refresh
+// token. Each refresh token works once; using one again means it leaked, so
+// every token descended from the same sign-in is revoked.
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req refreshTokenRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	token, err := h.store.GetRefreshTokenByHash(ctx, identity.HashToken(req.RefreshToken))
+	if err != nil || !token.ExpiresAt.Time.After(time.Now()) {
+		util.ErrorJson(w, util.ErrInvalidToken)
+		return
+	}
+
+	revoked := int64(0)
+	if !token.RevokedAt.Valid {
+		revoked, err = h.store.RevokeRefreshToken(ctx, token.ID)
+		if err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+	}
+	if revoked == 0 {
+		logger.Error("Refresh token reused, revoking its sign-in for user %s", token.UserID)
+		if err := h.store.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+			logger.Error("Failed to revoke refresh token family %s: %v", token.FamilyID, err)
+		}
+		util.ErrorJson(w, util.ErrInvalidToken)
+		return
+	}
+
+	user, err := h.store.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		util.ErrorJson(w, util.ErrInvalidToken)
+		return
+	}
+
+	tokens, err := h.issueTokens(ctx, user.ID, user.Email, user.Fullname, token.FamilyID)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, tokens)
+}
@@ -0,0 +1,90 @@
+package badge
+
+import (
+	"container/list"
+	"crypto/sha1"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// badgeCacheTTL is how long a rendered SVG is reused before the uptime is queried again
+const badgeCacheTTL = 60 * time.Second
+
+// badgeCacheSize bounds the number of rendered SVGs kept in memory
+const badgeCacheSize = 10000
+
+type cachedSVG struct {
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+type cacheItem struct {
+	key   string
+	entry cachedSVG
+}
+
+// svgCache is a small in-memory LRU cache of rendered badges keyed by token
+// and query. The least recently used entry is evicted once it is full.
+type svgCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newSVGCache(ttl time.Duration, size int) *svgCache {
+	return &svgCache{
+		ttl:     ttl,
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *svgCache) get(key string) (cachedSVG, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return cachedSVG{}, false
+	}
+	item := elem.Value.(*cacheItem)
+	if time.Now().After(item.entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return cachedSVG{}, false
+	}
+	c.order.MoveToFront(elem)
+	return item.entry, true
+}
+
+func (c *svgCache) set(key string, body []byte) cachedSVG {
+	sum := sha1.Sum(body)
+	entry := cachedSVG{
+		body:      body,
+		etag:      `"` + hex.EncodeToString(sum[:8]) + `"`,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheItem).entry = entry
+		c.order.MoveToFront(elem)
+		return entry
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheItem{key: key, entry: entry})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheItem).key)
+	}
+
+	return entry
+}
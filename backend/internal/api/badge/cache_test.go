@@ -0,0 +1,41 @@
+package badge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSVGCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newSVGCache(time.Minute, 2)
+	cache.set("a", []byte("<svg>a</svg>"))
+	cache.set("b", []byte("<svg>b</svg>"))
+
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("a was not cached")
+	}
+	cache.set("c", []byte("<svg>c</svg>"))
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("b was kept, it is the least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.get(key); !ok {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+	if len(cache.entries) != 2 || cache.order.Len() != 2 {
+		t.Errorf("cache holds %d entries, want 2", len(cache.entries))
+	}
+}
+
+func TestSVGCacheExpires(t *testing.T) {
+	cache := newSVGCache(-time.Second, 10)
+	cache.set("a", []byte("<svg>a</svg>"))
+
+	if _, ok := cache.get("a"); ok {
+		t.Error("expired entry was returned")
+	}
+	if len(cache.entries) != 0 {
+		t.Error("expired entry was not removed")
+	}
+}
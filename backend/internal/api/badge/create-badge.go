@@ -0,0 +1,87 @@
+package badge
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CreateBadge issues a badge token for the monitor. Calling it again rotates
+// the token, which invalidates every previously embedded badge.
+func (h *Handler) CreateBadge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	monitor, err := h.getOwnedMonitor(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	token, err := util.RandomToken(20)
+	if err != nil {
+		util.ErrorJson(w, util.ErrTokenGenError)
+		return
+	}
+
+	badge, err := h.store.UpsertMonitorBadge(ctx, db.UpsertMonitorBadgeParams{
+		MonitorID: monitor.ID,
+		Token:     token,
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	// The token is a credential, so only the rotation itself is recorded
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceBadge,
+		ResourceID:   monitor.ID,
+		After:        map[string]int32{"monitor_id": monitor.ID},
+	})
+
+	util.WriteJson(w, http.StatusCreated, h.badgeResponse(badge))
+}
+
+// getOwnedMonitor loads the monitor from the {id} URL param, scoped to the organization
+func (h *Handler) getOwnedMonitor(r *http.Request, orgId uuid.UUID) (db.Monitor, error) {
+	monitorID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		return db.Monitor{}, util.ErrNotValidRequest
+	}
+
+	monitor, err := h.store.GetMonitorByID(r.Context(), db.GetMonitorByIDParams{
+		ID:    int32(monitorID),
+		OrgID: orgId,
+	})
+	if err != nil {
+		return db.Monitor{}, util.ErrMonitorNotFound
+	}
+
+	return monitor, nil
+}
+
+func (h *Handler) badgeResponse(badge db.MonitorBadge) BadgeResponse {
+	base := strings.TrimRight(h.config.PUBLIC_BASE_URL, "/") + "/v1/public/badges/" + badge.Token
+	return BadgeResponse{
+		MonitorID: badge.MonitorID,
+		Token:     badge.Token,
+		StatusURL: base + "/status.svg",
+		UptimeURL: base + "/uptime.svg",
+		CreatedAt: badge.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,39 @@
+package badge
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// DeleteBadge revokes the monitor's badge token
+func (h *Handler) DeleteBadge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	monitor, err := h.getOwnedMonitor(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	if err := h.store.DeleteMonitorBadge(ctx, monitor.ID); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceBadge,
+		ResourceID:   monitor.ID,
+		Before:       map[string]int32{"monitor_id": monitor.ID},
+	})
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Badge revoked"})
+}
@@ -0,0 +1,32 @@
+package badge
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// GetBadge returns the monitor's current badge token and embed URLs
+func (h *Handler) GetBadge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	monitor, err := h.getOwnedMonitor(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	badge, err := h.store.GetMonitorBadge(ctx, monitor.ID)
+	if err != nil {
+		util.ErrorJson(w, util.ErrBadgeNotFound)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, h.badgeResponse(badge))
+}
@@ -0,0 +1,51 @@
+package badge
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config *config.Config
+	store  db.Store
+	cache  *svgCache
+}
+
+func NewHandler(config *config.Config, store db.Store) *Handler {
+	return &Handler{
+		config: config,
+		store:  store,
+		cache:  newSVGCache(badgeCacheTTL, badgeCacheSize),
+	}
+}
+
+// Routes let a monitor owner issue, look up and revoke the badge token
+func (h *Handler) Routes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenMiddleware(h.store))
+		r.Use(middleware.RequireRoleForWrites(db.OrgRoleEditor))
+		r.Use(middleware.RequireScopeForWrites(middleware.ScopeMonitorsWrite))
+
+		r.Post("/monitors/{id}", h.CreateBadge)
+		r.Get("/monitors/{id}", h.GetBadge)
+		r.Delete("/monitors/{id}", h.DeleteBadge)
+	})
+
+	return router
+}
+
+// PublicRoutes serve the SVGs, the token in the path is the only credential
+func (h *Handler) PublicRoutes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Get("/{token}/status.svg", h.StatusBadge)
+	router.Get("/{token}/uptime.svg", h.UptimeSparkline)
+
+	return router
+}
@@ -0,0 +1,9 @@
+package badge
+
+type BadgeResponse struct {
+	MonitorID int32  `json:"monitor_id"`
+	Token     string `json:"token"`
+	StatusURL string `json:"status_url"`
+	UptimeURL string `json:"uptime_url"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,190 @@
+package badge
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultBadgeDays = 30
+	maxBadgeDays     = 90
+	maxLabelLength   = 40
+)
+
+type dailyUptime struct {
+	date   string
+	uptime *float64
+}
+
+// StatusBadge renders "<label>: <status> | <uptime>" for the badge's monitor
+func (h *Handler) StatusBadge(w http.ResponseWriter, r *http.Request) {
+	label := requestedLabel(r)
+	h.serveSVG(w, r, "status", label, func(ctx context.Context, monitor db.GetBadgeMonitorByTokenRow, days int) ([]byte, error) {
+		daily, err := h.dailyUptime(ctx, monitor.ID, days)
+		if err != nil {
+			return nil, err
+		}
+
+		var total, successful int64
+		for _, d := range daily {
+			total += d.TotalChecks
+			successful += d.SuccessfulChecks
+		}
+
+		status, color := badgeStatus(monitor)
+		message := status
+		if total > 0 {
+			uptime := float64(successful) / float64(total) * 100
+			message += " | " + strconv.FormatFloat(uptime, 'f', 2, 64) + "%"
+		}
+
+		if label == "" {
+			return renderBadge(defaultLabel(monitor.Url), message, color), nil
+		}
+		return renderBadge(label, message, color), nil
+	})
+}
+
+// requestedLabel is the trimmed and truncated label query parameter, so every
+// label that renders the same badge shares a cache entry
+func requestedLabel(r *http.Request) string {
+	label := strings.TrimSpace(r.URL.Query().Get("label"))
+	if len([]rune(label)) > maxLabelLength {
+		label = string([]rune(label)[:maxLabelLength])
+	}
+	return label
+}
+
+// UptimeSparkline renders one bar per day of uptime for the badge's monitor
+func (h *Handler) UptimeSparkline(w http.ResponseWriter, r *http.Request) {
+	h.serveSVG(w, r, "uptime", "", func(ctx context.Context, monitor db.GetBadgeMonitorByTokenRow, days int) ([]byte, error) {
+		daily, err := h.dailyUptime(ctx, monitor.ID, days)
+		if err != nil {
+			return nil, err
+		}
+
+		byDay := make(map[string]db.GetDailyUptimeForMonitorsRow, len(daily))
+		for _, row := range daily {
+			byDay[row.Day.Time.Format("2006-01-02")] = row
+		}
+
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		bars := make([]dailyUptime, 0, days)
+		for d := today.AddDate(0, 0, -(days - 1)); !d.After(today); d = d.AddDate(0, 0, 1) {
+			key := d.Format("2006-01-02")
+			bar := dailyUptime{date: key}
+			if row, ok := byDay[key]; ok && row.TotalChecks > 0 {
+				pct := float64(row.SuccessfulChecks) / float64(row.TotalChecks) * 100
+				bar.uptime = &pct
+			}
+			bars = append(bars, bar)
+		}
+
+		return renderSparkline(bars), nil
+	})
+}
+
+// serveSVG resolves the token, answers from cache when possible and honours If-None-Match.
+// variant is whatever else changes the rendered SVG, like the normalized label.
+func (h *Handler) serveSVG(w http.ResponseWriter, r *http.Request, kind string, variant string, render func(context.Context, db.GetBadgeMonitorByTokenRow, int) ([]byte, error)) {
+	token := chi.URLParam(r, "token")
+	days := parseDays(r.URL.Query().Get("days"))
+	key := kind + ":" + token + ":" + strconv.Itoa(days) + ":" + variant
+
+	entry, ok := h.cache.get(key)
+	if !ok {
+		monitor, err := h.store.GetBadgeMonitorByToken(r.Context(), token)
+		if err != nil {
+			http.Error(w, "Badge not found", http.StatusNotFound)
+			return
+		}
+
+		body, err := render(r.Context(), monitor, days)
+		if err != nil {
+			log.Printf("failed to render %s badge: %v", kind, err)
+			http.Error(w, "Badge unavailable", http.StatusInternalServerError)
+			return
+		}
+		entry = h.cache.set(key, body)
+	}
+
+	w.Header().Set("ETag", entry.etag)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	if etagMatches(r.Header.Get("If-None-Match"), entry.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(entry.body)
+}
+
+func (h *Handler) dailyUptime(ctx context.Context, monitorID int32, days int) ([]db.GetDailyUptimeForMonitorsRow, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return h.store.GetDailyUptimeForMonitors(ctx, db.GetDailyUptimeForMonitorsParams{
+		MonitorIds: []int32{monitorID},
+		Since:      pgtype.Timestamp{Time: today.AddDate(0, 0, -(days - 1)), Valid: true},
+		Today:      pgtype.Timestamp{Time: today, Valid: true},
+	})
+}
+
+func badgeStatus(monitor db.GetBadgeMonitorByTokenRow) (string, string) {
+	if monitor.IsActive.Valid && !monitor.IsActive.Bool {
+		return "paused", colorUnknown
+	}
+	if !monitor.Status.Valid {
+		return "unknown", colorUnknown
+	}
+
+	switch monitor.Status.MonitorStatus {
+	case db.MonitorStatusUp:
+		return "up", colorUp
+	case db.MonitorStatusDown:
+		return "down", colorDown
+	default:
+		return "unknown", colorUnknown
+	}
+}
+
+func parseDays(raw string) int {
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 1 {
+		return defaultBadgeDays
+	}
+	if days > maxBadgeDays {
+		return maxBadgeDays
+	}
+	return days
+}
+
+// defaultLabel uses the monitored host so the URL path and query stay private
+func defaultLabel(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "status"
+	}
+	return parsed.Hostname()
+}
+
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+)
+
+const (
+	colorUp      = "#4c1"
+	colorDegrade = "#dfb317"
+	colorDown    = "#e05d44"
+	colorUnknown = "#9f9f9f"
+)
+
+// textWidth roughly estimates the rendered width of 11px Verdana text
+func textWidth(s string) int {
+	return len([]rune(s))*7 + 10
+}
+
+// renderBadge draws a flat two-part badge, e.g. "api: up | 99.98%"
+func renderBadge(label, message, color string) []byte {
+	labelWidth := textWidth(label)
+	messageWidth := textWidth(message)
+	width := labelWidth + messageWidth
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, message)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, label, message)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	b.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="#555"/>`, labelWidth)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" fill="%s"/>`, labelWidth, messageWidth, color)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" fill="url(#s)"/>`, width)
+	b.WriteString(`</g>`)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`, labelWidth+messageWidth/2, message)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, message)
+	b.WriteString(`</g></svg>`)
+
+	return b.Bytes()
+}
+
+const (
+	sparkBarWidth = 4
+	sparkBarGap   = 1
+	sparkHeight   = 20
+	// Uptime at or below sparkFloor is drawn as the shortest bar
+	sparkFloor = 90.0
+)
+
+// renderSparkline draws one bar per day, scaled and colored by that day's uptime.
+// Days without checks are drawn as short grey bars.
+func renderSparkline(days []dailyUptime) []byte {
+	width := len(days) * (sparkBarWidth + sparkBarGap)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="uptime">`, width, sparkHeight)
+	for i, d := range days {
+		height, color := 3.0, colorUnknown
+		if d.uptime != nil {
+			color = uptimeColor(*d.uptime)
+			scaled := (*d.uptime - sparkFloor) / (100 - sparkFloor)
+			if scaled < 0 {
+				scaled = 0
+			}
+			height = 3 + scaled*(sparkHeight-3)
+		}
+		x := i * (sparkBarWidth + sparkBarGap)
+		fmt.Fprintf(&b, `<rect x="%d" y="%.1f" width="%d" height="%.1f" rx="1" fill="%s"><title>%s: %s</title></rect>`,
+			x, sparkHeight-height, sparkBarWidth, height, color, d.date, formatUptime(d.uptime))
+	}
+	b.WriteString(`</svg>`)
+
+	return b.Bytes()
+}
+
+func uptimeColor(uptime float64) string {
+	switch {
+	case uptime >= 99.5:
+		return colorUp
+	case uptime >= 95:
+		return colorDegrade
+	default:
+		return colorDown
+	}
+}
+
+func formatUptime(uptime *float64) string {
+	if uptime == nil {
+		return "no data"
+	}
+	return fmt.Sprintf("%.2f%%", *uptime)
+}
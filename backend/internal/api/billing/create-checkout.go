@@ -0,0 +1,83 @@
+package billing
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/stripe"
+	"better-uptime/common/util"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateCheckout starts a Stripe Checkout for a paid plan of the active
+// organization. The subscription is recorded once Stripe's webhook confirms it.
+// Organizations with an active subscription can't check out again, since
+// a second Stripe subscription would keep billing without being tracked.
+func (h *Handler) CreateCheckout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	if h.stripe == nil {
+		util.ErrorJson(w, util.ErrBillingNotConfigured)
+		return
+	}
+
+	var req CheckoutRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	plan, ok := plans.Lookup(req.Plan)
+	if !ok || !plan.Paid {
+		util.ErrorJson(w, util.ErrUnknownPlan)
+		return
+	}
+	price, ok := h.stripe.PriceForPlan(plan.Name)
+	if !ok {
+		util.ErrorJson(w, util.ErrUnknownPlan)
+		return
+	}
+
+	params := stripe.CheckoutParams{
+		Price:             price,
+		CustomerEmail:     payload.Email,
+		ClientReferenceID: payload.OrgId.String(),
+		Metadata: map[string]string{
+			"org_id":  payload.OrgId.String(),
+			"user_id": payload.UserId.String(),
+			"plan":    plan.Name,
+		},
+		SuccessURL: strings.TrimRight(h.config.APP_BASE_URL, "/") + "/settings/billing?checkout=success",
+		CancelURL:  strings.TrimRight(h.config.APP_BASE_URL, "/") + "/settings/billing?checkout=cancelled",
+	}
+	sub, err := h.store.GetSubscriptionByOrg(ctx, payload.OrgId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		util.ErrorJson(w, err)
+		return
+	}
+	if err == nil && sub.IsActive {
+		util.ErrorJson(w, util.ErrAlreadySubscribed)
+		return
+	}
+	// Returning customers keep their Stripe customer and payment methods
+	if err == nil && sub.StripeCustomerID.Valid {
+		params.Customer = sub.StripeCustomerID.String
+	}
+
+	session, err := h.stripe.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, CheckoutResponse{URL: session.URL})
+}
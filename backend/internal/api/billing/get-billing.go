@@ -0,0 +1,58 @@
+package billing
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetBilling shows the active organization's plan, subscription and usage
+func (h *Handler) GetBilling(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	resp := BillingResponse{
+		Plan:            plans.Default(*h.config),
+		Plans:           plans.All(),
+		CheckoutEnabled: h.stripe != nil,
+	}
+
+	sub, err := h.store.GetOrgSubscription(ctx, payload.OrgId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		util.ErrorJson(w, err)
+		return
+	}
+	if err == nil {
+		if plan, ok := plans.Lookup(sub.Plan); ok {
+			resp.Plan = plan
+		}
+		resp.Status = sub.Status
+		if sub.CurrentPeriodEnd.Valid {
+			resp.CurrentPeriodEnd = &sub.CurrentPeriodEnd.Time
+		}
+	}
+
+	if resp.Usage.Monitors, err = h.store.CountOrgMonitors(ctx, payload.OrgId); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if resp.Usage.AlertContacts, err = h.store.CountOrgAlertContacts(ctx, payload.OrgId); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if resp.Usage.StatusPages, err = h.store.CountOrgStatusPages(ctx, payload.OrgId); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
@@ -0,0 +1,58 @@
+package billing
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	"better-uptime/common/stripe"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+	"errors"
+	"log"
+
+	"better-uptime/common/util"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config *config.Config
+	store  db.Store
+	// stripe is nil when billing is not configured
+	stripe *stripe.Client
+}
+
+func NewHandler(config *config.Config, store db.Store) *Handler {
+	client, err := stripe.NewClient(*config)
+	if err != nil && !errors.Is(err, util.ErrBillingNotConfigured) {
+		log.Printf("Warning: Stripe initialization failed, billing is disabled: %v", err)
+	}
+	return &Handler{
+		config: config,
+		store:  store,
+		stripe: client,
+	}
+}
+
+// Routes show the organization's plan and usage, start Stripe Checkout and
+// take Stripe's webhooks
+func (h *Handler) Routes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	// Authenticated by the Stripe-Signature header
+	router.Post("/webhook", h.StripeWebhook)
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenMiddleware(h.store))
+
+		r.Get("/", h.GetBilling)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireSession)
+			r.Use(middleware.RequireRole(db.OrgRoleAdmin))
+
+			r.Post("/checkout", h.CreateCheckout)
+		})
+	})
+
+	return router
+}
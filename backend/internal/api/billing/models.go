@@ -0,0 +1,31 @@
+package billing
+
+import (
+	"better-uptime/common/plans"
+	"time"
+)
+
+type Usage struct {
+	Monitors      int64 `json:"monitors"`
+	AlertContacts int64 `json:"alert_contacts"`
+	StatusPages   int64 `json:"status_pages"`
+}
+
+type BillingResponse struct {
+	Plan plans.Plan `json:"plan"`
+	// Status is the Stripe subscription status, empty on the default plan
+	Status           string       `json:"status,omitempty"`
+	CurrentPeriodEnd *time.Time   `json:"current_period_end,omitempty"`
+	Usage            Usage        `json:"usage"`
+	Plans            []plans.Plan `json:"plans"`
+	// CheckoutEnabled tells whether paid plans can be bought here
+	CheckoutEnabled bool `json:"checkout_enabled"`
+}
+
+type CheckoutRequest struct {
+	Plan string `json:"plan" validate:"required"`
+}
+
+type CheckoutResponse struct {
+	URL string `json:"url"`
+}
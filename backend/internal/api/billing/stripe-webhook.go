@@ -0,0 +1,260 @@
+package billing
+
+import (
+	"better-uptime/common/logger"
+	"better-uptime/common/plans"
+	"better-uptime/common/stripe"
+	"better-uptime/common/util"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxWebhookBytes bounds webhook bodies, which Stripe keeps well below this
+const maxWebhookBytes = 1 << 20
+
+type checkoutSession struct {
+	ClientReferenceID string `json:"client_reference_id"`
+	Customer          string `json:"customer"`
+	Subscription      string `json:"subscription"`
+	// PaymentStatus is paid, unpaid (e.g. a bank debit still clearing) or
+	// no_payment_required
+	PaymentStatus string            `json:"payment_status"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+type subscriptionObject struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// StripeWebhook keeps subscriptions in sync with Stripe. Events we don't use
+// are acknowledged so Stripe doesn't retry them.
+func (h *Handler) StripeWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	event, err := stripe.ConstructEvent(payload, r.Header.Get("Stripe-Signature"), h.config.STRIPE_WEBHOOK_SECRET)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	switch event.Type {
+	case "checkout.session.completed":
+		err = h.checkoutCompleted(r.Context(), event)
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		err = h.subscriptionChanged(r.Context(), event)
+	}
+	if err != nil {
+		logger.Error("Stripe webhook %s (%s) failed: %v", event.ID, event.Type, err)
+		// Stripe retries failed deliveries
+		util.ErrorJson(w, util.ErrInternal)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, map[string]bool{"received": true})
+}
+
+func (h *Handler) checkoutCompleted(ctx context.Context, event stripe.Event) error {
+	var session checkoutSession
+	if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+		return err
+	}
+
+	orgID, err := uuid.Parse(session.ClientReferenceID)
+	if err != nil {
+		// Not one of our checkouts
+		logger.Info("Stripe webhook %s: ignoring checkout without an organization", event.ID)
+		return nil
+	}
+	// The subscription events activate the plan once a pending payment clears
+	if session.PaymentStatus != "paid" && session.PaymentStatus != "no_payment_required" {
+		logger.Info("Stripe webhook %s: ignoring checkout with payment status %q", event.ID, session.PaymentStatus)
+		return nil
+	}
+	plan, ok := plans.Lookup(session.Metadata["plan"])
+	if !ok {
+		logger.Info("Stripe webhook %s: ignoring checkout for unknown plan %q", event.ID, session.Metadata["plan"])
+		return nil
+	}
+
+	params := db.UpsertOrgSubscriptionParams{
+		UserID:               metadataUUID(session.Metadata, "user_id"),
+		OrgID:                orgID,
+		Plan:                 plan.Name,
+		StripeCustomerID:     text(session.Customer),
+		StripeSubscriptionID: text(session.Subscription),
+		Status:               "active",
+		IsActive:             true,
+		StripeEventAt:        eventTime(event),
+	}
+
+	// The checkout may be delivered after the subscription was already changed
+	// or cancelled, so its state comes from Stripe rather than the checkout
+	if h.stripe != nil && session.Subscription != "" {
+		raw, err := h.stripe.GetSubscription(ctx, session.Subscription)
+		if err != nil {
+			return err
+		}
+		var sub subscriptionObject
+		if err := json.Unmarshal(raw, &sub); err != nil {
+			return err
+		}
+		state := h.subscriptionState(sub, event.Type)
+		if name, ok := state.plan(); ok {
+			params.Plan = name
+		}
+		params.Status = sub.Status
+		params.CurrentPeriodEnd = state.periodEnd
+		params.IsActive = state.active
+	}
+
+	_, err = h.store.UpsertOrgSubscription(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Info("Stripe webhook %s: ignoring checkout older than the subscription's last update", event.ID)
+		return nil
+	}
+	return err
+}
+
+// subscriptionState is what a Stripe subscription means for the plan
+type subscriptionState struct {
+	planName  string
+	periodEnd pgtype.Timestamp
+	active    bool
+}
+
+func (s subscriptionState) plan() (string, bool) {
+	plan, ok := plans.Lookup(s.planName)
+	return plan.Name, ok
+}
+
+func (h *Handler) subscriptionState(sub subscriptionObject, eventType string) subscriptionState {
+	state := subscriptionState{planName: sub.Metadata["plan"]}
+	periodEnd := sub.CurrentPeriodEnd
+	if len(sub.Items.Data) > 0 {
+		item := sub.Items.Data[0]
+		if name, ok := h.planForPrice(item.Price.ID); ok {
+			state.planName = name
+		}
+		// Newer API versions only set the period on items
+		if periodEnd == 0 {
+			periodEnd = item.CurrentPeriodEnd
+		}
+	}
+	if periodEnd > 0 {
+		state.periodEnd = pgtype.Timestamp{Time: time.Unix(periodEnd, 0).UTC(), Valid: true}
+	}
+
+	// past_due keeps the plan while Stripe retries the payment
+	state.active = eventType != "customer.subscription.deleted" &&
+		(sub.Status == "active" || sub.Status == "trialing" || sub.Status == "past_due")
+	return state
+}
+
+func (h *Handler) subscriptionChanged(ctx context.Context, event stripe.Event) error {
+	var sub subscriptionObject
+	if err := json.Unmarshal(event.Data.Object, &sub); err != nil {
+		return err
+	}
+
+	state := h.subscriptionState(sub, event.Type)
+	planName, ok := state.plan()
+	if !ok {
+		logger.Info("Stripe webhook %s: ignoring subscription %s for unknown plan %q", event.ID, sub.ID, state.planName)
+		return nil
+	}
+
+	_, err := h.store.UpdateSubscriptionByStripeID(ctx, db.UpdateSubscriptionByStripeIDParams{
+		StripeSubscriptionID: text(sub.ID),
+		Plan:                 planName,
+		Status:               sub.Status,
+		CurrentPeriodEnd:     state.periodEnd,
+		IsActive:             state.active,
+		StripeEventAt:        eventTime(event),
+	})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// The subscription event can arrive before checkout.session.completed
+	orgID, err := uuid.Parse(sub.Metadata["org_id"])
+	if err != nil {
+		logger.Info("Stripe webhook %s: ignoring subscription %s without an organization", event.ID, sub.ID)
+		return nil
+	}
+	// An untracked subscription ending must not end the one the org is on
+	if !state.active {
+		current, err := h.store.GetSubscriptionByOrg(ctx, orgID)
+		if err == nil && current.StripeSubscriptionID.Valid && current.StripeSubscriptionID.String != sub.ID {
+			logger.Info("Stripe webhook %s: ignoring inactive subscription %s, the organization is on %s", event.ID, sub.ID, current.StripeSubscriptionID.String)
+			return nil
+		}
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+	}
+	_, err = h.store.UpsertOrgSubscription(ctx, db.UpsertOrgSubscriptionParams{
+		UserID:               metadataUUID(sub.Metadata, "user_id"),
+		OrgID:                orgID,
+		Plan:                 planName,
+		StripeCustomerID:     text(sub.Customer),
+		StripeSubscriptionID: text(sub.ID),
+		Status:               sub.Status,
+		CurrentPeriodEnd:     state.periodEnd,
+		IsActive:             state.active,
+		StripeEventAt:        eventTime(event),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Info("Stripe webhook %s: ignoring subscription %s event older than its last update", event.ID, sub.ID)
+		return nil
+	}
+	return err
+}
+
+func (h *Handler) planForPrice(price string) (string, bool) {
+	if h.stripe == nil {
+		return "", false
+	}
+	return h.stripe.PlanForPrice(price)
+}
+
+func metadataUUID(metadata map[string]string, key string) pgtype.UUID {
+	id, err := uuid.Parse(metadata[key])
+	if err != nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+func eventTime(event stripe.Event) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Unix(event.Created, 0).UTC(), Valid: event.Created > 0}
+}
+
+func text(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
@@ -0,0 +1,426 @@
+package billing
+
+import (
+	"better-uptime/common/firebase"
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/stripe"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	testWebhookSecret = "whsec_test"
+	testSecretKey     = "sk_test_123"
+	testPriceBusiness = "price_business"
+)
+
+// subscriptionStore keeps subscriptions in memory with the same event-time
+// guard as the queries. Other Store methods are not used by the webhook.
+type subscriptionStore struct {
+	db.Store
+
+	mu    sync.Mutex
+	byOrg map[uuid.UUID]db.Subscription
+}
+
+func newSubscriptionStore() *subscriptionStore {
+	return &subscriptionStore{byOrg: map[uuid.UUID]db.Subscription{}}
+}
+
+func eventIsNewer(stored, at pgtype.Timestamp) bool {
+	return !stored.Valid || (at.Valid && !at.Time.Before(stored.Time))
+}
+
+func (s *subscriptionStore) UpsertOrgSubscription(_ context.Context, arg db.UpsertOrgSubscriptionParams) (db.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, exists := s.byOrg[arg.OrgID]
+	if exists && !eventIsNewer(sub.StripeEventAt, arg.StripeEventAt) {
+		return db.Subscription{}, pgx.ErrNoRows
+	}
+	sub.OrgID = arg.OrgID
+	if arg.UserID.Valid || !exists {
+		sub.UserID = arg.UserID
+	}
+	if arg.StripeCustomerID.Valid || !exists {
+		sub.StripeCustomerID = arg.StripeCustomerID
+	}
+	if arg.StripeSubscriptionID.Valid || !exists {
+		sub.StripeSubscriptionID = arg.StripeSubscriptionID
+	}
+	if arg.CurrentPeriodEnd.Valid || !exists {
+		sub.CurrentPeriodEnd = arg.CurrentPeriodEnd
+	}
+	sub.Plan = arg.Plan
+	sub.Status = arg.Status
+	sub.IsActive = arg.IsActive
+	sub.StripeEventAt = arg.StripeEventAt
+	s.byOrg[arg.OrgID] = sub
+	return sub, nil
+}
+
+func (s *subscriptionStore) UpdateSubscriptionByStripeID(_ context.Context, arg db.UpdateSubscriptionByStripeIDParams) (db.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for org, sub := range s.byOrg {
+		if sub.StripeSubscriptionID != arg.StripeSubscriptionID || !eventIsNewer(sub.StripeEventAt, arg.StripeEventAt) {
+			continue
+		}
+		sub.Plan = arg.Plan
+		sub.Status = arg.Status
+		sub.CurrentPeriodEnd = arg.CurrentPeriodEnd
+		sub.IsActive = arg.IsActive
+		sub.StripeEventAt = arg.StripeEventAt
+		s.byOrg[org] = sub
+		return sub, nil
+	}
+	return db.Subscription{}, pgx.ErrNoRows
+}
+
+func (s *subscriptionStore) GetSubscriptionByOrg(_ context.Context, org uuid.UUID) (db.Subscription, error) {
+	if sub, ok := s.get(org); ok {
+		return sub, nil
+	}
+	return db.Subscription{}, pgx.ErrNoRows
+}
+
+func (s *subscriptionStore) get(org uuid.UUID) (db.Subscription, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.byOrg[org]
+	return sub, ok
+}
+
+// stripeStub stands in for the Stripe API, serving the subscriptions it holds
+type stripeStub struct {
+	mu            sync.Mutex
+	subscriptions map[string]map[string]any
+	fail          bool
+}
+
+func (s *stripeStub) set(id string, sub map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions[id] = sub
+}
+
+func (s *stripeStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, _, _ := r.BasicAuth(); key != testSecretKey {
+		http.Error(w, `{"error":{"message":"Invalid API Key provided"}}`, http.StatusUnauthorized)
+		return
+	}
+	if s.fail {
+		http.Error(w, `{"error":{"message":"stub is down"}}`, http.StatusInternalServerError)
+		return
+	}
+
+	id, ok := strings.CutPrefix(r.URL.Path, "/v1/subscriptions/")
+	sub, found := s.subscriptions[id]
+	if r.Method != http.MethodGet || !ok || !found {
+		http.Error(w, `{"error":{"message":"No such subscription"}}`, http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(sub)
+}
+
+type webhookTest struct {
+	t       *testing.T
+	handler *Handler
+	store   *subscriptionStore
+	stripe  *stripeStub
+	org     uuid.UUID
+}
+
+func newWebhookTest(t *testing.T) *webhookTest {
+	t.Helper()
+	stub := &stripeStub{subscriptions: map[string]map[string]any{}}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		STRIPE_SECRET_KEY:     testSecretKey,
+		STRIPE_WEBHOOK_SECRET: testWebhookSecret,
+		STRIPE_API_BASE:       server.URL,
+		STRIPE_PRICE_PRO:      "price_pro",
+		STRIPE_PRICE_BUSINESS: testPriceBusiness,
+	}
+	client, err := stripe.NewClient(*cfg)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	store := newSubscriptionStore()
+
+	return &webhookTest{
+		t:       t,
+		handler: &Handler{config: cfg, store: store, stripe: client},
+		store:   store,
+		stripe:  stub,
+		org:     uuid.New(),
+	}
+}
+
+// sendSigned delivers an event created at created, signed at signedAt with secret
+func (wt *webhookTest) sendSigned(eventType string, created time.Time, object map[string]any, secret string, signedAt time.Time) int {
+	wt.t.Helper()
+	payload, err := json.Marshal(map[string]any{
+		"id":      "evt_" + uuid.NewString(),
+		"type":    eventType,
+		"created": created.Unix(),
+		"data":    map[string]any{"object": object},
+	})
+	if err != nil {
+		wt.t.Fatalf("marshal event: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", stripe.SignPayload(payload, secret, signedAt))
+	rec := httptest.NewRecorder()
+	wt.handler.StripeWebhook(rec, req)
+	return rec.Code
+}
+
+func (wt *webhookTest) send(eventType string, created time.Time, object map[string]any) {
+	wt.t.Helper()
+	if code := wt.sendSigned(eventType, created, object, testWebhookSecret, time.Now()); code != http.StatusOK {
+		wt.t.Fatalf("%s: status %d, want 200", eventType, code)
+	}
+}
+
+func (wt *webhookTest) subscription() db.Subscription {
+	wt.t.Helper()
+	sub, ok := wt.store.get(wt.org)
+	if !ok {
+		wt.t.Fatal("no subscription was stored for the organization")
+	}
+	return sub
+}
+
+func (wt *webhookTest) checkoutSession(paymentStatus string) map[string]any {
+	return map[string]any{
+		"client_reference_id": wt.org.String(),
+		"customer":            "cus_1",
+		"subscription":        "sub_1",
+		"payment_status":      paymentStatus,
+		"metadata":            map[string]string{"plan": plans.Pro},
+	}
+}
+
+func (wt *webhookTest) subscriptionObject(status string, price string, periodEnd time.Time) map[string]any {
+	return map[string]any{
+		"id":       "sub_1",
+		"customer": "cus_1",
+		"status":   status,
+		"metadata": map[string]string{"plan": plans.Pro, "org_id": wt.org.String()},
+		"items": map[string]any{"data": []map[string]any{{
+			"current_period_end": periodEnd.Unix(),
+			"price":              map[string]string{"id": price},
+		}}},
+	}
+}
+
+func TestStripeWebhookSignature(t *testing.T) {
+	wt := newWebhookTest(t)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		secret   string
+		signedAt time.Time
+		want     int
+	}{
+		{name: "valid signature", secret: testWebhookSecret, signedAt: now, want: http.StatusOK},
+		{name: "wrong secret", secret: "whsec_other", signedAt: now, want: http.StatusBadRequest},
+		{name: "stale timestamp", secret: testWebhookSecret, signedAt: now.Add(-10 * time.Minute), want: http.StatusBadRequest},
+		{name: "future timestamp", secret: testWebhookSecret, signedAt: now.Add(10 * time.Minute), want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An event billing doesn't use, so only the signature decides
+			if got := wt.sendSigned("invoice.paid", now, map[string]any{"id": "in_1"}, tt.secret, tt.signedAt); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{"type":"invoice.paid"}`))
+	rec := httptest.NewRecorder()
+	wt.handler.StripeWebhook(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unsigned: status = %d, want 400", rec.Code)
+	}
+
+	if _, ok := wt.store.get(wt.org); ok {
+		t.Error("an unused event changed the subscription")
+	}
+}
+
+func TestCheckoutCompleted(t *testing.T) {
+	wt := newWebhookTest(t)
+	periodEnd := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second).UTC()
+	wt.stripe.set("sub_1", wt.subscriptionObject("active", "price_pro", periodEnd))
+
+	wt.send("checkout.session.completed", time.Now(), wt.checkoutSession("paid"))
+
+	sub := wt.subscription()
+	if !sub.IsActive || sub.Status != "active" || sub.Plan != plans.Pro {
+		t.Errorf("subscription = %s/%s active %v, want pro/active", sub.Plan, sub.Status, sub.IsActive)
+	}
+	if sub.StripeSubscriptionID.String != "sub_1" || sub.StripeCustomerID.String != "cus_1" {
+		t.Errorf("Stripe ids = %q, %q", sub.StripeSubscriptionID.String, sub.StripeCustomerID.String)
+	}
+	if !sub.CurrentPeriodEnd.Time.Equal(periodEnd) {
+		t.Errorf("current period end = %s, want %s from Stripe", sub.CurrentPeriodEnd.Time, periodEnd)
+	}
+}
+
+func TestCheckoutCompletedUnpaid(t *testing.T) {
+	wt := newWebhookTest(t)
+	wt.stripe.set("sub_1", wt.subscriptionObject("incomplete", "price_pro", time.Now()))
+
+	wt.send("checkout.session.completed", time.Now(), wt.checkoutSession("unpaid"))
+
+	if _, ok := wt.store.get(wt.org); ok {
+		t.Error("an unpaid checkout activated the subscription")
+	}
+}
+
+func TestCheckoutCompletedAfterCancellation(t *testing.T) {
+	wt := newWebhookTest(t)
+	checkoutAt := time.Now().Add(-time.Hour)
+	wt.stripe.set("sub_1", wt.subscriptionObject("canceled", "price_pro", time.Now()))
+
+	// Stripe delivers the cancellation before the checkout that preceded it
+	wt.send("customer.subscription.deleted", time.Now(), wt.subscriptionObject("canceled", "price_pro", time.Now()))
+	wt.send("checkout.session.completed", checkoutAt, wt.checkoutSession("paid"))
+
+	if sub := wt.subscription(); sub.IsActive || sub.Status != "canceled" {
+		t.Errorf("subscription = %s, active %v, want canceled and inactive", sub.Status, sub.IsActive)
+	}
+}
+
+func TestCheckoutCompletedUsesCurrentStatus(t *testing.T) {
+	wt := newWebhookTest(t)
+	// Cancelled right after checkout, before either event was delivered
+	wt.stripe.set("sub_1", wt.subscriptionObject("canceled", "price_pro", time.Now()))
+
+	wt.send("checkout.session.completed", time.Now(), wt.checkoutSession("paid"))
+
+	if sub := wt.subscription(); sub.IsActive {
+		t.Errorf("checkout activated subscription that Stripe reports as %s", sub.Status)
+	}
+}
+
+func TestCheckoutCompletedStripeDown(t *testing.T) {
+	wt := newWebhookTest(t)
+	wt.stripe.fail = true
+
+	code := wt.sendSigned("checkout.session.completed", time.Now(), wt.checkoutSession("paid"), testWebhookSecret, time.Now())
+	if code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 so Stripe retries", code)
+	}
+}
+
+func TestSubscriptionEvents(t *testing.T) {
+	wt := newWebhookTest(t)
+	start := time.Now().Add(-time.Hour)
+	periodEnd := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second).UTC()
+
+	tests := []struct {
+		eventType string
+		status    string
+		price     string
+		plan      string
+		active    bool
+	}{
+		// Created before the checkout arrives, found through the metadata
+		{"customer.subscription.created", "active", "price_pro", plans.Pro, true},
+		{"customer.subscription.updated", "active", testPriceBusiness, plans.Business, true},
+		{"customer.subscription.updated", "past_due", testPriceBusiness, plans.Business, true},
+		{"customer.subscription.updated", "unpaid", testPriceBusiness, plans.Business, false},
+		{"customer.subscription.deleted", "canceled", testPriceBusiness, plans.Business, false},
+	}
+	for i, tt := range tests {
+		wt.send(tt.eventType, start.Add(time.Duration(i)*time.Minute), wt.subscriptionObject(tt.status, tt.price, periodEnd))
+
+		sub := wt.subscription()
+		if sub.Plan != tt.plan || sub.Status != tt.status || sub.IsActive != tt.active {
+			t.Errorf("after %s (%s): subscription = %s/%s active %v, want %s/%s active %v",
+				tt.eventType, tt.status, sub.Plan, sub.Status, sub.IsActive, tt.plan, tt.status, tt.active)
+		}
+		if !sub.CurrentPeriodEnd.Time.Equal(periodEnd) {
+			t.Errorf("after %s: current period end = %s, want %s", tt.eventType, sub.CurrentPeriodEnd.Time, periodEnd)
+		}
+	}
+}
+
+func TestSubscriptionEventsOutOfOrder(t *testing.T) {
+	wt := newWebhookTest(t)
+	now := time.Now()
+
+	wt.send("customer.subscription.updated", now, wt.subscriptionObject("active", testPriceBusiness, now))
+	wt.send("customer.subscription.updated", now.Add(-time.Minute), wt.subscriptionObject("active", "price_pro", now))
+
+	if sub := wt.subscription(); sub.Plan != plans.Business {
+		t.Errorf("plan = %s, want %s from the newer event", sub.Plan, plans.Business)
+	}
+}
+
+func TestSubscriptionEventUnknownPlan(t *testing.T) {
+	wt := newWebhookTest(t)
+	object := wt.subscriptionObject("active", "price_unknown", time.Now())
+	object["metadata"] = map[string]string{"org_id": wt.org.String()}
+
+	wt.send("customer.subscription.updated", time.Now(), object)
+
+	if _, ok := wt.store.get(wt.org); ok {
+		t.Error("a subscription for an unknown plan was stored")
+	}
+}
+
+func TestUntrackedSubscriptionCancelled(t *testing.T) {
+	wt := newWebhookTest(t)
+	now := time.Now()
+	wt.send("customer.subscription.created", now.Add(-time.Hour), wt.subscriptionObject("active", "price_pro", now))
+
+	// A second subscription of the same organization that was never tracked
+	other := wt.subscriptionObject("canceled", "price_pro", now)
+	other["id"] = "sub_2"
+	wt.send("customer.subscription.deleted", now, other)
+
+	if sub := wt.subscription(); !sub.IsActive || sub.StripeSubscriptionID.String != "sub_1" {
+		t.Errorf("subscription = %s active %v, want sub_1 still active", sub.StripeSubscriptionID.String, sub.IsActive)
+	}
+}
+
+func TestCreateCheckoutAlreadySubscribed(t *testing.T) {
+	wt := newWebhookTest(t)
+	wt.send("customer.subscription.created", time.Now(), wt.subscriptionObject("active", "price_pro", time.Now()))
+
+	req := httptest.NewRequest(http.MethodPost, "/checkout", strings.NewReader(`{"plan":"business"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.TokenPayloadKey, firebase.FirebasePayload{OrgId: wt.org, UserId: uuid.New()}))
+	rec := httptest.NewRecorder()
+	wt.handler.CreateCheckout(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d (%s), want 409", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,75 @@
+package manifest
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/storage"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"net/http"
+)
+
+// ApplyManifest reconciles the organization with the submitted document in
+// one transaction. Resources are matched by external id; unmanaged ones may
+// be adopted by their generated id or natural key. Managed resources missing
+// from the document are deleted only with ?prune=true, and ?dry_run=true
+// plans without applying.
+func (h *Handler) ApplyManifest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	prune, err := queryFlag(r, "prune")
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	dryRun, err := queryFlag(r, "dry_run")
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	st, changes, summary, err := h.plan(w, r, payload.OrgId, prune)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	if !dryRun && len(changes) > 0 {
+		var a *applier
+		err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+			a = newApplier(q, st, payload.OrgId, payload.UserId)
+			return a.apply(ctx, changes)
+		})
+		if err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		if len(a.screenshots) > 0 {
+			go storage.DeleteAll(context.WithoutCancel(ctx), h.artifacts, a.screenshots)
+		}
+
+		for _, c := range changes {
+			audit.Record(r, h.store, audit.Entry{
+				Action:       c.Action,
+				ResourceType: c.Kind,
+				ResourceID:   c.ID,
+				Before:       c.before,
+				After:        c.after,
+			})
+		}
+	}
+
+	util.WriteJson(w, http.StatusOK, ApplyResponse{
+		DryRun:  dryRun,
+		Prune:   prune,
+		Summary: summary,
+		Changes: changes,
+	})
+}
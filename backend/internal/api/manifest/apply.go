@@ -0,0 +1,351 @@
+package manifest
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/jsondiff"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// applier makes the changes of a plan inside one transaction
+type applier struct {
+	q      *db.Queries
+	st     *state
+	orgID  uuid.UUID
+	userID pgtype.UUID
+	// ids maps the refs of each kind to resource ids, including resources
+	// created along the way
+	ids map[string]map[string]int32
+	// screenshots of deleted monitors, deleted from storage after the commit
+	screenshots []string
+}
+
+func newApplier(q *db.Queries, st *state, orgID uuid.UUID, userID uuid.UUID) *applier {
+	a := &applier{
+		q:      q,
+		st:     st,
+		orgID:  orgID,
+		userID: pgtype.UUID{Bytes: userID, Valid: userID != uuid.Nil},
+		ids:    make(map[string]map[string]int32),
+	}
+	for _, k := range []*kindState{st.groups, st.contacts, st.monitors, st.pages} {
+		ids := make(map[string]int32, len(k.list))
+		for ref, r := range k.byRef {
+			ids[ref] = r.id
+		}
+		for _, r := range k.list {
+			if r.target != "" {
+				ids[r.target] = r.id
+			}
+		}
+		a.ids[k.kind] = ids
+	}
+	return a
+}
+
+// apply makes the changes in order, filling in the ids of created resources
+func (a *applier) apply(ctx context.Context, changes []Change) error {
+	for i := range changes {
+		c := &changes[i]
+
+		var err error
+		switch c.Kind {
+		case a.st.groups.kind:
+			err = a.applyGroup(ctx, c)
+		case a.st.contacts.kind:
+			err = a.applyContact(ctx, c)
+		case a.st.monitors.kind:
+			err = a.applyMonitor(ctx, c)
+		case a.st.pages.kind:
+			err = a.applyPage(ctx, c)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s %s: %w", c.Action, c.Kind, c.ExternalID, err)
+		}
+	}
+	return nil
+}
+
+// created records the id of a new resource so later changes can refer to it
+func (a *applier) created(c *Change, id int32) {
+	c.ID = id
+	a.ids[c.Kind][c.ExternalID] = id
+}
+
+// needsExternalID reports whether the change leaves a resource without its
+// external id stored
+func needsExternalID(c *Change) bool {
+	return c.Action == audit.ActionCreate || c.Adopted
+}
+
+func externalID(c *Change) pgtype.Text {
+	return pgtype.Text{String: c.ExternalID, Valid: true}
+}
+
+func (a *applier) applyGroup(ctx context.Context, c *Change) error {
+	if c.Action == audit.ActionDelete {
+		_, err := a.q.DeleteMonitorGroup(ctx, db.DeleteMonitorGroupParams{ID: c.ID, OrgID: a.orgID})
+		return err
+	}
+
+	g := c.after.(GroupSpec)
+	if c.Action == audit.ActionCreate {
+		group, err := a.q.CreateMonitorGroup(ctx, db.CreateMonitorGroupParams{
+			OrgID:        a.orgID,
+			Name:         g.Name,
+			Description:  g.Description,
+			Rollup:       g.Rollup,
+			MinUpPercent: g.MinUpPercent,
+		})
+		if err != nil {
+			return err
+		}
+		a.created(c, group.ID)
+	} else {
+		_, err := a.q.UpdateMonitorGroup(ctx, db.UpdateMonitorGroupParams{
+			ID:           c.ID,
+			OrgID:        a.orgID,
+			Name:         g.Name,
+			Description:  g.Description,
+			Rollup:       g.Rollup,
+			MinUpPercent: g.MinUpPercent,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if !needsExternalID(c) {
+		return nil
+	}
+	return a.q.SetMonitorGroupExternalID(ctx, db.SetMonitorGroupExternalIDParams{ID: c.ID, OrgID: a.orgID, ExternalID: externalID(c)})
+}
+
+func (a *applier) applyContact(ctx context.Context, c *Change) error {
+	if c.Action == audit.ActionDelete {
+		return a.q.DeleteAlertContact(ctx, db.DeleteAlertContactParams{ID: c.ID, OrgID: a.orgID})
+	}
+
+	contact := c.after.(ContactSpec)
+	if c.Action == audit.ActionCreate {
+		created, err := a.q.CreateAlertContact(ctx, db.CreateAlertContactParams{
+			UserID: a.userID,
+			OrgID:  a.orgID,
+			Name:   contact.Name,
+			Email:  contact.Email,
+		})
+		if err != nil {
+			return err
+		}
+		a.created(c, created.ID)
+	} else {
+		_, err := a.q.UpdateAlertContact(ctx, db.UpdateAlertContactParams{
+			ID:    c.ID,
+			OrgID: a.orgID,
+			Name:  contact.Name,
+			Email: contact.Email,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if !needsExternalID(c) {
+		return nil
+	}
+	return a.q.SetAlertContactExternalID(ctx, db.SetAlertContactExternalIDParams{ID: c.ID, OrgID: a.orgID, ExternalID: externalID(c)})
+}
+
+func (a *applier) applyMonitor(ctx context.Context, c *Change) error {
+	if c.Action == audit.ActionDelete {
+		keys, err := a.q.ListMonitorScreenshotKeys(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		a.screenshots = append(a.screenshots, keys...)
+		return a.q.DeleteMonitor(ctx, db.DeleteMonitorParams{ID: c.ID, OrgID: a.orgID})
+	}
+
+	m := c.after.(MonitorSpec)
+	var group pgtype.Int4
+	if m.Group != "" {
+		group = util.ToPgInt4(a.ids[a.st.groups.kind][m.Group])
+	}
+	tags := m.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	if c.Action == audit.ActionCreate {
+		created, err := a.q.CreateMonitor(ctx, db.CreateMonitorParams{
+			UserID:   a.userID,
+			OrgID:    a.orgID,
+			Url:      m.URL,
+			Method:   pgtype.Text{String: m.Method, Valid: true},
+			Type:     pgtype.Text{String: m.Type, Valid: true},
+			Interval: m.Interval,
+			Status:   db.NullMonitorStatus{MonitorStatus: db.MonitorStatusPending, Valid: true},
+			IsActive: util.ToPgBool(!m.Paused),
+			Config:   m.Config,
+			GroupID:  group,
+			Tags:     tags,
+		})
+		if err != nil {
+			return err
+		}
+		a.created(c, created.ID)
+	} else {
+		// A JSON null, unlike SQL NULL, clears the config
+		config := m.Config
+		if config == nil {
+			config = []byte("null")
+		}
+		_, err := a.q.UpdateMonitor(ctx, db.UpdateMonitorParams{
+			ID:       c.ID,
+			OrgID:    a.orgID,
+			Url:      m.URL,
+			Method:   pgtype.Text{String: m.Method, Valid: true},
+			Type:     pgtype.Text{String: m.Type, Valid: true},
+			Interval: m.Interval,
+			IsActive: util.ToPgBool(!m.Paused),
+			Config:   config,
+			GroupID:  group,
+			Tags:     tags,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if needsExternalID(c) {
+		err := a.q.SetMonitorExternalID(ctx, db.SetMonitorExternalIDParams{ID: c.ID, OrgID: a.orgID, ExternalID: externalID(c)})
+		if err != nil {
+			return err
+		}
+	}
+
+	before, _ := c.before.(MonitorSpec)
+	return a.syncAlerts(ctx, c.ID, before.Alerts, m.Alerts)
+}
+
+// syncAlerts sets the monitor's alert configs to those listed, removing the
+// ones no longer listed
+func (a *applier) syncAlerts(ctx context.Context, monitorID int32, before, after []AlertSpec) error {
+	contacts := a.ids[a.st.contacts.kind]
+
+	listed := make(map[int32]bool, len(after))
+	for _, alert := range after {
+		contactID := contacts[alert.Contact]
+		listed[contactID] = true
+		_, err := a.q.UpsertMonitorAlertConfig(ctx, db.UpsertMonitorAlertConfigParams{
+			MonitorID:       util.ToPgInt4(monitorID),
+			AlertContactID:  util.ToPgInt4(contactID),
+			AlertOnUp:       util.ToPgBool(alert.OnUp),
+			AlertOnDown:     util.ToPgBool(*alert.OnDown),
+			AlertOnSlow:     util.ToPgBool(alert.OnSlow),
+			SlowThresholdMs: util.ToPgInt4(alert.SlowThresholdMs),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, alert := range before {
+		contactID := contacts[alert.Contact]
+		if listed[contactID] {
+			continue
+		}
+		err := a.q.DeleteMonitorAlertConfig(ctx, db.DeleteMonitorAlertConfigParams{
+			MonitorID:      util.ToPgInt4(monitorID),
+			AlertContactID: util.ToPgInt4(contactID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyPage(ctx context.Context, c *Change) error {
+	if c.Action == audit.ActionDelete {
+		return a.q.DeleteStatusPage(ctx, db.DeleteStatusPageParams{ID: c.ID, OrgID: a.orgID})
+	}
+
+	page := c.after.(StatusPageSpec)
+	if c.Action == audit.ActionCreate {
+		created, err := a.q.CreateStatusPage(ctx, db.CreateStatusPageParams{
+			UserID:       a.userID,
+			OrgID:        a.orgID,
+			Slug:         page.Slug,
+			Title:        page.Title,
+			Description:  pgtype.Text{String: page.Description, Valid: page.Description != ""},
+			CustomDomain: pgtype.Text{String: page.CustomDomain, Valid: page.CustomDomain != ""},
+			IsPublished:  util.ToPgBool(page.Published),
+		})
+		if err != nil {
+			return err
+		}
+		a.created(c, created.ID)
+	} else {
+		_, err := a.q.UpdateStatusPage(ctx, db.UpdateStatusPageParams{
+			ID:           c.ID,
+			OrgID:        a.orgID,
+			Slug:         page.Slug,
+			Title:        page.Title,
+			Description:  pgtype.Text{String: page.Description, Valid: page.Description != ""},
+			CustomDomain: pgtype.Text{String: page.CustomDomain, Valid: page.CustomDomain != ""},
+			IsPublished:  util.ToPgBool(page.Published),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if needsExternalID(c) {
+		err := a.q.SetStatusPageExternalID(ctx, db.SetStatusPageExternalIDParams{ID: c.ID, OrgID: a.orgID, ExternalID: externalID(c)})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Replacing the layout resets section ids, so leave it alone unless it
+	// changed
+	before, existed := c.before.(StatusPageSpec)
+	if existed && jsondiff.Equal(marshalSpec(before.Sections), marshalSpec(page.Sections)) {
+		return nil
+	}
+	return a.replaceLayout(ctx, c.ID, page.Sections)
+}
+
+func (a *applier) replaceLayout(ctx context.Context, pageID int32, sections []SectionSpec) error {
+	if err := a.q.DeleteStatusPageSections(ctx, pageID); err != nil {
+		return err
+	}
+
+	monitors := a.ids[a.st.monitors.kind]
+	for i, section := range sections {
+		created, err := a.q.CreateStatusPageSection(ctx, db.CreateStatusPageSectionParams{
+			StatusPageID: pageID,
+			Name:         section.Name,
+			Position:     int32(i),
+		})
+		if err != nil {
+			return err
+		}
+
+		for j, m := range section.Monitors {
+			_, err := a.q.AddStatusPageMonitor(ctx, db.AddStatusPageMonitorParams{
+				StatusPageID: pageID,
+				SectionID:    created.ID,
+				MonitorID:    monitors[m.Monitor],
+				DisplayName:  m.DisplayName,
+				Position:     int32(j),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
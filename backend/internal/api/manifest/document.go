@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the document format this server reads and writes
+const Version = 1
+
+// Document describes an organization's monitoring config. Every resource has
+// an external_id that stays the same across applies and is how other
+// resources refer to it.
+type Document struct {
+	Version       int              `json:"version"`
+	MonitorGroups []GroupSpec      `json:"monitor_groups"`
+	AlertContacts []ContactSpec    `json:"alert_contacts"`
+	Monitors      []MonitorSpec    `json:"monitors"`
+	StatusPages   []StatusPageSpec `json:"status_pages"`
+}
+
+type GroupSpec struct {
+	ExternalID   string `json:"external_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Rollup       string `json:"rollup,omitempty"`
+	MinUpPercent int32  `json:"min_up_percent,omitempty"`
+}
+
+type ContactSpec struct {
+	ExternalID string `json:"external_id"`
+	Name       string `json:"name"`
+	Email      string `json:"email"`
+}
+
+// MonitorSpec leaves out secrets, which are only ever written through the
+// monitor endpoints
+type MonitorSpec struct {
+	ExternalID string `json:"external_id"`
+	URL        string `json:"url"`
+	Type       string `json:"type,omitempty"`
+	Method     string `json:"method,omitempty"`
+	Interval   int32  `json:"interval"`
+	Paused     bool   `json:"paused,omitempty"`
+	// Group is the external_id of a monitor group
+	Group  string          `json:"group,omitempty"`
+	Tags   []string        `json:"tags,omitempty"`
+	Config json.RawMessage `json:"config,omitempty"`
+	Alerts []AlertSpec     `json:"alerts,omitempty"`
+}
+
+// AlertSpec sends the monitor's alerts to a contact
+type AlertSpec struct {
+	// Contact is the external_id of an alert contact
+	Contact string `json:"contact"`
+	// OnDown defaults to true
+	OnDown          *bool `json:"on_down,omitempty"`
+	OnUp            bool  `json:"on_up,omitempty"`
+	OnSlow          bool  `json:"on_slow,omitempty"`
+	SlowThresholdMs int32 `json:"slow_threshold_ms,omitempty"`
+}
+
+type StatusPageSpec struct {
+	ExternalID   string        `json:"external_id"`
+	Slug         string        `json:"slug"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description,omitempty"`
+	CustomDomain string        `json:"custom_domain,omitempty"`
+	Published    bool          `json:"published"`
+	Sections     []SectionSpec `json:"sections,omitempty"`
+}
+
+type SectionSpec struct {
+	Name     string            `json:"name"`
+	Monitors []PageMonitorSpec `json:"monitors,omitempty"`
+}
+
+type PageMonitorSpec struct {
+	// Monitor is the external_id of a monitor
+	Monitor string `json:"monitor"`
+	// DisplayName defaults to the monitor's host
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// ParseDocument reads a document in YAML or JSON, which is valid YAML too.
+// Unknown fields are rejected so that typos don't silently drop settings.
+func ParseDocument(data []byte) (Document, error) {
+	var tree any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return Document{}, fmt.Errorf("invalid document: %w", err)
+	}
+	if tree == nil {
+		return Document{}, errors.New("invalid document: empty")
+	}
+	// Round trip through JSON so config keeps its JSON form and the
+	// json tags are the only field names
+	asJSON, err := json.Marshal(tree)
+	if err != nil {
+		return Document{}, fmt.Errorf("invalid document: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(asJSON))
+	decoder.DisallowUnknownFields()
+	var doc Document
+	if err := decoder.Decode(&doc); err != nil {
+		return Document{}, fmt.Errorf("invalid document: %w", err)
+	}
+	if doc.Version != Version {
+		return Document{}, fmt.Errorf("unsupported document version %d, this server reads version %d", doc.Version, Version)
+	}
+	return doc, nil
+}
+
+// EncodeYAML writes v as block-style YAML with the field order of its JSON
+// form
+func EncodeYAML(v any) ([]byte, error) {
+	asJSON, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(asJSON, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// blockStyle drops the flow style and quoting JSON parses with, letting the
+// encoder quote only where YAML needs it
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,44 @@
+package manifest
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// ExportManifest returns the organization's monitor groups, alert contacts,
+// monitors and status pages as a document, in JSON or with ?format=yaml in
+// YAML. Resources without an external id yet get a generated one that apply
+// adopts them by.
+func (h *Handler) ExportManifest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	st, err := loadState(ctx, h.store, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	doc := st.export()
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		util.WriteJson(w, http.StatusOK, doc)
+	case "yaml":
+		out, err := EncodeYAML(doc)
+		if err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(out)
+	default:
+		util.ErrorJson(w, util.ErrInvalidQueryParams)
+	}
+}
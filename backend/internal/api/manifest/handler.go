@@ -0,0 +1,49 @@
+package manifest
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	"better-uptime/common/storage"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config    *config.Config
+	store     db.Store
+	artifacts storage.Storage
+}
+
+func NewHandler(config *config.Config, store db.Store, artifacts storage.Storage) *Handler {
+	return &Handler{
+		config:    config,
+		store:     store,
+		artifacts: artifacts,
+	}
+}
+
+// Routes export the organization's monitoring config as a document and
+// reconcile it against a submitted one
+func (h *Handler) Routes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenMiddleware(h.store))
+
+		r.Get("/", h.ExportManifest)
+		// Planning writes nothing, so viewers and read-only keys may preview
+		r.Post("/plan", h.PlanManifest)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireRoleForWrites(db.OrgRoleEditor))
+			r.Use(middleware.RequireScopeForWrites(middleware.ScopeMonitorsWrite))
+			r.Use(middleware.RequireScopeForWrites(middleware.ScopeAlertsWrite))
+
+			r.Post("/apply", h.ApplyManifest)
+		})
+	})
+
+	return router
+}
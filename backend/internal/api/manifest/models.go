@@ -0,0 +1,13 @@
+package manifest
+
+// maxDocumentBytes bounds a submitted document, well above what a large
+// organization's config takes
+const maxDocumentBytes = 5 << 20
+
+type ApplyResponse struct {
+	// DryRun is set when the changes were only planned
+	DryRun  bool     `json:"dry_run"`
+	Prune   bool     `json:"prune"`
+	Summary Summary  `json:"summary"`
+	Changes []Change `json:"changes"`
+}
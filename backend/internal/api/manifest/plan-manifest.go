@@ -0,0 +1,88 @@
+package manifest
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// PlanManifest shows the changes applying the submitted document would make,
+// without making them. It takes the same ?prune flag as apply.
+func (h *Handler) PlanManifest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	prune, err := queryFlag(r, "prune")
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	_, changes, summary, err := h.plan(w, r, payload.OrgId, prune)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, ApplyResponse{
+		DryRun:  true,
+		Prune:   prune,
+		Summary: summary,
+		Changes: changes,
+	})
+}
+
+// plan reads the submitted document and works out the changes it needs
+func (h *Handler) plan(w http.ResponseWriter, r *http.Request, orgID uuid.UUID, prune bool) (*state, []Change, Summary, error) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDocumentBytes))
+	if err != nil {
+		return nil, nil, Summary{}, err
+	}
+	doc, err := ParseDocument(body)
+	if err != nil {
+		return nil, nil, Summary{}, err
+	}
+
+	limit, err := plans.ForOrg(ctx, h.store, *h.config, orgID)
+	if err != nil {
+		return nil, nil, Summary{}, err
+	}
+	st, err := loadState(ctx, h.store, orgID)
+	if err != nil {
+		return nil, nil, Summary{}, err
+	}
+
+	changes, summary, err := buildPlan(st, doc, prune, limit)
+	if err != nil {
+		return nil, nil, Summary{}, err
+	}
+	if changes == nil {
+		changes = []Change{}
+	}
+	return st, changes, summary, nil
+}
+
+// queryFlag reads an optional boolean query parameter
+func queryFlag(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, util.ErrInvalidQueryParams
+	}
+	return flag, nil
+}
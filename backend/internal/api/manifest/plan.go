@@ -0,0 +1,396 @@
+package manifest
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/jsondiff"
+	"better-uptime/common/plans"
+	"better-uptime/internal/api/statuspage"
+	db "better-uptime/internal/db/sqlc"
+	"encoding/json"
+)
+
+// Change is one create, update or delete needed to reach the document
+type Change struct {
+	Kind       string `json:"kind"`
+	ExternalID string `json:"external_id"`
+	Action     string `json:"action"`
+	// ID is zero for resources still to be created
+	ID int32 `json:"id,omitempty"`
+	// Adopted is set when a resource created outside the document gets its
+	// external id
+	Adopted bool              `json:"adopted,omitempty"`
+	Changes []jsondiff.Change `json:"changes"`
+
+	// before and after are the specs, nil for creations and deletions
+	before any
+	after  any
+}
+
+// Summary counts changes by action. Unmanaged resources were never applied
+// from a document and are left alone; unlisted ones were but are missing
+// from this document and are only deleted with prune.
+type Summary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Delete    int `json:"delete"`
+	Unchanged int `json:"unchanged"`
+	Unmanaged int `json:"unmanaged"`
+	Unlisted  int `json:"unlisted"`
+}
+
+// planner works out the changes that take the current state to a document
+type planner struct {
+	st    *state
+	doc   Document
+	prune bool
+	limit plans.Plan
+
+	groupMatches   []*resource
+	contactMatches []*resource
+	monitorMatches []*resource
+	pageMatches    []*resource
+
+	changes []Change
+	summary Summary
+}
+
+func buildPlan(st *state, doc Document, prune bool, limit plans.Plan) ([]Change, Summary, error) {
+	if err := validateDocument(&doc); err != nil {
+		return nil, Summary{}, err
+	}
+
+	p := &planner{st: st, doc: doc, prune: prune, limit: limit}
+	p.match()
+	if err := p.checkReferences(); err != nil {
+		return nil, Summary{}, err
+	}
+	p.canonicalRefs()
+	p.fillDisplayNames()
+
+	p.diffGroups()
+	p.diffContacts()
+	p.diffMonitors()
+	p.diffPages()
+	p.diffUnlisted()
+
+	if err := p.checkPlan(); err != nil {
+		return nil, Summary{}, err
+	}
+	return p.changes, p.summary, nil
+}
+
+// match pairs document entries with existing resources. Exact references
+// go first so that adopting by natural key can't take a resource another
+// entry names.
+func (p *planner) match() {
+	p.groupMatches = matchAll(p.st.groups, len(p.doc.MonitorGroups),
+		func(i int) string { return p.doc.MonitorGroups[i].ExternalID },
+		func(i int) string { return p.doc.MonitorGroups[i].Name })
+	p.contactMatches = matchAll(p.st.contacts, len(p.doc.AlertContacts),
+		func(i int) string { return p.doc.AlertContacts[i].ExternalID },
+		func(i int) string { return p.doc.AlertContacts[i].Email })
+	p.monitorMatches = matchAll(p.st.monitors, len(p.doc.Monitors),
+		func(i int) string { return p.doc.Monitors[i].ExternalID },
+		func(i int) string { return p.doc.Monitors[i].URL })
+	p.pageMatches = matchAll(p.st.pages, len(p.doc.StatusPages),
+		func(i int) string { return p.doc.StatusPages[i].ExternalID },
+		func(i int) string { return p.doc.StatusPages[i].Slug })
+}
+
+func matchAll(k *kindState, n int, externalID, key func(int) string) []*resource {
+	matches := make([]*resource, n)
+	for i := range matches {
+		if r := k.matchRef(externalID(i)); r != nil {
+			r.target = externalID(i)
+			matches[i] = r
+		}
+	}
+	for i := range matches {
+		if matches[i] != nil {
+			continue
+		}
+		if r := k.matchKey(key(i)); r != nil {
+			r.target = externalID(i)
+			matches[i] = r
+		}
+	}
+	return matches
+}
+
+// refs lists the external ids of a kind that will exist after applying:
+// the document's own, and existing ones the document leaves in place
+func (p *planner) refs(k *kindState, docRefs []string) map[string]bool {
+	refs := make(map[string]bool)
+	for _, ref := range docRefs {
+		refs[ref] = true
+	}
+	for ref, r := range k.byRef {
+		if p.prune && r.managed && r.target == "" {
+			continue
+		}
+		refs[ref] = true
+	}
+	return refs
+}
+
+func (p *planner) checkReferences() error {
+	var groupRefs, contactRefs, monitorRefs []string
+	for _, g := range p.doc.MonitorGroups {
+		groupRefs = append(groupRefs, g.ExternalID)
+	}
+	for _, c := range p.doc.AlertContacts {
+		contactRefs = append(contactRefs, c.ExternalID)
+	}
+	for _, m := range p.doc.Monitors {
+		monitorRefs = append(monitorRefs, m.ExternalID)
+	}
+	groups := p.refs(p.st.groups, groupRefs)
+	contacts := p.refs(p.st.contacts, contactRefs)
+	monitors := p.refs(p.st.monitors, monitorRefs)
+
+	for i, m := range p.doc.Monitors {
+		if m.Group != "" && !groups[m.Group] {
+			return entryError("monitors", i, m.ExternalID, "unknown monitor group %q", m.Group)
+		}
+		for _, a := range m.Alerts {
+			if !contacts[a.Contact] {
+				return entryError("monitors", i, m.ExternalID, "unknown alert contact %q", a.Contact)
+			}
+		}
+	}
+	for i, page := range p.doc.StatusPages {
+		for _, s := range page.Sections {
+			for _, m := range s.Monitors {
+				if !monitors[m.Monitor] {
+					return entryError("status_pages", i, page.ExternalID, "unknown monitor %q", m.Monitor)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// canonicalRefs rewrites references by generated id to resources the
+// document adopts under another external id, so they compare equal to the
+// current state
+func (p *planner) canonicalRefs() {
+	canonical := func(k *kindState, ref string) string {
+		if r, ok := k.byRef[ref]; ok && r.target != "" {
+			return r.target
+		}
+		return ref
+	}
+
+	for i := range p.doc.Monitors {
+		m := &p.doc.Monitors[i]
+		if m.Group != "" {
+			m.Group = canonical(p.st.groups, m.Group)
+		}
+		for j := range m.Alerts {
+			m.Alerts[j].Contact = canonical(p.st.contacts, m.Alerts[j].Contact)
+		}
+		*m = m.normalized()
+	}
+	for i := range p.doc.StatusPages {
+		for _, s := range p.doc.StatusPages[i].Sections {
+			for j := range s.Monitors {
+				s.Monitors[j].Monitor = canonical(p.st.monitors, s.Monitors[j].Monitor)
+			}
+		}
+	}
+}
+
+// fillDisplayNames names page monitors after their host where the document
+// doesn't, as the layout endpoint does
+func (p *planner) fillDisplayNames() {
+	urls := make(map[string]string)
+	for _, m := range p.st.monitorRows {
+		urls[p.st.monitors.byID[m.ID].ref] = m.Url
+	}
+	for _, m := range p.doc.Monitors {
+		urls[m.ExternalID] = m.URL
+	}
+
+	for i := range p.doc.StatusPages {
+		sections := p.doc.StatusPages[i].Sections
+		for j := range sections {
+			for k, m := range sections[j].Monitors {
+				if m.DisplayName == "" {
+					sections[j].Monitors[k].DisplayName = statuspage.DefaultDisplayName(urls[m.Monitor])
+				}
+			}
+		}
+	}
+}
+
+// add records the change between two specs, either of which may be nil
+func (p *planner) add(kind, externalID string, r *resource, before, after any) {
+	change := Change{Kind: kind, ExternalID: externalID, before: before, after: after}
+	if r != nil {
+		change.ID = r.id
+		change.Adopted = !r.managed && after != nil
+	}
+
+	beforeJSON, afterJSON := marshalSpec(before), marshalSpec(after)
+	switch {
+	case before == nil:
+		change.Action = audit.ActionCreate
+		p.summary.Create++
+	case after == nil:
+		change.Action = audit.ActionDelete
+		p.summary.Delete++
+	case jsondiff.Equal(beforeJSON, afterJSON) && !change.Adopted:
+		p.summary.Unchanged++
+		return
+	default:
+		change.Action = audit.ActionUpdate
+		p.summary.Update++
+	}
+	change.Changes = jsondiff.Diff(beforeJSON, afterJSON)
+	p.changes = append(p.changes, change)
+}
+
+func marshalSpec(spec any) json.RawMessage {
+	if spec == nil {
+		return nil
+	}
+	data, _ := json.Marshal(spec)
+	return data
+}
+
+func (p *planner) diffGroups() {
+	rows := make(map[int32]db.MonitorGroup, len(p.st.groupRows))
+	for _, g := range p.st.groupRows {
+		rows[g.ID] = g
+	}
+	for i, g := range p.doc.MonitorGroups {
+		if r := p.groupMatches[i]; r != nil {
+			p.add(p.st.groups.kind, g.ExternalID, r, p.st.groupSpec(rows[r.id]), g)
+		} else {
+			p.add(p.st.groups.kind, g.ExternalID, nil, nil, g)
+		}
+	}
+}
+
+func (p *planner) diffContacts() {
+	rows := make(map[int32]db.AlertContact, len(p.st.contactRows))
+	for _, c := range p.st.contactRows {
+		rows[c.ID] = c
+	}
+	for i, c := range p.doc.AlertContacts {
+		if r := p.contactMatches[i]; r != nil {
+			p.add(p.st.contacts.kind, c.ExternalID, r, p.st.contactSpec(rows[r.id]), c)
+		} else {
+			p.add(p.st.contacts.kind, c.ExternalID, nil, nil, c)
+		}
+	}
+}
+
+func (p *planner) diffMonitors() {
+	rows := make(map[int32]db.Monitor, len(p.st.monitorRows))
+	for _, m := range p.st.monitorRows {
+		rows[m.ID] = m
+	}
+	for i, m := range p.doc.Monitors {
+		if r := p.monitorMatches[i]; r != nil {
+			p.add(p.st.monitors.kind, m.ExternalID, r, p.st.monitorSpec(rows[r.id]), m)
+		} else {
+			p.add(p.st.monitors.kind, m.ExternalID, nil, nil, m)
+		}
+	}
+}
+
+func (p *planner) diffPages() {
+	rows := make(map[int32]db.StatusPage, len(p.st.pageRows))
+	for _, page := range p.st.pageRows {
+		rows[page.ID] = page
+	}
+	for i, page := range p.doc.StatusPages {
+		if r := p.pageMatches[i]; r != nil {
+			p.add(p.st.pages.kind, page.ExternalID, r, p.st.pageSpec(rows[r.id]), page)
+		} else {
+			p.add(p.st.pages.kind, page.ExternalID, nil, nil, page)
+		}
+	}
+}
+
+// diffUnlisted deletes managed resources the document no longer lists when
+// pruning, dependents first
+func (p *planner) diffUnlisted() {
+	for _, page := range p.st.pageRows {
+		p.unlisted(p.st.pages, page.ID, p.st.pageSpec(page))
+	}
+	for _, m := range p.st.monitorRows {
+		p.unlisted(p.st.monitors, m.ID, p.st.monitorSpec(m))
+	}
+	for _, c := range p.st.contactRows {
+		p.unlisted(p.st.contacts, c.ID, p.st.contactSpec(c))
+	}
+	for _, g := range p.st.groupRows {
+		p.unlisted(p.st.groups, g.ID, p.st.groupSpec(g))
+	}
+}
+
+func (p *planner) unlisted(k *kindState, id int32, spec any) {
+	r := k.byID[id]
+	switch {
+	case r.target != "":
+	case !r.managed:
+		p.summary.Unmanaged++
+	case !p.prune:
+		p.summary.Unlisted++
+	default:
+		p.add(k.kind, r.ref, r, spec, nil)
+	}
+}
+
+// checkPlan holds the result to the organization's plan. Only totals that
+// grow and intervals that change are checked, so an organization over its
+// limits after a downgrade can still apply its document.
+func (p *planner) checkPlan() error {
+	monitors := int64(len(p.st.monitorRows))
+	contacts := int64(len(p.st.contactRows))
+	pages := int64(len(p.st.pageRows))
+
+	for _, c := range p.changes {
+		delta := int64(0)
+		switch c.Action {
+		case audit.ActionCreate:
+			delta = 1
+		case audit.ActionDelete:
+			delta = -1
+		}
+
+		switch c.Kind {
+		case p.st.monitors.kind:
+			monitors += delta
+			after, ok := c.after.(MonitorSpec)
+			if !ok {
+				continue
+			}
+			before, existed := c.before.(MonitorSpec)
+			if !existed || before.Interval != after.Interval {
+				if err := p.limit.CheckInterval(after.Interval); err != nil {
+					return err
+				}
+			}
+		case p.st.contacts.kind:
+			contacts += delta
+		case p.st.pages.kind:
+			pages += delta
+		}
+	}
+	return p.limit.CheckTotals(
+		grown(monitors, int64(len(p.st.monitorRows))),
+		grown(contacts, int64(len(p.st.contactRows))),
+		grown(pages, int64(len(p.st.pageRows))),
+	)
+}
+
+// grown is total when it exceeds was, else zero, which no limit rejects
+func grown(total, was int64) int64 {
+	if total > was {
+		return total
+	}
+	return 0
+}
@@ -0,0 +1,281 @@
+package manifest
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/internal/api/monitor"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// resource is an existing monitor group, alert contact, monitor or status page
+type resource struct {
+	id int32
+	// ref is the external id, or a generated one for unmanaged resources
+	ref     string
+	managed bool
+	// key is the natural key a document may adopt an unmanaged resource by,
+	// like a monitor's URL
+	key string
+	// target is the external id of the document entry matched to the resource
+	target string
+}
+
+// finalRef is how the resource is referred to once the document is applied
+func (r *resource) finalRef() string {
+	if r.target != "" {
+		return r.target
+	}
+	return r.ref
+}
+
+// kindState indexes the existing resources of one kind
+type kindState struct {
+	kind  string
+	list  []*resource
+	byID  map[int32]*resource
+	byRef map[string]*resource
+	byKey map[string][]*resource
+}
+
+func newKindState(kind string) *kindState {
+	return &kindState{
+		kind:  kind,
+		byID:  make(map[int32]*resource),
+		byRef: make(map[string]*resource),
+		byKey: make(map[string][]*resource),
+	}
+}
+
+// generatedRef names a resource that has no external id yet
+func generatedRef(kind string, id int32) string {
+	return fmt.Sprintf("%s-%d", kind, id)
+}
+
+func (k *kindState) add(id int32, externalID pgtype.Text, key string) {
+	r := &resource{id: id, ref: externalID.String, managed: externalID.Valid, key: key}
+	if !r.managed {
+		r.ref = generatedRef(k.kind, id)
+		k.byKey[key] = append(k.byKey[key], r)
+	}
+	k.list = append(k.list, r)
+	k.byID[id] = r
+	// An external id that looks generated must not hide the resource it names
+	if existing, taken := k.byRef[r.ref]; !taken || !existing.managed {
+		k.byRef[r.ref] = r
+	}
+}
+
+// matchRef finds the resource with an external id, or the unmanaged one it
+// names by generated id
+func (k *kindState) matchRef(externalID string) *resource {
+	if r, ok := k.byRef[externalID]; ok && r.target == "" {
+		return r
+	}
+	return nil
+}
+
+// matchKey finds the unmanaged resource with a natural key, when there is
+// exactly one left to adopt
+func (k *kindState) matchKey(key string) *resource {
+	var candidate *resource
+	for _, r := range k.byKey[key] {
+		if r.target != "" {
+			continue
+		}
+		if candidate != nil {
+			return nil
+		}
+		candidate = r
+	}
+	return candidate
+}
+
+// refOf is the final ref of the resource with id, empty when there is none
+func (k *kindState) refOf(id int32) string {
+	if r, ok := k.byID[id]; ok {
+		return r.finalRef()
+	}
+	return ""
+}
+
+// state is everything a document describes as it is now
+type state struct {
+	groups   *kindState
+	contacts *kindState
+	monitors *kindState
+	pages    *kindState
+
+	groupRows    []db.MonitorGroup
+	contactRows  []db.AlertContact
+	monitorRows  []db.Monitor
+	pageRows     []db.StatusPage
+	alerts       map[int32][]db.MonitorAlertConfig
+	sections     map[int32][]db.StatusPageSection
+	pageMonitors map[int32][]db.GetStatusPageMonitorsRow
+}
+
+func loadState(ctx context.Context, store db.Store, orgID uuid.UUID) (*state, error) {
+	st := &state{
+		groups:       newKindState(audit.ResourceMonitorGroup),
+		contacts:     newKindState(audit.ResourceAlertContact),
+		monitors:     newKindState(audit.ResourceMonitor),
+		pages:        newKindState(audit.ResourceStatusPage),
+		alerts:       make(map[int32][]db.MonitorAlertConfig),
+		sections:     make(map[int32][]db.StatusPageSection),
+		pageMonitors: make(map[int32][]db.GetStatusPageMonitorsRow),
+	}
+
+	var err error
+	if st.groupRows, err = store.GetOrgMonitorGroups(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if st.contactRows, err = store.GetAlertContactsByOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if st.monitorRows, err = store.GetOrgMonitors(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if st.pageRows, err = store.GetStatusPagesByOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+	configs, err := store.GetOrgMonitorAlertConfigs(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range st.groupRows {
+		st.groups.add(g.ID, g.ExternalID, g.Name)
+	}
+	for _, c := range st.contactRows {
+		st.contacts.add(c.ID, c.ExternalID, strings.ToLower(c.Email))
+	}
+	for _, m := range st.monitorRows {
+		st.monitors.add(m.ID, m.ExternalID, m.Url)
+	}
+	for _, p := range st.pageRows {
+		st.pages.add(p.ID, p.ExternalID, p.Slug)
+
+		if st.sections[p.ID], err = store.GetStatusPageSections(ctx, p.ID); err != nil {
+			return nil, err
+		}
+		if st.pageMonitors[p.ID], err = store.GetStatusPageMonitors(ctx, p.ID); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range configs {
+		st.alerts[c.MonitorID.Int32] = append(st.alerts[c.MonitorID.Int32], c)
+	}
+	return st, nil
+}
+
+func (st *state) groupSpec(g db.MonitorGroup) GroupSpec {
+	return GroupSpec{
+		ExternalID:   st.groups.refOf(g.ID),
+		Name:         g.Name,
+		Description:  g.Description,
+		Rollup:       g.Rollup,
+		MinUpPercent: g.MinUpPercent,
+	}.normalized()
+}
+
+func (st *state) contactSpec(c db.AlertContact) ContactSpec {
+	return ContactSpec{
+		ExternalID: st.contacts.refOf(c.ID),
+		Name:       c.Name,
+		Email:      c.Email,
+	}.normalized()
+}
+
+// monitorSpec masks sensitive values left in the config, so exporting and
+// applying the export changes nothing
+func (st *state) monitorSpec(m db.Monitor) MonitorSpec {
+	spec := MonitorSpec{
+		ExternalID: st.monitors.refOf(m.ID),
+		URL:        m.Url,
+		Type:       m.Type.String,
+		Method:     m.Method.String,
+		Interval:   m.Interval,
+		Paused:     m.IsActive.Valid && !m.IsActive.Bool,
+		Tags:       m.Tags,
+		Config:     monitor.MaskConfig(m.Config),
+	}
+	if m.GroupID.Valid {
+		spec.Group = st.groups.refOf(m.GroupID.Int32)
+	}
+	for _, c := range st.alerts[m.ID] {
+		onDown := c.AlertOnDown.Bool
+		spec.Alerts = append(spec.Alerts, AlertSpec{
+			Contact:         st.contacts.refOf(c.AlertContactID.Int32),
+			OnDown:          &onDown,
+			OnUp:            c.AlertOnUp.Bool,
+			OnSlow:          c.AlertOnSlow.Bool,
+			SlowThresholdMs: c.SlowThresholdMs.Int32,
+		})
+	}
+	return spec.normalized()
+}
+
+func (st *state) pageSpec(p db.StatusPage) StatusPageSpec {
+	spec := StatusPageSpec{
+		ExternalID:   st.pages.refOf(p.ID),
+		Slug:         p.Slug,
+		Title:        p.Title,
+		Description:  p.Description.String,
+		CustomDomain: p.CustomDomain.String,
+		Published:    p.IsPublished.Bool,
+	}
+
+	// Sections and their monitors come ordered by position
+	index := make(map[int32]int)
+	for _, s := range st.sections[p.ID] {
+		index[s.ID] = len(spec.Sections)
+		spec.Sections = append(spec.Sections, SectionSpec{Name: s.Name})
+	}
+	for _, m := range st.pageMonitors[p.ID] {
+		i, ok := index[m.SectionID]
+		if !ok {
+			continue
+		}
+		spec.Sections[i].Monitors = append(spec.Sections[i].Monitors, PageMonitorSpec{
+			Monitor:     st.monitors.refOf(m.MonitorID),
+			DisplayName: m.DisplayName,
+		})
+	}
+	return spec.normalized()
+}
+
+// export describes the current state as a document, sorted by external id so
+// that exports diff cleanly
+func (st *state) export() Document {
+	doc := Document{
+		Version:       Version,
+		MonitorGroups: []GroupSpec{},
+		AlertContacts: []ContactSpec{},
+		Monitors:      []MonitorSpec{},
+		StatusPages:   []StatusPageSpec{},
+	}
+	for _, g := range st.groupRows {
+		doc.MonitorGroups = append(doc.MonitorGroups, st.groupSpec(g))
+	}
+	for _, c := range st.contactRows {
+		doc.AlertContacts = append(doc.AlertContacts, st.contactSpec(c))
+	}
+	for _, m := range st.monitorRows {
+		doc.Monitors = append(doc.Monitors, st.monitorSpec(m))
+	}
+	for _, p := range st.pageRows {
+		doc.StatusPages = append(doc.StatusPages, st.pageSpec(p))
+	}
+
+	sort.Slice(doc.MonitorGroups, func(i, j int) bool { return doc.MonitorGroups[i].ExternalID < doc.MonitorGroups[j].ExternalID })
+	sort.Slice(doc.AlertContacts, func(i, j int) bool { return doc.AlertContacts[i].ExternalID < doc.AlertContacts[j].ExternalID })
+	sort.Slice(doc.Monitors, func(i, j int) bool { return doc.Monitors[i].ExternalID < doc.Monitors[j].ExternalID })
+	sort.Slice(doc.StatusPages, func(i, j int) bool { return doc.StatusPages[i].ExternalID < doc.StatusPages[j].ExternalID })
+	return doc
+}
@@ -0,0 +1,215 @@
+package manifest
+
+import (
+	"better-uptime/internal/api/monitor"
+	"better-uptime/internal/api/statuspage"
+	"bytes"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultSlowThresholdMs matches the monitor_alert_configs column default
+const defaultSlowThresholdMs = 5000
+
+var externalIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]{0,127}$`)
+
+// The normalized forms fill in defaults, so a document that leaves them out
+// and one that spells them out plan the same changes
+
+func (g GroupSpec) normalized() GroupSpec {
+	g.Name = strings.TrimSpace(g.Name)
+	if g.Rollup == "" {
+		g.Rollup = monitor.RollupWorst
+	}
+	if g.MinUpPercent == 0 {
+		g.MinUpPercent = 100
+	}
+	return g
+}
+
+func (c ContactSpec) normalized() ContactSpec {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+	return c
+}
+
+func (m MonitorSpec) normalized() MonitorSpec {
+	m.URL = strings.TrimSpace(m.URL)
+	m.Type = strings.ToLower(m.Type)
+	if m.Type == "" {
+		m.Type = monitor.MonitorTypeHTTP
+	}
+	m.Method = strings.ToUpper(m.Method)
+	if m.Method == "" {
+		m.Method = "GET"
+	}
+	if tags, err := monitor.NormalizeTags(m.Tags); err == nil {
+		m.Tags = tags
+	}
+	if len(m.Tags) == 0 {
+		m.Tags = nil
+	}
+	if trimmed := bytes.TrimSpace(m.Config); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		m.Config = nil
+	}
+
+	alerts := make([]AlertSpec, 0, len(m.Alerts))
+	for _, a := range m.Alerts {
+		if a.OnDown == nil {
+			onDown := true
+			a.OnDown = &onDown
+		}
+		if a.SlowThresholdMs == 0 {
+			a.SlowThresholdMs = defaultSlowThresholdMs
+		}
+		alerts = append(alerts, a)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Contact < alerts[j].Contact })
+	m.Alerts = nil
+	if len(alerts) > 0 {
+		m.Alerts = alerts
+	}
+	return m
+}
+
+func (p StatusPageSpec) normalized() StatusPageSpec {
+	p.Slug = strings.TrimSpace(p.Slug)
+	p.CustomDomain = strings.ToLower(strings.TrimSpace(p.CustomDomain))
+	if len(p.Sections) == 0 {
+		p.Sections = nil
+	}
+	return p
+}
+
+// entryError names the document entry a problem is in
+func entryError(list string, i int, externalID string, format string, args ...any) error {
+	return fmt.Errorf("%s[%d] (%s): %s", list, i, externalID, fmt.Sprintf(format, args...))
+}
+
+// validateDocument checks each entry on its own and normalizes it. References
+// between entries are checked once the document is matched to the current
+// state, since they may name existing resources.
+func validateDocument(doc *Document) error {
+	if err := checkExternalIDs("monitor_groups", len(doc.MonitorGroups), func(i int) string { return doc.MonitorGroups[i].ExternalID }); err != nil {
+		return err
+	}
+	if err := checkExternalIDs("alert_contacts", len(doc.AlertContacts), func(i int) string { return doc.AlertContacts[i].ExternalID }); err != nil {
+		return err
+	}
+	if err := checkExternalIDs("monitors", len(doc.Monitors), func(i int) string { return doc.Monitors[i].ExternalID }); err != nil {
+		return err
+	}
+	if err := checkExternalIDs("status_pages", len(doc.StatusPages), func(i int) string { return doc.StatusPages[i].ExternalID }); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for i, g := range doc.MonitorGroups {
+		g = g.normalized()
+		switch {
+		case g.Name == "" || len(g.Name) > 120:
+			return entryError("monitor_groups", i, g.ExternalID, "name must be 1 to 120 characters")
+		case names[g.Name]:
+			return entryError("monitor_groups", i, g.ExternalID, "name %q is used by another group", g.Name)
+		case len(g.Description) > 500:
+			return entryError("monitor_groups", i, g.ExternalID, "description is longer than 500 characters")
+		case g.Rollup != monitor.RollupWorst && g.Rollup != monitor.RollupPercentage:
+			return entryError("monitor_groups", i, g.ExternalID, "rollup must be %s or %s", monitor.RollupWorst, monitor.RollupPercentage)
+		case g.MinUpPercent < 1 || g.MinUpPercent > 100:
+			return entryError("monitor_groups", i, g.ExternalID, "min_up_percent must be between 1 and 100")
+		}
+		names[g.Name] = true
+		doc.MonitorGroups[i] = g
+	}
+
+	for i, c := range doc.AlertContacts {
+		c = c.normalized()
+		if c.Name == "" {
+			return entryError("alert_contacts", i, c.ExternalID, "name is required")
+		}
+		if _, err := mail.ParseAddress(c.Email); err != nil {
+			return entryError("alert_contacts", i, c.ExternalID, "invalid email %q", c.Email)
+		}
+		doc.AlertContacts[i] = c
+	}
+
+	for i, m := range doc.Monitors {
+		if _, err := monitor.NormalizeTags(m.Tags); err != nil {
+			return entryError("monitors", i, m.ExternalID, "%v", err)
+		}
+		m = m.normalized()
+		if m.URL == "" {
+			return entryError("monitors", i, m.ExternalID, "url is required")
+		}
+		if m.Interval < 1 {
+			return entryError("monitors", i, m.ExternalID, "interval must be at least 1 second")
+		}
+		if err := monitor.ValidateMonitor(m.Type, m.URL, m.Config); err != nil {
+			return entryError("monitors", i, m.ExternalID, "%v", err)
+		}
+		contacts := make(map[string]bool)
+		for _, a := range m.Alerts {
+			if contacts[a.Contact] {
+				return entryError("monitors", i, m.ExternalID, "alerts list contact %q more than once", a.Contact)
+			}
+			if a.SlowThresholdMs < 0 {
+				return entryError("monitors", i, m.ExternalID, "slow_threshold_ms must not be negative")
+			}
+			contacts[a.Contact] = true
+		}
+		doc.Monitors[i] = m
+	}
+
+	slugs := make(map[string]bool)
+	for i, p := range doc.StatusPages {
+		p = p.normalized()
+		switch {
+		case !statuspage.ValidSlug(p.Slug):
+			return entryError("status_pages", i, p.ExternalID, "invalid slug %q", p.Slug)
+		case slugs[p.Slug]:
+			return entryError("status_pages", i, p.ExternalID, "slug %q is used by another page", p.Slug)
+		case p.Title == "" || len(p.Title) > 120:
+			return entryError("status_pages", i, p.ExternalID, "title must be 1 to 120 characters")
+		case len(p.Description) > 500:
+			return entryError("status_pages", i, p.ExternalID, "description is longer than 500 characters")
+		}
+		shown := make(map[string]bool)
+		for _, s := range p.Sections {
+			if s.Name == "" || len(s.Name) > 120 {
+				return entryError("status_pages", i, p.ExternalID, "section names must be 1 to 120 characters")
+			}
+			for _, m := range s.Monitors {
+				if shown[m.Monitor] {
+					return entryError("status_pages", i, p.ExternalID, "monitor %q appears more than once", m.Monitor)
+				}
+				if len(m.DisplayName) > 120 {
+					return entryError("status_pages", i, p.ExternalID, "display names must be at most 120 characters")
+				}
+				shown[m.Monitor] = true
+			}
+		}
+		slugs[p.Slug] = true
+		doc.StatusPages[i] = p
+	}
+	return nil
+}
+
+// checkExternalIDs requires every entry of a list to have a well-formed
+// external id of its own
+func checkExternalIDs(list string, n int, externalID func(int) string) error {
+	seen := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		id := externalID(i)
+		if !externalIDPattern.MatchString(id) {
+			return entryError(list, i, id, "external_id must be 1 to 128 letters, digits or ._:/- and start with a letter or digit")
+		}
+		if seen[id] {
+			return entryError(list, i, id, "external_id is used by another entry")
+		}
+		seen[id] = true
+	}
+	return nil
+}
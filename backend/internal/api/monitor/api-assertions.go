@@ -0,0 +1,210 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Extraction saves part of a step's response into a variable.
+// From is "json" (Path like data.items[0].id), "header" (Header) or
+// "regex" (Regex over the body; the first group, or the whole match).
+type Extraction struct {
+	Var    string `json:"var"`
+	From   string `json:"from"`
+	Path   string `json:"path,omitempty"`
+	Header string `json:"header,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+// Assertion checks a step's response. Source is "status", "header", "json",
+// "body" or "response_time"; Property names the header or JSON path.
+// Op is one of equals, not_equals, contains, matches, lt, gt or exists.
+type Assertion struct {
+	Source   string `json:"source"`
+	Property string `json:"property,omitempty"`
+	Op       string `json:"op"`
+	Value    string `json:"value,omitempty"`
+}
+
+var assertionOps = map[string]bool{
+	"equals":     true,
+	"not_equals": true,
+	"contains":   true,
+	"matches":    true,
+	"lt":         true,
+	"gt":         true,
+	"exists":     true,
+}
+
+func (e Extraction) validate() error {
+	if e.Var == "" {
+		return fmt.Errorf("extract needs a var name")
+	}
+	switch e.From {
+	case "json":
+		if e.Path == "" {
+			return fmt.Errorf("extract %q needs a path", e.Var)
+		}
+	case "header":
+		if e.Header == "" {
+			return fmt.Errorf("extract %q needs a header", e.Var)
+		}
+	case "regex":
+		if _, err := regexp.Compile(e.Regex); err != nil || e.Regex == "" {
+			return fmt.Errorf("extract %q needs a valid regex", e.Var)
+		}
+	default:
+		return fmt.Errorf("extract %q: from must be json, header or regex", e.Var)
+	}
+	return nil
+}
+
+func (e Extraction) extract(resp tracedResponse) (string, error) {
+	switch e.From {
+	case "json":
+		value, ok := lookupJSON(resp.body, e.Path)
+		if !ok {
+			return "", fmt.Errorf("could not extract %q: no JSON value at %s", e.Var, e.Path)
+		}
+		return value, nil
+	case "header":
+		value := resp.header.Get(e.Header)
+		if value == "" {
+			return "", fmt.Errorf("could not extract %q: no %s header", e.Var, e.Header)
+		}
+		return value, nil
+	default:
+		match := regexp.MustCompile(e.Regex).FindSubmatch(resp.body)
+		if match == nil {
+			return "", fmt.Errorf("could not extract %q: body does not match %s", e.Var, e.Regex)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	}
+}
+
+func (a Assertion) validate() error {
+	switch a.Source {
+	case "status", "body", "response_time":
+	case "header", "json":
+		if a.Property == "" {
+			return fmt.Errorf("%s assertion needs a property", a.Source)
+		}
+	default:
+		return fmt.Errorf("unknown assertion source %q", a.Source)
+	}
+	if !assertionOps[a.Op] {
+		return fmt.Errorf("unknown assertion op %q", a.Op)
+	}
+	if a.Op == "matches" {
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("invalid assertion regex: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a Assertion) check(outcome checkOutcome, resp tracedResponse) error {
+	var actual string
+	found := true
+	switch a.Source {
+	case "status":
+		actual = strconv.Itoa(int(outcome.statusCode))
+	case "response_time":
+		actual = strconv.FormatFloat(outcome.responseTime, 'f', 0, 64)
+	case "body":
+		actual = string(resp.body)
+	case "header":
+		actual = resp.header.Get(a.Property)
+		found = actual != ""
+	case "json":
+		actual, found = lookupJSON(resp.body, a.Property)
+	}
+
+	subject := a.Source
+	if a.Property != "" {
+		subject = fmt.Sprintf("%s %q", a.Source, a.Property)
+	}
+	if !found {
+		return fmt.Errorf("assertion failed: %s is missing", subject)
+	}
+
+	ok := false
+	switch a.Op {
+	case "exists":
+		ok = true
+	case "equals":
+		ok = actual == a.Value
+	case "not_equals":
+		ok = actual != a.Value
+	case "contains":
+		ok = strings.Contains(actual, a.Value)
+	case "matches":
+		ok = regexp.MustCompile(a.Value).MatchString(actual)
+	case "lt", "gt":
+		got, errGot := strconv.ParseFloat(actual, 64)
+		want, errWant := strconv.ParseFloat(a.Value, 64)
+		if errGot == nil && errWant == nil {
+			ok = (a.Op == "lt" && got < want) || (a.Op == "gt" && got > want)
+		}
+	}
+	if ok {
+		return nil
+	}
+
+	// Keep long bodies out of the failure message
+	if len(actual) > 200 {
+		actual = actual[:200] + "..."
+	}
+	return fmt.Errorf("assertion failed: %s %s %q, got %q", subject, a.Op, a.Value, actual)
+}
+
+// lookupJSON follows a dot path such as data.items[0].id (or $.data.items.0.id)
+// through a JSON document. Strings are returned as is, other values as JSON.
+func lookupJSON(body []byte, path string) (string, bool) {
+	var node any
+	if err := json.Unmarshal(body, &node); err != nil {
+		return "", false
+	}
+
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	if path != "" {
+		for _, part := range strings.Split(path, ".") {
+			switch v := node.(type) {
+			case map[string]any:
+				next, ok := v[part]
+				if !ok {
+					return "", false
+				}
+				node = next
+			case []any:
+				i, err := strconv.Atoi(part)
+				if err != nil || i < 0 || i >= len(v) {
+					return "", false
+				}
+				node = v[i]
+			default:
+				return "", false
+			}
+		}
+	}
+
+	switch v := node.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(raw), true
+	}
+}
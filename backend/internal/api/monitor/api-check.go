@@ -0,0 +1,237 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxAPISteps bounds how many requests one check of an api monitor makes
+	maxAPISteps = 20
+	// maxStepBodyBytes is how much of a step's response is kept for extractions and assertions
+	maxStepBodyBytes = 1 << 20
+)
+
+// APICheckConfig is the config of an "api" monitor. Steps run in order, values
+// extracted from one response are available to later steps as {{name}}.
+type APICheckConfig struct {
+	Variables map[string]string `json:"variables,omitempty"`
+	Steps     []APIStep         `json:"steps"`
+}
+
+type APIStep struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Extract []Extraction      `json:"extract,omitempty"`
+	// Without a status assertion the step expects a 2xx or 3xx response
+	Assert []Assertion `json:"assert,omitempty"`
+}
+
+// StepResult is stored per step in monitor_logs.step_results
+type StepResult struct {
+	Name         string       `json:"name"`
+	Method       string       `json:"method"`
+	URL          string       `json:"url"`
+	StatusCode   int32        `json:"status_code"`
+	ResponseTime float64      `json:"response_time"`
+	Timings      PhaseTimings `json:"timings"`
+	OK           bool         `json:"ok"`
+	Error        string       `json:"error,omitempty"`
+}
+
+var templateVar = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// parseAPICheckConfig decodes and validates an api monitor's config
+func parseAPICheckConfig(raw json.RawMessage) (*APICheckConfig, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("api monitors need a config with steps")
+	}
+
+	var cfg APICheckConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid api monitor config: %w", err)
+	}
+	if len(cfg.Steps) == 0 || len(cfg.Steps) > maxAPISteps {
+		return nil, fmt.Errorf("api monitors need between 1 and %d steps", maxAPISteps)
+	}
+
+	for i := range cfg.Steps {
+		step := &cfg.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+		if step.Method == "" {
+			step.Method = http.MethodGet
+		}
+		if step.URL == "" {
+			return nil, fmt.Errorf("%s: url is required", step.Name)
+		}
+		for name, value := range step.Headers {
+			if err := checkSensitiveHeader(name, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}
+		for _, e := range step.Extract {
+			if err := e.validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}
+		for _, a := range step.Assert {
+			if err := a.validate(); err != nil {
+				return nil, fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}
+	}
+
+	return &cfg, nil
+}
+
+// runAPICheck runs the steps of an api monitor and stops at the first failing one.
+// Response time and phase timings are summed over the steps that ran.
+func runAPICheck(ctx context.Context, raw json.RawMessage, values map[string]string) checkOutcome {
+	cfg, err := parseAPICheckConfig(raw)
+	if err != nil {
+		return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}
+	}
+
+	vars := secretVars(values)
+	for k, v := range cfg.Variables {
+		vars[k] = v
+	}
+
+	overall := checkOutcome{dnsOk: true, sslOk: true}
+	for i, step := range cfg.Steps {
+		outcome, resp := runAPIStep(ctx, step, vars)
+
+		result := StepResult{
+			Name:         step.Name,
+			Method:       step.Method,
+			URL:          step.URL,
+			StatusCode:   outcome.statusCode,
+			ResponseTime: outcome.responseTime,
+			Timings:      outcome.timings,
+			OK:           outcome.errorType == ErrorNone,
+			Error:        outcome.errorMsg,
+		}
+		overall.steps = append(overall.steps, result)
+
+		overall.statusCode = outcome.statusCode
+		overall.responseTime += outcome.responseTime
+		overall.dnsOk = overall.dnsOk && outcome.dnsOk
+		overall.sslOk = overall.sslOk && outcome.sslOk
+		overall.timings.add(outcome.timings)
+
+		if !result.OK {
+			overall.errorType = outcome.errorType
+			overall.errorMsg = fmt.Sprintf("Step %d (%s) failed: %s", i+1, step.Name, outcome.errorMsg)
+			overall.attachEvidence(resp)
+			return overall
+		}
+	}
+
+	return overall
+}
+
+// runAPIStep sends one step, then applies its assertions and extractions.
+// Extracted values are added to vars for the following steps.
+func runAPIStep(ctx context.Context, step APIStep, vars map[string]string) (checkOutcome, tracedResponse) {
+	url, err := renderTemplate(step.URL, vars)
+	if err != nil {
+		return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}, tracedResponse{}
+	}
+	body, err := renderTemplate(step.Body, vars)
+	if err != nil {
+		return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}, tracedResponse{}
+	}
+
+	req, err := http.NewRequest(step.Method, url, strings.NewReader(body))
+	if err != nil {
+		return checkOutcome{
+			errorType: ErrorUnknown,
+			errorMsg:  fmt.Sprintf("Failed to create request: %s", err.Error()),
+		}, tracedResponse{}
+	}
+	for name, value := range step.Headers {
+		rendered, err := renderTemplate(value, vars)
+		if err != nil {
+			return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}, tracedResponse{}
+		}
+		req.Header.Set(name, rendered)
+	}
+
+	outcome, resp := tracedRequest(ctx, req, maxStepBodyBytes)
+	if outcome.errorType != ErrorNone {
+		return outcome, resp
+	}
+
+	checkedStatus := false
+	for _, a := range step.Assert {
+		checkedStatus = checkedStatus || a.Source == "status"
+		if err := a.check(outcome, resp); err != nil {
+			outcome.errorType = ErrorAssertionFailed
+			outcome.errorMsg = err.Error()
+			return outcome, resp
+		}
+	}
+	if !checkedStatus && (outcome.statusCode < 200 || outcome.statusCode >= 400) {
+		outcome.errorType = ErrorHTTPError
+		outcome.errorMsg = fmt.Sprintf("HTTP %d - %s", outcome.statusCode, http.StatusText(int(outcome.statusCode)))
+		return outcome, resp
+	}
+
+	for _, e := range step.Extract {
+		value, err := e.extract(resp)
+		if err != nil {
+			outcome.errorType = ErrorAssertionFailed
+			outcome.errorMsg = err.Error()
+			return outcome, resp
+		}
+		vars[e.Var] = value
+	}
+
+	return outcome, resp
+}
+
+// renderTemplate replaces {{name}} with the variable's value
+func renderTemplate(s string, vars map[string]string) (string, error) {
+	var missing string
+	rendered := templateVar.ReplaceAllStringFunc(s, func(m string) string {
+		name := templateVar.FindStringSubmatch(m)[1]
+		value, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable %q", missing)
+	}
+	return rendered, nil
+}
+
+// add sums b's phases into t, a phase stays nil only if neither had it
+func (t *PhaseTimings) add(b PhaseTimings) {
+	sum := func(a, b *float64) *float64 {
+		if b == nil {
+			return a
+		}
+		total := *b
+		if a != nil {
+			total += *a
+		}
+		return &total
+	}
+
+	t.DNSMs = sum(t.DNSMs, b.DNSMs)
+	t.ConnectMs = sum(t.ConnectMs, b.ConnectMs)
+	t.TLSMs = sum(t.TLSMs, b.TLSMs)
+	t.TTFBMs = sum(t.TTFBMs, b.TTFBMs)
+	t.TransferMs = sum(t.TransferMs, b.TransferMs)
+}
@@ -0,0 +1,372 @@
+package monitor
+
+import (
+	"better-uptime/common/screenshot"
+	db "better-uptime/internal/db/sqlc"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	defaultBrowserTimeout = 30 * time.Second
+	maxBrowserTimeout     = 2 * time.Minute
+	// maxBrowserFindings bounds the console errors and failed requests kept per check
+	maxBrowserFindings = 20
+)
+
+// BrowserCheckConfig is the config of a "browser" monitor. The page at the
+// monitor's URL is loaded in headless Chrome, then the actions run in order.
+type BrowserCheckConfig struct {
+	// WaitFor is a CSS selector that must become visible after the page loads,
+	// e.g. the root of a rendered SPA
+	WaitFor        string          `json:"wait_for,omitempty"`
+	Actions        []BrowserAction `json:"actions,omitempty"`
+	TimeoutSeconds int             `json:"timeout_seconds,omitempty"`
+	// The check fails on uncaught exceptions or console.error calls
+	FailOnConsoleErrors bool `json:"fail_on_console_errors,omitempty"`
+	// The check fails when a sub-resource fails to load or returns 4xx/5xx
+	FailOnFailedRequests bool `json:"fail_on_failed_requests,omitempty"`
+	// MaxLCPMs fails the check when Largest Contentful Paint is slower
+	MaxLCPMs float64 `json:"max_lcp_ms,omitempty"`
+}
+
+// BrowserAction is one scripted step. Type is click, fill, wait (for Selector
+// to be visible), sleep (Ms), navigate (URL) or assert_text (Selector contains Value).
+type BrowserAction struct {
+	Type     string `json:"type"`
+	Selector string `json:"selector,omitempty"`
+	Value    string `json:"value,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Ms       int    `json:"ms,omitempty"`
+}
+
+// BrowserResult is stored in monitor_logs.browser_result
+type BrowserResult struct {
+	DOMContentLoadedMs *float64        `json:"dom_content_loaded_ms"`
+	LoadMs             *float64        `json:"load_ms"`
+	LCPMs              *float64        `json:"lcp_ms"`
+	ConsoleErrors      []string        `json:"console_errors"`
+	FailedRequests     []FailedRequest `json:"failed_requests"`
+	// FailedAction is the 1-based index of the action that failed, if any
+	FailedAction int `json:"failed_action,omitempty"`
+}
+
+type FailedRequest struct {
+	URL        string `json:"url"`
+	StatusCode int64  `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// pageMetricsJS reads navigation timings and the latest LCP candidate
+const pageMetricsJS = `new Promise(resolve => {
+	const nav = performance.getEntriesByType('navigation')[0];
+	const metrics = {
+		dcl: nav ? nav.domContentLoadedEventEnd : null,
+		load: nav ? nav.loadEventEnd : null,
+		lcp: null,
+	};
+	try {
+		new PerformanceObserver(list => {
+			const entries = list.getEntries();
+			if (entries.length) metrics.lcp = entries[entries.length - 1].startTime;
+		}).observe({type: 'largest-contentful-paint', buffered: true});
+	} catch (e) {}
+	setTimeout(() => resolve(metrics), 100);
+})`
+
+// parseBrowserCheckConfig decodes and validates a browser monitor's config
+func parseBrowserCheckConfig(raw json.RawMessage) (*BrowserCheckConfig, error) {
+	var cfg BrowserCheckConfig
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid browser monitor config: %w", err)
+		}
+	}
+
+	if cfg.TimeoutSeconds < 0 || time.Duration(cfg.TimeoutSeconds)*time.Second > maxBrowserTimeout {
+		return nil, fmt.Errorf("timeout_seconds must be between 1 and %d", int(maxBrowserTimeout.Seconds()))
+	}
+	for i, a := range cfg.Actions {
+		switch a.Type {
+		case "click", "fill", "wait", "assert_text":
+			if a.Selector == "" {
+				return nil, fmt.Errorf("action %d: %s needs a selector", i+1, a.Type)
+			}
+		case "sleep":
+			if a.Ms <= 0 {
+				return nil, fmt.Errorf("action %d: sleep needs ms", i+1)
+			}
+		case "navigate":
+			if a.URL == "" {
+				return nil, fmt.Errorf("action %d: navigate needs a url", i+1)
+			}
+		default:
+			return nil, fmt.Errorf("action %d: unknown type %q", i+1, a.Type)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// pageListener collects what the page reports while it runs
+type pageListener struct {
+	mu sync.Mutex
+
+	requests       map[network.RequestID]string
+	documentStatus int64
+	consoleErrors  []string
+	failedRequests []FailedRequest
+}
+
+func (l *pageListener) handle(ev any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		l.requests[ev.RequestID] = ev.Request.URL
+	case *network.EventResponseReceived:
+		if ev.Type == network.ResourceTypeDocument && l.documentStatus == 0 {
+			l.documentStatus = ev.Response.Status
+		}
+		if ev.Response.Status >= 400 && len(l.failedRequests) < maxBrowserFindings {
+			l.failedRequests = append(l.failedRequests, FailedRequest{URL: ev.Response.URL, StatusCode: ev.Response.Status})
+		}
+	case *network.EventLoadingFailed:
+		if !ev.Canceled && len(l.failedRequests) < maxBrowserFindings {
+			l.failedRequests = append(l.failedRequests, FailedRequest{URL: l.requests[ev.RequestID], Error: ev.ErrorText})
+		}
+	case *runtime.EventExceptionThrown:
+		l.addConsoleError(exceptionText(ev.ExceptionDetails))
+	case *runtime.EventConsoleAPICalled:
+		if ev.Type == runtime.APITypeError {
+			args := make([]string, 0, len(ev.Args))
+			for _, arg := range ev.Args {
+				args = append(args, remoteObjectText(arg))
+			}
+			l.addConsoleError(strings.Join(args, " "))
+		}
+	}
+}
+
+func (l *pageListener) addConsoleError(msg string) {
+	if len(l.consoleErrors) < maxBrowserFindings {
+		l.consoleErrors = append(l.consoleErrors, msg)
+	}
+}
+
+func exceptionText(d *runtime.ExceptionDetails) string {
+	if d.Exception != nil && d.Exception.Description != "" {
+		return d.Exception.Description
+	}
+	return d.Text
+}
+
+func remoteObjectText(o *runtime.RemoteObject) string {
+	if o.Description != "" {
+		return o.Description
+	}
+	var s string
+	if err := json.Unmarshal(o.Value, &s); err == nil {
+		return s
+	}
+	return string(o.Value)
+}
+
+// runBrowserCheck loads the monitor's page in headless Chrome and runs its actions.
+// A screenshot of the final page is stored when the check fails. Action values
+// and URLs may reference {{secrets.<name>}}, e.g. to fill in a login form.
+func (h *Handler) runBrowserCheck(ctx context.Context, monitor db.Monitor, values map[string]string) checkOutcome {
+	cfg, err := parseBrowserCheckConfig(monitor.Config)
+	if err != nil {
+		return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}
+	}
+
+	vars := secretVars(values)
+	for i := range cfg.Actions {
+		a := &cfg.Actions[i]
+		if a.Value, err = renderTemplate(a.Value, vars); err == nil {
+			a.URL, err = renderTemplate(a.URL, vars)
+		}
+		if err != nil {
+			return checkOutcome{errorType: ErrorUnknown, errorMsg: fmt.Sprintf("Action %d: %s", i+1, err.Error())}
+		}
+	}
+
+	timeout := defaultBrowserTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	browserCtx, cancelBrowser := screenshot.NewChromeContext(ctx, h.config.CHROME_DEVTOOLS_URL)
+	defer cancelBrowser()
+
+	listener := &pageListener{requests: make(map[network.RequestID]string)}
+	chromedp.ListenTarget(browserCtx, listener.handle)
+
+	start := time.Now()
+	navErr := chromedp.Run(browserCtx, network.Enable(), chromedp.Navigate(monitor.Url))
+	outcome := checkOutcome{
+		responseTime: time.Since(start).Seconds() * 1000,
+		dnsOk:        true,
+		sslOk:        strings.HasPrefix(monitor.Url, "https://"),
+	}
+	result := &BrowserResult{}
+
+	switch {
+	case navErr != nil:
+		classifyBrowserError(&outcome, navErr)
+	default:
+		var metrics struct {
+			DCL  *float64 `json:"dcl"`
+			Load *float64 `json:"load"`
+			LCP  *float64 `json:"lcp"`
+		}
+		if err := chromedp.Run(browserCtx, chromedp.Evaluate(pageMetricsJS, &metrics, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		})); err != nil {
+			log.Printf("Failed to read page metrics for monitor %d: %v", monitor.ID, err)
+		}
+		result.DOMContentLoadedMs, result.LoadMs, result.LCPMs = metrics.DCL, metrics.Load, metrics.LCP
+		if metrics.Load != nil && *metrics.Load > 0 {
+			outcome.responseTime = *metrics.Load
+		}
+
+		h.runBrowserActions(browserCtx, cfg, &outcome, result)
+	}
+
+	listener.mu.Lock()
+	outcome.statusCode = int32(listener.documentStatus)
+	result.ConsoleErrors = listener.consoleErrors
+	result.FailedRequests = listener.failedRequests
+	listener.mu.Unlock()
+
+	if outcome.errorType == ErrorNone {
+		judgeBrowserResult(cfg, &outcome, result)
+	}
+	outcome.browser = result
+
+	if outcome.status() == "down" && navErr == nil {
+		outcome.screenshotKey = h.storeBrowserScreenshot(browserCtx, monitor)
+	}
+
+	return outcome
+}
+
+// runBrowserActions waits for the page to render and runs the scripted actions,
+// recording the first failure on outcome
+func (h *Handler) runBrowserActions(ctx context.Context, cfg *BrowserCheckConfig, outcome *checkOutcome, result *BrowserResult) {
+	if cfg.WaitFor != "" {
+		if err := chromedp.Run(ctx, chromedp.WaitVisible(cfg.WaitFor, chromedp.ByQuery)); err != nil {
+			outcome.errorType = ErrorBrowserCheck
+			outcome.errorMsg = fmt.Sprintf("Page did not render %s: %s", cfg.WaitFor, err.Error())
+			return
+		}
+	}
+
+	for i, a := range cfg.Actions {
+		var err error
+		switch a.Type {
+		case "click":
+			err = chromedp.Run(ctx, chromedp.Click(a.Selector, chromedp.ByQuery))
+		case "fill":
+			err = chromedp.Run(ctx, chromedp.SendKeys(a.Selector, a.Value, chromedp.ByQuery))
+		case "wait":
+			err = chromedp.Run(ctx, chromedp.WaitVisible(a.Selector, chromedp.ByQuery))
+		case "sleep":
+			err = chromedp.Run(ctx, chromedp.Sleep(time.Duration(a.Ms)*time.Millisecond))
+		case "navigate":
+			err = chromedp.Run(ctx, chromedp.Navigate(a.URL))
+		case "assert_text":
+			var text string
+			err = chromedp.Run(ctx, chromedp.Text(a.Selector, &text, chromedp.ByQuery))
+			if err == nil && !strings.Contains(text, a.Value) {
+				err = fmt.Errorf("%s does not contain %q", a.Selector, a.Value)
+			}
+		}
+
+		if err != nil {
+			result.FailedAction = i + 1
+			outcome.errorType = ErrorBrowserCheck
+			outcome.errorMsg = fmt.Sprintf("Action %d (%s) failed: %s", i+1, a.Type, err.Error())
+			return
+		}
+	}
+}
+
+// judgeBrowserResult turns what the page reported into a failure, per the config
+func judgeBrowserResult(cfg *BrowserCheckConfig, outcome *checkOutcome, result *BrowserResult) {
+	switch {
+	case outcome.statusCode == 0:
+		outcome.errorType = ErrorBrowserCheck
+		outcome.errorMsg = "No response received for the page document"
+	case outcome.statusCode >= 400:
+		outcome.errorType = ErrorHTTPError
+		outcome.errorMsg = fmt.Sprintf("Page returned HTTP %d", outcome.statusCode)
+	case cfg.FailOnConsoleErrors && len(result.ConsoleErrors) > 0:
+		outcome.errorType = ErrorBrowserCheck
+		outcome.errorMsg = fmt.Sprintf("%d console error(s), first: %s", len(result.ConsoleErrors), result.ConsoleErrors[0])
+	case cfg.FailOnFailedRequests && len(result.FailedRequests) > 0:
+		outcome.errorType = ErrorBrowserCheck
+		outcome.errorMsg = fmt.Sprintf("%d failed request(s), first: %s", len(result.FailedRequests), result.FailedRequests[0].URL)
+	case cfg.MaxLCPMs > 0 && result.LCPMs != nil && *result.LCPMs > cfg.MaxLCPMs:
+		outcome.errorType = ErrorBrowserCheck
+		outcome.errorMsg = fmt.Sprintf("Largest Contentful Paint %.0fms exceeds %.0fms", *result.LCPMs, cfg.MaxLCPMs)
+	}
+}
+
+// classifyBrowserError maps Chrome's net::ERR_* navigation errors onto ErrorType
+func classifyBrowserError(outcome *checkOutcome, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "ERR_NAME_NOT_RESOLVED"):
+		outcome.dnsOk = false
+		outcome.errorType = ErrorDNSFailed
+		outcome.errorMsg = fmt.Sprintf("Domain does not exist or DNS lookup failed: %s", msg)
+	case strings.Contains(msg, "ERR_CERT") || strings.Contains(msg, "ERR_SSL"):
+		outcome.sslOk = false
+		outcome.errorType = ErrorSSLError
+		outcome.errorMsg = fmt.Sprintf("SSL certificate error: %s", msg)
+	case strings.Contains(msg, "ERR_CONNECTION_REFUSED"):
+		outcome.errorType = ErrorConnectionRefused
+		outcome.errorMsg = "Connection refused - server is not accepting connections"
+	case strings.Contains(msg, "ERR_TIMED_OUT") || strings.Contains(msg, "deadline exceeded"):
+		outcome.errorType = ErrorTimeout
+		outcome.errorMsg = "Page load timed out"
+	default:
+		outcome.errorType = ErrorBrowserCheck
+		outcome.errorMsg = fmt.Sprintf("Page failed to load: %s", msg)
+	}
+}
+
+// storeBrowserScreenshot captures the page as the check left it and returns its key
+func (h *Handler) storeBrowserScreenshot(ctx context.Context, monitor db.Monitor) string {
+	if h.artifacts == nil {
+		return ""
+	}
+
+	var png []byte
+	if err := chromedp.Run(ctx, chromedp.FullScreenshot(&png, 100)); err != nil {
+		log.Printf("Browser screenshot for monitor %d failed: %v", monitor.ID, err)
+		return ""
+	}
+
+	key := fmt.Sprintf("monitors/%d/browser/%d.png", monitor.ID, time.Now().Unix())
+	if err := h.artifacts.Put(ctx, key, bytes.NewReader(png), int64(len(png)), "image/png"); err != nil {
+		log.Printf("Failed to store browser screenshot for monitor %d: %v", monitor.ID, err)
+		return ""
+	}
+	return key
+}
@@ -0,0 +1,230 @@
+package monitor
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxBulkMonitors bounds how many monitors one bulk request changes
+const maxBulkMonitors = 500
+
+// Bulk actions
+const (
+	BulkPause       = "pause"
+	BulkResume      = "resume"
+	BulkDelete      = "delete"
+	BulkRetag       = "retag"
+	BulkSetInterval = "set_interval"
+	BulkSetGroup    = "set_group"
+)
+
+type BulkMonitorsRequest struct {
+	IDs    []int32 `json:"ids" validate:"required,min=1"`
+	Action string  `json:"action" validate:"required"`
+	// AddTags and RemoveTags are for retag; a tag in both is removed
+	AddTags    []string `json:"add_tags,omitempty"`
+	RemoveTags []string `json:"remove_tags,omitempty"`
+	// Interval is for set_interval, in seconds
+	Interval int32 `json:"interval,omitempty"`
+	// GroupID is for set_group, 0 or null takes the monitors out of their group
+	GroupID *int32 `json:"group_id,omitempty"`
+}
+
+type BulkMonitorsResponse struct {
+	Action string `json:"action"`
+	// Monitors are the changed monitors, Deleted their ids for delete
+	Monitors []db.Monitor `json:"monitors,omitempty"`
+	Deleted  []int32      `json:"deleted,omitempty"`
+	// NotFound lists requested ids that aren't monitors of the organization
+	NotFound []int32 `json:"not_found"`
+}
+
+// BulkMonitors applies one action to many monitors of the organization.
+// Unknown ids are reported rather than failing the request.
+func (h *Handler) BulkMonitors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+	orgId := payload.OrgId
+
+	var req BulkMonitorsRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	ids := dedupeIDs(req.IDs)
+	if len(ids) > maxBulkMonitors {
+		util.ErrorJson(w, util.ErrTooManyBulkMonitors)
+		return
+	}
+
+	existing, err := h.store.GetOrgMonitorsByIDs(ctx, db.GetOrgMonitorsByIDsParams{OrgID: orgId, Ids: ids})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	before := make(map[int32]db.Monitor, len(existing))
+	found := make([]int32, 0, len(existing))
+	for _, m := range existing {
+		before[m.ID] = m
+		found = append(found, m.ID)
+	}
+	resp := BulkMonitorsResponse{Action: req.Action, NotFound: []int32{}}
+	for _, id := range ids {
+		if _, ok := before[id]; !ok {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+
+	var updated []db.Monitor
+	switch req.Action {
+	case BulkPause, BulkResume:
+		updated, err = h.store.BulkSetMonitorsActive(ctx, db.BulkSetMonitorsActiveParams{
+			IsActive: req.Action == BulkResume,
+			OrgID:    orgId,
+			Ids:      found,
+		})
+
+	case BulkRetag:
+		var add, remove []string
+		if add, err = NormalizeTags(req.AddTags); err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		if remove, err = NormalizeTags(req.RemoveTags); err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		for _, m := range existing {
+			if len(retag(m.Tags, add, remove)) > maxMonitorTags {
+				util.ErrorJson(w, util.ErrTooManyTags)
+				return
+			}
+		}
+		updated, err = h.store.BulkRetagMonitors(ctx, db.BulkRetagMonitorsParams{
+			AddTags:    add,
+			RemoveTags: remove,
+			OrgID:      orgId,
+			Ids:        found,
+		})
+
+	case BulkSetInterval:
+		if req.Interval < 1 {
+			util.ErrorJson(w, util.ErrRequiredInputMissing("interval"))
+			return
+		}
+		var plan plans.Plan
+		if plan, err = plans.ForOrg(ctx, h.store, *h.config, orgId); err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		if err = plan.CheckInterval(req.Interval); err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		updated, err = h.store.BulkSetMonitorsInterval(ctx, db.BulkSetMonitorsIntervalParams{
+			Interval: req.Interval,
+			OrgID:    orgId,
+			Ids:      found,
+		})
+
+	case BulkSetGroup:
+		var groupID pgtype.Int4
+		if groupID, err = h.groupParam(ctx, orgId, req.GroupID); err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		updated, err = h.store.BulkSetMonitorsGroup(ctx, db.BulkSetMonitorsGroupParams{
+			GroupID: groupID,
+			OrgID:   orgId,
+			Ids:     found,
+		})
+
+	case BulkDelete:
+		resp.Deleted, err = h.store.BulkDeleteMonitors(ctx, db.BulkDeleteMonitorsParams{OrgID: orgId, Ids: found})
+		if err != nil {
+			util.ErrorJson(w, err)
+			return
+		}
+		for _, id := range resp.Deleted {
+			audit.Record(r, h.store, audit.Entry{
+				Action:       audit.ActionDelete,
+				ResourceType: audit.ResourceMonitor,
+				ResourceID:   id,
+				Before:       maskMonitor(before[id]),
+			})
+		}
+		util.WriteJson(w, http.StatusOK, resp)
+		return
+
+	default:
+		util.ErrorJson(w, util.ErrInvalidBulkAction)
+		return
+	}
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	action := audit.ActionUpdate
+	switch req.Action {
+	case BulkPause:
+		action = audit.ActionPause
+	case BulkResume:
+		action = audit.ActionResume
+	}
+	for _, m := range updated {
+		audit.Record(r, h.store, audit.Entry{
+			Action:       action,
+			ResourceType: audit.ResourceMonitor,
+			ResourceID:   m.ID,
+			Before:       maskMonitor(before[m.ID]),
+			After:        maskMonitor(m),
+		})
+	}
+
+	resp.Monitors = maskMonitors(updated)
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func dedupeIDs(ids []int32) []int32 {
+	seen := make(map[int32]bool, len(ids))
+	unique := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// retag is what BulkRetagMonitors makes of a monitor's tags
+func retag(tags, add, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+	result := make(map[string]bool, len(tags)+len(add))
+	for _, tag := range append(append([]string(nil), tags...), add...) {
+		if !removed[tag] {
+			result[tag] = true
+		}
+	}
+	kept := make([]string, 0, len(result))
+	for tag := range result {
+		kept = append(kept, tag)
+	}
+	return kept
+}
@@ -64,14 +64,14 @@ func (h *Handler) CheckSingleMonitor(ctx context.Context, monitor db.Monitor) er
 		ResponseTime: pgtype.Float8{Float64: responseTime, Valid: true},
 		DnsOk:        pgtype.Bool{Bool: (respErr == nil), Valid: true},
 		SslOk:        pgtype.Bool{Bool: true, Valid: true},
-		ContentOk:    pgtype.Bool{Bool: true, Valid: true},
+		ContentOk:    pgtype.Bool{Bool: status == "up", Valid: true},
 	})
 	if err != nil {
 		return err
 	}
 
 	// Update monitor status
-	logger.Debug(monitor.UserID.String())
+	logger.Debug("%s", monitor.UserID.String())
 
 	monitorNew, err := h.store.UpdateMonitor(ctx, db.UpdateMonitorParams{
 		ID:       monitor.ID,
@@ -81,12 +81,12 @@ func (h *Handler) CheckSingleMonitor(ctx context.Context, monitor db.Monitor) er
 		Interval: monitor.Interval,
 		Status:   db.NullMonitorStatus{MonitorStatus: db.MonitorStatus(status), Valid: true},
 		IsActive: monitor.IsActive,
-		UserID:   monitor.UserID,
+		OrgID:    monitor.OrgID,
 	})
 	if err != nil {
 		return err
 	}
-	logger.Debug(fmt.Sprintf("%+v", monitorNew))
+	logger.Debug("%+v", monitorNew)
 
 	return nil
 }
@@ -99,17 +99,17 @@ func (h *Handler) CheckAllActiveMonitors(w http.ResponseWriter, r *http.Request)
 		util.ErrorJson(w, util.ErrUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
-	monitors, err := h.store.GetActiveMonitorsForUser(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	monitors, err := h.store.GetActiveMonitorsForOrg(ctx, orgId)
 	if err != nil {
 		util.ErrorJson(w, err)
 		return
 	}
 
 	for _, monitor := range monitors {
-		// Only check monitors belonging to this user
-		if monitor.UserID.Bytes != userId {
+		// Only check monitors belonging to this organization
+		if monitor.OrgID != orgId {
 			continue
 		}
 
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CheckURLRequest struct {
+	Url    string `json:"url" validate:"required"`
+	Method string `json:"method"`
+	Type   string `json:"type"`
+	// Config holds type-specific settings, as for a monitor
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// CheckURL runs one check through the server's checker without creating a
+// monitor or recording anything. Browser and database checks are left out,
+// since they store screenshots or need saved credentials.
+func (h *Handler) CheckURL(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	_, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req CheckURLRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	switch req.Type {
+	case "", MonitorTypeHTTP, MonitorTypeAPI, MonitorTypeGRPC:
+	default:
+		util.ErrorJson(w, fmt.Errorf("ad-hoc checks support http, api and grpc monitors, not %q", req.Type))
+		return
+	}
+	if err := ValidateMonitor(req.Type, req.Url, req.Config); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	monitor := db.Monitor{
+		Url:    req.Url,
+		Method: pgtype.Text{String: method, Valid: true},
+		Type:   pgtype.Text{String: req.Type, Valid: true},
+		Config: req.Config,
+	}
+	outcome := h.runCheck(ctx, monitor, nil)
+
+	util.WriteJson(w, http.StatusOK, &TestURLResponse{
+		Url:          req.Url,
+		StatusCode:   outcome.statusCode,
+		ResponseTime: outcome.responseTime,
+		Status:       outcome.status(),
+		DnsOk:        outcome.dnsOk,
+		SslOk:        outcome.sslOk,
+		Error:        outcome.errorMsg,
+		Timings:      &outcome.timings,
+		Steps:        outcome.steps,
+	})
+}
@@ -1,14 +1,12 @@
 package monitor
 
 import (
+	"better-uptime/common/logger"
 	db "better-uptime/internal/db/sqlc"
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
-	"net"
-	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -25,148 +23,107 @@ const (
 	ErrorTimeout           ErrorType = "TIMEOUT"
 	ErrorHTTPError         ErrorType = "HTTP_ERROR"
 	ErrorUnknown           ErrorType = "UNKNOWN_ERROR"
+	ErrorAssertionFailed   ErrorType = "ASSERTION_FAILED"
+	ErrorBrowserCheck      ErrorType = "BROWSER_CHECK_FAILED"
+	ErrorNotServing        ErrorType = "NOT_SERVING"
+	ErrorHealthUnknown     ErrorType = "HEALTH_UNKNOWN"
+	ErrorAuthFailed        ErrorType = "AUTH_FAILED"
+	ErrorQueryFailed       ErrorType = "QUERY_FAILED"
 )
 
 func (h *Handler) PerformMonitorCheck(
 	ctx context.Context,
 	monitor db.Monitor,
 ) (*TestURLResponse, error) {
-	start := time.Now()
-
-	// Parse the URL to get the host
-	parsedURL, err := url.Parse(monitor.Url)
-	if err != nil {
-		return h.createErrorResponse(ctx, monitor, 0, 0, false, false, ErrorUnknown, fmt.Sprintf("Invalid URL: %s", err.Error()))
+	// Secrets are decrypted here, for the check only, and redacted from its results
+	var values map[string]string
+	if usesSecrets(monitor.Type.String) {
+		var err error
+		values, err = h.loadSecrets(ctx, monitor.ID)
+		if err != nil {
+			return h.recordCheck(ctx, monitor, checkOutcome{
+				errorType: ErrorUnknown,
+				errorMsg:  fmt.Sprintf("Could not load the monitor's secrets: %s", err.Error()),
+			})
+		}
 	}
 
-	host := parsedURL.Hostname()
-	isHTTPS := parsedURL.Scheme == "https"
+	outcome := h.runCheck(ctx, monitor, values)
+	outcome.redact(values)
 
-	// Step 1: DNS Resolution Check
+	return h.recordCheck(ctx, monitor, outcome)
+}
 
-	dnsOk := true
-	_, dnsErr := net.LookupHost(host)
-	if dnsErr != nil {
-		dnsOk = false
-		responseTime := time.Since(start).Seconds() * 1000
-		return h.createErrorResponse(ctx, monitor, 0, responseTime, false, false, ErrorDNSFailed,
-			fmt.Sprintf("Domain does not exist or DNS lookup failed: %s", dnsErr.Error()))
+// runCheck probes the monitor according to its type
+func (h *Handler) runCheck(ctx context.Context, monitor db.Monitor, values map[string]string) checkOutcome {
+	switch monitor.Type.String {
+	case MonitorTypeAPI:
+		return runAPICheck(ctx, monitor.Config, values)
+	case MonitorTypeBrowser:
+		return h.runBrowserCheck(ctx, monitor, values)
+	case MonitorTypeGRPC:
+		return runGRPCMonitorCheck(ctx, monitor, values)
+	case MonitorTypePostgres, MonitorTypeMySQL, MonitorTypeRedis:
+		return runDatabaseCheck(ctx, monitor, values)
 	}
 
-
-	// Step 2: SSL Certificate Check (for HTTPS)
-
-	sslOk := true
-	if isHTTPS {
-		port := parsedURL.Port()
-		if port == "" {
-			port = "443"
-		}
-		conn, sslErr := tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", host+":"+port, &tls.Config{
-			InsecureSkipVerify: false,
-		})
-		if sslErr != nil {
-			sslOk = false
-			// Don't fail entirely for SSL errors, but record it
-			if strings.Contains(sslErr.Error(), "certificate") {
-				responseTime := time.Since(start).Seconds() * 1000
-				return h.createErrorResponse(ctx, monitor, 0, responseTime, true, false, ErrorSSLError,
-					fmt.Sprintf("SSL certificate error: %s", sslErr.Error()))
-			}
-		} else {
-			conn.Close()
+	// Parse the URL to get the scheme
+	parsedURL, err := url.Parse(monitor.Url)
+	if err != nil {
+		return checkOutcome{
+			errorType: ErrorUnknown,
+			errorMsg:  fmt.Sprintf("Invalid URL: %s", err.Error()),
 		}
-	} else {
-		sslOk = false // HTTP doesn't have SSL
 	}
 
 	// -----------------------------------------
-	// Step 3: HTTP Request
+	// Step 1: Traced HTTP request (DNS, connect, TLS, TTFB, transfer)
 	// -----------------------------------------
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// Allow up to 10 redirects
-			if len(via) >= 10 {
-				return fmt.Errorf("too many redirects")
-			}
-			return nil
-		},
-	}
-
-	req, err := http.NewRequestWithContext(
-		ctx,
-		monitor.Method.String,
-		monitor.Url,
-		nil,
-	)
-	if err != nil {
-		responseTime := time.Since(start).Seconds() * 1000
-		return h.createErrorResponse(ctx, monitor, 0, responseTime, dnsOk, sslOk, ErrorUnknown,
-			fmt.Sprintf("Failed to create request: %s", err.Error()))
-	}
-
-	// Add common headers
-	req.Header.Set("User-Agent", "BetterUptime/1.0")
-
-	resp, httpErr := client.Do(req)
-	responseTime := time.Since(start).Seconds() * 1000
-
-	var statusCode int32
-	var status string
-	var errorMsg string
-	var errorType ErrorType
-
-	if httpErr != nil {
-		statusCode = 0
-		status = "down"
+	return probeHTTP(ctx, monitor.Method.String, monitor.Url, parsedURL.Scheme == "https")
+}
 
-		// Categorize the error
-		if strings.Contains(httpErr.Error(), "connection refused") {
-			errorType = ErrorConnectionRefused
-			errorMsg = "Connection refused - server is not accepting connections"
-		} else if strings.Contains(httpErr.Error(), "timeout") || strings.Contains(httpErr.Error(), "deadline exceeded") {
-			errorType = ErrorTimeout
-			errorMsg = "Request timed out - server did not respond in time"
-		} else if strings.Contains(httpErr.Error(), "no such host") {
-			errorType = ErrorDNSFailed
-			errorMsg = "Domain does not exist"
-		} else {
-			errorType = ErrorUnknown
-			errorMsg = httpErr.Error()
-		}
-	} else {
-		defer resp.Body.Close()
-		statusCode = int32(resp.StatusCode)
+// recordCheck saves the log, updates the monitor's status and raises alerts
+func (h *Handler) recordCheck(ctx context.Context, monitor db.Monitor, outcome checkOutcome) (*TestURLResponse, error) {
+	status := outcome.status()
+	statusCode := outcome.statusCode
+	responseTime := outcome.responseTime
+	errorType := outcome.errorType
+	errorMsg := outcome.errorMsg
 
-		if statusCode >= 200 && statusCode < 400 {
-			status = "up"
-			errorType = ErrorNone
-			errorMsg = ""
-		} else {
-			status = "down"
-			errorType = ErrorHTTPError
-			errorMsg = fmt.Sprintf("HTTP %d - %s", statusCode, http.StatusText(int(statusCode)))
-		}
-	}
+	// Responses of internal targets are never stored
+	outcome.dropInternalEvidence()
 
 	// -----------------------------------------
-	// Step 4: Save log
+	// Step 2: Save log
 	// -----------------------------------------
-	_, err = h.store.CreateMonitorLog(ctx, db.CreateMonitorLogParams{
+	logRow, err := h.store.CreateMonitorLog(ctx, db.CreateMonitorLogParams{
 		MonitorID:    pgtype.Int4{Int32: monitor.ID, Valid: true},
 		StatusCode:   pgtype.Int4{Int32: statusCode, Valid: true},
 		ResponseTime: pgtype.Float8{Float64: responseTime, Valid: true},
-		DnsOk:        pgtype.Bool{Bool: dnsOk, Valid: true},
-		SslOk:        pgtype.Bool{Bool: sslOk, Valid: true},
+		DnsOk:        pgtype.Bool{Bool: outcome.dnsOk, Valid: true},
+		SslOk:        pgtype.Bool{Bool: outcome.sslOk, Valid: true},
 		ContentOk:    pgtype.Bool{Bool: status == "up", Valid: true},
+		DnsMs:        toPgFloat(outcome.timings.DNSMs),
+		ConnectMs:    toPgFloat(outcome.timings.ConnectMs),
+		TlsMs:        toPgFloat(outcome.timings.TLSMs),
+		TtfbMs:       toPgFloat(outcome.timings.TTFBMs),
+		TransferMs:   toPgFloat(outcome.timings.TransferMs),
+		// Evidence is empty for successful checks
+		ErrorType:       toPgText(string(errorType)),
+		ErrorMessage:    toPgText(errorMsg),
+		RemoteIp:        toPgText(outcome.remoteIP),
+		ResponseHeaders: headersJSON(outcome.headers),
+		ResponseBody:    toPgText(outcome.body),
+		StepResults:     stepsJSON(outcome.steps),
+		BrowserResult:   browserJSON(outcome.browser),
+		ScreenshotKey:   toPgText(outcome.screenshotKey),
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	// -----------------------------------------
-	// Step 5: Update monitor status and check for status change
+	// Step 3: Update monitor status and check for status change
 	// -----------------------------------------
 	previousStatus := string(monitor.Status.MonitorStatus)
 	consecutiveFailures := monitor.ConsecutiveFailures.Int32
@@ -203,8 +160,17 @@ func (h *Handler) PerformMonitorCheck(
 		return nil, err
 	}
 
+	h.trackIncident(ctx, monitor.ID, previousStatus, status, errorType, errorMsg)
+
+	// Screenshot the broken page once per outage, after the incident is open.
+	// Browser checks already took theirs while the page was loaded.
+	var screenshot <-chan string
+	if status == "down" && previousStatus != "down" && takesScreenshot(monitor, outcome) {
+		screenshot = h.captureDownScreenshot(ctx, monitor, logRow, outcome.screenshotKey)
+	}
+
 	// -----------------------------------------
-	// Step 6: Create alert if status changed to "down"
+	// Step 4: Create alert if status changed to "down"
 	// -----------------------------------------
 	if status == "down" && previousStatus != "down" && previousStatus != "" {
 		// Create an alert for the status change
@@ -241,93 +207,82 @@ func (h *Handler) PerformMonitorCheck(
 		}
 	}
 
+	timings := outcome.timings
 	return &TestURLResponse{
 		Url:          monitor.Url,
 		StatusCode:   statusCode,
 		ResponseTime: responseTime,
 		Status:       status,
-		DnsOk:        dnsOk,
-		SslOk:        sslOk,
+		DnsOk:        outcome.dnsOk,
+		SslOk:        outcome.sslOk,
 		Error:        errorMsg,
+		Timings:      &timings,
+		Steps:        outcome.steps,
+		Browser:      outcome.browser,
+		screenshot:   screenshot,
 	}, nil
 }
 
-// createErrorResponse is a helper to create error responses and log them
-func (h *Handler) createErrorResponse(
-	ctx context.Context,
-	monitor db.Monitor,
-	statusCode int32,
-	responseTime float64,
-	dnsOk, sslOk bool,
-	errorType ErrorType,
-	errorMsg string,
-) (*TestURLResponse, error) {
-	status := "down"
+func toPgText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
 
-	// Save log
-	_, err := h.store.CreateMonitorLog(ctx, db.CreateMonitorLogParams{
-		MonitorID:    pgtype.Int4{Int32: monitor.ID, Valid: true},
-		StatusCode:   pgtype.Int4{Int32: statusCode, Valid: true},
-		ResponseTime: pgtype.Float8{Float64: responseTime, Valid: true},
-		DnsOk:        pgtype.Bool{Bool: dnsOk, Valid: true},
-		SslOk:        pgtype.Bool{Bool: sslOk, Valid: true},
-		ContentOk:    pgtype.Bool{Bool: false, Valid: true},
-	})
+func headersJSON(headers map[string]string) json.RawMessage {
+	if len(headers) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(headers)
 	if err != nil {
-		return nil, err
+		return nil
 	}
+	return raw
+}
 
-	// Update monitor status
-	previousStatus := string(monitor.Status.MonitorStatus)
-	consecutiveFailures := monitor.ConsecutiveFailures.Int32
-	isActive := monitor.IsActive.Bool
-
-	consecutiveFailures++
-	if consecutiveFailures >= 5 {
-		isActive = false
-		// Create deactivation alert
-		_, alertErr := h.store.CreateAlert(ctx, db.CreateAlertParams{
-			MonitorID: pgtype.Int4{Int32: monitor.ID, Valid: true},
-			AlertType: "down",
-			Message:   fmt.Sprintf("⛔ Monitor %s deactivated after 5 consecutive failures. Last error: %s", monitor.Url, errorMsg),
-		})
-		if alertErr != nil {
-			fmt.Printf("Failed to create deactivation alert: %v\n", alertErr)
-		}
+func stepsJSON(steps []StepResult) json.RawMessage {
+	if len(steps) == 0 {
+		return nil
 	}
+	raw, err := json.Marshal(steps)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
 
-	_, err = h.store.UpdateMonitorStatusAndFailures(ctx, db.UpdateMonitorStatusAndFailuresParams{
-		ID: monitor.ID,
-		Status: db.NullMonitorStatus{
-			MonitorStatus: db.MonitorStatus(status),
-			Valid:         true,
-		},
-		ConsecutiveFailures: pgtype.Int4{Int32: consecutiveFailures, Valid: true},
-		IsActive:            pgtype.Bool{Bool: isActive, Valid: true},
-	})
+func browserJSON(result *BrowserResult) json.RawMessage {
+	if result == nil {
+		return nil
+	}
+	raw, err := json.Marshal(result)
 	if err != nil {
-		return nil, err
+		return nil
 	}
+	return raw
+}
 
-	// Create alert if status changed
-	if previousStatus != "down" && previousStatus != "" {
-		_, alertErr := h.store.CreateAlert(ctx, db.CreateAlertParams{
-			MonitorID: pgtype.Int4{Int32: monitor.ID, Valid: true},
-			AlertType: "down",
-			Message:   fmt.Sprintf("Monitor %s is DOWN. %s: %s", monitor.Url, errorType, errorMsg),
-		})
-		if alertErr != nil {
-			fmt.Printf("Failed to create alert: %v\n", alertErr)
+func toPgFloat(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
+
+// trackIncident opens an incident when a monitor goes down and resolves it
+// once the monitor is back up. Failures are logged but never fail the check.
+func (h *Handler) trackIncident(ctx context.Context, monitorID int32, previousStatus, status string, errorType ErrorType, cause string) {
+	if status == "down" && previousStatus != "down" {
+		if err := h.store.OpenIncident(ctx, db.OpenIncidentParams{
+			MonitorID: monitorID,
+			Cause:     cause,
+			ErrorType: toPgText(string(errorType)),
+		}); err != nil {
+			logger.Error("Failed to open incident for monitor %d: %v", monitorID, err)
 		}
 	}
 
-	return &TestURLResponse{
-		Url:          monitor.Url,
-		StatusCode:   statusCode,
-		ResponseTime: responseTime,
-		Status:       status,
-		DnsOk:        dnsOk,
-		SslOk:        sslOk,
-		Error:        errorMsg,
-	}, nil
+	if status == "up" && previousStatus == "down" {
+		if err := h.store.ResolveOpenIncident(ctx, monitorID); err != nil {
+			logger.Error("Failed to resolve incident for monitor %d: %v", monitorID, err)
+		}
+	}
 }
@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+)
+
+func (h *Handler) CreateMonitorGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req MonitorGroupRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	rollup, minUp := req.rollup()
+	group, err := h.store.CreateMonitorGroup(ctx, db.CreateMonitorGroupParams{
+		OrgID:        payload.OrgId,
+		Name:         req.Name,
+		Description:  req.Description,
+		Rollup:       rollup,
+		MinUpPercent: minUp,
+	})
+	if err != nil {
+		util.ErrorJson(w, groupWriteError(err))
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceMonitorGroup,
+		ResourceID:   group.ID,
+		After:        group,
+	})
+
+	util.WriteJson(w, http.StatusCreated, rollupGroup(group, GroupCounts{}))
+}
@@ -1,17 +1,22 @@
 package monitor
 
 import (
+	"better-uptime/common/audit"
 	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type CreateMonitorResponse struct {
-	Monitor    *db.Monitor      `json:"monitor"`
+	Monitor    *MonitorResponse `json:"monitor"`
 	FirstCheck *TestURLResponse `json:"first_check,omitempty"`
 	Message    string           `json:"message"`
 }
@@ -33,35 +38,25 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if a monitor with the same URL already exists for this user
-	existingMonitor, err := h.store.GetMonitorByIdandURL(ctx, db.GetMonitorByIdandURLParams{
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
-		Url:    req.Url,
-	})
-	if err == nil && existingMonitor.ID != 0 {
-		util.ErrorJson(w, fmt.Errorf("a monitor with the same URL already exists"))
-		return
-	}
-
-	monitor, err := h.store.CreateMonitor(ctx, db.CreateMonitorParams{
-		UserID:   pgtype.UUID{Bytes: userId, Valid: true},
-		Url:      req.Url,
-		Method:   pgtype.Text{String: req.Method, Valid: true},
-		Type:     pgtype.Text{String: req.Type, Valid: true},
-		Interval: req.Interval,
-		Status:   db.NullMonitorStatus{MonitorStatus: db.MonitorStatus(req.Status), Valid: true},
-		IsActive: util.ToPgBool(req.IsActive),
-	})
+	monitor, err := h.createMonitor(ctx, userId, payload.OrgId, req)
 	if err != nil {
 		util.ErrorJson(w, err)
 		return
 	}
 
+	resp := h.monitorResponse(ctx, monitor)
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   monitor.ID,
+		After:        resp,
+	})
+
 	checkResult, err := h.PerformMonitorCheck(ctx,monitor)
 	if err != nil {
 		// Monitor created but check failed
 		response := CreateMonitorResponse{
-			Monitor: &monitor,
+			Monitor: &resp,
 			Message: "Monitor created but initial check failed",
 		}
 		util.WriteJson(w, http.StatusCreated, response)
@@ -78,10 +73,82 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := CreateMonitorResponse{
-		Monitor:    &monitor,
+		Monitor:    &resp,
 		FirstCheck: checkResult,
 		Message:    "Monitor created and initial check completed",
 	}
 
 	util.WriteJson(w, http.StatusCreated, response)
 }
+
+// errMonitorURLTaken reports a URL another monitor of the organization checks
+var errMonitorURLTaken = errors.New("a monitor with the same URL already exists")
+
+// createMonitor validates req against the organization's plan and stores the
+// monitor with its secrets
+func (h *Handler) createMonitor(ctx context.Context, userId, orgId uuid.UUID, req CreateMonitorRequest) (db.Monitor, error) {
+	if err := validateMonitorConfig(req.Type, req.Config); err != nil {
+		return db.Monitor{}, err
+	}
+	if err := validateMonitorTarget(req.Type, req.Url, req.Credentials); err != nil {
+		return db.Monitor{}, err
+	}
+	writes, err := secretWrites(req.Secrets, req.Credentials)
+	if err != nil {
+		return db.Monitor{}, err
+	}
+	if err := h.checkSecretWrites(writes); err != nil {
+		return db.Monitor{}, err
+	}
+	tags, err := NormalizeTags(req.Tags)
+	if err != nil {
+		return db.Monitor{}, err
+	}
+	groupID, err := h.groupParam(ctx, orgId, req.GroupID)
+	if err != nil {
+		return db.Monitor{}, err
+	}
+
+	plan, err := plans.ForOrg(ctx, h.store, *h.config, orgId)
+	if err != nil {
+		return db.Monitor{}, err
+	}
+	if err := plan.CheckInterval(req.Interval); err != nil {
+		return db.Monitor{}, err
+	}
+	if err := plan.CheckNewMonitor(ctx, h.store, orgId); err != nil {
+		return db.Monitor{}, err
+	}
+
+	// Check if a monitor with the same URL already exists in this organization
+	existingMonitor, err := h.store.GetMonitorByIdandURL(ctx, db.GetMonitorByIdandURLParams{
+		OrgID: orgId,
+		Url:   req.Url,
+	})
+	if err == nil && existingMonitor.ID != 0 {
+		return db.Monitor{}, errMonitorURLTaken
+	}
+
+	monitor, err := h.store.CreateMonitor(ctx, db.CreateMonitorParams{
+		UserID:   pgtype.UUID{Bytes: userId, Valid: true},
+		OrgID:    orgId,
+		Url:      req.Url,
+		Method:   pgtype.Text{String: req.Method, Valid: true},
+		Type:     pgtype.Text{String: req.Type, Valid: true},
+		Interval: req.Interval,
+		Status:   db.NullMonitorStatus{MonitorStatus: db.MonitorStatus(req.Status), Valid: true},
+		IsActive: util.ToPgBool(req.IsActive),
+		Config:   req.Config,
+		GroupID:  groupID,
+		Tags:     tags,
+	})
+	if err != nil {
+		return db.Monitor{}, err
+	}
+
+	if err := h.saveSecrets(ctx, monitor.ID, writes); err != nil {
+		return db.Monitor{}, err
+	}
+
+	return monitor, nil
+}
@@ -0,0 +1,430 @@
+package monitor
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultDatabaseTimeout = 10 * time.Second
+	maxDatabaseTimeout     = time.Minute
+	// maxProbeRows bounds the rows of a probe query kept for assertions
+	maxProbeRows = 100
+)
+
+// redisReadOnlyCommands are the commands a redis monitor may send
+var redisReadOnlyCommands = map[string]bool{
+	"PING": true, "ECHO": true, "TIME": true, "INFO": true, "DBSIZE": true,
+	"EXISTS": true, "TYPE": true, "TTL": true, "PTTL": true,
+	"GET": true, "MGET": true, "STRLEN": true,
+	"HGET": true, "HGETALL": true, "HLEN": true,
+	"LLEN": true, "LINDEX": true, "LRANGE": true,
+	"SCARD": true, "SISMEMBER": true, "ZCARD": true, "ZSCORE": true, "XLEN": true,
+}
+
+// DatabaseCheckConfig is the config of a "postgres", "mysql" or "redis" monitor.
+// The monitor's URL names the server without a password, e.g.
+// postgres://db.internal:5432/app?sslmode=require, mysql://db.internal:3306/app?tls=true
+// or rediss://cache.internal:6380/0. Credentials are stored as secrets.
+type DatabaseCheckConfig struct {
+	// Query is the probe for SQL databases, run in a read-only transaction.
+	// Defaults to SELECT 1.
+	Query string `json:"query,omitempty"`
+	// Command is the probe for redis, limited to read-only commands. Defaults to PING.
+	Command []string `json:"command,omitempty"`
+	// Assert checks the result. SQL rows are a JSON array of objects keyed by
+	// column (json property "0.count"), a redis reply is JSON ("body" contains PONG).
+	Assert         []Assertion `json:"assert,omitempty"`
+	TimeoutSeconds int         `json:"timeout_seconds,omitempty"`
+}
+
+// parseDatabaseCheckConfig decodes and validates a database monitor's config
+func parseDatabaseCheckConfig(monitorType string, raw json.RawMessage) (*DatabaseCheckConfig, error) {
+	var cfg DatabaseCheckConfig
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s monitor config: %w", monitorType, err)
+		}
+	}
+
+	if cfg.TimeoutSeconds < 0 || time.Duration(cfg.TimeoutSeconds)*time.Second > maxDatabaseTimeout {
+		return nil, fmt.Errorf("timeout_seconds must be between 1 and %d", int(maxDatabaseTimeout.Seconds()))
+	}
+
+	if monitorType == MonitorTypeRedis {
+		if cfg.Query != "" {
+			return nil, fmt.Errorf("redis monitors take a command, not a query")
+		}
+		if len(cfg.Command) == 0 {
+			cfg.Command = []string{"PING"}
+		}
+		if !redisReadOnlyCommands[strings.ToUpper(cfg.Command[0])] {
+			return nil, fmt.Errorf("redis command %q is not allowed, only read-only commands are", cfg.Command[0])
+		}
+	} else {
+		if len(cfg.Command) > 0 {
+			return nil, fmt.Errorf("%s monitors take a query, not a command", monitorType)
+		}
+		if strings.TrimSpace(cfg.Query) == "" {
+			cfg.Query = "SELECT 1"
+		}
+	}
+
+	for _, a := range cfg.Assert {
+		if a.Source == "header" {
+			return nil, fmt.Errorf("%s monitors have no headers to assert on", monitorType)
+		}
+		if err := a.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
+
+// validateDatabaseURL checks the scheme of a database monitor's URL and that
+// it carries no password, which belongs in the encrypted credentials
+func validateDatabaseURL(monitorType, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid %s URL", monitorType)
+	}
+
+	schemes := map[string][]string{
+		MonitorTypePostgres: {"postgres", "postgresql"},
+		MonitorTypeMySQL:    {"mysql"},
+		MonitorTypeRedis:    {"redis", "rediss"},
+	}[monitorType]
+	ok := false
+	for _, scheme := range schemes {
+		ok = ok || u.Scheme == scheme
+	}
+	if !ok {
+		return fmt.Errorf("%s URLs must start with %s://", monitorType, schemes[0])
+	}
+
+	if _, hasPassword := u.User.Password(); hasPassword {
+		return fmt.Errorf("put the password in credentials, not in the URL")
+	}
+	return nil
+}
+
+// probeResult is what a database probe measured
+type probeResult struct {
+	connectMs float64
+	queryMs   float64
+	// result is the rows or reply as JSON, for assertions and evidence
+	result []byte
+}
+
+// runDatabaseCheck connects to the monitor's database with its "username" and
+// "password" secrets and runs the probe. Connect latency is recorded as the connect
+// phase and query latency as time to first byte; a passing probe reads as 200.
+func runDatabaseCheck(ctx context.Context, monitor db.Monitor, values map[string]string) checkOutcome {
+	monitorType := monitor.Type.String
+	cfg, err := parseDatabaseCheckConfig(monitorType, monitor.Config)
+	if err != nil {
+		return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}
+	}
+
+	creds := &MonitorCredentials{Username: values["username"], Password: values["password"]}
+
+	timeout := defaultDatabaseTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var probe probeResult
+	var connectErr, queryErr error
+	switch monitorType {
+	case MonitorTypePostgres:
+		probe, connectErr, queryErr = probePostgres(ctx, monitor.Url, creds, cfg.Query)
+	case MonitorTypeMySQL:
+		probe, connectErr, queryErr = probeMySQL(ctx, monitor.Url, creds, cfg.Query)
+	case MonitorTypeRedis:
+		probe, connectErr, queryErr = probeRedis(ctx, monitor.Url, creds, cfg.Command)
+	}
+
+	outcome := checkOutcome{
+		responseTime: probe.connectMs + probe.queryMs,
+		dnsOk:        true,
+		sslOk:        databaseUsesTLS(monitor.Url),
+	}
+	if connectErr == nil {
+		outcome.timings.ConnectMs = &probe.connectMs
+	}
+	if connectErr == nil && queryErr == nil {
+		outcome.timings.TTFBMs = &probe.queryMs
+	}
+
+	switch {
+	case connectErr != nil:
+		classifyDatabaseError(&outcome, connectErr)
+	case queryErr != nil:
+		outcome.errorType = ErrorQueryFailed
+		outcome.errorMsg = fmt.Sprintf("Probe failed: %s", queryErr.Error())
+	default:
+		outcome.statusCode = 200
+		resp := tracedResponse{body: probe.result}
+		for _, a := range cfg.Assert {
+			if err := a.check(outcome, resp); err != nil {
+				outcome.errorType = ErrorAssertionFailed
+				outcome.errorMsg = err.Error()
+				break
+			}
+		}
+		outcome.attachEvidence(resp)
+	}
+
+	return outcome
+}
+
+// databaseUsesTLS reports whether the URL asks for an encrypted connection
+func databaseUsesTLS(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	q := u.Query()
+	switch u.Scheme {
+	case "rediss":
+		return true
+	case "mysql":
+		return q.Get("tls") != "" && q.Get("tls") != "false"
+	case "postgres", "postgresql":
+		mode := q.Get("sslmode")
+		return mode == "require" || mode == "verify-ca" || mode == "verify-full"
+	}
+	return false
+}
+
+func probePostgres(ctx context.Context, rawURL string, creds *MonitorCredentials, query string) (probeResult, error, error) {
+	var probe probeResult
+
+	connConfig, err := pgx.ParseConfig(rawURL)
+	if err != nil {
+		return probe, err, nil
+	}
+	if creds.Username != "" {
+		connConfig.User = creds.Username
+	}
+	connConfig.Password = creds.Password
+
+	start := time.Now()
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
+	probe.connectMs = time.Since(start).Seconds() * 1000
+	if err != nil {
+		return probe, err, nil
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	start = time.Now()
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return probe, nil, err
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return probe, nil, err
+	}
+	defer rows.Close()
+
+	var result []map[string]any
+	for rows.Next() && len(result) < maxProbeRows {
+		values, err := rows.Values()
+		if err != nil {
+			return probe, nil, err
+		}
+		row := make(map[string]any, len(values))
+		for i, field := range rows.FieldDescriptions() {
+			row[field.Name] = values[i]
+		}
+		result = append(result, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return probe, nil, err
+	}
+	probe.queryMs = time.Since(start).Seconds() * 1000
+
+	probe.result, err = json.Marshal(result)
+	return probe, nil, err
+}
+
+func probeMySQL(ctx context.Context, rawURL string, creds *MonitorCredentials, query string) (probeResult, error, error) {
+	var probe probeResult
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return probe, err, nil
+	}
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = u.Host
+	if u.Port() == "" {
+		mysqlConfig.Addr = net.JoinHostPort(u.Hostname(), "3306")
+	}
+	mysqlConfig.DBName = strings.TrimPrefix(u.Path, "/")
+	mysqlConfig.User = u.User.Username()
+	if creds.Username != "" {
+		mysqlConfig.User = creds.Username
+	}
+	mysqlConfig.Passwd = creds.Password
+	if tls := u.Query().Get("tls"); tls != "" {
+		mysqlConfig.TLSConfig = tls
+	}
+
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return probe, err, nil
+	}
+	sqlDB := sql.OpenDB(connector)
+	defer sqlDB.Close()
+	sqlDB.SetMaxOpenConns(1)
+
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	probe.connectMs = time.Since(start).Seconds() * 1000
+	if err != nil {
+		return probe, err, nil
+	}
+
+	start = time.Now()
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return probe, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return probe, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return probe, nil, err
+	}
+	var result []map[string]any
+	for rows.Next() && len(result) < maxProbeRows {
+		values := make([]sql.RawBytes, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return probe, nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			if values[i] == nil {
+				row[column] = nil
+			} else {
+				row[column] = string(values[i])
+			}
+		}
+		result = append(result, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return probe, nil, err
+	}
+	probe.queryMs = time.Since(start).Seconds() * 1000
+
+	probe.result, err = json.Marshal(result)
+	return probe, nil, err
+}
+
+func probeRedis(ctx context.Context, rawURL string, creds *MonitorCredentials, command []string) (probeResult, error, error) {
+	var probe probeResult
+
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return probe, err, nil
+	}
+	if creds.Username != "" {
+		opts.Username = creds.Username
+	}
+	if creds.Password != "" {
+		opts.Password = creds.Password
+	}
+	opts.PoolSize = 1
+	opts.MaxRetries = -1
+	opts.DisableIdentity = true
+
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	// The first command dials, authenticates and selects the database
+	start := time.Now()
+	err = client.Ping(ctx).Err()
+	probe.connectMs = time.Since(start).Seconds() * 1000
+	if err != nil {
+		return probe, err, nil
+	}
+
+	args := make([]any, len(command))
+	for i, arg := range command {
+		args[i] = arg
+	}
+	start = time.Now()
+	reply, err := client.Do(ctx, args...).Result()
+	probe.queryMs = time.Since(start).Seconds() * 1000
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return probe, nil, err
+	}
+
+	probe.result, err = json.Marshal(reply)
+	return probe, nil, err
+}
+
+// classifyDatabaseError maps a failed connection attempt to an ErrorType
+func classifyDatabaseError(outcome *checkOutcome, err error) {
+	var dnsErr *net.DNSError
+	var pgErr *pgconn.PgError
+	var mysqlErr *mysql.MySQLError
+	msg := err.Error()
+
+	switch {
+	case errors.As(err, &dnsErr):
+		outcome.dnsOk = false
+		outcome.errorType = ErrorDNSFailed
+		outcome.errorMsg = fmt.Sprintf("Domain does not exist or DNS lookup failed: %s", msg)
+	case errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, "28"),
+		errors.As(err, &mysqlErr) && mysqlErr.Number == 1045,
+		strings.Contains(msg, "WRONGPASS") || strings.Contains(msg, "NOAUTH"):
+		outcome.errorType = ErrorAuthFailed
+		outcome.errorMsg = fmt.Sprintf("Authentication failed: %s", msg)
+	case strings.Contains(msg, "connection refused"):
+		outcome.errorType = ErrorConnectionRefused
+		outcome.errorMsg = "Connection refused - server is not accepting connections"
+	case strings.Contains(msg, "x509") || strings.Contains(msg, "tls:") || strings.Contains(msg, "SSL"):
+		outcome.sslOk = false
+		outcome.errorType = ErrorSSLError
+		outcome.errorMsg = fmt.Sprintf("TLS error: %s", msg)
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		outcome.errorType = ErrorTimeout
+		outcome.errorMsg = "Connection timed out - server did not respond in time"
+	default:
+		outcome.errorType = ErrorUnknown
+		outcome.errorMsg = fmt.Sprintf("Connection failed: %s", msg)
+	}
+}
@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DeleteMonitorGroup removes a group. Its monitors are kept, ungrouped.
+func (h *Handler) DeleteMonitorGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	existing, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: int32(id), OrgID: payload.OrgId})
+	if err != nil {
+		util.ErrorJson(w, util.ErrMonitorGroupNotFound)
+		return
+	}
+
+	if _, err := h.store.DeleteMonitorGroup(ctx, db.DeleteMonitorGroupParams{ID: existing.ID, OrgID: payload.OrgId}); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceMonitorGroup,
+		ResourceID:   existing.ID,
+		Before:       existing,
+	})
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Monitor group deleted"})
+}
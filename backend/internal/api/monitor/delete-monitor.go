@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"better-uptime/common/audit"
 	"better-uptime/common/middleware"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
@@ -9,7 +10,6 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (h *Handler) DeleteMonitor(w http.ResponseWriter, r *http.Request) {
@@ -21,7 +21,7 @@ func (h *Handler) DeleteMonitor(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	monitorIdStr := chi.URLParam(r, "id")
 	if monitorIdStr == "" {
@@ -36,15 +36,33 @@ func (h *Handler) DeleteMonitor(w http.ResponseWriter, r *http.Request) {
 	}
 	monitorId := int32(monitorIdInt)
 
-	// Fetch the monitor from the database
+	existing, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+		ID:    monitorId,
+		OrgID: orgId,
+	})
+	if err != nil {
+		util.ErrorJson(w, errors.New("could not delete monitor"))
+		return
+	}
+	before := h.monitorResponse(ctx, existing)
+	screenshots := h.monitorScreenshotKeys(ctx, monitorId)
+
 	err = h.store.DeleteMonitor(ctx, db.DeleteMonitorParams{
-		ID:     monitorId,
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
+		ID:    monitorId,
+		OrgID: orgId,
 	})
 	if err != nil {
 		util.ErrorJson(w, errors.New("could not delete monitor"))
 		return
 	}
+	h.deleteScreenshots(ctx, screenshots)
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   monitorId,
+		Before:       before,
+	})
 
 	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Monitor deleted successfully"})
 }
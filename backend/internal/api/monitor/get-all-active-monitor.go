@@ -5,8 +5,6 @@ import (
 	"better-uptime/common/util"
 	"errors"
 	"net/http"
-
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (h *Handler) GetAllActiveMonitors(w http.ResponseWriter, r *http.Request) {
@@ -18,14 +16,14 @@ func (h *Handler) GetAllActiveMonitors(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	// Fetch the monitor from the database
-	ActiveMonitors, err := h.store.GetActiveMonitorsForUser(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	ActiveMonitors, err := h.store.GetActiveMonitorsForOrg(ctx, orgId)
 	if err != nil {
 		util.ErrorJson(w, errors.New("monitor not found"))
 		return
 	}
 
-	util.WriteJson(w, http.StatusOK, ActiveMonitors)
+	util.WriteJson(w, http.StatusOK, maskMonitors(ActiveMonitors))
 }
@@ -3,6 +3,7 @@ package monitor
 import (
 	"better-uptime/common/middleware"
 	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
 	"fmt"
 	"net/http"
 	"time"
@@ -21,6 +22,8 @@ type MonitorStatsResponse struct {
 	AvgResponseTime  string  `json:"avg_response_time"` // "123ms"
 	UptimePercentage string  `json:"uptime_percentage"` // "99.9%"
 	LastCheck        string  `json:"last_check"`        // "2h ago" or ISO/UTC
+	GroupID          *int32   `json:"group_id"`
+	Tags             []string `json:"tags"`
 }
 
 func (h *Handler) GetUserMonitorsWithStats(w http.ResponseWriter, r *http.Request) {
@@ -31,9 +34,22 @@ func (h *Handler) GetUserMonitorsWithStats(w http.ResponseWriter, r *http.Reques
 		util.ErrorJson(w, util.ErrUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
-	monitors, err := h.store.GetUserMonitorsWithStats(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	filter, err := parseMonitorFilter(r)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	monitors, err := h.store.GetOrgMonitorsWithStats(ctx, db.GetOrgMonitorsWithStatsParams{
+		OrgID:    orgId,
+		Tags:     filter.Tags,
+		Status:   filter.Status,
+		IsActive: filter.IsActive,
+		GroupID:  filter.GroupID,
+		Search:   filter.Search,
+	})
 	if err != nil {
 		util.ErrorJson(w, err)
 		return
@@ -95,6 +111,11 @@ func (h *Handler) GetUserMonitorsWithStats(w http.ResponseWriter, r *http.Reques
 			AvgResponseTime:  avgResponse,
 			UptimePercentage: uptime,
 			LastCheck:        lastCheck,
+			Tags:             m.Tags,
+		}
+		if m.GroupID.Valid {
+			groupID := m.GroupID.Int32
+			response[i].GroupID = &groupID
 		}
 	}
 
@@ -3,12 +3,13 @@ package monitor
 import (
 	"better-uptime/common/middleware"
 	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
 	"errors"
 	"net/http"
-
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// GetAllMonitors lists the organization's monitors, filtered as described at
+// parseMonitorFilter
 func (h *Handler) GetAllMonitors(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
@@ -18,14 +19,27 @@ func (h *Handler) GetAllMonitors(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
+
+	filter, err := parseMonitorFilter(r)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
 
 	// Fetch the monitor from the database
-	monitors, err := h.store.GetUserMonitors(ctx, pgtype.UUID{Bytes: userId, Valid: true})
+	monitors, err := h.store.SearchOrgMonitors(ctx, db.SearchOrgMonitorsParams{
+		OrgID:    orgId,
+		Tags:     filter.Tags,
+		Status:   filter.Status,
+		IsActive: filter.IsActive,
+		GroupID:  filter.GroupID,
+		Search:   filter.Search,
+	})
 	if err != nil {
 		util.ErrorJson(w, errors.New("monitor not found"))
 		return
 	}
 
-	util.WriteJson(w, http.StatusOK, monitors)
+	util.WriteJson(w, http.StatusOK, maskMonitors(monitors))
 }
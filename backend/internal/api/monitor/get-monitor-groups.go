@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetMonitorGroups lists the organization's groups with their rolled-up status
+func (h *Handler) GetMonitorGroups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	groups, err := h.store.GetOrgMonitorGroups(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	counts, err := h.groupCounts(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	response := make([]MonitorGroupResponse, len(groups))
+	for i, group := range groups {
+		response[i] = rollupGroup(group, counts[group.ID])
+	}
+
+	util.WriteJson(w, http.StatusOK, response)
+}
+
+// GetMonitorGroup returns one group with its status and monitors
+func (h *Handler) GetMonitorGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	group, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: int32(id), OrgID: payload.OrgId})
+	if err != nil {
+		util.ErrorJson(w, util.ErrMonitorGroupNotFound)
+		return
+	}
+	counts, err := h.groupCounts(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	monitors, err := h.store.SearchOrgMonitors(ctx, db.SearchOrgMonitorsParams{
+		OrgID:   payload.OrgId,
+		GroupID: pgtype.Int4{Int32: group.ID, Valid: true},
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	response := rollupGroup(group, counts[group.ID])
+	response.Monitors = maskMonitors(monitors)
+
+	util.WriteJson(w, http.StatusOK, response)
+}
@@ -9,7 +9,6 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func (h *Handler) GetMonitorByID(w http.ResponseWriter, r *http.Request) {
@@ -21,7 +20,7 @@ func (h *Handler) GetMonitorByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	monitorIdStr := chi.URLParam(r, "id")
 	if monitorIdStr == "" {
@@ -38,13 +37,13 @@ func (h *Handler) GetMonitorByID(w http.ResponseWriter, r *http.Request) {
 
 	// Fetch the monitor from the database
 	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
-		ID:     monitorId,
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
+		ID:    monitorId,
+		OrgID: orgId,
 	})
 	if err != nil {
 		util.ErrorJson(w, errors.New("monitor not found"))
 		return
 	}
 
-	util.WriteJson(w, http.StatusOK, monitor)
+	util.WriteJson(w, http.StatusOK, h.monitorResponse(ctx, monitor))
 }
@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetMonitorLog returns a single log row, including the failure evidence
+// (error, remote IP, response headers and body snippet) the list leaves out
+func (h *Handler) GetMonitorLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	monitorID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+	logID, err := strconv.ParseInt(chi.URLParam(r, "logID"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+		ID:    int32(monitorID),
+		OrgID: payload.OrgId,
+	})
+	if err != nil {
+		util.ErrorJson(w, util.ErrMonitorNotFound)
+		return
+	}
+
+	log, err := h.store.GetMonitorLogByID(ctx, db.GetMonitorLogByIDParams{
+		ID:        int32(logID),
+		MonitorID: pgtype.Int4{Int32: monitor.ID, Valid: true},
+	})
+	if err != nil {
+		util.ErrorJson(w, util.ErrMonitorLogNotFound)
+		return
+	}
+
+	// Screenshots are private, hand out a short-lived link instead of the key
+	log.ScreenshotUrl = h.screenshotURL(ctx, log.ScreenshotKey, log.ScreenshotUrl)
+
+	util.WriteJson(w, http.StatusOK, log)
+}
@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxSeriesPoints keeps a single chart request bounded
+const maxSeriesPoints = 1500
+
+var seriesBuckets = map[db.RollupResolution]time.Duration{
+	db.RollupResolution1m: time.Minute,
+	db.RollupResolution1h: time.Hour,
+	db.RollupResolution1d: 24 * time.Hour,
+}
+
+var defaultSeriesWindows = map[db.RollupResolution]time.Duration{
+	db.RollupResolution1m: 6 * time.Hour,
+	db.RollupResolution1h: 7 * 24 * time.Hour,
+	db.RollupResolution1d: 90 * 24 * time.Hour,
+}
+
+type SeriesResponse struct {
+	MonitorID  int32         `json:"monitor_id"`
+	Resolution string        `json:"resolution"`
+	From       string        `json:"from"`
+	To         string        `json:"to"`
+	Points     []SeriesPoint `json:"points"`
+}
+
+// SeriesPoint is one rollup bucket. Buckets without checks are omitted.
+type SeriesPoint struct {
+	Timestamp        string   `json:"timestamp"`
+	Checks           int32    `json:"checks"`
+	SuccessfulChecks int32    `json:"successful_checks"`
+	UptimePercent    float64  `json:"uptime_percent"`
+	MinResponseTime  *float64 `json:"min_response_time"`
+	AvgResponseTime  *float64 `json:"avg_response_time"`
+	MaxResponseTime  *float64 `json:"max_response_time"`
+	P50ResponseTime  *float64 `json:"p50_response_time"`
+	P90ResponseTime  *float64 `json:"p90_response_time"`
+	P95ResponseTime  *float64 `json:"p95_response_time"`
+	P99ResponseTime  *float64 `json:"p99_response_time"`
+}
+
+// GetMonitorSeries returns response time percentiles and uptime per bucket for charting.
+// Query: ?resolution=1m|1h|1d (default 1h), optional ?from= and ?to= in RFC3339.
+func (h *Handler) GetMonitorSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	monitorID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+		ID:    int32(monitorID),
+		OrgID: payload.OrgId,
+	})
+	if err != nil {
+		util.ErrorJson(w, util.ErrMonitorNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+
+	resolution := db.RollupResolution1h
+	if raw := query.Get("resolution"); raw != "" {
+		resolution = db.RollupResolution(raw)
+	}
+	bucket, ok := seriesBuckets[resolution]
+	if !ok {
+		util.ErrorJson(w, util.ErrInvalidQueryParams)
+		return
+	}
+
+	to := time.Now().UTC()
+	if raw := query.Get("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			util.ErrorJson(w, util.ErrInvalidTimeFormat)
+			return
+		}
+	}
+	from := to.Add(-defaultSeriesWindows[resolution])
+	if raw := query.Get("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			util.ErrorJson(w, util.ErrInvalidTimeFormat)
+			return
+		}
+	}
+	from, to = from.UTC().Truncate(bucket), to.UTC()
+
+	if !to.After(from) {
+		util.ErrorJson(w, util.ErrTimingError)
+		return
+	}
+	if to.Sub(from)/bucket > maxSeriesPoints {
+		util.ErrorJson(w, util.ErrTooManyEntries)
+		return
+	}
+
+	rollups, err := h.store.GetMonitorRollups(ctx, db.GetMonitorRollupsParams{
+		MonitorID:  monitor.ID,
+		Resolution: resolution,
+		Since:      pgtype.Timestamp{Time: from, Valid: true},
+		Until:      pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	points := make([]SeriesPoint, 0, len(rollups))
+	for _, b := range rollups {
+		point := SeriesPoint{
+			Timestamp:        b.BucketStart.Time.Format(time.RFC3339),
+			Checks:           b.CheckCount,
+			SuccessfulChecks: b.SuccessCount,
+			MinResponseTime:  float8Ptr(b.MinResponseTime),
+			AvgResponseTime:  float8Ptr(b.AvgResponseTime),
+			MaxResponseTime:  float8Ptr(b.MaxResponseTime),
+			P50ResponseTime:  float8Ptr(b.P50ResponseTime),
+			P90ResponseTime:  float8Ptr(b.P90ResponseTime),
+			P95ResponseTime:  float8Ptr(b.P95ResponseTime),
+			P99ResponseTime:  float8Ptr(b.P99ResponseTime),
+		}
+		if b.CheckCount > 0 {
+			point.UptimePercent = float64(b.SuccessCount) / float64(b.CheckCount) * 100
+		}
+		points = append(points, point)
+	}
+
+	util.WriteJson(w, http.StatusOK, SeriesResponse{
+		MonitorID:  monitor.ID,
+		Resolution: string(resolution),
+		From:       from.Format(time.RFC3339),
+		To:         to.Format(time.RFC3339),
+		Points:     points,
+	})
+}
+
+func float8Ptr(v pgtype.Float8) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
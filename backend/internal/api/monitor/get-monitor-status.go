@@ -4,18 +4,20 @@ import (
 	"better-uptime/common/middleware"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type MonitorStatusResponse struct {
-	Monitor *db.Monitor     `json:"monitor"`
-	Logs    []db.MonitorLog `json:"logs"`
-	Stats   *MonitorStats   `json:"stats"`
+	Monitor *db.Monitor            `json:"monitor"`
+	Logs    []db.GetMonitorLogsRow `json:"logs"`
+	Stats   *MonitorStats          `json:"stats"`
 }
 
 type MonitorStats struct {
@@ -24,6 +26,8 @@ type MonitorStats struct {
 	TotalChecks      int64   `json:"total_checks"`
 	Last24HUp        int64   `json:"last_24h_up"`
 	Last24HDown      int64   `json:"last_24h_down"`
+	// AvgPhases averages the phase timings of the returned logs
+	AvgPhases *PhaseTimings `json:"avg_phases,omitempty"`
 }
 
 func (h *Handler) GetMonitorStatus(w http.ResponseWriter, r *http.Request) {
@@ -44,8 +48,8 @@ func (h *Handler) GetMonitorStatus(w http.ResponseWriter, r *http.Request) {
 
 	// Get monitor
 	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
-		ID:     int32(monitorID),
-		UserID: pgtype.UUID{Bytes: payload.UserId, Valid: true},
+		ID:    int32(monitorID),
+		OrgID: payload.OrgId,
 	})
 	if err != nil {
 		util.ErrorJson(w, fmt.Errorf("monitor not found: %v", err))
@@ -62,9 +66,15 @@ func (h *Handler) GetMonitorStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Calculate basic stats
-	stats := h.calculateMonitorStats(logs)
+	// Stats come from the 1-minute rollups of the last 24 hours
+	stats, err := h.calculateMonitorStats(ctx, monitor.ID)
+	if err != nil {
+		util.ErrorJson(w, fmt.Errorf("failed to get monitor stats: %v", err))
+		return
+	}
+	stats.AvgPhases = averagePhaseTimings(logs)
 
+	monitor = maskMonitor(monitor)
 	response := MonitorStatusResponse{
 		Monitor: &monitor,
 		Logs:    logs,
@@ -74,40 +84,52 @@ func (h *Handler) GetMonitorStatus(w http.ResponseWriter, r *http.Request) {
 	util.WriteJson(w, http.StatusOK, response)
 }
 
-func (h *Handler) calculateMonitorStats(logs []db.MonitorLog) *MonitorStats {
-	if len(logs) == 0 {
-		return &MonitorStats{}
+func (h *Handler) calculateMonitorStats(ctx context.Context, monitorID int32) (*MonitorStats, error) {
+	totals, err := h.store.GetRollupTotalsForMonitors(ctx, db.GetRollupTotalsForMonitorsParams{
+		MonitorIds: []int32{monitorID},
+		Resolution: db.RollupResolution1m,
+		Since:      pgtype.Timestamp{Time: time.Now().UTC().Add(-24 * time.Hour), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(totals) == 0 || totals[0].TotalChecks == 0 {
+		return &MonitorStats{}, nil
 	}
 
-	var totalResponseTime float64
-	var successfulChecks int64
-	var responseTimeCount int64
+	t := totals[0]
+	return &MonitorStats{
+		UptimePercentage: float64(t.SuccessfulChecks) / float64(t.TotalChecks) * 100,
+		AvgResponseTime:  t.AvgResponseTime,
+		TotalChecks:      t.TotalChecks,
+		Last24HUp:        t.SuccessfulChecks,
+		Last24HDown:      t.TotalChecks - t.SuccessfulChecks,
+	}, nil
+}
 
-	for _, log := range logs {
-		if log.ResponseTime.Valid && log.ResponseTime.Float64 > 0 {
-			totalResponseTime += log.ResponseTime.Float64
-			responseTimeCount++
+// averagePhaseTimings averages each phase over the logs that recorded it
+func averagePhaseTimings(logs []db.GetMonitorLogsRow) *PhaseTimings {
+	avg := func(pick func(db.GetMonitorLogsRow) pgtype.Float8) *float64 {
+		var sum float64
+		var n int
+		for _, l := range logs {
+			if v := pick(l); v.Valid {
+				sum += v.Float64
+				n++
+			}
 		}
-		if log.StatusCode.Valid && log.StatusCode.Int32 >= 200 && log.StatusCode.Int32 < 400 {
-			successfulChecks++
+		if n == 0 {
+			return nil
 		}
+		mean := sum / float64(n)
+		return &mean
 	}
 
-	uptimePercentage := 0.0
-	if len(logs) > 0 {
-		uptimePercentage = float64(successfulChecks) / float64(len(logs)) * 100
-	}
-
-	avgResponseTime := 0.0
-	if responseTimeCount > 0 {
-		avgResponseTime = totalResponseTime / float64(responseTimeCount)
-	}
-
-	return &MonitorStats{
-		UptimePercentage: uptimePercentage,
-		AvgResponseTime:  avgResponseTime,
-		TotalChecks:      int64(len(logs)),
-		Last24HUp:        successfulChecks,
-		Last24HDown:      int64(len(logs)) - successfulChecks,
+	return &PhaseTimings{
+		DNSMs:      avg(func(l db.GetMonitorLogsRow) pgtype.Float8 { return l.DnsMs }),
+		ConnectMs:  avg(func(l db.GetMonitorLogsRow) pgtype.Float8 { return l.ConnectMs }),
+		TLSMs:      avg(func(l db.GetMonitorLogsRow) pgtype.Float8 { return l.TlsMs }),
+		TTFBMs:     avg(func(l db.GetMonitorLogsRow) pgtype.Float8 { return l.TtfbMs }),
+		TransferMs: avg(func(l db.GetMonitorLogsRow) pgtype.Float8 { return l.TransferMs }),
 	}
 }
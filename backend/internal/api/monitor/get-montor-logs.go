@@ -21,7 +21,7 @@ func (h *Handler) GetMonitorLogs(w http.ResponseWriter, r *http.Request) {
 		util.ErrorJson(w, util.ErrUnauthorized)
 	}
 
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	monitorIdStr := chi.URLParam(r, "id")
 
@@ -34,14 +34,14 @@ func (h *Handler) GetMonitorLogs(w http.ResponseWriter, r *http.Request) {
 	monitorID := int32(monitorId)
 
 	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
-		ID:     monitorID,
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
+		ID:    monitorID,
+		OrgID: orgId,
 	})
 	if err != nil {
 		util.ErrorJson(w, errors.New("new error occured"))
 		return
 	}
-	if monitor.UserID.Bytes != userId {
+	if monitor.OrgID != orgId {
 		util.ErrorJson(w, util.ErrUnauthorized)
 		return
 	}
@@ -0,0 +1,225 @@
+package monitor
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultGRPCTimeout = 10 * time.Second
+	maxGRPCTimeout     = time.Minute
+)
+
+// GRPCCheckConfig is the config of a "grpc" monitor, which calls
+// grpc.health.v1.Health/Check on the monitor's URL (host:port, grpc://host:port
+// or grpcs://host:port).
+type GRPCCheckConfig struct {
+	// Service is the service name to check, "" checks the server as a whole
+	Service string `json:"service,omitempty"`
+	// TLS dials with TLS; grpcs:// URLs always do
+	TLS                bool `json:"tls,omitempty"`
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+	// Metadata is sent as request headers, e.g. an authorization token
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+}
+
+// parseGRPCCheckConfig decodes and validates a grpc monitor's config
+func parseGRPCCheckConfig(raw json.RawMessage) (*GRPCCheckConfig, error) {
+	var cfg GRPCCheckConfig
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("invalid grpc monitor config: %w", err)
+		}
+	}
+
+	if cfg.TimeoutSeconds < 0 || time.Duration(cfg.TimeoutSeconds)*time.Second > maxGRPCTimeout {
+		return nil, fmt.Errorf("timeout_seconds must be between 1 and %d", int(maxGRPCTimeout.Seconds()))
+	}
+	for key, value := range cfg.Metadata {
+		if key == "" || strings.HasPrefix(strings.ToLower(key), "grpc-") {
+			return nil, fmt.Errorf("metadata key %q is not allowed", key)
+		}
+		if err := checkSensitiveHeader(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
+
+// grpcTarget splits a monitor URL into host:port and whether it asks for TLS
+func grpcTarget(rawURL string) (string, bool) {
+	switch {
+	case strings.HasPrefix(rawURL, "grpcs://"):
+		return strings.TrimSuffix(strings.TrimPrefix(rawURL, "grpcs://"), "/"), true
+	case strings.HasPrefix(rawURL, "grpc://"):
+		return strings.TrimSuffix(strings.TrimPrefix(rawURL, "grpc://"), "/"), false
+	default:
+		return rawURL, false
+	}
+}
+
+// runGRPCMonitorCheck checks a grpc monitor. Metadata values may reference
+// {{secrets.<name>}}.
+func runGRPCMonitorCheck(ctx context.Context, monitor db.Monitor, values map[string]string) checkOutcome {
+	cfg, err := parseGRPCCheckConfig(monitor.Config)
+	if err != nil {
+		return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}
+	}
+
+	vars := secretVars(values)
+	for key, value := range cfg.Metadata {
+		if cfg.Metadata[key], err = renderTemplate(value, vars); err != nil {
+			return checkOutcome{errorType: ErrorUnknown, errorMsg: err.Error()}
+		}
+	}
+
+	target, useTLS := grpcTarget(monitor.Url)
+	if useTLS {
+		cfg.TLS = true
+	}
+	return runGRPCCheck(ctx, target, cfg)
+}
+
+// dialRecorder remembers how the connection attempt went, grpc only reports
+// Unavailable with a message
+type dialRecorder struct {
+	mu        sync.Mutex
+	dialErr   error
+	connectMs *float64
+}
+
+func (d *dialRecorder) dial(ctx context.Context, addr string) (net.Conn, error) {
+	start := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err != nil {
+		d.dialErr = err
+	} else if d.connectMs == nil {
+		ms := time.Since(start).Seconds() * 1000
+		d.connectMs = &ms
+	}
+	return conn, err
+}
+
+// runGRPCCheck calls Health/Check on target (host:port) and maps the answer onto
+// the monitor status. SERVING is reported as status code 200 so it reads as up.
+func runGRPCCheck(ctx context.Context, target string, cfg *GRPCCheckConfig) checkOutcome {
+	timeout := defaultGRPCTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify})
+	}
+
+	recorder := &dialRecorder{}
+	// passthrough hands host:port to our dialer, so DNS failures surface as such
+	conn, err := grpc.NewClient("passthrough:///"+target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(recorder.dial),
+	)
+	if err != nil {
+		return checkOutcome{dnsOk: true, errorType: ErrorUnknown, errorMsg: fmt.Sprintf("Invalid target: %s", err.Error())}
+	}
+	defer conn.Close()
+
+	if len(cfg.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(cfg.Metadata))
+	}
+
+	var p peer.Peer
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: cfg.Service}, grpc.Peer(&p))
+
+	outcome := checkOutcome{
+		responseTime: time.Since(start).Seconds() * 1000,
+		dnsOk:        true,
+		sslOk:        cfg.TLS,
+	}
+	recorder.mu.Lock()
+	outcome.timings.ConnectMs = recorder.connectMs
+	dialErr := recorder.dialErr
+	recorder.mu.Unlock()
+	if p.Addr != nil {
+		if host, _, splitErr := net.SplitHostPort(p.Addr.String()); splitErr == nil {
+			outcome.remoteIP = host
+		}
+	}
+
+	if err != nil {
+		classifyGRPCError(&outcome, err, dialErr, cfg.Service)
+		return outcome
+	}
+
+	switch resp.GetStatus() {
+	case healthpb.HealthCheckResponse_SERVING:
+		outcome.statusCode = 200
+	case healthpb.HealthCheckResponse_NOT_SERVING:
+		outcome.errorType = ErrorNotServing
+		outcome.errorMsg = "Health check returned NOT_SERVING"
+	default:
+		outcome.errorType = ErrorHealthUnknown
+		outcome.errorMsg = fmt.Sprintf("Health check returned %s", resp.GetStatus())
+	}
+
+	return outcome
+}
+
+// classifyGRPCError maps a failed Health/Check call to an ErrorType
+func classifyGRPCError(outcome *checkOutcome, err, dialErr error, service string) {
+	st := status.Convert(err)
+	msg := st.Message()
+
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(dialErr, &dnsErr):
+		outcome.dnsOk = false
+		outcome.errorType = ErrorDNSFailed
+		outcome.errorMsg = fmt.Sprintf("Domain does not exist or DNS lookup failed: %s", dialErr.Error())
+	case dialErr != nil && strings.Contains(dialErr.Error(), "connection refused"):
+		outcome.errorType = ErrorConnectionRefused
+		outcome.errorMsg = "Connection refused - server is not accepting connections"
+	case strings.Contains(msg, "handshake") || strings.Contains(msg, "x509") || strings.Contains(msg, "tls:"):
+		outcome.sslOk = false
+		outcome.errorType = ErrorSSLError
+		outcome.errorMsg = fmt.Sprintf("TLS handshake failed: %s", msg)
+	case st.Code() == codes.DeadlineExceeded:
+		outcome.errorType = ErrorTimeout
+		outcome.errorMsg = "Health check timed out - server did not respond in time"
+	case st.Code() == codes.NotFound:
+		// The server does not know the service, which the protocol reports as an error
+		outcome.errorType = ErrorHealthUnknown
+		outcome.errorMsg = fmt.Sprintf("Service %q is unknown to the health server", service)
+	case st.Code() == codes.Unimplemented:
+		outcome.errorType = ErrorUnknown
+		outcome.errorMsg = "Server does not implement grpc.health.v1.Health"
+	default:
+		outcome.errorType = ErrorUnknown
+		outcome.errorMsg = fmt.Sprintf("Health check failed: %s: %s", st.Code(), msg)
+	}
+}
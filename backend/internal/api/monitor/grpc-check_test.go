@@ -0,0 +1,200 @@
+package monitor
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// startHealthServer serves grpc.health.v1.Health on a loopback port and
+// returns its address
+func startHealthServer(t *testing.T, healthServer healthpb.HealthServer, opts ...grpc.ServerOption) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+// monitorWithConfig builds a monitor of url with cfg as its JSON config
+func monitorWithConfig(t *testing.T, url string, cfg any) db.Monitor {
+	t.Helper()
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	return db.Monitor{Url: url, Config: raw}
+}
+
+// selfSignedCert returns a certificate for 127.0.0.1 that no client trusts
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// slowHealthServer answers SERVING after delay, and records the request metadata
+type slowHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	delay    time.Duration
+	metadata chan metadata.MD
+}
+
+func (s *slowHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if s.metadata != nil {
+		md, _ := metadata.FromIncomingContext(ctx)
+		s.metadata <- md
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func TestGRPCCheckStatuses(t *testing.T) {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+	addr := startHealthServer(t, healthServer)
+
+	tests := []struct {
+		name       string
+		service    string
+		statusCode int32
+		errorType  ErrorType
+		status     string
+	}{
+		{name: "server as a whole", service: "", statusCode: 200, errorType: ErrorNone, status: "up"},
+		{name: "serving service", service: "orders", statusCode: 200, errorType: ErrorNone, status: "up"},
+		{name: "not serving service", service: "payments", errorType: ErrorNotServing, status: "down"},
+		{name: "unknown service", service: "inventory", errorType: ErrorHealthUnknown, status: "down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := runGRPCCheck(context.Background(), addr, &GRPCCheckConfig{Service: tt.service, TimeoutSeconds: 5})
+			if outcome.statusCode != tt.statusCode || outcome.errorType != tt.errorType {
+				t.Fatalf("got status code %d and error %q (%s), want %d and %q",
+					outcome.statusCode, outcome.errorType, outcome.errorMsg, tt.statusCode, tt.errorType)
+			}
+			if got := outcome.status(); got != tt.status {
+				t.Errorf("status = %q, want %q", got, tt.status)
+			}
+			if outcome.remoteIP != "127.0.0.1" {
+				t.Errorf("remoteIP = %q, want 127.0.0.1", outcome.remoteIP)
+			}
+			if outcome.timings.ConnectMs == nil {
+				t.Error("connect time was not recorded")
+			}
+		})
+	}
+}
+
+func TestGRPCCheckTLS(t *testing.T) {
+	cert := selfSignedCert(t)
+	addr := startHealthServer(t, health.NewServer(),
+		grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+
+	outcome := runGRPCCheck(context.Background(), addr, &GRPCCheckConfig{TLS: true, InsecureSkipVerify: true, TimeoutSeconds: 5})
+	if outcome.status() != "up" || !outcome.sslOk {
+		t.Fatalf("with verification skipped: status %q, sslOk %v, error %q", outcome.status(), outcome.sslOk, outcome.errorMsg)
+	}
+
+	outcome = runGRPCCheck(context.Background(), addr, &GRPCCheckConfig{TLS: true, TimeoutSeconds: 5})
+	if outcome.errorType != ErrorSSLError || outcome.sslOk {
+		t.Fatalf("untrusted certificate: error %q (%s), sslOk %v, want %q", outcome.errorType, outcome.errorMsg, outcome.sslOk, ErrorSSLError)
+	}
+}
+
+func TestGRPCCheckTLSURL(t *testing.T) {
+	cert := selfSignedCert(t)
+	addr := startHealthServer(t, health.NewServer(),
+		grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+
+	monitor := monitorWithConfig(t, "grpcs://"+addr, GRPCCheckConfig{InsecureSkipVerify: true, TimeoutSeconds: 5})
+	outcome := runGRPCMonitorCheck(context.Background(), monitor, nil)
+	if outcome.status() != "up" || !outcome.sslOk {
+		t.Fatalf("grpcs:// URL: status %q, sslOk %v, error %q", outcome.status(), outcome.sslOk, outcome.errorMsg)
+	}
+}
+
+func TestGRPCCheckDeadline(t *testing.T) {
+	addr := startHealthServer(t, &slowHealthServer{delay: 5 * time.Second})
+
+	start := time.Now()
+	outcome := runGRPCCheck(context.Background(), addr, &GRPCCheckConfig{TimeoutSeconds: 1})
+	if outcome.errorType != ErrorTimeout {
+		t.Fatalf("error = %q (%s), want %q", outcome.errorType, outcome.errorMsg, ErrorTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("check took %s, the deadline is 1s", elapsed)
+	}
+}
+
+func TestGRPCCheckMetadataSecrets(t *testing.T) {
+	received := make(chan metadata.MD, 1)
+	addr := startHealthServer(t, &slowHealthServer{metadata: received})
+
+	monitor := monitorWithConfig(t, "grpc://"+addr, GRPCCheckConfig{
+		Metadata:       map[string]string{"authorization": "Bearer {{secrets.token}}"},
+		TimeoutSeconds: 5,
+	})
+	outcome := runGRPCMonitorCheck(context.Background(), monitor, map[string]string{"token": "s3cret"})
+	if outcome.status() != "up" {
+		t.Fatalf("status = %q (%s), want up", outcome.status(), outcome.errorMsg)
+	}
+	if got := (<-received).Get("authorization"); len(got) != 1 || got[0] != "Bearer s3cret" {
+		t.Errorf("authorization metadata = %q, want the rendered secret", got)
+	}
+}
+
+func TestGRPCCheckConnectionRefused(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	outcome := runGRPCCheck(context.Background(), addr, &GRPCCheckConfig{TimeoutSeconds: 2})
+	if outcome.errorType != ErrorConnectionRefused {
+		t.Fatalf("error = %q (%s), want %q", outcome.errorType, outcome.errorMsg, ErrorConnectionRefused)
+	}
+}
@@ -1,18 +1,28 @@
 package monitor
 
 import (
+	"better-uptime/common/apiv2"
 	"better-uptime/common/middleware"
 	"better-uptime/common/routes"
+	"better-uptime/common/screenshot"
+	"better-uptime/common/secrets"
+	"better-uptime/common/storage"
 	"better-uptime/config"
 	db "better-uptime/internal/db/sqlc"
+	"errors"
 	"go/token"
+	"log"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type Handler struct {
-	config *config.Config
-	store  db.Store
+	config      *config.Config
+	store       db.Store
+	artifacts   storage.Storage
+	screenshots *screenshot.Service
+	// secrets seals monitor secrets, nil when SECRETS_KEY is not set
+	secrets *secrets.Box
 }
 
 type HandlerConfig struct {
@@ -25,10 +35,18 @@ func (h *Handler) GetStore() db.Store {
 	return h.store
 }
 
-func NewHandler(config *config.Config, store db.Store) *Handler {
+func NewHandler(config *config.Config, store db.Store, artifacts storage.Storage) *Handler {
+	box, err := secrets.NewFromConfig(*config)
+	if err != nil && !errors.Is(err, secrets.ErrNoKey) {
+		log.Printf("Monitor secrets are disabled: %v", err)
+	}
+
 	return &Handler{
-		config: config,
-		store:  store,
+		config:      config,
+		store:       store,
+		artifacts:   artifacts,
+		screenshots: screenshot.NewService(screenshot.NewProvider(*config), artifacts),
+		secrets:     box,
 	}
 }
 
@@ -37,18 +55,41 @@ func (h *Handler) Routes() *chi.Mux {
 
 	router.Group(func(r chi.Router) {
 		r.Use(middleware.TokenMiddleware(h.store))
+		r.Use(middleware.RequireRoleForWrites(db.OrgRoleEditor))
+		r.Use(middleware.RequireScopeForWrites(middleware.ScopeMonitorsWrite))
 		r.Post("/create-monitor", h.CreateMonitor)
 		r.Get("/get-monitor/{id}", h.GetMonitorByID)
 		r.Get("/monitors/{id}/metrics", h.GetMonitorStatus)
+		r.Get("/monitors/{id}/series", h.GetMonitorSeries)
 		r.Post("/toggle-monitor", h.ToggleMonitor)
 		r.Delete("/delete-monitor/{id}", h.DeleteMonitor)
 		r.Get("/get-active-monitors", h.GetAllActiveMonitors)
 		r.Get("/get-all-monitors", h.GetAllMonitors)
 		r.Get("/monitor/{id}/logs", h.GetMonitorLogs)
+		r.Get("/monitor/{id}/logs/{logID}", h.GetMonitorLog)
 		r.Put("/update-monitor", h.UpdateMonitor)
 		r.Get("/monitors/stats", h.GetUserMonitorsWithStats)
+		r.Get("/monitors/tags", h.GetTags)
+		r.Post("/monitors/bulk", h.BulkMonitors)
+		r.Post("/check-url", h.CheckURL)
+
+		r.Get("/monitor-groups", h.GetMonitorGroups)
+		r.Post("/monitor-groups", h.CreateMonitorGroup)
+		r.Get("/monitor-groups/{id}", h.GetMonitorGroup)
+		r.Put("/monitor-groups/{id}", h.UpdateMonitorGroup)
+		r.Delete("/monitor-groups/{id}", h.DeleteMonitorGroup)
 
 	})
 
 	return router
 }
+
+// V2Routes adds the monitor and monitor group resources to the v2 API
+func (h *Handler) V2Routes(api *apiv2.API) {
+	api = api.With(
+		middleware.RequireRoleForWrites(db.OrgRoleEditor),
+		middleware.RequireScopeForWrites(middleware.ScopeMonitorsWrite),
+	)
+	h.v2MonitorRoutes(api)
+	h.v2MonitorGroupRoutes(api)
+}
@@ -0,0 +1,376 @@
+package monitor
+
+import (
+	"better-uptime/common/webhook"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodyBytes bounds how much of the response body is read to time the transfer
+const maxBodyBytes = 10 << 20
+
+// maxEvidenceBodyBytes caps the response body kept on a failed check's log
+const maxEvidenceBodyBytes = 4 << 10
+
+// evidenceHeaders are the response headers worth keeping to explain a failure
+var evidenceHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Location",
+	"Retry-After",
+	"Server",
+	"Via",
+	"X-Cache",
+	"Cf-Ray",
+	"X-Request-Id",
+	"Date",
+}
+
+// PhaseTimings breaks a check's response time down in milliseconds. A phase is
+// nil when it did not happen, e.g. no TLS for http:// or no DNS for an IP.
+// Across redirects the phases of every hop are summed.
+type PhaseTimings struct {
+	DNSMs      *float64 `json:"dns_ms"`
+	ConnectMs  *float64 `json:"connect_ms"`
+	TLSMs      *float64 `json:"tls_ms"`
+	TTFBMs     *float64 `json:"ttfb_ms"`
+	TransferMs *float64 `json:"transfer_ms"`
+}
+
+// checkOutcome is everything a probe learned about the target, before it is recorded
+type checkOutcome struct {
+	statusCode   int32
+	responseTime float64
+	dnsOk        bool
+	sslOk        bool
+	errorType    ErrorType
+	errorMsg     string
+	timings      PhaseTimings
+
+	// Evidence for failed checks
+	remoteIP string
+	headers  map[string]string
+	body     string
+
+	// Per-step results of multi-step monitors
+	steps []StepResult
+
+	// Page findings of browser monitors, and the key of the screenshot
+	// taken when the check failed
+	browser       *BrowserResult
+	screenshotKey string
+}
+
+func (o checkOutcome) status() string {
+	if o.errorType == ErrorNone && o.statusCode >= 200 && o.statusCode < 400 {
+		return "up"
+	}
+	return "down"
+}
+
+// phaseRecorder collects httptrace callbacks, which may fire from several goroutines
+type phaseRecorder struct {
+	mu sync.Mutex
+
+	dnsStart, connectStart, tlsStart, gotConn time.Time
+	dns, connect, tls, ttfb                   time.Duration
+	sawDNS, sawConnect, sawTLS, sawTTFB       bool
+	dnsErr, tlsErr                            error
+	tlsOk                                     bool
+	remoteAddr                                string
+}
+
+func (p *phaseRecorder) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.dnsStart = time.Now()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.dns += time.Since(p.dnsStart)
+			p.sawDNS = true
+			p.dnsErr = info.Err
+		},
+		ConnectStart: func(string, string) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			// Happy eyeballs may dial in parallel, time from the first attempt
+			if p.connectStart.IsZero() {
+				p.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, addr string, err error) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.remoteAddr = addr
+			if err == nil && !p.connectStart.IsZero() {
+				p.connect += time.Since(p.connectStart)
+				p.sawConnect = true
+				p.connectStart = time.Time{}
+			}
+		},
+		TLSHandshakeStart: func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.tls += time.Since(p.tlsStart)
+			p.sawTLS = true
+			p.tlsErr = err
+			p.tlsOk = err == nil
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.gotConn = time.Now()
+			if info.Conn != nil {
+				p.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+		},
+		GotFirstResponseByte: func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if !p.gotConn.IsZero() {
+				p.ttfb += time.Since(p.gotConn)
+				p.sawTTFB = true
+			}
+		},
+	}
+}
+
+func (p *phaseRecorder) timings(transfer time.Duration, sawTransfer bool) PhaseTimings {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var t PhaseTimings
+	if p.sawDNS {
+		t.DNSMs = durationMs(p.dns)
+	}
+	if p.sawConnect {
+		t.ConnectMs = durationMs(p.connect)
+	}
+	if p.sawTLS {
+		t.TLSMs = durationMs(p.tls)
+	}
+	if p.sawTTFB {
+		t.TTFBMs = durationMs(p.ttfb)
+	}
+	if sawTransfer {
+		t.TransferMs = durationMs(transfer)
+	}
+	return t
+}
+
+// remoteIP is the address of the last connection made, without its port
+func (p *phaseRecorder) remoteIP() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if host, _, err := net.SplitHostPort(p.remoteAddr); err == nil {
+		return host
+	}
+	return p.remoteAddr
+}
+
+func durationMs(d time.Duration) *float64 {
+	ms := d.Seconds() * 1000
+	return &ms
+}
+
+// probeHTTP performs a single traced request against the monitor's URL. DNS and
+// TLS problems surface from the request itself, there is no separate lookup or dial.
+func probeHTTP(ctx context.Context, method, rawURL string, isHTTPS bool) checkOutcome {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return checkOutcome{
+			errorType: ErrorUnknown,
+			errorMsg:  fmt.Sprintf("Failed to create request: %s", err.Error()),
+		}
+	}
+
+	outcome, resp := tracedRequest(ctx, req, maxEvidenceBodyBytes)
+	outcome.sslOk = outcome.sslOk && isHTTPS
+	if outcome.errorType == ErrorNone && (outcome.statusCode < 200 || outcome.statusCode >= 400) {
+		outcome.errorType = ErrorHTTPError
+		outcome.errorMsg = fmt.Sprintf("HTTP %d - %s", outcome.statusCode, http.StatusText(int(outcome.statusCode)))
+	}
+	outcome.attachEvidence(resp)
+
+	return outcome
+}
+
+// tracedResponse is what came back from a traced request, header is nil when
+// no response arrived. body holds at most the keepBody bytes asked for.
+type tracedResponse struct {
+	header   http.Header
+	body     []byte
+	remoteIP string
+}
+
+// tracedRequest sends req with phase tracing. The outcome only reports transport
+// problems; judging the status code is left to the caller.
+func tracedRequest(ctx context.Context, req *http.Request, keepBody int) (checkOutcome, tracedResponse) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// Allow up to 10 redirects
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			return nil
+		},
+	}
+
+	recorder := &phaseRecorder{}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, recorder.trace()))
+
+	// Add common headers
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", "BetterUptime/1.0")
+	}
+
+	start := time.Now()
+	resp, httpErr := client.Do(req)
+	if httpErr != nil {
+		outcome := classifyRequestError(httpErr, recorder)
+		outcome.responseTime = time.Since(start).Seconds() * 1000
+		outcome.timings = recorder.timings(0, false)
+		return outcome, tracedResponse{remoteIP: recorder.remoteIP()}
+	}
+	defer resp.Body.Close()
+
+	firstByte := time.Now()
+	kept := &cappedBuffer{limit: keepBody}
+	_, readErr := io.Copy(kept, io.LimitReader(resp.Body, maxBodyBytes))
+	transfer := time.Since(firstByte)
+
+	outcome := checkOutcome{
+		statusCode:   int32(resp.StatusCode),
+		responseTime: time.Since(start).Seconds() * 1000,
+		dnsOk:        true,
+		sslOk:        resp.TLS != nil,
+		timings:      recorder.timings(transfer, true),
+	}
+	if readErr != nil {
+		outcome.errorType = ErrorTimeout
+		outcome.errorMsg = fmt.Sprintf("Failed to read response body: %s", readErr.Error())
+	}
+
+	return outcome, tracedResponse{
+		header:   resp.Header,
+		body:     kept.buf,
+		remoteIP: recorder.remoteIP(),
+	}
+}
+
+// attachEvidence keeps what explains a failure on a down outcome
+func (o *checkOutcome) attachEvidence(resp tracedResponse) {
+	if o.status() != "down" {
+		return
+	}
+
+	o.remoteIP = resp.remoteIP
+	if resp.header != nil {
+		o.headers = pickHeaders(resp.header)
+	}
+	body := resp.body
+	if len(body) > maxEvidenceBodyBytes {
+		body = body[:maxEvidenceBodyBytes]
+	}
+	o.body = strings.ToValidUTF8(string(body), "")
+}
+
+// dropInternalEvidence forgets the evidence of a target that is not a public
+// address, so responses of link-local, metadata or internal services are
+// never stored. Webhooks refuse to connect to the same addresses.
+func (o *checkOutcome) dropInternalEvidence() {
+	if ip, err := netip.ParseAddr(o.remoteIP); err == nil && webhook.IsPublic(ip) {
+		return
+	}
+	o.remoteIP = ""
+	o.headers = nil
+	o.body = ""
+}
+
+// pickHeaders keeps the evidenceHeaders present on a response
+func pickHeaders(h http.Header) map[string]string {
+	picked := make(map[string]string)
+	for _, name := range evidenceHeaders {
+		if v := h.Get(name); v != "" {
+			picked[name] = v
+		}
+	}
+	return picked
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest
+type cappedBuffer struct {
+	limit int
+	buf   []byte
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if room := c.limit - len(c.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		c.buf = append(c.buf, p[:room]...)
+	}
+	return len(p), nil
+}
+
+// classifyRequestError maps a failed request to an ErrorType using what the trace saw
+func classifyRequestError(err error, recorder *phaseRecorder) checkOutcome {
+	recorder.mu.Lock()
+	dnsErr, tlsErr, tlsOk := recorder.dnsErr, recorder.tlsErr, recorder.tlsOk
+	recorder.mu.Unlock()
+
+	outcome := checkOutcome{dnsOk: true, sslOk: tlsOk}
+
+	var netDNSErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+
+	switch {
+	case dnsErr != nil || errors.As(err, &netDNSErr):
+		outcome.dnsOk = false
+		outcome.errorType = ErrorDNSFailed
+		outcome.errorMsg = fmt.Sprintf("Domain does not exist or DNS lookup failed: %s", err.Error())
+	case errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) || errors.As(err, &invalidCert):
+		outcome.errorType = ErrorSSLError
+		outcome.errorMsg = fmt.Sprintf("SSL certificate error: %s", err.Error())
+	case tlsErr != nil:
+		outcome.errorType = ErrorSSLError
+		outcome.errorMsg = fmt.Sprintf("TLS handshake failed: %s", tlsErr.Error())
+	case strings.Contains(err.Error(), "connection refused"):
+		outcome.errorType = ErrorConnectionRefused
+		outcome.errorMsg = "Connection refused - server is not accepting connections"
+	case strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline exceeded"):
+		outcome.errorType = ErrorTimeout
+		outcome.errorMsg = "Request timed out - server did not respond in time"
+	default:
+		outcome.errorType = ErrorUnknown
+		outcome.errorMsg = err.Error()
+	}
+
+	return outcome
+}
@@ -0,0 +1,31 @@
+package monitor
+
+import "testing"
+
+func TestDropInternalEvidence(t *testing.T) {
+	tests := []struct {
+		remoteIP string
+		kept     bool
+	}{
+		{remoteIP: "93.184.216.34", kept: true},
+		{remoteIP: "2606:4700::6810:84e5", kept: true},
+		{remoteIP: "169.254.169.254", kept: false},
+		{remoteIP: "127.0.0.1", kept: false},
+		{remoteIP: "10.0.0.5", kept: false},
+		{remoteIP: "fd00::1", kept: false},
+		{remoteIP: "", kept: false},
+	}
+	for _, tt := range tests {
+		outcome := checkOutcome{
+			remoteIP: tt.remoteIP,
+			headers:  map[string]string{"Server": "nginx"},
+			body:     `{"AccessKeyId":"..."}`,
+		}
+		outcome.dropInternalEvidence()
+
+		kept := outcome.remoteIP != "" || outcome.headers != nil || outcome.body != ""
+		if kept != tt.kept {
+			t.Errorf("remote %q: evidence kept = %v, want %v", tt.remoteIP, kept, tt.kept)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package monitor
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type CreateMonitorRequest struct {
 	Url      string `json:"url"`
@@ -9,6 +15,15 @@ type CreateMonitorRequest struct {
 	Interval int32  `json:"interval"`
 	Status   string `json:"status,omitempty"`
 	IsActive bool   `json:"is_active"`
+	// Config holds type-specific settings, e.g. the steps of an api monitor
+	Config json.RawMessage `json:"config,omitempty"`
+	// Secrets are write-only values checks reference as {{secrets.<name>}}
+	Secrets map[string]*string `json:"secrets,omitempty"`
+	// Credentials log database and cache monitors in; stored as secrets
+	Credentials *MonitorCredentials `json:"credentials,omitempty"`
+	// GroupID puts the monitor in one of the organization's groups
+	GroupID *int32   `json:"group_id,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
 }
 
 type TestURLResponse struct {
@@ -19,6 +34,35 @@ type TestURLResponse struct {
 	DnsOk        bool    `json:"dns_ok"`
 	SslOk        bool    `json:"ssl_ok"`
 	Error        string  `json:"error,omitempty"`
+	// Timings breaks ResponseTime down by phase
+	Timings *PhaseTimings `json:"timings,omitempty"`
+	// Steps holds the per-step results of api monitors
+	Steps []StepResult `json:"steps,omitempty"`
+	// Browser holds the page findings of browser monitors
+	Browser *BrowserResult `json:"browser,omitempty"`
+
+	// screenshot delivers the URL of the down screenshot, nil when none was started
+	screenshot <-chan string
+}
+
+// WaitForScreenshot returns the URL of the screenshot taken for this check,
+// or "" when there is none or it is not ready within timeout
+func (r *TestURLResponse) WaitForScreenshot(ctx context.Context, timeout time.Duration) string {
+	if r.screenshot == nil {
+		return ""
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case url := <-r.screenshot:
+		return url
+	case <-timer.C:
+		return ""
+	case <-ctx.Done():
+		return ""
+	}
 }
 
 type MonitorLogParamas struct {
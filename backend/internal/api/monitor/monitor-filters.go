@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// monitorFilter narrows monitor listings. Unset fields don't filter.
+type monitorFilter struct {
+	Tags     []string
+	Status   db.NullMonitorStatus
+	IsActive pgtype.Bool
+	GroupID  pgtype.Int4
+	Search   pgtype.Text
+}
+
+// parseMonitorFilter reads the listing filters from the query string:
+//
+//	?tag=env:prod&tag=team:payments  monitors with all of these tags
+//	?status=up|down|unknown|pending  active monitors in that state
+//	?status=paused                   paused monitors
+//	?group_id=3                      monitors of a group
+//	?q=api.example                   monitors whose URL contains this
+func parseMonitorFilter(r *http.Request) (monitorFilter, error) {
+	query := r.URL.Query()
+	var filter monitorFilter
+
+	if tags := query["tag"]; len(tags) > 0 {
+		normalized, err := NormalizeTags(tags)
+		if err != nil {
+			return monitorFilter{}, err
+		}
+		filter.Tags = normalized
+	}
+
+	switch status := query.Get("status"); status {
+	case "":
+	case "paused":
+		filter.IsActive = pgtype.Bool{Bool: false, Valid: true}
+	case string(db.MonitorStatusUp), string(db.MonitorStatusDown), string(db.MonitorStatusUnknown), string(db.MonitorStatusPending):
+		filter.Status = db.NullMonitorStatus{MonitorStatus: db.MonitorStatus(status), Valid: true}
+		filter.IsActive = pgtype.Bool{Bool: true, Valid: true}
+	default:
+		return monitorFilter{}, util.ErrInvalidQueryParams
+	}
+
+	if raw := query.Get("group_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return monitorFilter{}, util.ErrInvalidQueryParams
+		}
+		filter.GroupID = pgtype.Int4{Int32: int32(id), Valid: true}
+	}
+
+	if q := strings.TrimSpace(query.Get("q")); q != "" {
+		filter.Search = pgtype.Text{String: escapeLike(q), Valid: true}
+	}
+
+	return filter, nil
+}
+
+// escapeLike makes s match literally inside a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,143 @@
+package monitor
+
+import (
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// How a group's status is rolled up from its active monitors
+const (
+	// RollupWorst reports the worst status of any monitor
+	RollupWorst = "worst"
+	// RollupPercentage reports up while at least min_up_percent are up
+	RollupPercentage = "percentage"
+)
+
+// Group statuses. Degraded only comes from the percentage rollup.
+const (
+	GroupStatusUp       = "up"
+	GroupStatusDegraded = "degraded"
+	GroupStatusDown     = "down"
+	GroupStatusUnknown  = "unknown"
+)
+
+type MonitorGroupRequest struct {
+	Name        string `json:"name" validate:"required,max=120"`
+	Description string `json:"description" validate:"max=500"`
+	Rollup      string `json:"rollup" validate:"omitempty,oneof=worst percentage"`
+	// MinUpPercent defaults to 100
+	MinUpPercent int32 `json:"min_up_percent" validate:"omitempty,min=1,max=100"`
+}
+
+// GroupCounts counts a group's monitors by state
+type GroupCounts struct {
+	Total   int64 `json:"total"`
+	Up      int64 `json:"up"`
+	Down    int64 `json:"down"`
+	Pending int64 `json:"pending"`
+	Paused  int64 `json:"paused"`
+}
+
+type MonitorGroupResponse struct {
+	db.MonitorGroup
+	Status string `json:"status"`
+	// PercentUp is the share of active monitors with a known status that are
+	// up, nil while there are none
+	PercentUp *float64    `json:"percent_up"`
+	Counts    GroupCounts `json:"counts"`
+	// Monitors is only filled when a single group is fetched
+	Monitors []db.Monitor `json:"monitors,omitempty"`
+}
+
+// rollupGroup derives a group's status from its monitor counts. Paused
+// monitors don't count, and a group without active monitors is unknown.
+func rollupGroup(group db.MonitorGroup, counts GroupCounts) MonitorGroupResponse {
+	resp := MonitorGroupResponse{MonitorGroup: group, Status: GroupStatusUnknown, Counts: counts}
+
+	known := counts.Up + counts.Down
+	if known > 0 {
+		percent := float64(counts.Up) / float64(known) * 100
+		resp.PercentUp = &percent
+	}
+
+	switch group.Rollup {
+	case RollupPercentage:
+		switch {
+		case resp.PercentUp == nil:
+		case *resp.PercentUp >= float64(group.MinUpPercent):
+			resp.Status = GroupStatusUp
+		case counts.Up == 0:
+			resp.Status = GroupStatusDown
+		default:
+			resp.Status = GroupStatusDegraded
+		}
+	default:
+		switch {
+		case counts.Down > 0:
+			resp.Status = GroupStatusDown
+		case counts.Pending > 0 || counts.Up == 0:
+			resp.Status = GroupStatusUnknown
+		default:
+			resp.Status = GroupStatusUp
+		}
+	}
+	return resp
+}
+
+// groupCounts loads the monitor counts of every group of the organization
+func (h *Handler) groupCounts(ctx context.Context, orgId uuid.UUID) (map[int32]GroupCounts, error) {
+	rows, err := h.store.GetMonitorGroupCounts(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int32]GroupCounts, len(rows))
+	for _, row := range rows {
+		counts[row.GroupID] = GroupCounts{
+			Total:   row.Total,
+			Up:      row.Up,
+			Down:    row.Down,
+			Pending: row.Pending,
+			Paused:  row.Paused,
+		}
+	}
+	return counts, nil
+}
+
+// groupParam checks that a requested group belongs to the organization. Nil
+// and 0 mean no group.
+func (h *Handler) groupParam(ctx context.Context, orgId uuid.UUID, id *int32) (pgtype.Int4, error) {
+	if id == nil || *id == 0 {
+		return pgtype.Int4{}, nil
+	}
+	group, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: *id, OrgID: orgId})
+	if err != nil {
+		return pgtype.Int4{}, util.ErrMonitorGroupNotFound
+	}
+	return pgtype.Int4{Int32: group.ID, Valid: true}, nil
+}
+
+// groupWriteError reports a name taken by another group of the organization
+func groupWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return util.ErrMonitorGroupExists
+	}
+	return err
+}
+
+func (req MonitorGroupRequest) rollup() (string, int32) {
+	rollup, minUp := req.Rollup, req.MinUpPercent
+	if rollup == "" {
+		rollup = RollupWorst
+	}
+	if minUp == 0 {
+		minUp = 100
+	}
+	return rollup, minUp
+}
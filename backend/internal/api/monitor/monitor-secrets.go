@@ -0,0 +1,263 @@
+package monitor
+
+import (
+	"better-uptime/common/secrets"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxMonitorSecrets bounds how many secrets one monitor keeps
+const maxMonitorSecrets = 20
+
+var secretName = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// sensitiveHeaders must reference a {{variable}} in monitor configs rather than
+// hold the value, which would be stored and returned in plaintext
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"x-api-key":           true,
+	"api-key":             true,
+	"x-auth-token":        true,
+}
+
+// MonitorCredentials log database and cache monitors in. They are stored as
+// the monitor's "username" and "password" secrets.
+type MonitorCredentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// SecretHint is how a stored secret is shown: never the value, only a mask
+type SecretHint struct {
+	Name      string    `json:"name"`
+	Hint      string    `json:"hint"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MonitorResponse is a monitor as the API returns it, with sensitive config
+// values masked and its secrets as hints
+type MonitorResponse struct {
+	db.Monitor
+	Secrets []SecretHint `json:"secrets"`
+}
+
+// SecretAAD binds a sealed secret to its monitor and name
+func SecretAAD(monitorID int32, name string) []byte {
+	return []byte(fmt.Sprintf("monitor_secrets:%d:%s", monitorID, name))
+}
+
+// secretWrites merges the write-only secrets and credentials of a request into
+// name -> value, where nil deletes the secret
+func secretWrites(values map[string]*string, creds *MonitorCredentials) (map[string]*string, error) {
+	writes := make(map[string]*string, len(values)+2)
+	for name, value := range values {
+		if !secretName.MatchString(name) {
+			return nil, fmt.Errorf("secret names may only contain letters, numbers and underscores: %q", name)
+		}
+		writes[name] = value
+	}
+	if creds != nil {
+		username, password := creds.Username, creds.Password
+		writes["username"], writes["password"] = &username, &password
+		if username == "" {
+			writes["username"] = nil
+		}
+		if password == "" {
+			writes["password"] = nil
+		}
+	}
+
+	if len(writes) > maxMonitorSecrets {
+		return nil, fmt.Errorf("a monitor can have at most %d secrets", maxMonitorSecrets)
+	}
+	return writes, nil
+}
+
+// checkSecretWrites fails early when secrets would be written without a key
+func (h *Handler) checkSecretWrites(writes map[string]*string) error {
+	for _, value := range writes {
+		if value != nil && h.secrets == nil {
+			return util.ErrSecretsNotConfigured
+		}
+	}
+	return nil
+}
+
+// saveSecrets seals and stores writes for the monitor, deleting nil entries
+func (h *Handler) saveSecrets(ctx context.Context, monitorID int32, writes map[string]*string) error {
+	for name, value := range writes {
+		if value == nil {
+			if err := h.store.DeleteMonitorSecret(ctx, db.DeleteMonitorSecretParams{MonitorID: monitorID, Name: name}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sealed, err := h.secrets.Seal([]byte(*value), SecretAAD(monitorID, name))
+		if err != nil {
+			return err
+		}
+		if err := h.store.UpsertMonitorSecret(ctx, db.UpsertMonitorSecretParams{
+			MonitorID:  monitorID,
+			Name:       name,
+			Ciphertext: sealed,
+			KeyID:      secrets.KeyID(sealed),
+			Hint:       secrets.Hint(*value),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSecrets decrypts the monitor's secrets. Only checks call it: values are
+// never returned by the API.
+func (h *Handler) loadSecrets(ctx context.Context, monitorID int32) (map[string]string, error) {
+	rows, err := h.store.GetMonitorSecrets(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	if h.secrets == nil {
+		return nil, util.ErrSecretsNotConfigured
+	}
+
+	values := make(map[string]string, len(rows))
+	for _, row := range rows {
+		plaintext, err := h.secrets.Open(row.Ciphertext, SecretAAD(monitorID, row.Name))
+		if err != nil {
+			return nil, fmt.Errorf("secret %q: %w", row.Name, err)
+		}
+		values[row.Name] = string(plaintext)
+	}
+	return values, nil
+}
+
+// secretVars exposes secrets to config templates as {{secrets.<name>}}
+func secretVars(values map[string]string) map[string]string {
+	vars := make(map[string]string, len(values))
+	for name, value := range values {
+		vars["secrets."+name] = value
+	}
+	return vars
+}
+
+// monitorResponse masks the monitor's config and attaches its secret hints
+func (h *Handler) monitorResponse(ctx context.Context, monitor db.Monitor) MonitorResponse {
+	resp := MonitorResponse{Monitor: maskMonitor(monitor), Secrets: []SecretHint{}}
+
+	rows, err := h.store.ListMonitorSecretHints(ctx, monitor.ID)
+	if err != nil {
+		return resp
+	}
+	for _, row := range rows {
+		resp.Secrets = append(resp.Secrets, SecretHint{Name: row.Name, Hint: row.Hint, UpdatedAt: row.UpdatedAt.Time})
+	}
+	return resp
+}
+
+// maskMonitor hides sensitive header values stored in the config before
+// secrets existed, so the API never echoes them
+func maskMonitor(monitor db.Monitor) db.Monitor {
+	monitor.Config = MaskConfig(monitor.Config)
+	return monitor
+}
+
+// MaskConfig hides the values of sensitive headers in a monitor config
+func MaskConfig(config json.RawMessage) json.RawMessage {
+	if len(config) == 0 {
+		return config
+	}
+
+	var parsed any
+	if err := json.Unmarshal(config, &parsed); err != nil {
+		return config
+	}
+	masked, err := json.Marshal(maskSensitive(parsed))
+	if err != nil {
+		return config
+	}
+	return masked
+}
+
+func maskMonitors(monitors []db.Monitor) []db.Monitor {
+	for i := range monitors {
+		monitors[i] = maskMonitor(monitors[i])
+	}
+	return monitors
+}
+
+func maskSensitive(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if s, ok := value.(string); ok && sensitiveHeaders[strings.ToLower(key)] && !templateVar.MatchString(s) {
+				v[key] = secrets.Hint(s)
+				continue
+			}
+			v[key] = maskSensitive(value)
+		}
+	case []any:
+		for i := range v {
+			v[i] = maskSensitive(v[i])
+		}
+	}
+	return node
+}
+
+// checkSensitiveHeader rejects plaintext values for sensitive headers
+func checkSensitiveHeader(name, value string) error {
+	if sensitiveHeaders[strings.ToLower(name)] && !templateVar.MatchString(value) {
+		return fmt.Errorf("%s must reference a secret such as {{secrets.token}} instead of holding the value", name)
+	}
+	return nil
+}
+
+// redact replaces secret values that found their way into a check's results,
+// e.g. a URL or an echoed response, before they are stored
+func (o *checkOutcome) redact(values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+
+	pairs := make([]string, 0, len(values)*2)
+	for _, value := range values {
+		// Very short values would redact unrelated text
+		if len(value) >= 4 {
+			pairs = append(pairs, value, "••••")
+		}
+	}
+	if len(pairs) == 0 {
+		return
+	}
+	r := strings.NewReplacer(pairs...)
+
+	o.errorMsg = r.Replace(o.errorMsg)
+	o.body = r.Replace(o.body)
+	for name, value := range o.headers {
+		o.headers[name] = r.Replace(value)
+	}
+	for i := range o.steps {
+		o.steps[i].URL = r.Replace(o.steps[i].URL)
+		o.steps[i].Error = r.Replace(o.steps[i].Error)
+	}
+	if o.browser != nil {
+		for i := range o.browser.ConsoleErrors {
+			o.browser.ConsoleErrors[i] = r.Replace(o.browser.ConsoleErrors[i])
+		}
+		for i := range o.browser.FailedRequests {
+			o.browser.FailedRequests[i].URL = r.Replace(o.browser.FailedRequests[i].URL)
+			o.browser.FailedRequests[i].Error = r.Replace(o.browser.FailedRequests[i].Error)
+		}
+	}
+}
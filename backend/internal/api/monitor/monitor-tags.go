@@ -0,0 +1,57 @@
+package monitor
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxMonitorTags bounds how many tags one monitor carries
+const maxMonitorTags = 20
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:/-]{0,63}$`)
+
+// NormalizeTags lowercases, validates, sorts and dedupes tags. The result is
+// never nil, so an empty list clears a monitor's tags.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, util.ErrInvalidTag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxMonitorTags {
+		return nil, util.ErrTooManyTags
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// GetTags lists the tags in use in the organization with their monitor counts
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	tags, err := h.store.ListOrgTags(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, tags)
+}
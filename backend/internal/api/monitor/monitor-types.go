@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Monitor types, stored in monitors.type. Types other than http keep their
+// settings in monitors.config.
+const (
+	MonitorTypeHTTP    = "http"
+	MonitorTypeAPI     = "api"
+	MonitorTypeBrowser = "browser"
+	MonitorTypeGRPC    = "grpc"
+	// Database and cache monitors log in with encrypted MonitorCredentials
+	MonitorTypePostgres = "postgres"
+	MonitorTypeMySQL    = "mysql"
+	MonitorTypeRedis    = "redis"
+)
+
+// validateMonitorConfig checks that config is usable for monitorType
+func validateMonitorConfig(monitorType string, config json.RawMessage) error {
+	switch monitorType {
+	case "", MonitorTypeHTTP:
+		return nil
+	case MonitorTypeAPI:
+		_, err := parseAPICheckConfig(config)
+		return err
+	case MonitorTypeBrowser:
+		_, err := parseBrowserCheckConfig(config)
+		return err
+	case MonitorTypeGRPC:
+		_, err := parseGRPCCheckConfig(config)
+		return err
+	case MonitorTypePostgres, MonitorTypeMySQL, MonitorTypeRedis:
+		_, err := parseDatabaseCheckConfig(monitorType, config)
+		return err
+	default:
+		return fmt.Errorf("unknown monitor type %q", monitorType)
+	}
+}
+
+// ValidateMonitor checks type, URL and config as the create and update
+// endpoints do, for monitors defined in a config document
+func ValidateMonitor(monitorType, rawURL string, config json.RawMessage) error {
+	if err := validateMonitorConfig(monitorType, config); err != nil {
+		return err
+	}
+	return validateMonitorTarget(monitorType, rawURL, nil)
+}
+
+// usesSecrets reports whether checks of monitorType can reference secrets
+func usesSecrets(monitorType string) bool {
+	return monitorType != "" && monitorType != MonitorTypeHTTP
+}
+
+// isDatabaseMonitor reports whether monitorType connects with stored credentials
+func isDatabaseMonitor(monitorType string) bool {
+	return monitorType == MonitorTypePostgres || monitorType == MonitorTypeMySQL || monitorType == MonitorTypeRedis
+}
+
+// validateMonitorTarget checks that rawURL suits monitorType and, for database
+// monitors, that any credentials come separately
+func validateMonitorTarget(monitorType, rawURL string, creds *MonitorCredentials) error {
+	if !isDatabaseMonitor(monitorType) {
+		if creds != nil {
+			return fmt.Errorf("credentials are only used by postgres, mysql and redis monitors")
+		}
+		return nil
+	}
+	return validateDatabaseURL(monitorType, rawURL)
+}
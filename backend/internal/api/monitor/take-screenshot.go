@@ -1,28 +1,133 @@
 package monitor
 
 import (
-	"better-uptime/common/screenshot"
-	"better-uptime/config"
+	"better-uptime/common/storage"
 	db "better-uptime/internal/db/sqlc"
 	"context"
 	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// screenshotTimeout bounds a capture plus upload
+	screenshotTimeout = 90 * time.Second
+	// alertScreenshotTTL is how long the screenshot link in an alert stays valid
+	alertScreenshotTTL = 7 * 24 * time.Hour
+	// screenshotURLTTL is how long screenshot links returned by the API stay valid
+	screenshotURLTTL = time.Hour
 )
 
+// TakeScreenshot captures the monitor's page and returns its artifact key
 func (h *Handler) TakeScreenshot(ctx context.Context, monitor db.Monitor) (string, error) {
+	key := fmt.Sprintf("monitors/%d/down/%d.png", monitor.ID, time.Now().Unix())
 
-	cfg := config.LoadConfig()
+	if err := h.screenshots.CaptureAndStore(ctx, monitor.Url, key); err != nil {
+		return "", fmt.Errorf("failed to take screenshot: %w", err)
+	}
+
+	return key, nil
+}
+
+// screenshotURL signs a stored screenshot key, falling back to the legacy URL column
+func (h *Handler) screenshotURL(ctx context.Context, key, legacyURL pgtype.Text) pgtype.Text {
+	if !key.Valid || h.artifacts == nil {
+		return legacyURL
+	}
+
+	url, err := h.artifacts.SignedURL(ctx, key.String, screenshotURLTTL)
+	if err != nil {
+		log.Printf("Failed to sign screenshot %s: %v", key.String, err)
+		return legacyURL
+	}
+	return pgtype.Text{String: url, Valid: true}
+}
 
-	// Validate that cfg is not nil
-	if cfg == nil {
-		return "", fmt.Errorf("configuration is not loaded")
+// takesScreenshot reports whether a failed check gets a screenshot. Only
+// web pages are captured by the screenshot provider: other targets aren't
+// pages, and their URLs can carry credentials. Browser checks only attach
+// the screenshot they took themselves.
+func takesScreenshot(monitor db.Monitor, outcome checkOutcome) bool {
+	switch monitor.Type.String {
+	case "", MonitorTypeHTTP:
+		return true
+	case MonitorTypeBrowser:
+		return outcome.screenshotKey != ""
+	default:
+		return false
 	}
+}
+
+// captureDownScreenshot screenshots the monitor in the background after it went
+// down and attaches the image to the failing log row and the open incident. When
+// the check already stored a screenshot, existingKey is attached instead. The
+// returned channel yields a signed URL, or is closed empty when the capture failed.
+func (h *Handler) captureDownScreenshot(ctx context.Context, monitor db.Monitor, failing db.MonitorLog, existingKey string) <-chan string {
+	result := make(chan string, 1)
+
+	go func() {
+		defer close(result)
+
+		// The check's context may belong to a request that ends before we are done
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), screenshotTimeout)
+		defer cancel()
+
+		key := existingKey
+		if key == "" {
+			var err error
+			key, err = h.TakeScreenshot(ctx, monitor)
+			if err != nil {
+				log.Printf("Screenshot for monitor %d failed: %v", monitor.ID, err)
+				return
+			}
+
+			if err := h.store.SetMonitorLogScreenshot(ctx, db.SetMonitorLogScreenshotParams{
+				ID:            failing.ID,
+				CheckedAt:     failing.CheckedAt,
+				ScreenshotKey: pgtype.Text{String: key, Valid: true},
+			}); err != nil {
+				log.Printf("Failed to attach screenshot to log %d: %v", failing.ID, err)
+			}
+		}
+
+		if err := h.store.SetOpenIncidentScreenshot(ctx, db.SetOpenIncidentScreenshotParams{
+			MonitorID:     monitor.ID,
+			ScreenshotKey: pgtype.Text{String: key, Valid: true},
+		}); err != nil {
+			log.Printf("Failed to attach screenshot to incident of monitor %d: %v", monitor.ID, err)
+		}
 
-	folderPath := fmt.Sprintf("monitors/%d/down", monitor.ID)
+		url, err := h.artifacts.SignedURL(ctx, key, alertScreenshotTTL)
+		if err != nil {
+			log.Printf("Failed to sign screenshot %s: %v", key, err)
+			return
+		}
+		result <- url
+	}()
 
-	url, err := screenshot.ScreenshotAndUpload(monitor.Url, folderPath, *cfg)
+	return result
+}
+
+// monitorScreenshotKeys lists the stored screenshots of a monitor. They have to
+// be read before the monitor is deleted, its logs and incidents go with it.
+func (h *Handler) monitorScreenshotKeys(ctx context.Context, monitorID int32) []string {
+	if h.artifacts == nil {
+		return nil
+	}
+	keys, err := h.store.ListMonitorScreenshotKeys(ctx, monitorID)
 	if err != nil {
-		return "", fmt.Errorf("failed to take screenshot: %w", err)
+		log.Printf("Failed to list screenshots of monitor %d: %v", monitorID, err)
+		return nil
 	}
+	return keys
+}
 
-	return url, nil
+// deleteScreenshots deletes the screenshots of a deleted monitor in the background
+func (h *Handler) deleteScreenshots(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	go storage.DeleteAll(context.WithoutCancel(ctx), h.artifacts, keys)
 }
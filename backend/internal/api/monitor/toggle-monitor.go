@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"better-uptime/common/audit"
 	"better-uptime/common/logger"
 	"better-uptime/common/middleware"
 	"better-uptime/common/util"
@@ -29,7 +30,7 @@ func (h *Handler) ToggleMonitor(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	var req ToggleMonitorRequest
 	 err = util.ReadJsonAndValidate(w, r, &req); 
@@ -39,9 +40,18 @@ func (h *Handler) ToggleMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existing, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+		ID:    int32(req.ID),
+		OrgID: orgId,
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
 	monitor, err := h.store.ToggleMonitor(ctx, db.ToggleMonitorParams{
 		ID:       int32(req.ID),
-		UserID:   pgtype.UUID{Bytes: userId, Valid: true},
+		OrgID:    orgId,
 		IsActive: pgtype.Bool{Bool: req.IsActive, Valid: true},
 	})
 	if err != nil {
@@ -49,6 +59,18 @@ func (h *Handler) ToggleMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.WriteJson(w, http.StatusOK, monitor)
+	action := audit.ActionPause
+	if req.IsActive {
+		action = audit.ActionResume
+	}
+	audit.Record(r, h.store, audit.Entry{
+		Action:       action,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   monitor.ID,
+		Before:       maskMonitor(existing),
+		After:        maskMonitor(monitor),
+	})
+
+	util.WriteJson(w, http.StatusOK, maskMonitor(monitor))
 
 }
@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (h *Handler) UpdateMonitorGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	var req MonitorGroupRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	existing, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: int32(id), OrgID: payload.OrgId})
+	if err != nil {
+		util.ErrorJson(w, util.ErrMonitorGroupNotFound)
+		return
+	}
+
+	rollup, minUp := req.rollup()
+	group, err := h.store.UpdateMonitorGroup(ctx, db.UpdateMonitorGroupParams{
+		ID:           existing.ID,
+		OrgID:        payload.OrgId,
+		Name:         req.Name,
+		Description:  req.Description,
+		Rollup:       rollup,
+		MinUpPercent: minUp,
+	})
+	if err != nil {
+		util.ErrorJson(w, groupWriteError(err))
+		return
+	}
+
+	counts, err := h.groupCounts(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceMonitorGroup,
+		ResourceID:   group.ID,
+		Before:       existing,
+		After:        group,
+	})
+
+	util.WriteJson(w, http.StatusOK, rollupGroup(group, counts[group.ID]))
+}
@@ -1,12 +1,17 @@
 package monitor
 
 import (
+	"better-uptime/common/audit"
 	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
 	"better-uptime/common/util"
 	db "better-uptime/internal/db/sqlc"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -16,6 +21,16 @@ type UpdateMonitorRequest struct {
 	Method   string `json:"method"`
 	Type     string `json:"type"`
 	Interval int32  `json:"interval" validate:"min=1"`
+	// Config replaces the type-specific settings when set
+	Config json.RawMessage `json:"config,omitempty"`
+	// Secrets are upserted by name, a null value deletes one; others are kept
+	Secrets map[string]*string `json:"secrets,omitempty"`
+	// Credentials replace the stored ones when set
+	Credentials *MonitorCredentials `json:"credentials,omitempty"`
+	// GroupID moves the monitor to another group when set, 0 takes it out
+	GroupID *int32 `json:"group_id,omitempty"`
+	// Tags replace the monitor's tags when set, an empty list clears them
+	Tags []string `json:"tags,omitempty"`
 }
 
 func (h *Handler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +41,7 @@ func (h *Handler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
-	userId := payload.UserId
+	orgId := payload.OrgId
 
 	var req UpdateMonitorRequest
 	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
@@ -36,27 +51,101 @@ func (h *Handler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
 
 	existing, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
 		ID:     int32(req.ID),
-		UserID: pgtype.UUID{Bytes: userId, Valid: true},
+		OrgID:  orgId,
 	})
 	if err != nil {
 		util.ErrorJson(w, fmt.Errorf("not found this monitor"))
 		return
 	}
 
+	before := h.monitorResponse(ctx, existing)
+
+	monitor, err := h.updateMonitor(ctx, orgId, existing, req, existing.IsActive)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	resp := h.monitorResponse(ctx, monitor)
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   monitor.ID,
+		Before:       before,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+// updateMonitor applies req to an existing monitor of the organization.
+// isActive pauses or resumes it; the monitor's own value keeps it as is.
+func (h *Handler) updateMonitor(ctx context.Context, orgId uuid.UUID, existing db.Monitor, req UpdateMonitorRequest, isActive pgtype.Bool) (db.Monitor, error) {
+	// Monitors kept from a bigger plan keep their interval until it changes
+	if req.Interval != existing.Interval {
+		plan, err := plans.ForOrg(ctx, h.store, *h.config, orgId)
+		if err != nil {
+			return db.Monitor{}, err
+		}
+		if err := plan.CheckInterval(req.Interval); err != nil {
+			return db.Monitor{}, err
+		}
+	}
+
+	monitorType := req.Type
+	if monitorType == "" {
+		monitorType = existing.Type.String
+	}
+	config := req.Config
+	if config == nil {
+		config = existing.Config
+	}
+	if err := validateMonitorConfig(monitorType, config); err != nil {
+		return db.Monitor{}, err
+	}
+	if err := validateMonitorTarget(monitorType, req.Url, req.Credentials); err != nil {
+		return db.Monitor{}, err
+	}
+	writes, err := secretWrites(req.Secrets, req.Credentials)
+	if err != nil {
+		return db.Monitor{}, err
+	}
+	if err := h.checkSecretWrites(writes); err != nil {
+		return db.Monitor{}, err
+	}
+	var tags []string
+	if req.Tags != nil {
+		if tags, err = NormalizeTags(req.Tags); err != nil {
+			return db.Monitor{}, err
+		}
+	}
+	groupID := existing.GroupID
+	if req.GroupID != nil {
+		if groupID, err = h.groupParam(ctx, orgId, req.GroupID); err != nil {
+			return db.Monitor{}, err
+		}
+	}
+
 	monitor, err := h.store.UpdateMonitor(ctx, db.UpdateMonitorParams{
-		ID:       int32(req.ID),
-		UserID:   pgtype.UUID{Bytes: userId, Valid: true},
+		ID:       existing.ID,
+		OrgID:    orgId,
 		Url:      req.Url,
 		Method:   pgtype.Text{String: req.Method, Valid: req.Method != ""},
 		Type:     pgtype.Text{String: req.Type, Valid: req.Type != ""},
 		Interval: int32(req.Interval),
 		Status:   existing.Status,  
-		IsActive: existing.IsActive, 
+		IsActive: isActive,
+		Config:   req.Config,
+		GroupID:  groupID,
+		Tags:     tags,
 	})
 	if err != nil {
-		util.ErrorJson(w, err)
-		return
+		return db.Monitor{}, err
+	}
+
+	if err := h.saveSecrets(ctx, monitor.ID, writes); err != nil {
+		return db.Monitor{}, err
 	}
 
-	util.WriteJson(w, http.StatusOK, monitor)
+	return monitor, nil
 }
@@ -0,0 +1,292 @@
+package monitor
+
+import (
+	"better-uptime/common/apiv2"
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"time"
+)
+
+// MonitorGroup is a monitor group in the v2 API, with the status rolled up
+// from its monitors
+type MonitorGroup struct {
+	ID           int32   `json:"id"`
+	ExternalID   *string `json:"external_id" doc:"Key of the group in a manifest, null when it is not managed by one"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Rollup       string  `json:"rollup" doc:"worst or percentage"`
+	MinUpPercent int32   `json:"min_up_percent"`
+	Status       string  `json:"status" doc:"up, degraded, down or unknown"`
+	// PercentUp is nil while no active monitor has a known status
+	PercentUp *float64    `json:"percent_up" doc:"Share of active monitors with a known status that are up"`
+	Counts    GroupCounts `json:"counts"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type MonitorGroupInput struct {
+	Name         string `json:"name" validate:"required,max=120"`
+	Description  string `json:"description,omitempty" validate:"max=500"`
+	Rollup       string `json:"rollup,omitempty" validate:"omitempty,oneof=worst percentage" doc:"Defaults to worst"`
+	MinUpPercent int32  `json:"min_up_percent,omitempty" validate:"omitempty,min=1,max=100" doc:"Defaults to 100"`
+}
+
+// MonitorGroupPatch updates the fields it sets and keeps the others
+type MonitorGroupPatch struct {
+	Name         *string `json:"name,omitempty" validate:"omitempty,min=1,max=120"`
+	Description  *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Rollup       *string `json:"rollup,omitempty" validate:"omitempty,oneof=worst percentage"`
+	MinUpPercent *int32  `json:"min_up_percent,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+func (h *Handler) v2MonitorGroupRoutes(api *apiv2.API) {
+	api.Handle(apiv2.Operation{
+		ID: "listMonitorGroups", Method: http.MethodGet, Path: "/monitor-groups", Tag: "Monitor groups",
+		Summary: "List monitor groups", Paginated: true, Response: apiv2.Page[MonitorGroup]{},
+	}, h.ListMonitorGroupsV2)
+	api.Handle(apiv2.Operation{
+		ID: "createMonitorGroup", Method: http.MethodPost, Path: "/monitor-groups", Tag: "Monitor groups",
+		Summary: "Create a monitor group", Body: MonitorGroupInput{},
+		Status: http.StatusCreated, Response: MonitorGroup{},
+	}, h.CreateMonitorGroupV2)
+	api.Handle(apiv2.Operation{
+		ID: "getMonitorGroup", Method: http.MethodGet, Path: "/monitor-groups/{id}", Tag: "Monitor groups",
+		Summary: "Get a monitor group", Response: MonitorGroup{},
+	}, h.GetMonitorGroupV2)
+	api.Handle(apiv2.Operation{
+		ID: "updateMonitorGroup", Method: http.MethodPatch, Path: "/monitor-groups/{id}", Tag: "Monitor groups",
+		Summary: "Update a monitor group", Body: MonitorGroupPatch{}, Response: MonitorGroup{},
+	}, h.UpdateMonitorGroupV2)
+	api.Handle(apiv2.Operation{
+		ID: "deleteMonitorGroup", Method: http.MethodDelete, Path: "/monitor-groups/{id}", Tag: "Monitor groups",
+		Summary:     "Delete a monitor group",
+		Description: "The group's monitors are kept, ungrouped.",
+		Status:      http.StatusNoContent,
+	}, h.DeleteMonitorGroupV2)
+}
+
+func (h *Handler) ListMonitorGroupsV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var after apiv2.IDCursor
+	limit, err := apiv2.ReadPage(r, &after)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	groups, err := h.store.ListOrgMonitorGroupsPage(ctx, db.ListOrgMonitorGroupsPageParams{
+		OrgID:    payload.OrgId,
+		AfterID:  after.ID,
+		PageSize: limit + 1,
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	counts, err := h.groupCounts(ctx, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, apiv2.NewPage(groups, limit,
+		func(group db.MonitorGroup) MonitorGroup { return toV2MonitorGroup(group, counts[group.ID]) },
+		func(group db.MonitorGroup) any { return apiv2.IDCursor{ID: group.ID} }))
+}
+
+func (h *Handler) GetMonitorGroupV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	group, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	counts, err := h.groupCounts(ctx, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, toV2MonitorGroup(group, counts[group.ID]))
+}
+
+func (h *Handler) CreateMonitorGroupV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req MonitorGroupInput
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	rollup, minUp := MonitorGroupRequest(req).rollup()
+	group, err := h.store.CreateMonitorGroup(ctx, db.CreateMonitorGroupParams{
+		OrgID:        payload.OrgId,
+		Name:         req.Name,
+		Description:  req.Description,
+		Rollup:       rollup,
+		MinUpPercent: minUp,
+	})
+	if err != nil {
+		apiv2.WriteError(w, groupWriteError(err))
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceMonitorGroup,
+		ResourceID:   group.ID,
+		After:        group,
+	})
+
+	util.WriteJson(w, http.StatusCreated, toV2MonitorGroup(group, GroupCounts{}))
+}
+
+func (h *Handler) UpdateMonitorGroupV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	var req MonitorGroupPatch
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	merged := MonitorGroupRequest{
+		Name:         existing.Name,
+		Description:  existing.Description,
+		Rollup:       existing.Rollup,
+		MinUpPercent: existing.MinUpPercent,
+	}
+	if req.Name != nil {
+		merged.Name = *req.Name
+	}
+	if req.Description != nil {
+		merged.Description = *req.Description
+	}
+	if req.Rollup != nil {
+		merged.Rollup = *req.Rollup
+	}
+	if req.MinUpPercent != nil {
+		merged.MinUpPercent = *req.MinUpPercent
+	}
+
+	rollup, minUp := merged.rollup()
+	group, err := h.store.UpdateMonitorGroup(ctx, db.UpdateMonitorGroupParams{
+		ID:           existing.ID,
+		OrgID:        payload.OrgId,
+		Name:         merged.Name,
+		Description:  merged.Description,
+		Rollup:       rollup,
+		MinUpPercent: minUp,
+	})
+	if err != nil {
+		apiv2.WriteError(w, groupWriteError(err))
+		return
+	}
+	counts, err := h.groupCounts(ctx, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceMonitorGroup,
+		ResourceID:   group.ID,
+		Before:       existing,
+		After:        group,
+	})
+
+	util.WriteJson(w, http.StatusOK, toV2MonitorGroup(group, counts[group.ID]))
+}
+
+func (h *Handler) DeleteMonitorGroupV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.store.GetMonitorGroup(ctx, db.GetMonitorGroupParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if _, err := h.store.DeleteMonitorGroup(ctx, db.DeleteMonitorGroupParams{ID: existing.ID, OrgID: payload.OrgId}); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceMonitorGroup,
+		ResourceID:   existing.ID,
+		Before:       existing,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toV2MonitorGroup(group db.MonitorGroup, counts GroupCounts) MonitorGroup {
+	rolled := rollupGroup(group, counts)
+	return MonitorGroup{
+		ID:           group.ID,
+		ExternalID:   textPtr(group.ExternalID),
+		Name:         group.Name,
+		Description:  group.Description,
+		Rollup:       group.Rollup,
+		MinUpPercent: group.MinUpPercent,
+		Status:       rolled.Status,
+		PercentUp:    rolled.PercentUp,
+		Counts:       counts,
+		CreatedAt:    group.CreatedAt.Time,
+		UpdatedAt:    group.UpdatedAt.Time,
+	}
+}
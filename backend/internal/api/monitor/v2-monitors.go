@@ -0,0 +1,686 @@
+package monitor
+
+import (
+	"better-uptime/common/apiv2"
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Monitor is a monitor in the v2 API
+type Monitor struct {
+	ID int32 `json:"id"`
+	// ExternalID is set on monitors managed by a manifest
+	ExternalID *string `json:"external_id" doc:"Key of the monitor in a manifest, null when it is not managed by one"`
+	URL        string  `json:"url"`
+	Type       string  `json:"type"`
+	Method     string  `json:"method"`
+	Interval   int32   `json:"interval" doc:"Seconds between checks"`
+	Paused     bool    `json:"paused"`
+	Status     string  `json:"status" doc:"up, down, unknown or pending"`
+	// Config has sensitive header values masked
+	Config    json.RawMessage `json:"config" doc:"Type-specific settings, with sensitive header values masked"`
+	GroupID   *int32          `json:"group_id"`
+	Tags      []string        `json:"tags"`
+	Alerts    []MonitorAlert  `json:"alerts"`
+	Secrets   []SecretHint    `json:"secrets"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// MonitorAlert sends a contact the monitor's alerts
+type MonitorAlert struct {
+	ContactID       int32 `json:"contact_id"`
+	OnDown          bool  `json:"on_down"`
+	OnUp            bool  `json:"on_up"`
+	OnSlow          bool  `json:"on_slow"`
+	SlowThresholdMs int32 `json:"slow_threshold_ms"`
+}
+
+type MonitorAlertInput struct {
+	ContactID int32 `json:"contact_id" validate:"required"`
+	// OnDown defaults to true
+	OnDown          *bool `json:"on_down,omitempty" doc:"Defaults to true"`
+	OnUp            bool  `json:"on_up,omitempty"`
+	OnSlow          bool  `json:"on_slow,omitempty"`
+	SlowThresholdMs int32 `json:"slow_threshold_ms,omitempty" validate:"omitempty,min=1" doc:"Defaults to 5000"`
+}
+
+// MonitorInput creates a monitor
+type MonitorInput struct {
+	URL      string          `json:"url" validate:"required"`
+	Type     string          `json:"type,omitempty" validate:"omitempty,oneof=http api browser grpc postgres mysql redis" doc:"Defaults to http"`
+	Method   string          `json:"method,omitempty" doc:"Defaults to GET"`
+	Interval int32           `json:"interval" validate:"min=1" doc:"Seconds between checks"`
+	Paused   bool            `json:"paused,omitempty"`
+	Config   json.RawMessage `json:"config,omitempty" doc:"Type-specific settings, e.g. the steps of an api monitor"`
+	// Secrets and Credentials are write-only
+	Secrets     map[string]*string  `json:"secrets,omitempty" doc:"Write-only values checks reference as {{secrets.<name>}}"`
+	Credentials *MonitorCredentials `json:"credentials,omitempty" doc:"Login of database and cache monitors, stored as secrets"`
+	GroupID     *int32              `json:"group_id,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Alerts      []MonitorAlertInput `json:"alerts,omitempty" validate:"omitempty,max=50,dive"`
+}
+
+// MonitorPatch updates the fields it sets and keeps the others
+type MonitorPatch struct {
+	URL      *string            `json:"url,omitempty" validate:"omitempty,min=1"`
+	Type     *string            `json:"type,omitempty" validate:"omitempty,oneof=http api browser grpc postgres mysql redis"`
+	Method   *string            `json:"method,omitempty"`
+	Interval *int32             `json:"interval,omitempty" validate:"omitempty,min=1"`
+	Paused   *bool              `json:"paused,omitempty"`
+	Config   json.RawMessage    `json:"config,omitempty" doc:"Replaces the type-specific settings"`
+	Secrets  map[string]*string `json:"secrets,omitempty" doc:"Upserted by name, null deletes one; the others are kept"`
+	// Credentials replace the stored ones
+	Credentials *MonitorCredentials `json:"credentials,omitempty"`
+	GroupID     *int32              `json:"group_id,omitempty" doc:"0 takes the monitor out of its group"`
+	Tags        []string            `json:"tags,omitempty" doc:"Replaces the tags, an empty list clears them"`
+	Alerts      []MonitorAlertInput `json:"alerts,omitempty" validate:"omitempty,max=50,dive" doc:"Replaces the alerts, an empty list removes them"`
+}
+
+// Check is one recorded check of a monitor
+type Check struct {
+	ID             int32        `json:"id"`
+	CheckedAt      time.Time    `json:"checked_at"`
+	Status         string       `json:"status" doc:"up or down"`
+	StatusCode     *int32       `json:"status_code"`
+	ResponseTimeMs *float64     `json:"response_time_ms"`
+	DNSOk          *bool        `json:"dns_ok"`
+	SSLOk          *bool        `json:"ssl_ok"`
+	Timings        PhaseTimings `json:"timings"`
+	ErrorType      *string      `json:"error_type"`
+	ErrorMessage   *string      `json:"error_message"`
+}
+
+// checkCursor is the position in a monitor's checks, newest first
+type checkCursor struct {
+	CheckedAt time.Time `json:"checked_at"`
+	ID        int32     `json:"id"`
+}
+
+// defaultSlowThresholdMs is the slow alert threshold when none is given
+const defaultSlowThresholdMs = 5000
+
+var monitorFilterParams = []apiv2.Param{
+	{Name: "tag", Description: "Only monitors with all of these tags", Repeated: true},
+	{Name: "status", Description: "up, down, unknown, pending or paused"},
+	{Name: "group_id", Description: "Only monitors of this group", Type: "integer"},
+	{Name: "q", Description: "Only monitors whose URL contains this"},
+}
+
+func (h *Handler) v2MonitorRoutes(api *apiv2.API) {
+	api.Handle(apiv2.Operation{
+		ID: "listMonitors", Method: http.MethodGet, Path: "/monitors", Tag: "Monitors",
+		Summary: "List monitors", Query: monitorFilterParams, Paginated: true,
+		Response: apiv2.Page[Monitor]{},
+	}, h.ListMonitorsV2)
+	api.Handle(apiv2.Operation{
+		ID: "createMonitor", Method: http.MethodPost, Path: "/monitors", Tag: "Monitors",
+		Summary:     "Create a monitor",
+		Description: "The monitor starts as pending and is checked on the next round.",
+		Body:        MonitorInput{}, Status: http.StatusCreated, Response: Monitor{},
+	}, h.CreateMonitorV2)
+	api.Handle(apiv2.Operation{
+		ID: "getMonitor", Method: http.MethodGet, Path: "/monitors/{id}", Tag: "Monitors",
+		Summary: "Get a monitor", Response: Monitor{},
+	}, h.GetMonitorV2)
+	api.Handle(apiv2.Operation{
+		ID: "updateMonitor", Method: http.MethodPatch, Path: "/monitors/{id}", Tag: "Monitors",
+		Summary: "Update a monitor", Body: MonitorPatch{}, Response: Monitor{},
+	}, h.UpdateMonitorV2)
+	api.Handle(apiv2.Operation{
+		ID: "deleteMonitor", Method: http.MethodDelete, Path: "/monitors/{id}", Tag: "Monitors",
+		Summary: "Delete a monitor", Status: http.StatusNoContent,
+	}, h.DeleteMonitorV2)
+	api.Handle(apiv2.Operation{
+		ID: "listMonitorChecks", Method: http.MethodGet, Path: "/monitors/{id}/checks", Tag: "Monitors",
+		Summary: "List a monitor's checks, newest first",
+		Query: []apiv2.Param{
+			{Name: "since", Description: "RFC 3339 time of the oldest check"},
+			{Name: "until", Description: "RFC 3339 time the checks are before"},
+		},
+		Paginated: true, Response: apiv2.Page[Check]{},
+	}, h.ListChecksV2)
+}
+
+func (h *Handler) ListMonitorsV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var after apiv2.IDCursor
+	limit, err := apiv2.ReadPage(r, &after)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	filter, err := parseMonitorFilter(r)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	monitors, err := h.store.ListOrgMonitorsPage(ctx, db.ListOrgMonitorsPageParams{
+		OrgID:    payload.OrgId,
+		AfterID:  after.ID,
+		Tags:     filter.Tags,
+		Status:   filter.Status,
+		IsActive: filter.IsActive,
+		GroupID:  filter.GroupID,
+		Search:   filter.Search,
+		PageSize: limit + 1,
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	converted, err := h.v2Monitors(ctx, monitors)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, apiv2.NewPage(converted, limit,
+		func(m Monitor) Monitor { return m },
+		func(m Monitor) any { return apiv2.IDCursor{ID: m.ID} }))
+}
+
+func (h *Handler) GetMonitorV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	resp, err := h.v2Monitor(ctx, monitor)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func (h *Handler) CreateMonitorV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req MonitorInput
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if err := h.checkAlertInputs(ctx, payload.OrgId, req.Alerts); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	if req.Type == "" {
+		req.Type = MonitorTypeHTTP
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	monitor, err := h.createMonitor(ctx, payload.UserId, payload.OrgId, CreateMonitorRequest{
+		Url:         req.URL,
+		Method:      req.Method,
+		Type:        req.Type,
+		Interval:    req.Interval,
+		Status:      string(db.MonitorStatusPending),
+		IsActive:    !req.Paused,
+		Config:      req.Config,
+		Secrets:     req.Secrets,
+		Credentials: req.Credentials,
+		GroupID:     req.GroupID,
+		Tags:        req.Tags,
+	})
+	if err != nil {
+		apiv2.WriteError(w, v2MonitorError(err))
+		return
+	}
+	if err := h.syncAlerts(ctx, monitor.ID, req.Alerts); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	resp, err := h.v2Monitor(ctx, monitor)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   monitor.ID,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) UpdateMonitorV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	var req MonitorPatch
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	before, err := h.v2Monitor(ctx, existing)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if req.Alerts != nil {
+		if err := h.checkAlertInputs(ctx, payload.OrgId, req.Alerts); err != nil {
+			apiv2.WriteError(w, err)
+			return
+		}
+	}
+
+	update := UpdateMonitorRequest{
+		ID:          int64(existing.ID),
+		Url:         existing.Url,
+		Interval:    existing.Interval,
+		Config:      req.Config,
+		Secrets:     req.Secrets,
+		Credentials: req.Credentials,
+		GroupID:     req.GroupID,
+		Tags:        req.Tags,
+	}
+	if req.URL != nil {
+		update.Url = *req.URL
+	}
+	if req.Type != nil {
+		update.Type = *req.Type
+	}
+	if req.Method != nil {
+		update.Method = *req.Method
+	}
+	if req.Interval != nil {
+		update.Interval = *req.Interval
+	}
+	isActive := existing.IsActive
+	if req.Paused != nil {
+		isActive = pgtype.Bool{Bool: !*req.Paused, Valid: true}
+	}
+
+	monitor, err := h.updateMonitor(ctx, payload.OrgId, existing, update, isActive)
+	if err != nil {
+		apiv2.WriteError(w, v2MonitorError(err))
+		return
+	}
+	if req.Alerts != nil {
+		if err := h.syncAlerts(ctx, monitor.ID, req.Alerts); err != nil {
+			apiv2.WriteError(w, err)
+			return
+		}
+	}
+
+	resp, err := h.v2Monitor(ctx, monitor)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   monitor.ID,
+		Before:       before,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteMonitorV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{ID: id, OrgID: payload.OrgId})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	before := h.monitorResponse(ctx, existing)
+	screenshots := h.monitorScreenshotKeys(ctx, id)
+
+	if err := h.store.DeleteMonitor(ctx, db.DeleteMonitorParams{ID: id, OrgID: payload.OrgId}); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	h.deleteScreenshots(ctx, screenshots)
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceMonitor,
+		ResourceID:   id,
+		Before:       before,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListChecksV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+	id, err := apiv2.PathID(r, "id")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	var after checkCursor
+	limit, err := apiv2.ReadPage(r, &after)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	since, err := timeParam(r, "since")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	until, err := timeParam(r, "until")
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	if _, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{ID: id, OrgID: payload.OrgId}); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	rows, err := h.store.ListMonitorLogsPage(ctx, db.ListMonitorLogsPageParams{
+		MonitorID:       util.ToPgInt4(id),
+		Since:           since,
+		Until:           until,
+		BeforeCheckedAt: pgtype.Timestamp{Time: after.CheckedAt, Valid: !after.CheckedAt.IsZero()},
+		BeforeID:        after.ID,
+		PageSize:        limit + 1,
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, apiv2.NewPage(rows, limit, toV2Check,
+		func(row db.ListMonitorLogsPageRow) any {
+			return checkCursor{CheckedAt: row.CheckedAt.Time, ID: row.ID}
+		}))
+}
+
+// timeParam reads an optional RFC 3339 time from the query string
+func timeParam(r *http.Request, name string) (pgtype.Timestamp, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return pgtype.Timestamp{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return pgtype.Timestamp{}, apiv2.Errorf(http.StatusBadRequest, "%s must be an RFC 3339 time", name)
+	}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}, nil
+}
+
+func toV2Check(row db.ListMonitorLogsPageRow) Check {
+	check := Check{
+		ID:             row.ID,
+		CheckedAt:      row.CheckedAt.Time,
+		Status:         string(db.MonitorStatusUp),
+		StatusCode:     int4Ptr(row.StatusCode),
+		ResponseTimeMs: float8Ptr(row.ResponseTime),
+		DNSOk:          boolPtr(row.DnsOk),
+		SSLOk:          boolPtr(row.SslOk),
+		Timings: PhaseTimings{
+			DNSMs:      float8Ptr(row.DnsMs),
+			ConnectMs:  float8Ptr(row.ConnectMs),
+			TLSMs:      float8Ptr(row.TlsMs),
+			TTFBMs:     float8Ptr(row.TtfbMs),
+			TransferMs: float8Ptr(row.TransferMs),
+		},
+		ErrorType:    textPtr(row.ErrorType),
+		ErrorMessage: textPtr(row.ErrorMessage),
+	}
+	if check.ErrorType != nil && *check.ErrorType != string(ErrorNone) {
+		check.Status = string(db.MonitorStatusDown)
+	}
+	return check
+}
+
+// v2Monitor converts a monitor with its alerts and secret hints
+func (h *Handler) v2Monitor(ctx context.Context, monitor db.Monitor) (Monitor, error) {
+	converted, err := h.v2Monitors(ctx, []db.Monitor{monitor})
+	if err != nil {
+		return Monitor{}, err
+	}
+	return converted[0], nil
+}
+
+// v2Monitors converts monitors, loading the alerts and secret hints of all of
+// them in one query each
+func (h *Handler) v2Monitors(ctx context.Context, monitors []db.Monitor) ([]Monitor, error) {
+	ids := make([]int32, len(monitors))
+	for i, m := range monitors {
+		ids[i] = m.ID
+	}
+
+	configs, err := h.store.GetAlertConfigsForMonitors(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	alerts := make(map[int32][]MonitorAlert)
+	for _, c := range configs {
+		alerts[c.MonitorID.Int32] = append(alerts[c.MonitorID.Int32], MonitorAlert{
+			ContactID:       c.AlertContactID.Int32,
+			OnDown:          c.AlertOnDown.Bool,
+			OnUp:            c.AlertOnUp.Bool,
+			OnSlow:          c.AlertOnSlow.Bool,
+			SlowThresholdMs: c.SlowThresholdMs.Int32,
+		})
+	}
+
+	hints, err := h.store.ListSecretHintsForMonitors(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[int32][]SecretHint)
+	for _, hint := range hints {
+		secrets[hint.MonitorID] = append(secrets[hint.MonitorID], SecretHint{
+			Name:      hint.Name,
+			Hint:      hint.Hint,
+			UpdatedAt: hint.UpdatedAt.Time,
+		})
+	}
+
+	converted := make([]Monitor, len(monitors))
+	for i, m := range monitors {
+		converted[i] = toV2Monitor(m)
+		if list := alerts[m.ID]; list != nil {
+			converted[i].Alerts = list
+		}
+		if list := secrets[m.ID]; list != nil {
+			converted[i].Secrets = list
+		}
+	}
+	return converted, nil
+}
+
+func toV2Monitor(m db.Monitor) Monitor {
+	monitor := Monitor{
+		ID:         m.ID,
+		ExternalID: textPtr(m.ExternalID),
+		URL:        m.Url,
+		Type:       m.Type.String,
+		Method:     m.Method.String,
+		Interval:   m.Interval,
+		Paused:     m.IsActive.Valid && !m.IsActive.Bool,
+		Status:     string(m.Status.MonitorStatus),
+		Config:     MaskConfig(m.Config),
+		GroupID:    int4Ptr(m.GroupID),
+		Tags:       m.Tags,
+		Alerts:     []MonitorAlert{},
+		Secrets:    []SecretHint{},
+		CreatedAt:  m.CreatedAt.Time,
+		UpdatedAt:  m.UpdatedAt.Time,
+	}
+	if monitor.Type == "" {
+		monitor.Type = MonitorTypeHTTP
+	}
+	if !m.Status.Valid {
+		monitor.Status = string(db.MonitorStatusPending)
+	}
+	if monitor.Tags == nil {
+		monitor.Tags = []string{}
+	}
+	return monitor
+}
+
+// checkAlertInputs checks that the alerts go to the organization's contacts,
+// each at most once
+func (h *Handler) checkAlertInputs(ctx context.Context, orgId uuid.UUID, alerts []MonitorAlertInput) error {
+	seen := make(map[int32]bool, len(alerts))
+	for _, alert := range alerts {
+		if seen[alert.ContactID] {
+			return apiv2.Errorf(http.StatusUnprocessableEntity, "alert contact %d is listed twice", alert.ContactID)
+		}
+		seen[alert.ContactID] = true
+
+		_, err := h.store.GetOrgAlertContact(ctx, db.GetOrgAlertContactParams{ID: alert.ContactID, OrgID: orgId})
+		if err != nil {
+			return apiv2.Errorf(http.StatusUnprocessableEntity, "alert contact %d not found", alert.ContactID)
+		}
+	}
+	return nil
+}
+
+// syncAlerts sets the monitor's alerts to those listed, removing the others
+func (h *Handler) syncAlerts(ctx context.Context, monitorID int32, alerts []MonitorAlertInput) error {
+	existing, err := h.store.GetAlertConfigsForMonitors(ctx, []int32{monitorID})
+	if err != nil {
+		return err
+	}
+
+	return h.store.ExecTx(ctx, func(q *db.Queries) error {
+		listed := make(map[int32]bool, len(alerts))
+		for _, alert := range alerts {
+			listed[alert.ContactID] = true
+			onDown := alert.OnDown == nil || *alert.OnDown
+			threshold := alert.SlowThresholdMs
+			if threshold == 0 {
+				threshold = defaultSlowThresholdMs
+			}
+			_, err := q.UpsertMonitorAlertConfig(ctx, db.UpsertMonitorAlertConfigParams{
+				MonitorID:       util.ToPgInt4(monitorID),
+				AlertContactID:  util.ToPgInt4(alert.ContactID),
+				AlertOnUp:       util.ToPgBool(alert.OnUp),
+				AlertOnDown:     util.ToPgBool(onDown),
+				AlertOnSlow:     util.ToPgBool(alert.OnSlow),
+				SlowThresholdMs: util.ToPgInt4(threshold),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, config := range existing {
+			if listed[config.AlertContactID.Int32] {
+				continue
+			}
+			err := q.DeleteMonitorAlertConfig(ctx, db.DeleteMonitorAlertConfigParams{
+				MonitorID:      config.MonitorID,
+				AlertContactID: config.AlertContactID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// v2MonitorError reports a URL that is already monitored as a conflict
+func v2MonitorError(err error) error {
+	if errors.Is(err, errMonitorURLTaken) {
+		return apiv2.Errorf(http.StatusConflict, "%s", err)
+	}
+	return err
+}
+
+func int4Ptr(v pgtype.Int4) *int32 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int32
+}
+
+func boolPtr(v pgtype.Bool) *bool {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Bool
+}
+
+func textPtr(v pgtype.Text) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
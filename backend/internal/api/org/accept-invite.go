@@ -0,0 +1,79 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AcceptInvite adds the signed-in user to the inviting organization. The
+// invite only works for the address it was sent to.
+func (h *Handler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req AcceptInviteRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var resp OrganizationResponse
+	var membership db.OrgMembership
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		invite, err := q.GetPendingOrgInviteByToken(ctx, req.Token)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return util.ErrInviteNotFound
+		} else if err != nil {
+			return err
+		}
+		if !strings.EqualFold(invite.Email, payload.Email) {
+			return util.ErrInviteEmailMismatch
+		}
+
+		membership, err = q.AddOrgMember(ctx, db.AddOrgMemberParams{
+			OrgID:  invite.OrgID,
+			UserID: payload.UserId,
+			Role:   invite.Role,
+		})
+		if err != nil {
+			return err
+		}
+		if err := q.MarkOrgInviteAccepted(ctx, invite.ID); err != nil {
+			return err
+		}
+
+		org, err := q.GetOrganization(ctx, invite.OrgID)
+		if err != nil {
+			return err
+		}
+		resp = OrganizationResponse{ID: org.ID, Name: org.Name, Role: membership.Role}
+		return nil
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	// Recorded in the organization joined, not the one active for the request
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceMember,
+		ResourceID:   membership.UserID,
+		After:        membership,
+		OrgID:        membership.OrgID,
+	})
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
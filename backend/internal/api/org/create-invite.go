@@ -0,0 +1,82 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/email"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const inviteTTL = 7 * 24 * time.Hour
+
+// CreateInvite emails an invitation to join the active organization. Inviting
+// an address again replaces the pending invite.
+func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req InviteRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if req.Role == db.OrgRoleOwner && db.OrgRole(payload.OrgRole) != db.OrgRoleOwner {
+		util.ErrorJson(w, util.ErrInsufficientRole)
+		return
+	}
+
+	org, err := h.store.GetOrganization(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	token, err := util.RandomToken(24)
+	if err != nil {
+		util.ErrorJson(w, util.ErrTokenGenError)
+		return
+	}
+
+	invite, err := h.store.CreateOrgInvite(ctx, db.CreateOrgInviteParams{
+		OrgID:     payload.OrgId,
+		Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:      req.Role,
+		Token:     token,
+		InvitedBy: pgtype.UUID{Bytes: payload.UserId, Valid: true},
+		ExpiresAt: pgtype.Timestamp{Time: time.Now().Add(inviteTTL), Valid: true},
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	acceptURL := strings.TrimRight(h.config.APP_BASE_URL, "/") + "/invites/accept?token=" + url.QueryEscape(invite.Token)
+	go func() {
+		if err := email.SendOrgInvite(invite.Email, org.Name, payload.Fullname, string(invite.Role), acceptURL); err != nil {
+			log.Printf("failed to send invite %d for organization %s: %v", invite.ID, org.ID, err)
+		}
+	}()
+
+	resp := inviteResponse(invite.ID, invite.Email, invite.Role, invite.ExpiresAt, invite.CreatedAt)
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceInvite,
+		ResourceID:   invite.ID,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusCreated, resp)
+}
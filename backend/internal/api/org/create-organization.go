@@ -0,0 +1,64 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateOrganization creates an organization owned by the user. Requests act
+// on it once they send its id in the X-Org-ID header.
+func (h *Handler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req OrganizationRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var resp OrganizationResponse
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		org, err := q.CreateOrganization(ctx, db.CreateOrganizationParams{
+			Name:      req.Name,
+			CreatedBy: pgtype.UUID{Bytes: payload.UserId, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := q.AddOrgMember(ctx, db.AddOrgMemberParams{
+			OrgID:  org.ID,
+			UserID: payload.UserId,
+			Role:   db.OrgRoleOwner,
+		}); err != nil {
+			return err
+		}
+
+		resp = OrganizationResponse{ID: org.ID, Name: org.Name, Role: db.OrgRoleOwner}
+		return nil
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceOrganization,
+		ResourceID:   resp.ID,
+		After:        resp,
+		OrgID:        resp.ID,
+	})
+
+	util.WriteJson(w, http.StatusCreated, resp)
+}
@@ -0,0 +1,47 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DeleteInvite revokes a pending invite
+func (h *Handler) DeleteInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	deleted, err := h.store.DeleteOrgInvite(ctx, db.DeleteOrgInviteParams{ID: int32(id), OrgID: payload.OrgId})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if deleted == 0 {
+		util.ErrorJson(w, util.ErrInviteNotFound)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceInvite,
+		ResourceID:   id,
+	})
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Invite revoked"})
+}
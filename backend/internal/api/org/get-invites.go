@@ -0,0 +1,31 @@
+package org
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// GetInvites lists the pending invites of the active organization
+func (h *Handler) GetInvites(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	invites, err := h.store.ListPendingOrgInvites(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	resp := make([]InviteResponse, 0, len(invites))
+	for _, i := range invites {
+		resp = append(resp, inviteResponse(i.ID, i.Email, i.Role, i.ExpiresAt, i.CreatedAt))
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
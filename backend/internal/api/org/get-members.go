@@ -0,0 +1,26 @@
+package org
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// GetMembers lists the members of the active organization and their roles
+func (h *Handler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	members, err := h.store.ListOrgMembers(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, members)
+}
@@ -0,0 +1,37 @@
+package org
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+)
+
+// GetOrganizations lists the organizations the user belongs to, marking the
+// one the request is acting on
+func (h *Handler) GetOrganizations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	orgs, err := h.store.ListUserOrganizations(ctx, payload.UserId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	resp := make([]OrganizationResponse, 0, len(orgs))
+	for _, o := range orgs {
+		resp = append(resp, OrganizationResponse{
+			ID:     o.ID,
+			Name:   o.Name,
+			Role:   o.Role,
+			Active: o.ID == payload.OrgId,
+		})
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
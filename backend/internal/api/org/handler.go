@@ -0,0 +1,53 @@
+package org
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config *config.Config
+	store  db.Store
+}
+
+func NewHandler(config *config.Config, store db.Store) *Handler {
+	return &Handler{
+		config: config,
+		store:  store,
+	}
+}
+
+// Routes manage organizations and their members. Member and invite routes act
+// on the active organization (see middleware.OrgHeader).
+func (h *Handler) Routes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenMiddleware(h.store))
+		r.Use(middleware.RequireSession)
+
+		r.Get("/", h.GetOrganizations)
+		r.Post("/", h.CreateOrganization)
+		r.Get("/members", h.GetMembers)
+		r.Post("/invites/accept", h.AcceptInvite)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequireRole(db.OrgRoleAdmin))
+
+			r.Put("/current", h.UpdateOrganization)
+			r.Put("/members/{userID}", h.UpdateMember)
+			r.Get("/invites", h.GetInvites)
+			r.Post("/invites", h.CreateInvite)
+			r.Delete("/invites/{id}", h.DeleteInvite)
+		})
+
+		// Members may leave on their own, removing others is checked in the handler
+		r.Delete("/members/{userID}", h.RemoveMember)
+	})
+
+	return router
+}
@@ -0,0 +1,60 @@
+package org
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// changeMembership applies change to the {userID} member of the active
+// organization. Only owners may change owners or make someone owner, and the
+// last owner can neither be demoted nor removed. newRole is "" for removals.
+// It returns the membership as it was before the change.
+func (h *Handler) changeMembership(ctx context.Context, r *http.Request, actorRole, newRole db.OrgRole, change func(q *db.Queries, orgId, userId uuid.UUID) error) (db.OrgMembership, error) {
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		return db.OrgMembership{}, util.ErrUnauthorized
+	}
+	userId, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		return db.OrgMembership{}, util.ErrInvalidUuid
+	}
+
+	var before db.OrgMembership
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		if err := q.LockOrganization(ctx, payload.OrgId); err != nil {
+			return err
+		}
+
+		member, err := q.GetOrgMembership(ctx, db.GetOrgMembershipParams{OrgID: payload.OrgId, UserID: userId})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return util.ErrMemberNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if (member.Role == db.OrgRoleOwner || newRole == db.OrgRoleOwner) && actorRole != db.OrgRoleOwner {
+			return util.ErrInsufficientRole
+		}
+		if member.Role == db.OrgRoleOwner && newRole != db.OrgRoleOwner {
+			owners, err := q.CountOrgOwners(ctx, payload.OrgId)
+			if err != nil {
+				return err
+			}
+			if owners <= 1 {
+				return util.ErrLastOwner
+			}
+		}
+
+		before = member
+		return change(q, payload.OrgId, userId)
+	})
+	return before, err
+}
@@ -0,0 +1,51 @@
+package org
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type OrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=120"`
+}
+
+type OrganizationResponse struct {
+	ID     uuid.UUID  `json:"id"`
+	Name   string     `json:"name"`
+	Role   db.OrgRole `json:"role"`
+	Active bool       `json:"active"`
+}
+
+type UpdateMemberRequest struct {
+	Role db.OrgRole `json:"role" validate:"required,oneof=owner admin editor viewer"`
+}
+
+type InviteRequest struct {
+	Email string     `json:"email" validate:"required,email"`
+	Role  db.OrgRole `json:"role" validate:"required,oneof=owner admin editor viewer"`
+}
+
+type AcceptInviteRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type InviteResponse struct {
+	ID        int32      `json:"id"`
+	Email     string     `json:"email"`
+	Role      db.OrgRole `json:"role"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func inviteResponse(id int32, email string, role db.OrgRole, expiresAt, createdAt pgtype.Timestamp) InviteResponse {
+	return InviteResponse{
+		ID:        id,
+		Email:     email,
+		Role:      role,
+		ExpiresAt: expiresAt.Time,
+		CreatedAt: createdAt.Time,
+	}
+}
@@ -0,0 +1,51 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// RemoveMember removes a member from the active organization. Admins may
+// remove others; any member may remove themselves to leave.
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	actorRole := db.OrgRole(payload.OrgRole)
+	if chi.URLParam(r, "userID") == payload.UserId.String() {
+		// Leaving only needs the permissions over one's own membership
+		actorRole = db.OrgRoleOwner
+	} else if !middleware.RoleAtLeast(actorRole, db.OrgRoleAdmin) {
+		util.ErrorJson(w, util.ErrInsufficientRole)
+		return
+	}
+
+	before, err := h.changeMembership(ctx, r, actorRole, "", func(q *db.Queries, orgId, userId uuid.UUID) error {
+		_, err := q.DeleteOrgMember(ctx, db.DeleteOrgMemberParams{OrgID: orgId, UserID: userId})
+		return err
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceMember,
+		ResourceID:   before.UserID,
+		Before:       before,
+	})
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Member removed"})
+}
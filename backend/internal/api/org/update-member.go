@@ -0,0 +1,52 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// UpdateMember changes a member's role in the active organization
+func (h *Handler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req UpdateMemberRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var updated db.OrgMembership
+	before, err := h.changeMembership(ctx, r, db.OrgRole(payload.OrgRole), req.Role, func(q *db.Queries, orgId, userId uuid.UUID) error {
+		updated, err = q.UpdateOrgMemberRole(ctx, db.UpdateOrgMemberRoleParams{
+			OrgID:  orgId,
+			UserID: userId,
+			Role:   req.Role,
+		})
+		return err
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceMember,
+		ResourceID:   updated.UserID,
+		Before:       before,
+		After:        updated,
+	})
+
+	util.WriteJson(w, http.StatusOK, updated)
+}
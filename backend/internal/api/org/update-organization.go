@@ -0,0 +1,56 @@
+package org
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+)
+
+// UpdateOrganization renames the active organization
+func (h *Handler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req OrganizationRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	before, err := h.store.GetOrganization(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	org, err := h.store.UpdateOrganization(ctx, db.UpdateOrganizationParams{
+		ID:   payload.OrgId,
+		Name: req.Name,
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceOrganization,
+		ResourceID:   org.ID,
+		Before:       before,
+		After:        org,
+	})
+
+	util.WriteJson(w, http.StatusOK, OrganizationResponse{
+		ID:     org.ID,
+		Name:   org.Name,
+		Role:   db.OrgRole(payload.OrgRole),
+		Active: true,
+	})
+}
@@ -1,20 +1,47 @@
 package api
 
 import (
+	"better-uptime/common/apiv2"
 	"better-uptime/common/routes"
+	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func (app *Server) routes() *chi.Mux {
-	router := routes.DefaultRouter()
+	// Published status pages can be served on their own custom domain
+	router := routes.DefaultRouter(app.statusPageHandler.CustomDomainMiddleware)
 
 	router.Route("/v1", func(r chi.Router) {
 		r.Mount("/auth", app.authHandler.Routes())
+		r.Mount("/orgs", app.orgHandler.Routes())
+		r.Mount("/api-keys", app.apiKeyHandler.Routes())
+		r.Mount("/audit-logs", app.auditLogHandler.Routes())
+		r.Mount("/billing", app.billingHandler.Routes())
+		r.Mount("/manifest", app.manifestHandler.Routes())
 		r.Mount("/monitor", app.monitorHandler.Routes())
 		r.Mount("/alert", app.alertHandler.Routes())
 		r.Mount("/analytics", app.analyticsHandler.Routes())
+		r.Mount("/status-pages", app.statusPageHandler.Routes())
+		r.Mount("/public/status-pages", app.statusPageHandler.PublicRoutes())
+		r.Mount("/badges", app.badgeHandler.Routes())
+		r.Mount("/public/badges", app.badgeHandler.PublicRoutes())
+		r.Mount("/public/artifacts", app.artifactHandler.PublicRoutes())
 	})
 
+	router.Mount("/v2", app.v2Routes())
+
+	// Server-rendered HTML status pages (no auth)
+	router.Get("/status/{slug}", app.statusPageHandler.RenderStatusPage)
+
 	return router
 }
+
+// v2Routes is the resource-oriented API, documented at /v2/openapi.json
+func (app *Server) v2Routes() http.Handler {
+	api := apiv2.New(app.store)
+	app.monitorHandler.V2Routes(api)
+	app.alertHandler.V2Routes(api)
+	app.statusPageHandler.V2Routes(api)
+	return api.Handler()
+}
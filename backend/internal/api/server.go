@@ -4,26 +4,42 @@ import (
 	"fmt"
 	"net/http"
 
-	"better-uptime/common/cloudinary"
+	"better-uptime/common/storage"
 	"better-uptime/config"
 	"better-uptime/internal/api/alert"
 	"better-uptime/internal/api/analytics"
+	"better-uptime/internal/api/apikey"
+	"better-uptime/internal/api/artifact"
+	"better-uptime/internal/api/auditlog"
 	"better-uptime/internal/api/auth"
+	"better-uptime/internal/api/badge"
+	"better-uptime/internal/api/billing"
+	"better-uptime/internal/api/manifest"
 	"better-uptime/internal/api/monitor"
+	"better-uptime/internal/api/org"
+	"better-uptime/internal/api/statuspage"
 	db "better-uptime/internal/db/sqlc"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type Server struct {
-	store            db.Store
-	cfg              *config.Config
-	router           *chi.Mux
-	authHandler      *auth.Handler
-	monitorHandler   *monitor.Handler
-	alertHandler     *alert.Handler
-	analyticsHandler *analytics.Handler
-	cloudinary       *cloudinary.ImageUploader
+	store             db.Store
+	cfg               *config.Config
+	router            *chi.Mux
+	authHandler       *auth.Handler
+	monitorHandler    *monitor.Handler
+	alertHandler      *alert.Handler
+	analyticsHandler  *analytics.Handler
+	statusPageHandler *statuspage.Handler
+	badgeHandler      *badge.Handler
+	artifactHandler   *artifact.Handler
+	orgHandler        *org.Handler
+	apiKeyHandler     *apikey.Handler
+	auditLogHandler   *auditlog.Handler
+	billingHandler    *billing.Handler
+	manifestHandler   *manifest.Handler
+	artifacts         storage.Storage
 }
 
 type ServerConfig struct {
@@ -38,20 +54,28 @@ type ServerConfig struct {
 }
 
 // NewServer creates a new API server instance
-func NewServer(store db.Store, cfg *config.Config, cloudinaryUploader *cloudinary.ImageUploader) *Server {
+func NewServer(store db.Store, cfg *config.Config, artifacts storage.Storage) *Server {
 
 	// Create the server instance first
 	server := &Server{
-		store:      store,
-		cfg:        cfg,
-		cloudinary: cloudinaryUploader,
+		store:     store,
+		cfg:       cfg,
+		artifacts: artifacts,
 	}
 
 	// Initialize the auth handler with only required dependencies
 	server.authHandler = auth.NewHandler(cfg, store)
-	server.monitorHandler = monitor.NewHandler(cfg, store)
+	server.monitorHandler = monitor.NewHandler(cfg, store, artifacts)
 	server.alertHandler = alert.NewHandler(cfg, store)
 	server.analyticsHandler = analytics.NewHandler(cfg, store)
+	server.statusPageHandler = statuspage.NewHandler(cfg, store)
+	server.badgeHandler = badge.NewHandler(cfg, store)
+	server.artifactHandler = artifact.NewHandler(cfg, store, artifacts)
+	server.orgHandler = org.NewHandler(cfg, store)
+	server.apiKeyHandler = apikey.NewHandler(cfg, store)
+	server.auditLogHandler = auditlog.NewHandler(cfg, store)
+	server.billingHandler = billing.NewHandler(cfg, store)
+	server.manifestHandler = manifest.NewHandler(cfg, store, artifacts)
 
 	// You can now mount auth routes here like:
 	// r.Post("/login", server.authHandler.Login)
@@ -0,0 +1,94 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AddNoticeUpdate moves a notice to its next status with a message,
+// e.g. investigating -> identified, and notifies subscribers
+func (h *Handler) AddNoticeUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	noticeID, err := strconv.ParseInt(chi.URLParam(r, "noticeID"), 10, 32)
+	if err != nil {
+		util.ErrorJson(w, util.ErrNotValidRequest)
+		return
+	}
+
+	notice, err := h.store.GetStatusPageNoticeByID(ctx, db.GetStatusPageNoticeByIDParams{
+		ID:           int32(noticeID),
+		StatusPageID: page.ID,
+	})
+	if err != nil {
+		util.ErrorJson(w, util.ErrNoticeNotFound)
+		return
+	}
+
+	var req NoticeUpdateRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	status := db.NoticeStatus(req.Status)
+	if !validNoticeStatus(notice.Kind, status) {
+		util.ErrorJson(w, util.ErrInvalidNoticeStatus)
+		return
+	}
+
+	before := notice
+	var update db.StatusPageNoticeUpdate
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		notice, err = q.UpdateStatusPageNoticeStatus(ctx, db.UpdateStatusPageNoticeStatusParams{
+			Status:   status,
+			Resolved: isFinalNoticeStatus(status),
+			ID:       notice.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		update, err = q.CreateStatusPageNoticeUpdate(ctx, db.CreateStatusPageNoticeUpdateParams{
+			NoticeID: notice.ID,
+			Status:   status,
+			Message:  req.Message,
+		})
+		return err
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceNotice,
+		ResourceID:   notice.ID,
+		Before:       before,
+		After:        NoticeResponse{Notice: notice, Updates: []db.StatusPageNoticeUpdate{update}},
+	})
+
+	go h.notifySubscribers(page, notice, update)
+
+	util.WriteJson(w, http.StatusCreated, update)
+}
@@ -0,0 +1,108 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateNotice posts a new incident or scheduled maintenance on a status page
+// and notifies the page's confirmed subscribers
+func (h *Handler) CreateNotice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var req NoticeRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	kind := db.NoticeKind(req.Kind)
+	status := db.NoticeStatus(req.Status)
+	if !validNoticeStatus(kind, status) {
+		util.ErrorJson(w, util.ErrInvalidNoticeStatus)
+		return
+	}
+
+	scheduledStart := pgtype.Timestamp{}
+	if req.ScheduledStart != nil {
+		scheduledStart = pgtype.Timestamp{Time: req.ScheduledStart.UTC(), Valid: true}
+	}
+	scheduledEnd := pgtype.Timestamp{}
+	if req.ScheduledEnd != nil {
+		scheduledEnd = pgtype.Timestamp{Time: req.ScheduledEnd.UTC(), Valid: true}
+	}
+	if scheduledStart.Valid && scheduledEnd.Valid && !scheduledEnd.Time.After(scheduledStart.Time) {
+		util.ErrorJson(w, util.ErrTimingError)
+		return
+	}
+
+	var response NoticeResponse
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		notice, err := q.CreateStatusPageNotice(ctx, db.CreateStatusPageNoticeParams{
+			StatusPageID:   page.ID,
+			Kind:           kind,
+			Title:          req.Title,
+			Status:         status,
+			ScheduledStart: scheduledStart,
+			ScheduledEnd:   scheduledEnd,
+		})
+		if err != nil {
+			return err
+		}
+
+		if isFinalNoticeStatus(status) {
+			notice, err = q.UpdateStatusPageNoticeStatus(ctx, db.UpdateStatusPageNoticeStatusParams{
+				Status:   status,
+				Resolved: true,
+				ID:       notice.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		update, err := q.CreateStatusPageNoticeUpdate(ctx, db.CreateStatusPageNoticeUpdateParams{
+			NoticeID: notice.ID,
+			Status:   status,
+			Message:  req.Message,
+		})
+		if err != nil {
+			return err
+		}
+
+		response = NoticeResponse{Notice: notice, Updates: []db.StatusPageNoticeUpdate{update}}
+		return nil
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceNotice,
+		ResourceID:   response.Notice.ID,
+		After:        response,
+	})
+
+	go h.notifySubscribers(page, response.Notice, response.Updates[0])
+
+	util.WriteJson(w, http.StatusCreated, response)
+}
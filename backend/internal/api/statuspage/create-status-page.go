@@ -0,0 +1,68 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateStatusPage creates an empty status page for the user
+func (h *Handler) CreateStatusPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+	userId := payload.UserId
+
+	var req StatusPageRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if !slugPattern.MatchString(req.Slug) {
+		util.ErrorJson(w, util.ErrInvalidSlug)
+		return
+	}
+
+	plan, err := plans.ForOrg(ctx, h.store, *h.config, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if err := plan.CheckNewStatusPage(ctx, h.store, payload.OrgId); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	page, err := h.store.CreateStatusPage(ctx, db.CreateStatusPageParams{
+		UserID:       pgtype.UUID{Bytes: userId, Valid: true},
+		OrgID:        payload.OrgId,
+		Slug:         req.Slug,
+		Title:        req.Title,
+		Description:  pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		CustomDomain: pgtype.Text{String: strings.ToLower(req.CustomDomain), Valid: req.CustomDomain != ""},
+		IsPublished:  util.ToPgBool(req.IsPublished),
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		After:        page,
+	})
+
+	util.WriteJson(w, http.StatusCreated, page)
+}
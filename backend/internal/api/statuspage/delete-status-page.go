@@ -0,0 +1,44 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"errors"
+	"net/http"
+)
+
+func (h *Handler) DeleteStatusPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	err = h.store.DeleteStatusPage(ctx, db.DeleteStatusPageParams{
+		ID:    page.ID,
+		OrgID: page.OrgID,
+	})
+	if err != nil {
+		util.ErrorJson(w, errors.New("could not delete status page"))
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		Before:       page,
+	})
+
+	util.WriteJson(w, http.StatusOK, map[string]string{"message": "Status page deleted successfully"})
+}
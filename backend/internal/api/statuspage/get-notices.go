@@ -0,0 +1,64 @@
+package statuspage
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+)
+
+// noticeHistoryLimit caps how many past notices the dashboard loads
+const noticeHistoryLimit = 50
+
+// GetNotices lists the most recent notices of a status page with their updates
+func (h *Handler) GetNotices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	notices, err := h.store.GetStatusPageNotices(ctx, db.GetStatusPageNoticesParams{
+		StatusPageID: page.ID,
+		Limit:        noticeHistoryLimit,
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	noticeIDs := make([]int32, 0, len(notices))
+	for _, n := range notices {
+		noticeIDs = append(noticeIDs, n.ID)
+	}
+
+	updates, err := h.store.GetStatusPageNoticeUpdates(ctx, noticeIDs)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	byNotice := make(map[int32][]db.StatusPageNoticeUpdate, len(notices))
+	for _, u := range updates {
+		byNotice[u.NoticeID] = append(byNotice[u.NoticeID], u)
+	}
+
+	response := make([]NoticeResponse, 0, len(notices))
+	for _, n := range notices {
+		noticeUpdates := byNotice[n.ID]
+		if noticeUpdates == nil {
+			noticeUpdates = []db.StatusPageNoticeUpdate{}
+		}
+		response = append(response, NoticeResponse{Notice: n, Updates: noticeUpdates})
+	}
+
+	util.WriteJson(w, http.StatusOK, response)
+}
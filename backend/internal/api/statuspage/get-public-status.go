@@ -0,0 +1,29 @@
+package statuspage
+
+import (
+	"better-uptime/common/util"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GetPublicStatus is the unauthenticated JSON API for a published status page
+func (h *Handler) GetPublicStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := h.store.GetPublishedStatusPageBySlug(ctx, chi.URLParam(r, "slug"))
+	if err != nil {
+		util.ErrorJson(w, util.ErrStatusPageNotFound)
+		return
+	}
+
+	status, err := h.buildPublicStatus(ctx, page)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, status, http.Header{
+		"Cache-Control": []string{"public, max-age=30"},
+	})
+}
@@ -0,0 +1,84 @@
+package statuspage
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// GetStatusPages lists the user's status pages
+func (h *Handler) GetStatusPages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	pages, err := h.store.GetStatusPagesByOrg(ctx, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, pages)
+}
+
+// GetStatusPage returns a status page together with its sections and monitors
+func (h *Handler) GetStatusPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	sections, err := h.store.GetStatusPageSections(ctx, page.ID)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	monitors, err := h.store.GetStatusPageMonitors(ctx, page.ID)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, StatusPageDetailResponse{
+		StatusPage: page,
+		Sections:   sections,
+		Monitors:   monitors,
+	})
+}
+
+// getOwnedStatusPage loads the status page from the {id} URL param, scoped to the organization
+func (h *Handler) getOwnedStatusPage(r *http.Request, orgId uuid.UUID) (db.StatusPage, error) {
+	pageID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		return db.StatusPage{}, util.ErrNotValidRequest
+	}
+
+	page, err := h.store.GetStatusPageByID(r.Context(), db.GetStatusPageByIDParams{
+		ID:    int32(pageID),
+		OrgID: orgId,
+	})
+	if err != nil {
+		return db.StatusPage{}, util.ErrStatusPageNotFound
+	}
+
+	return page, nil
+}
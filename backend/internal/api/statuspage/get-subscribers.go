@@ -0,0 +1,44 @@
+package statuspage
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	"net/http"
+	"time"
+)
+
+// GetSubscribers lists everyone subscribed to a status page, confirmed or not
+func (h *Handler) GetSubscribers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	subscribers, err := h.store.GetStatusPageSubscribers(ctx, page.ID)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	response := make([]SubscriberResponse, 0, len(subscribers))
+	for _, s := range subscribers {
+		response = append(response, SubscriberResponse{
+			ID:        s.ID,
+			Kind:      string(s.Kind),
+			Target:    s.Target,
+			Confirmed: s.ConfirmedAt.Valid,
+			CreatedAt: s.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	util.WriteJson(w, http.StatusOK, response)
+}
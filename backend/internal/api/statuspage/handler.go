@@ -0,0 +1,60 @@
+package statuspage
+
+import (
+	"better-uptime/common/middleware"
+	"better-uptime/common/routes"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	config          *config.Config
+	store           db.Store
+	subscribeLimits *subscribeLimiter
+}
+
+func NewHandler(config *config.Config, store db.Store) *Handler {
+	return &Handler{
+		config:          config,
+		store:           store,
+		subscribeLimits: newSubscribeLimiter(),
+	}
+}
+
+// Routes are the authenticated endpoints used to manage status pages
+func (h *Handler) Routes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.TokenMiddleware(h.store))
+		r.Use(middleware.RequireRoleForWrites(db.OrgRoleEditor))
+		r.Use(middleware.RequireScopeForWrites(middleware.ScopeMonitorsWrite))
+
+		r.Post("/", h.CreateStatusPage)
+		r.Get("/", h.GetStatusPages)
+		r.Get("/{id}", h.GetStatusPage)
+		r.Put("/{id}", h.UpdateStatusPage)
+		r.Delete("/{id}", h.DeleteStatusPage)
+		r.Put("/{id}/layout", h.UpdateStatusPageLayout)
+		r.Get("/{id}/notices", h.GetNotices)
+		r.Post("/{id}/notices", h.CreateNotice)
+		r.Post("/{id}/notices/{noticeID}/updates", h.AddNoticeUpdate)
+		r.Get("/{id}/subscribers", h.GetSubscribers)
+	})
+
+	return router
+}
+
+// PublicRoutes are unauthenticated and only ever expose published pages
+func (h *Handler) PublicRoutes() *chi.Mux {
+	router := routes.DefaultRouter()
+
+	router.Get("/{slug}", h.GetPublicStatus)
+	router.Post("/{slug}/subscribe", h.Subscribe)
+	router.Get("/subscriptions/confirm", h.ConfirmSubscription)
+	router.Get("/subscriptions/unsubscribe", h.Unsubscribe)
+
+	return router
+}
@@ -0,0 +1,125 @@
+package statuspage
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"regexp"
+	"time"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidSlug reports whether slug may address a status page
+func ValidSlug(slug string) bool {
+	return len(slug) >= 3 && len(slug) <= 64 && slugPattern.MatchString(slug)
+}
+
+type StatusPageRequest struct {
+	Slug         string `json:"slug" validate:"required,min=3,max=64"`
+	Title        string `json:"title" validate:"required,max=120"`
+	Description  string `json:"description" validate:"max=500"`
+	CustomDomain string `json:"custom_domain" validate:"omitempty,fqdn"`
+	IsPublished  bool   `json:"is_published"`
+}
+
+type LayoutRequest struct {
+	Sections []LayoutSection `json:"sections" validate:"dive"`
+}
+
+type LayoutSection struct {
+	Name     string          `json:"name" validate:"required,max=120"`
+	Monitors []LayoutMonitor `json:"monitors" validate:"dive"`
+}
+
+type LayoutMonitor struct {
+	MonitorID   int32  `json:"monitor_id" validate:"required"`
+	DisplayName string `json:"display_name,omitempty" validate:"max=120"`
+}
+
+type StatusPageDetailResponse struct {
+	StatusPage db.StatusPage                 `json:"status_page"`
+	Sections   []db.StatusPageSection        `json:"sections"`
+	Monitors   []db.GetStatusPageMonitorsRow `json:"monitors"`
+}
+
+type PublicStatusResponse struct {
+	Slug          string           `json:"slug"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description,omitempty"`
+	OverallStatus string           `json:"overall_status"`
+	Sections      []PublicSection  `json:"sections"`
+	Incidents     []PublicIncident `json:"incidents"`
+	Notices       []PublicNotice   `json:"notices"`
+	GeneratedAt   string           `json:"generated_at"`
+}
+
+type PublicSection struct {
+	Name     string          `json:"name"`
+	Monitors []PublicMonitor `json:"monitors"`
+}
+
+type PublicMonitor struct {
+	Name   string      `json:"name"`
+	Status string      `json:"status"`
+	Uptime *float64    `json:"uptime_90d"`
+	Days   []UptimeDay `json:"days"`
+}
+
+type UptimeDay struct {
+	Date   string   `json:"date"`
+	Uptime *float64 `json:"uptime"`
+	Status string   `json:"status"`
+}
+
+type PublicIncident struct {
+	Monitor   string `json:"monitor"`
+	Cause     string `json:"cause"`
+	StartedAt string `json:"started_at"`
+}
+
+type PublicNotice struct {
+	Kind           string               `json:"kind"`
+	Title          string               `json:"title"`
+	Status         string               `json:"status"`
+	ScheduledStart string               `json:"scheduled_start,omitempty"`
+	ScheduledEnd   string               `json:"scheduled_end,omitempty"`
+	Updates        []PublicNoticeUpdate `json:"updates"`
+}
+
+type PublicNoticeUpdate struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	CreatedAt string `json:"created_at"`
+}
+
+type NoticeRequest struct {
+	Kind           string     `json:"kind" validate:"required,oneof=incident maintenance"`
+	Title          string     `json:"title" validate:"required,max=200"`
+	Status         string     `json:"status" validate:"required"`
+	Message        string     `json:"message" validate:"required,max=5000"`
+	ScheduledStart *time.Time `json:"scheduled_start"`
+	ScheduledEnd   *time.Time `json:"scheduled_end"`
+}
+
+type NoticeUpdateRequest struct {
+	Status  string `json:"status" validate:"required"`
+	Message string `json:"message" validate:"required,max=5000"`
+}
+
+type NoticeResponse struct {
+	Notice  db.StatusPageNotice         `json:"notice"`
+	Updates []db.StatusPageNoticeUpdate `json:"updates"`
+}
+
+type SubscribeRequest struct {
+	Email      string `json:"email" validate:"required_without=WebhookURL,omitempty,email"`
+	WebhookURL string `json:"webhook_url" validate:"required_without=Email,omitempty,url"`
+}
+
+// SubscriberResponse hides the confirm and unsubscribe tokens from the page owner
+type SubscriberResponse struct {
+	ID        int32  `json:"id"`
+	Kind      string `json:"kind"`
+	Target    string `json:"target"`
+	Confirmed bool   `json:"confirmed"`
+	CreatedAt string `json:"created_at"`
+}
@@ -0,0 +1,85 @@
+package statuspage
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"time"
+)
+
+// noticeStatuses lists the statuses each notice kind may move through, in order
+var noticeStatuses = map[db.NoticeKind][]db.NoticeStatus{
+	db.NoticeKindIncident: {
+		db.NoticeStatusInvestigating,
+		db.NoticeStatusIdentified,
+		db.NoticeStatusMonitoring,
+		db.NoticeStatusResolved,
+	},
+	db.NoticeKindMaintenance: {
+		db.NoticeStatusScheduled,
+		db.NoticeStatusInProgress,
+		db.NoticeStatusCompleted,
+	},
+}
+
+func validNoticeStatus(kind db.NoticeKind, status db.NoticeStatus) bool {
+	for _, s := range noticeStatuses[kind] {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinalNoticeStatus reports whether the notice is over and leaves the public page
+func isFinalNoticeStatus(status db.NoticeStatus) bool {
+	return status == db.NoticeStatusResolved || status == db.NoticeStatusCompleted
+}
+
+// buildPublicNotices returns the unresolved notices of a page with their update timeline
+func (h *Handler) buildPublicNotices(ctx context.Context, pageID int32) ([]PublicNotice, error) {
+	notices, err := h.store.GetActiveStatusPageNotices(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	noticeIDs := make([]int32, 0, len(notices))
+	for _, n := range notices {
+		noticeIDs = append(noticeIDs, n.ID)
+	}
+
+	updates, err := h.store.GetStatusPageNoticeUpdates(ctx, noticeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byNotice := make(map[int32][]PublicNoticeUpdate, len(notices))
+	for _, u := range updates {
+		byNotice[u.NoticeID] = append(byNotice[u.NoticeID], PublicNoticeUpdate{
+			Status:    string(u.Status),
+			Message:   u.Message,
+			CreatedAt: u.CreatedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	result := make([]PublicNotice, 0, len(notices))
+	for _, n := range notices {
+		notice := PublicNotice{
+			Kind:    string(n.Kind),
+			Title:   n.Title,
+			Status:  string(n.Status),
+			Updates: byNotice[n.ID],
+		}
+		if notice.Updates == nil {
+			notice.Updates = []PublicNoticeUpdate{}
+		}
+		if n.ScheduledStart.Valid {
+			notice.ScheduledStart = n.ScheduledStart.Time.Format(time.RFC3339)
+		}
+		if n.ScheduledEnd.Valid {
+			notice.ScheduledEnd = n.ScheduledEnd.Time.Format(time.RFC3339)
+		}
+		result = append(result, notice)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package statuspage
+
+import (
+	"better-uptime/common/email"
+	"better-uptime/common/webhook"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// notifyTimeout bounds a whole fan-out to a page's subscribers
+const notifyTimeout = 5 * time.Minute
+
+type subscriptionEvent struct {
+	Event      string `json:"event"`
+	StatusPage string `json:"status_page"`
+	ConfirmURL string `json:"confirm_url"`
+}
+
+type noticeEvent struct {
+	Event          string `json:"event"`
+	StatusPage     string `json:"status_page"`
+	PageURL        string `json:"page_url"`
+	Kind           string `json:"kind"`
+	Title          string `json:"title"`
+	Status         string `json:"status"`
+	Message        string `json:"message"`
+	CreatedAt      string `json:"created_at"`
+	UnsubscribeURL string `json:"unsubscribe_url"`
+}
+
+// sendConfirmation delivers the double opt-in link to a new subscriber.
+// Webhooks receive it as a "subscription.confirm" event.
+func (h *Handler) sendConfirmation(page db.StatusPage, subscriber db.StatusPageSubscriber) {
+	confirmURL := h.publicURL("/v1/public/status-pages/subscriptions/confirm", subscriber.ConfirmToken)
+
+	var err error
+	switch subscriber.Kind {
+	case db.SubscriberKindEmail:
+		err = email.SendSubscriptionConfirmation(subscriber.Target, page.Title, confirmURL)
+	case db.SubscriberKindWebhook:
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+		err = webhook.Post(ctx, subscriber.Target, subscriptionEvent{
+			Event:      "subscription.confirm",
+			StatusPage: page.Title,
+			ConfirmURL: confirmURL,
+		})
+	}
+	if err != nil {
+		log.Printf("failed to send subscription confirmation for page %s: %v", page.Slug, err)
+	}
+}
+
+// notifySubscribers fans a notice update out to every confirmed subscriber.
+// It runs in the background so a slow inbox never blocks the dashboard.
+func (h *Handler) notifySubscribers(page db.StatusPage, notice db.StatusPageNotice, update db.StatusPageNoticeUpdate) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	subscribers, err := h.store.GetConfirmedStatusPageSubscribers(ctx, page.ID)
+	if err != nil {
+		log.Printf("failed to load subscribers for page %s: %v", page.Slug, err)
+		return
+	}
+
+	pageURL := strings.TrimRight(h.config.PUBLIC_BASE_URL, "/") + "/status/" + page.Slug
+	status := strings.ReplaceAll(string(update.Status), "_", " ")
+
+	for _, s := range subscribers {
+		unsubscribeURL := h.publicURL("/v1/public/status-pages/subscriptions/unsubscribe", s.UnsubscribeToken)
+
+		var err error
+		switch s.Kind {
+		case db.SubscriberKindEmail:
+			err = email.SendNoticeUpdate(s.Target, page.Title, notice.Title, status, update.Message, pageURL, unsubscribeURL)
+		case db.SubscriberKindWebhook:
+			err = webhook.Post(ctx, s.Target, noticeEvent{
+				Event:          "notice.updated",
+				StatusPage:     page.Title,
+				PageURL:        pageURL,
+				Kind:           string(notice.Kind),
+				Title:          notice.Title,
+				Status:         string(update.Status),
+				Message:        update.Message,
+				CreatedAt:      update.CreatedAt.Time.Format(time.RFC3339),
+				UnsubscribeURL: unsubscribeURL,
+			})
+		}
+		if err != nil {
+			log.Printf("failed to notify subscriber %d of page %s: %v", s.ID, page.Slug, err)
+		}
+	}
+}
+
+func (h *Handler) publicURL(path string, token string) string {
+	return strings.TrimRight(h.config.PUBLIC_BASE_URL, "/") + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,168 @@
+package statuspage
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RenderStatusPage serves the server-rendered HTML page at /status/{slug}
+func (h *Handler) RenderStatusPage(w http.ResponseWriter, r *http.Request) {
+	page, err := h.store.GetPublishedStatusPageBySlug(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		http.Error(w, "Status page not found", http.StatusNotFound)
+		return
+	}
+
+	h.renderHTML(w, r, page)
+}
+
+// CustomDomainMiddleware serves a published status page on "/" when the request
+// host matches the page's custom domain. Every other request passes through.
+func (h *Handler) CustomDomainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+
+		page, err := h.store.GetPublishedStatusPageByDomain(r.Context(), pgtype.Text{
+			String: strings.ToLower(host),
+			Valid:  true,
+		})
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.renderHTML(w, r, page)
+	})
+}
+
+func (h *Handler) renderHTML(w http.ResponseWriter, r *http.Request, page db.StatusPage) {
+	status, err := h.buildPublicStatus(r.Context(), page)
+	if err != nil {
+		log.Printf("failed to build status page %s: %v", page.Slug, err)
+		http.Error(w, "Status page unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	if err := statusPageTemplate.Execute(w, status); err != nil {
+		log.Printf("failed to render status page %s: %v", page.Slug, err)
+	}
+}
+
+var statusPageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"pct": func(v *float64) string {
+		if v == nil {
+			return "—"
+		}
+		return fmt.Sprintf("%.2f%%", *v)
+	},
+	"label": func(s string) string {
+		return strings.ReplaceAll(s, "_", " ")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>{{.Title}} Status</title>
+	<style>
+		body { font-family: Arial, sans-serif; background: #f9f9f9; color: #333; margin: 0; padding: 20px; }
+		.container { max-width: 860px; margin: auto; }
+		.banner { padding: 16px 20px; border-radius: 10px; color: white; font-size: 18px; font-weight: bold; margin: 20px 0; }
+		.banner.operational { background: #2e9d5b; }
+		.banner.partial_outage { background: #e39b1b; }
+		.banner.major_outage { background: #d63c3c; }
+		.card { background: white; border-radius: 10px; box-shadow: 0 2px 10px rgba(0,0,0,0.08); padding: 16px 20px; margin-bottom: 20px; }
+		.monitor { padding: 12px 0; border-top: 1px solid #eee; }
+		.monitor:first-of-type { border-top: none; }
+		.row { display: flex; justify-content: space-between; align-items: center; }
+		.status { text-transform: capitalize; font-weight: bold; }
+		.status.operational { color: #2e9d5b; }
+		.status.down { color: #d63c3c; }
+		.status.unknown, .status.paused { color: #888; }
+		.bars { display: flex; gap: 2px; margin-top: 8px; }
+		.bar { flex: 1; height: 28px; border-radius: 2px; background: #ddd; }
+		.bar.operational { background: #2e9d5b; }
+		.bar.degraded { background: #e39b1b; }
+		.bar.outage { background: #d63c3c; }
+		.muted { color: #777; font-size: 12px; }
+		.notice.incident { border-left: 4px solid #e39b1b; }
+		.notice.maintenance { border-left: 4px solid #007BFF; }
+		.update { padding: 8px 0; border-top: 1px solid #eee; }
+		.status-label { text-transform: capitalize; }
+		.footer { margin-top: 20px; font-size: 12px; color: #777; text-align: center; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<h1>{{.Title}}</h1>
+		{{if .Description}}<p>{{.Description}}</p>{{end}}
+		<div class="banner {{.OverallStatus}}">
+			{{if eq .OverallStatus "operational"}}All systems operational{{else}}{{label .OverallStatus}}{{end}}
+		</div>
+
+		{{if .Incidents}}
+		<div class="card">
+			<h3>Active incidents</h3>
+			{{range .Incidents}}
+			<p><strong>{{.Monitor}}</strong> is down since {{.StartedAt}}{{if .Cause}} — {{.Cause}}{{end}}</p>
+			{{end}}
+		</div>
+		{{end}}
+
+		{{range .Notices}}
+		<div class="card notice {{.Kind}}">
+			<h3>{{.Title}} <span class="muted">{{label .Status}}</span></h3>
+			{{if .ScheduledStart}}<p class="muted">Scheduled {{.ScheduledStart}}{{if .ScheduledEnd}} – {{.ScheduledEnd}}{{end}}</p>{{end}}
+			{{range .Updates}}
+			<div class="update">
+				<strong class="status-label">{{label .Status}}</strong> — {{.Message}}
+				<div class="muted">{{.CreatedAt}}</div>
+			</div>
+			{{end}}
+		</div>
+		{{end}}
+
+		{{range .Sections}}
+		<div class="card">
+			<h3>{{.Name}}</h3>
+			{{range .Monitors}}
+			<div class="monitor">
+				<div class="row">
+					<span>{{.Name}}</span>
+					<span class="status {{.Status}}">{{label .Status}}</span>
+				</div>
+				<div class="bars">
+					{{range .Days}}<div class="bar {{.Status}}" title="{{.Date}}: {{pct .Uptime}}"></div>{{end}}
+				</div>
+				<div class="row muted">
+					<span>90 days ago</span>
+					<span>{{pct .Uptime}} uptime</span>
+					<span>Today</span>
+				</div>
+			</div>
+			{{end}}
+		</div>
+		{{end}}
+
+		<div class="footer">Updated {{.GeneratedAt}} · Powered by <strong>Better Uptime Monitor</strong></div>
+	</div>
+</body>
+</html>`))
@@ -0,0 +1,46 @@
+package statuspage
+
+import (
+	"sync"
+	"time"
+)
+
+// Subscribe is anonymous and sends an email or webhook per request, so it is
+// limited per client IP and per page. Counts live in memory per API instance.
+const (
+	subscribeLimitWindow  = time.Hour
+	subscribeLimitPerIP   = 10
+	subscribeLimitPerPage = 100
+)
+
+// subscribeLimiter counts subscribe requests in fixed windows. All counts are
+// dropped when a window ends, which keeps memory bounded by one window's traffic.
+type subscribeLimiter struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	perIP       map[string]int
+	perPage     map[int32]int
+}
+
+func newSubscribeLimiter() *subscribeLimiter {
+	return &subscribeLimiter{perIP: make(map[string]int), perPage: make(map[int32]int)}
+}
+
+// allow records a request from ip for the page unless either is over its limit
+func (l *subscribeLimiter) allow(now time.Time, ip string, pageID int32) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.windowStart) >= subscribeLimitWindow {
+		l.windowStart = now
+		clear(l.perIP)
+		clear(l.perPage)
+	}
+
+	if l.perIP[ip] >= subscribeLimitPerIP || l.perPage[pageID] >= subscribeLimitPerPage {
+		return false
+	}
+	l.perIP[ip]++
+	l.perPage[pageID]++
+	return true
+}
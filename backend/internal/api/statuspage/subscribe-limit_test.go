@@ -0,0 +1,44 @@
+package statuspage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSubscribeLimiterPerIP(t *testing.T) {
+	limiter := newSubscribeLimiter()
+	now := time.Now()
+
+	for i := 0; i < subscribeLimitPerIP; i++ {
+		if !limiter.allow(now, "203.0.113.7", 1) {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+	if limiter.allow(now, "203.0.113.7", 2) {
+		t.Error("request over the per-IP limit was allowed")
+	}
+	if !limiter.allow(now, "203.0.113.8", 1) {
+		t.Error("another IP was limited")
+	}
+	if !limiter.allow(now.Add(subscribeLimitWindow), "203.0.113.7", 1) {
+		t.Error("limits were not reset after the window")
+	}
+}
+
+func TestSubscribeLimiterPerPage(t *testing.T) {
+	limiter := newSubscribeLimiter()
+	now := time.Now()
+
+	for i := 0; i < subscribeLimitPerPage; i++ {
+		if !limiter.allow(now, fmt.Sprintf("198.51.100.%d", i), 1) {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+	if limiter.allow(now, "192.0.2.1", 1) {
+		t.Error("request over the per-page limit was allowed")
+	}
+	if !limiter.allow(now, "192.0.2.1", 2) {
+		t.Error("another page was limited")
+	}
+}
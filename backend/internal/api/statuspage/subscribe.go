@@ -0,0 +1,138 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// confirmationTTL is how long the link in the confirmation message works
+const confirmationTTL = 48 * time.Hour
+
+// Subscribe registers an email address or webhook URL for updates of a published
+// page. Nothing is delivered until the confirmation link has been opened.
+func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := h.store.GetPublishedStatusPageBySlug(ctx, chi.URLParam(r, "slug"))
+	if err != nil {
+		util.ErrorJson(w, util.ErrStatusPageNotFound)
+		return
+	}
+
+	var req SubscribeRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	if !h.subscribeLimits.allow(time.Now(), audit.ClientIP(r), page.ID) {
+		util.ErrorJson(w, util.ErrTooManyRequests)
+		return
+	}
+
+	kind, target := db.SubscriberKindEmail, strings.ToLower(strings.TrimSpace(req.Email))
+	if req.WebhookURL != "" {
+		kind, target = db.SubscriberKindWebhook, strings.TrimSpace(req.WebhookURL)
+	}
+
+	confirmToken, err := util.RandomToken(24)
+	if err != nil {
+		util.ErrorJson(w, util.ErrTokenGenError)
+		return
+	}
+	unsubscribeToken, err := util.RandomToken(24)
+	if err != nil {
+		util.ErrorJson(w, util.ErrTokenGenError)
+		return
+	}
+
+	subscriber, err := h.store.CreateStatusPageSubscriber(ctx, db.CreateStatusPageSubscriberParams{
+		StatusPageID:     page.ID,
+		Kind:             kind,
+		Target:           target,
+		ConfirmToken:     confirmToken,
+		ConfirmExpiresAt: pgtype.Timestamp{Time: time.Now().Add(confirmationTTL), Valid: true},
+		UnsubscribeToken: unsubscribeToken,
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	if subscriber.ConfirmedAt.Valid {
+		util.WriteJson(w, http.StatusOK, map[string]string{"message": "Already subscribed"})
+		return
+	}
+
+	go h.sendConfirmation(page, subscriber)
+
+	util.WriteJson(w, http.StatusAccepted, map[string]string{
+		"message": "Check your inbox (or webhook endpoint) to confirm the subscription",
+	})
+}
+
+// ConfirmSubscription is the double opt-in link sent to new subscribers
+func (h *Handler) ConfirmSubscription(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		renderSubscriptionMessage(w, http.StatusBadRequest, "This confirmation link is invalid.")
+		return
+	}
+
+	if _, err := h.store.ConfirmStatusPageSubscriber(r.Context(), token); err != nil {
+		renderSubscriptionMessage(w, http.StatusNotFound, "This confirmation link is invalid or has expired.")
+		return
+	}
+
+	renderSubscriptionMessage(w, http.StatusOK, "Your subscription is confirmed. You will be notified about incidents and maintenance.")
+}
+
+// Unsubscribe removes the subscriber owning the token from the page
+func (h *Handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		renderSubscriptionMessage(w, http.StatusBadRequest, "This unsubscribe link is invalid.")
+		return
+	}
+
+	if _, err := h.store.DeleteStatusPageSubscriberByToken(r.Context(), token); err != nil {
+		renderSubscriptionMessage(w, http.StatusNotFound, "This subscription no longer exists.")
+		return
+	}
+
+	renderSubscriptionMessage(w, http.StatusOK, "You have been unsubscribed and will not receive further updates.")
+}
+
+// Confirmation and unsubscribe links are opened in a browser, so answer with HTML
+func renderSubscriptionMessage(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := subscriptionMessageTemplate.Execute(w, message); err != nil {
+		log.Printf("failed to render subscription message: %v", err)
+	}
+}
+
+var subscriptionMessageTemplate = template.Must(template.New("subscription").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Status updates</title>
+	<style>
+		body { font-family: Arial, sans-serif; background: #f9f9f9; color: #333; padding: 20px; }
+		.card { background: white; border-radius: 10px; box-shadow: 0 2px 10px rgba(0,0,0,0.08); padding: 20px; max-width: 600px; margin: 40px auto; text-align: center; }
+	</style>
+</head>
+<body>
+	<div class="card"><p>{{.}}</p></div>
+</body>
+</html>`))
@@ -0,0 +1,160 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+)
+
+// UpdateStatusPageLayout replaces the sections and monitors shown on a status page
+func (h *Handler) UpdateStatusPageLayout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var req LayoutRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	displayNames, err := h.layoutDisplayNames(ctx, payload.OrgId, req.Sections)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	before, err := h.statusPageLayout(ctx, page)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		return replaceLayout(ctx, q, page.ID, req.Sections, displayNames)
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	after, err := h.statusPageLayout(ctx, page)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		Before:       before,
+		After:        after,
+	})
+
+	util.WriteJson(w, http.StatusOK, after)
+}
+
+// layoutDisplayNames checks that the monitors of a layout belong to the
+// organization, each shown once, and names them. Only monitors owned by the
+// organization may be shown on its pages.
+func (h *Handler) layoutDisplayNames(ctx context.Context, orgId uuid.UUID, sections []LayoutSection) (map[int32]string, error) {
+	displayNames := make(map[int32]string)
+	for _, section := range sections {
+		for _, m := range section.Monitors {
+			if _, dup := displayNames[m.MonitorID]; dup {
+				return nil, fmt.Errorf("monitor %d appears more than once", m.MonitorID)
+			}
+
+			monitor, err := h.store.GetMonitorByID(ctx, db.GetMonitorByIDParams{
+				ID:    m.MonitorID,
+				OrgID: orgId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("monitor %d not found", m.MonitorID)
+			}
+
+			name := m.DisplayName
+			if name == "" {
+				name = DefaultDisplayName(monitor.Url)
+			}
+			displayNames[m.MonitorID] = name
+		}
+	}
+	return displayNames, nil
+}
+
+// replaceLayout replaces the sections and monitors of a status page
+func replaceLayout(ctx context.Context, q *db.Queries, pageID int32, sections []LayoutSection, displayNames map[int32]string) error {
+	if err := q.DeleteStatusPageSections(ctx, pageID); err != nil {
+		return err
+	}
+
+	for i, section := range sections {
+		created, err := q.CreateStatusPageSection(ctx, db.CreateStatusPageSectionParams{
+			StatusPageID: pageID,
+			Name:         section.Name,
+			Position:     int32(i),
+		})
+		if err != nil {
+			return err
+		}
+
+		for j, m := range section.Monitors {
+			_, err := q.AddStatusPageMonitor(ctx, db.AddStatusPageMonitorParams{
+				StatusPageID: pageID,
+				SectionID:    created.ID,
+				MonitorID:    m.MonitorID,
+				DisplayName:  displayNames[m.MonitorID],
+				Position:     int32(j),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *Handler) statusPageLayout(ctx context.Context, page db.StatusPage) (StatusPageDetailResponse, error) {
+	sections, err := h.store.GetStatusPageSections(ctx, page.ID)
+	if err != nil {
+		return StatusPageDetailResponse{}, err
+	}
+	monitors, err := h.store.GetStatusPageMonitors(ctx, page.ID)
+	if err != nil {
+		return StatusPageDetailResponse{}, err
+	}
+	return StatusPageDetailResponse{
+		StatusPage: page,
+		Sections:   sections,
+		Monitors:   monitors,
+	}, nil
+}
+
+// DefaultDisplayName uses the host so that paths and query strings
+// (which may contain tokens) never end up on a public page
+func DefaultDisplayName(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "Service"
+	}
+	return parsed.Hostname()
+}
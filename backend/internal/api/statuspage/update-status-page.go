@@ -0,0 +1,63 @@
+package statuspage
+
+import (
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/util"
+	db "better-uptime/internal/db/sqlc"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UpdateStatusPage updates the page settings, including publishing it
+func (h *Handler) UpdateStatusPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		util.ErrorJson(w, util.ErrUnauthorized)
+		return
+	}
+
+	existing, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	var req StatusPageRequest
+	if err := util.ReadJsonAndValidate(w, r, &req); err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+	if !slugPattern.MatchString(req.Slug) {
+		util.ErrorJson(w, util.ErrInvalidSlug)
+		return
+	}
+
+	page, err := h.store.UpdateStatusPage(ctx, db.UpdateStatusPageParams{
+		ID:           existing.ID,
+		OrgID:        existing.OrgID,
+		Slug:         req.Slug,
+		Title:        req.Title,
+		Description:  pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		CustomDomain: pgtype.Text{String: strings.ToLower(req.CustomDomain), Valid: req.CustomDomain != ""},
+		IsPublished:  util.ToPgBool(req.IsPublished),
+	})
+	if err != nil {
+		util.ErrorJson(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		Before:       existing,
+		After:        page,
+	})
+
+	util.WriteJson(w, http.StatusOK, page)
+}
@@ -0,0 +1,190 @@
+package statuspage
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// uptimeDays is the number of daily bars shown for every monitor
+const uptimeDays = 90
+
+// buildPublicStatus assembles everything a visitor sees on a published page.
+// Monitor URLs are deliberately left out, only display names are public.
+func (h *Handler) buildPublicStatus(ctx context.Context, page db.StatusPage) (*PublicStatusResponse, error) {
+	sections, err := h.store.GetStatusPageSections(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	monitors, err := h.store.GetStatusPageMonitors(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	monitorIDs := make([]int32, 0, len(monitors))
+	names := make(map[int32]string, len(monitors))
+	for _, m := range monitors {
+		monitorIDs = append(monitorIDs, m.MonitorID)
+		names[m.MonitorID] = m.DisplayName
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(uptimeDays - 1))
+
+	daily, err := h.store.GetDailyUptimeForMonitors(ctx, db.GetDailyUptimeForMonitorsParams{
+		MonitorIds: monitorIDs,
+		Since:      pgtype.Timestamp{Time: since, Valid: true},
+		Today:      pgtype.Timestamp{Time: today, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// monitor id -> day -> row
+	byDay := make(map[int32]map[string]db.GetDailyUptimeForMonitorsRow)
+	for _, row := range daily {
+		if byDay[row.MonitorID] == nil {
+			byDay[row.MonitorID] = make(map[string]db.GetDailyUptimeForMonitorsRow)
+		}
+		byDay[row.MonitorID][row.Day.Time.Format("2006-01-02")] = row
+	}
+
+	publicSections := make([]PublicSection, 0, len(sections))
+	sectionIndex := make(map[int32]int, len(sections))
+	for i, s := range sections {
+		sectionIndex[s.ID] = i
+		publicSections = append(publicSections, PublicSection{Name: s.Name, Monitors: []PublicMonitor{}})
+	}
+
+	activeCount, downCount := 0, 0
+	for _, m := range monitors {
+		status := monitorPublicStatus(m)
+		if status != "paused" {
+			activeCount++
+		}
+		if status == "down" {
+			downCount++
+		}
+
+		var total, successful int64
+		days := make([]UptimeDay, 0, uptimeDays)
+		for d := since; !d.After(today); d = d.AddDate(0, 0, 1) {
+			key := d.Format("2006-01-02")
+			day := UptimeDay{Date: key, Status: "no_data"}
+			if row, ok := byDay[m.MonitorID][key]; ok && row.TotalChecks > 0 {
+				pct := float64(row.SuccessfulChecks) / float64(row.TotalChecks) * 100
+				day.Uptime = &pct
+				day.Status = dayStatus(pct)
+				total += row.TotalChecks
+				successful += row.SuccessfulChecks
+			}
+			days = append(days, day)
+		}
+
+		var uptime *float64
+		if total > 0 {
+			pct := float64(successful) / float64(total) * 100
+			uptime = &pct
+		}
+
+		idx, ok := sectionIndex[m.SectionID]
+		if !ok {
+			continue
+		}
+		publicSections[idx].Monitors = append(publicSections[idx].Monitors, PublicMonitor{
+			Name:   m.DisplayName,
+			Status: status,
+			Uptime: uptime,
+			Days:   days,
+		})
+	}
+
+	openIncidents, err := h.store.GetOpenIncidentsForMonitors(ctx, monitorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	incidents := make([]PublicIncident, 0, len(openIncidents))
+	for _, inc := range openIncidents {
+		incidents = append(incidents, PublicIncident{
+			Monitor:   names[inc.MonitorID],
+			Cause:     publicCauses[inc.ErrorType.String],
+			StartedAt: inc.StartedAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	notices, err := h.buildPublicNotices(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicStatusResponse{
+		Slug:          page.Slug,
+		Title:         page.Title,
+		Description:   page.Description.String,
+		OverallStatus: overallStatus(activeCount, downCount),
+		Sections:      publicSections,
+		Incidents:     incidents,
+		Notices:       notices,
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func monitorPublicStatus(m db.GetStatusPageMonitorsRow) string {
+	if m.IsActive.Valid && !m.IsActive.Bool {
+		return "paused"
+	}
+	if !m.Status.Valid {
+		return "unknown"
+	}
+
+	switch m.Status.MonitorStatus {
+	case db.MonitorStatusUp:
+		return "operational"
+	case db.MonitorStatusDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+func overallStatus(active, down int) string {
+	switch {
+	case down == 0:
+		return "operational"
+	case down == active:
+		return "major_outage"
+	default:
+		return "partial_outage"
+	}
+}
+
+func dayStatus(uptime float64) string {
+	switch {
+	case uptime >= 99.5:
+		return "operational"
+	case uptime >= 95:
+		return "degraded"
+	default:
+		return "outage"
+	}
+}
+
+// publicCauses labels an incident's error type for anonymous visitors. The
+// recorded cause stays private: it can name URLs, hosts and IPs of the target.
+var publicCauses = map[string]string{
+	"DNS_FAILED":           "DNS resolution failed",
+	"CONNECTION_REFUSED":   "Connection refused",
+	"SSL_ERROR":            "TLS error",
+	"TIMEOUT":              "Timed out",
+	"HTTP_ERROR":           "Unexpected HTTP response",
+	"ASSERTION_FAILED":     "Assertion failed",
+	"BROWSER_CHECK_FAILED": "Browser check failed",
+	"NOT_SERVING":          "Service not serving",
+	"HEALTH_UNKNOWN":       "Service not serving",
+	"AUTH_FAILED":          "Authentication failed",
+	"QUERY_FAILED":         "Query failed",
+}
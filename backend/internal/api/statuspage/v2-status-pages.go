@@ -0,0 +1,400 @@
+package statuspage
+
+import (
+	"better-uptime/common/apiv2"
+	"better-uptime/common/audit"
+	"better-uptime/common/middleware"
+	"better-uptime/common/plans"
+	"better-uptime/common/util"
+	"better-uptime/common/validation"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// StatusPage is a status page in the v2 API, with its layout
+type StatusPage struct {
+	ID           int32               `json:"id"`
+	ExternalID   *string             `json:"external_id" doc:"Key of the page in a manifest, null when it is not managed by one"`
+	Slug         string              `json:"slug"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	CustomDomain *string             `json:"custom_domain"`
+	Published    bool                `json:"published"`
+	Sections     []StatusPageSection `json:"sections"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type StatusPageSection struct {
+	Name     string              `json:"name"`
+	Monitors []StatusPageMonitor `json:"monitors"`
+}
+
+type StatusPageMonitor struct {
+	MonitorID   int32  `json:"monitor_id"`
+	DisplayName string `json:"display_name"`
+}
+
+type StatusPageInput struct {
+	Slug         string `json:"slug" validate:"required,min=3,max=64" doc:"Lowercase letters and digits, separated by single dashes"`
+	Title        string `json:"title" validate:"required,max=120"`
+	Description  string `json:"description,omitempty" validate:"max=500"`
+	CustomDomain string `json:"custom_domain,omitempty" validate:"omitempty,fqdn"`
+	Published    bool   `json:"published,omitempty"`
+	// Sections default to none
+	Sections []LayoutSection `json:"sections,omitempty" validate:"dive" doc:"Monitors shown on the page, by section; display names default to the monitor's host"`
+}
+
+// StatusPagePatch updates the fields it sets and keeps the others
+type StatusPagePatch struct {
+	Slug         *string `json:"slug,omitempty"`
+	Title        *string `json:"title,omitempty"`
+	Description  *string `json:"description,omitempty"`
+	CustomDomain *string `json:"custom_domain,omitempty" doc:"An empty string removes the custom domain"`
+	Published    *bool   `json:"published,omitempty"`
+	// Sections replace the layout when set
+	Sections []LayoutSection `json:"sections,omitempty" validate:"dive" doc:"Replaces the layout, an empty list clears it"`
+}
+
+// V2Routes adds the status page resource to the v2 API
+func (h *Handler) V2Routes(api *apiv2.API) {
+	api = api.With(
+		middleware.RequireRoleForWrites(db.OrgRoleEditor),
+		middleware.RequireScopeForWrites(middleware.ScopeMonitorsWrite),
+	)
+
+	api.Handle(apiv2.Operation{
+		ID: "listStatusPages", Method: http.MethodGet, Path: "/status-pages", Tag: "Status pages",
+		Summary: "List status pages", Paginated: true, Response: apiv2.Page[StatusPage]{},
+	}, h.ListStatusPagesV2)
+	api.Handle(apiv2.Operation{
+		ID: "createStatusPage", Method: http.MethodPost, Path: "/status-pages", Tag: "Status pages",
+		Summary: "Create a status page", Body: StatusPageInput{},
+		Status: http.StatusCreated, Response: StatusPage{},
+	}, h.CreateStatusPageV2)
+	api.Handle(apiv2.Operation{
+		ID: "getStatusPage", Method: http.MethodGet, Path: "/status-pages/{id}", Tag: "Status pages",
+		Summary: "Get a status page", Response: StatusPage{},
+	}, h.GetStatusPageV2)
+	api.Handle(apiv2.Operation{
+		ID: "updateStatusPage", Method: http.MethodPatch, Path: "/status-pages/{id}", Tag: "Status pages",
+		Summary: "Update a status page", Body: StatusPagePatch{}, Response: StatusPage{},
+	}, h.UpdateStatusPageV2)
+	api.Handle(apiv2.Operation{
+		ID: "deleteStatusPage", Method: http.MethodDelete, Path: "/status-pages/{id}", Tag: "Status pages",
+		Summary: "Delete a status page", Status: http.StatusNoContent,
+	}, h.DeleteStatusPageV2)
+}
+
+func (h *Handler) ListStatusPagesV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var after apiv2.IDCursor
+	limit, err := apiv2.ReadPage(r, &after)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	pages, err := h.store.ListOrgStatusPagesPage(ctx, db.ListOrgStatusPagesPageParams{
+		OrgID:    payload.OrgId,
+		AfterID:  after.ID,
+		PageSize: limit + 1,
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	// Plans keep the number of pages small, so layouts are loaded per page
+	converted := make([]StatusPage, len(pages))
+	for i, page := range pages {
+		if converted[i], err = h.v2StatusPage(ctx, page); err != nil {
+			apiv2.WriteError(w, err)
+			return
+		}
+	}
+
+	util.WriteJson(w, http.StatusOK, apiv2.NewPage(converted, limit,
+		func(page StatusPage) StatusPage { return page },
+		func(page StatusPage) any { return apiv2.IDCursor{ID: page.ID} }))
+}
+
+func (h *Handler) GetStatusPageV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	resp, err := h.v2StatusPage(ctx, page)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func (h *Handler) CreateStatusPageV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req StatusPageInput
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if !ValidSlug(req.Slug) {
+		apiv2.WriteError(w, util.ErrInvalidSlug)
+		return
+	}
+	displayNames, err := h.layoutDisplayNames(ctx, payload.OrgId, req.Sections)
+	if err != nil {
+		apiv2.WriteError(w, apiv2.Errorf(http.StatusUnprocessableEntity, "%s", err))
+		return
+	}
+
+	plan, err := plans.ForOrg(ctx, h.store, *h.config, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if err := plan.CheckNewStatusPage(ctx, h.store, payload.OrgId); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	var page db.StatusPage
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		page, err = q.CreateStatusPage(ctx, db.CreateStatusPageParams{
+			UserID:       pgtype.UUID{Bytes: payload.UserId, Valid: true},
+			OrgID:        payload.OrgId,
+			Slug:         req.Slug,
+			Title:        req.Title,
+			Description:  pgtype.Text{String: req.Description, Valid: req.Description != ""},
+			CustomDomain: pgtype.Text{String: strings.ToLower(req.CustomDomain), Valid: req.CustomDomain != ""},
+			IsPublished:  util.ToPgBool(req.Published),
+		})
+		if err != nil {
+			return err
+		}
+		return replaceLayout(ctx, q, page.ID, req.Sections, displayNames)
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	resp, err := h.v2StatusPage(ctx, page)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionCreate,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusCreated, resp)
+}
+
+func (h *Handler) UpdateStatusPageV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	var req StatusPagePatch
+	if err := apiv2.ReadJSON(w, r, &req); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	existing, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	before, err := h.v2StatusPage(ctx, existing)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	// The patch is merged onto the page and the result validated as a whole
+	merged := StatusPageRequest{
+		Slug:         existing.Slug,
+		Title:        existing.Title,
+		Description:  existing.Description.String,
+		CustomDomain: existing.CustomDomain.String,
+		IsPublished:  existing.IsPublished.Bool,
+	}
+	if req.Slug != nil {
+		merged.Slug = *req.Slug
+	}
+	if req.Title != nil {
+		merged.Title = *req.Title
+	}
+	if req.Description != nil {
+		merged.Description = *req.Description
+	}
+	if req.CustomDomain != nil {
+		merged.CustomDomain = *req.CustomDomain
+	}
+	if req.Published != nil {
+		merged.IsPublished = *req.Published
+	}
+	if err := validation.ValidateRequest(&merged); err != nil {
+		apiv2.WriteError(w, &apiv2.Error{Status: http.StatusUnprocessableEntity, Code: apiv2.CodeValidationFailed, Message: err.Error()})
+		return
+	}
+	if !ValidSlug(merged.Slug) {
+		apiv2.WriteError(w, util.ErrInvalidSlug)
+		return
+	}
+
+	var displayNames map[int32]string
+	if req.Sections != nil {
+		if displayNames, err = h.layoutDisplayNames(ctx, payload.OrgId, req.Sections); err != nil {
+			apiv2.WriteError(w, apiv2.Errorf(http.StatusUnprocessableEntity, "%s", err))
+			return
+		}
+	}
+
+	var page db.StatusPage
+	err = h.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		page, err = q.UpdateStatusPage(ctx, db.UpdateStatusPageParams{
+			ID:           existing.ID,
+			OrgID:        existing.OrgID,
+			Slug:         merged.Slug,
+			Title:        merged.Title,
+			Description:  pgtype.Text{String: merged.Description, Valid: merged.Description != ""},
+			CustomDomain: pgtype.Text{String: strings.ToLower(merged.CustomDomain), Valid: merged.CustomDomain != ""},
+			IsPublished:  util.ToPgBool(merged.IsPublished),
+		})
+		if err != nil || req.Sections == nil {
+			return err
+		}
+		return replaceLayout(ctx, q, page.ID, req.Sections, displayNames)
+	})
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	resp, err := h.v2StatusPage(ctx, page)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionUpdate,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		Before:       before,
+		After:        resp,
+	})
+
+	util.WriteJson(w, http.StatusOK, resp)
+}
+
+func (h *Handler) DeleteStatusPageV2(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	payload, err := middleware.GetFirebasePayloadFromContext(ctx)
+	if err != nil {
+		apiv2.WriteError(w, util.ErrUnauthorized)
+		return
+	}
+
+	page, err := h.getOwnedStatusPage(r, payload.OrgId)
+	if err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+	if err := h.store.DeleteStatusPage(ctx, db.DeleteStatusPageParams{ID: page.ID, OrgID: page.OrgID}); err != nil {
+		apiv2.WriteError(w, err)
+		return
+	}
+
+	audit.Record(r, h.store, audit.Entry{
+		Action:       audit.ActionDelete,
+		ResourceType: audit.ResourceStatusPage,
+		ResourceID:   page.ID,
+		Before:       page,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// v2StatusPage converts a status page and loads its layout
+func (h *Handler) v2StatusPage(ctx context.Context, page db.StatusPage) (StatusPage, error) {
+	sections, err := h.store.GetStatusPageSections(ctx, page.ID)
+	if err != nil {
+		return StatusPage{}, err
+	}
+	monitors, err := h.store.GetStatusPageMonitors(ctx, page.ID)
+	if err != nil {
+		return StatusPage{}, err
+	}
+
+	resp := StatusPage{
+		ID:          page.ID,
+		Slug:        page.Slug,
+		Title:       page.Title,
+		Description: page.Description.String,
+		Published:   page.IsPublished.Bool,
+		Sections:    make([]StatusPageSection, len(sections)),
+		CreatedAt:   page.CreatedAt.Time,
+		UpdatedAt:   page.UpdatedAt.Time,
+	}
+	if page.ExternalID.Valid {
+		resp.ExternalID = &page.ExternalID.String
+	}
+	if page.CustomDomain.Valid {
+		resp.CustomDomain = &page.CustomDomain.String
+	}
+
+	index := make(map[int32]int, len(sections))
+	for i, section := range sections {
+		index[section.ID] = i
+		resp.Sections[i] = StatusPageSection{Name: section.Name, Monitors: []StatusPageMonitor{}}
+	}
+	for _, m := range monitors {
+		i, ok := index[m.SectionID]
+		if !ok {
+			continue
+		}
+		resp.Sections[i].Monitors = append(resp.Sections[i].Monitors, StatusPageMonitor{
+			MonitorID:   m.MonitorID,
+			DisplayName: m.DisplayName,
+		})
+	}
+	return resp, nil
+}
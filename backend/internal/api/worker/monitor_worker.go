@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"better-uptime/common/storage"
 	"better-uptime/config"
 	"better-uptime/internal/api/alert"
 	"better-uptime/internal/api/monitor"
@@ -17,9 +18,9 @@ type MonitorWorker struct {
 
 type TestURLResponse = alert.TestURLResponse
 
-func NewMonitorWorker(store db.Store, config *config.Config) *MonitorWorker {
+func NewMonitorWorker(store db.Store, config *config.Config, artifacts storage.Storage) *MonitorWorker {
 	return &MonitorWorker{
-		monitorHandler: monitor.NewHandler(config, store),
+		monitorHandler: monitor.NewHandler(config, store, artifacts),
 		alertHandler:   alert.NewHandler(config, store),
 	}
 }
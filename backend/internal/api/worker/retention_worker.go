@@ -0,0 +1,183 @@
+package worker
+
+import (
+	"better-uptime/common/plans"
+	"better-uptime/common/storage"
+	"better-uptime/config"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	retentionEvery = 24 * time.Hour
+	// Raw logs younger than this are never deleted so the daily rollups have
+	// always been computed (see rollupSchedules) before the rows go away
+	minRetention         = 3 * 24 * time.Hour
+	partitionMonthsAhead = 3
+	retentionBatchSize   = 5000
+	retentionBatchPause  = 200 * time.Millisecond
+)
+
+// RetentionWorker keeps monitor_logs bounded: it creates upcoming monthly
+// partitions, deletes raw logs past each plan's horizon in small batches,
+// drops partitions no plan needs anymore and prunes 1-minute rollups.
+// Screenshots of deleted logs are deleted from artifact storage as well.
+type RetentionWorker struct {
+	store     db.Store
+	config    *config.Config
+	artifacts storage.Storage
+}
+
+func NewRetentionWorker(store db.Store, config *config.Config, artifacts storage.Storage) *RetentionWorker {
+	return &RetentionWorker{store: store, config: config, artifacts: artifacts}
+}
+
+func (w *RetentionWorker) Start(ctx context.Context) {
+	log.Println("🧹 Retention worker started")
+
+	w.run(ctx)
+
+	ticker := time.NewTicker(retentionEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.run(ctx)
+		case <-ctx.Done():
+			log.Println("🛑 Retention worker stopped")
+			return
+		}
+	}
+}
+
+func (w *RetentionWorker) run(ctx context.Context) {
+	now := time.Now().UTC()
+
+	if err := w.store.EnsureMonitorLogPartitions(ctx, now.AddDate(0, -1, 0), partitionMonthsAhead); err != nil {
+		log.Printf("❌ Failed to create monitor_logs partitions: %v", err)
+	}
+
+	// Whole partitions can only go once they are past every plan's horizon
+	var longest time.Duration
+	keepForever := false
+	for _, plan := range plans.All() {
+		horizon := retentionHorizon(plan.RetentionDays)
+		if horizon == 0 {
+			keepForever = true
+			continue
+		}
+		w.deleteExpiredLogs(ctx, retentionCutoff(now, horizon), plan.Name)
+		longest = max(longest, horizon)
+	}
+
+	if !keepForever && longest > 0 {
+		dropped, err := w.store.DropMonitorLogPartitionsBefore(ctx, retentionCutoff(now, longest))
+		if err != nil {
+			log.Printf("❌ Failed to drop monitor_logs partitions: %v", err)
+		}
+		for _, name := range dropped {
+			log.Printf("🧹 Dropped partition %s", name)
+		}
+	}
+
+	if days := w.config.ROLLUP_1M_RETENTION_DAYS; days > 0 {
+		deleted, err := w.store.DeleteMonitorRollupsBefore(ctx, db.DeleteMonitorRollupsBeforeParams{
+			Resolution: db.RollupResolution1m,
+			Before:     pgtype.Timestamp{Time: now.AddDate(0, 0, -days), Valid: true},
+		})
+		if err != nil {
+			log.Printf("❌ Failed to prune 1m rollups: %v", err)
+		} else {
+			log.Printf("🧹 Pruned %d 1m rollups", deleted)
+		}
+	}
+
+	if deleted, err := w.store.DeleteExpiredRefreshTokens(ctx); err != nil {
+		log.Printf("❌ Failed to delete expired refresh tokens: %v", err)
+	} else if deleted > 0 {
+		log.Printf("🧹 Deleted %d expired refresh tokens", deleted)
+	}
+
+	if deleted, err := w.store.DeleteExpiredIdempotencyKeys(ctx); err != nil {
+		log.Printf("❌ Failed to delete expired idempotency keys: %v", err)
+	} else if deleted > 0 {
+		log.Printf("🧹 Deleted %d expired idempotency keys", deleted)
+	}
+}
+
+// deleteExpiredLogs deletes in batches until nothing older than cutoff is left,
+// then the screenshots of each batch
+func (w *RetentionWorker) deleteExpiredLogs(ctx context.Context, cutoff time.Time, plan string) {
+	var total, screenshots int
+	for {
+		keys, err := w.store.DeleteExpiredMonitorLogs(ctx, db.DeleteExpiredMonitorLogsParams{
+			Cutoff:      pgtype.Timestamp{Time: cutoff, Valid: true},
+			DefaultPlan: plans.Default(*w.config).Name,
+			Plan:        plan,
+			BatchSize:   retentionBatchSize,
+		})
+		if err != nil {
+			log.Printf("❌ Failed to delete expired logs (plan=%s): %v", plan, err)
+			return
+		}
+		total += len(keys)
+		screenshots += w.deleteScreenshots(ctx, keys)
+		if len(keys) < retentionBatchSize {
+			break
+		}
+
+		select {
+		case <-time.After(retentionBatchPause):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	log.Printf("🧹 Deleted %d logs and %d screenshots older than %s (plan=%s)", total, screenshots, cutoff.Format(time.RFC3339), plan)
+}
+
+func (w *RetentionWorker) deleteScreenshots(ctx context.Context, keys []pgtype.Text) int {
+	var stored []string
+	for _, key := range keys {
+		if key.Valid && key.String != "" {
+			stored = append(stored, key.String)
+		}
+	}
+	return storage.DeleteAll(ctx, w.artifacts, stored)
+}
+
+// retentionHorizon converts a plan's retention days to a duration, 0 disables deletion
+func retentionHorizon(days int) time.Duration {
+	if days <= 0 {
+		return 0
+	}
+	horizon := time.Duration(days) * 24 * time.Hour
+	if horizon < minRetention {
+		return minRetention
+	}
+	return horizon
+}
+
+// retentionCutoff is the start of the day horizon ago. Logs are only ever
+// deleted by whole days, so a daily rollup never gets recomputed from a
+// partly deleted day.
+func retentionCutoff(now time.Time, horizon time.Duration) time.Time {
+	return now.Add(-horizon).Truncate(24 * time.Hour)
+}
+
+// shortestRetention is the smallest horizon of any plan, 0 if no plan deletes logs
+func shortestRetention() time.Duration {
+	var shortest time.Duration
+	for _, plan := range plans.All() {
+		horizon := retentionHorizon(plan.RetentionDays)
+		if horizon > 0 && (shortest == 0 || horizon < shortest) {
+			shortest = horizon
+		}
+	}
+	return shortest
+}
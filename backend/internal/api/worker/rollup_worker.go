@@ -0,0 +1,86 @@
+package worker
+
+import (
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// rollupSchedule describes how often a resolution is refreshed and how far back
+// each run recomputes, so late or slow checks still land in their bucket
+type rollupSchedule struct {
+	resolution db.RollupResolution
+	bucketUnit string
+	bucket     time.Duration
+	every      time.Duration
+	lookback   time.Duration
+	backfill   time.Duration
+}
+
+var rollupSchedules = []rollupSchedule{
+	{resolution: db.RollupResolution1m, bucketUnit: "minute", bucket: time.Minute, every: time.Minute, lookback: 5 * time.Minute, backfill: 24 * time.Hour},
+	{resolution: db.RollupResolution1h, bucketUnit: "hour", bucket: time.Hour, every: 5 * time.Minute, lookback: 2 * time.Hour, backfill: 7 * 24 * time.Hour},
+	{resolution: db.RollupResolution1d, bucketUnit: "day", bucket: 24 * time.Hour, every: time.Hour, lookback: 48 * time.Hour, backfill: 90 * 24 * time.Hour},
+}
+
+// RollupWorker aggregates monitor_logs into monitor_rollups
+type RollupWorker struct {
+	store db.Store
+}
+
+func NewRollupWorker(store db.Store) *RollupWorker {
+	return &RollupWorker{store: store}
+}
+
+func (w *RollupWorker) Start(ctx context.Context) {
+	log.Println("📊 Rollup worker started")
+
+	for _, schedule := range rollupSchedules {
+		go w.runSchedule(ctx, schedule)
+	}
+}
+
+func (w *RollupWorker) runSchedule(ctx context.Context, s rollupSchedule) {
+	// Catch up on anything missed while the worker was down, but not past the
+	// retention horizon: older days may already have lost their raw logs and
+	// recomputing them would overwrite their rollups with partial counts
+	backfill := s.backfill
+	if horizon := shortestRetention(); horizon > 0 && horizon < backfill {
+		backfill = horizon
+	}
+	w.rollup(ctx, s, backfill)
+
+	ticker := time.NewTicker(s.every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.rollup(ctx, s, s.lookback)
+		case <-ctx.Done():
+			log.Printf("🛑 Rollup %s stopped", s.resolution)
+			return
+		}
+	}
+}
+
+func (w *RollupWorker) rollup(ctx context.Context, s rollupSchedule, lookback time.Duration) {
+	now := time.Now().UTC()
+	since := now.Add(-lookback).Truncate(s.bucket)
+
+	rows, err := w.store.RollupMonitorLogs(ctx, db.RollupMonitorLogsParams{
+		Resolution: s.resolution,
+		BucketUnit: s.bucketUnit,
+		Since:      pgtype.Timestamp{Time: since, Valid: true},
+		Until:      pgtype.Timestamp{Time: now, Valid: true},
+	})
+	if err != nil {
+		log.Printf("❌ Failed to roll up %s buckets: %v", s.resolution, err)
+		return
+	}
+
+	log.Printf("📊 Rolled up %d %s buckets since %s", rows, s.resolution, since.Format(time.RFC3339))
+}
@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"better-uptime/common/secrets"
+	"better-uptime/config"
+	"better-uptime/internal/api/monitor"
+	db "better-uptime/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	secretsRotationEvery     = time.Hour
+	secretsRotationBatchSize = 200
+)
+
+// SecretsRotationWorker rewraps monitor secrets sealed under a previous master
+// key onto SECRETS_KEY, so the old key can be dropped from
+// SECRETS_PREVIOUS_KEYS once it reports nothing left to rewrap.
+type SecretsRotationWorker struct {
+	store db.Store
+	box   *secrets.Box
+}
+
+// NewSecretsRotationWorker fails with secrets.ErrNoKey when SECRETS_KEY is not set
+func NewSecretsRotationWorker(store db.Store, config *config.Config) (*SecretsRotationWorker, error) {
+	box, err := secrets.NewFromConfig(*config)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretsRotationWorker{store: store, box: box}, nil
+}
+
+func (w *SecretsRotationWorker) Start(ctx context.Context) {
+	log.Println("🔑 Secrets rotation worker started")
+
+	w.run(ctx)
+
+	ticker := time.NewTicker(secretsRotationEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.run(ctx)
+		case <-ctx.Done():
+			log.Println("🛑 Secrets rotation worker stopped")
+			return
+		}
+	}
+}
+
+func (w *SecretsRotationWorker) run(ctx context.Context) {
+	active := w.box.ActiveKeyID()
+	var rewrapped, failed int
+
+	var after db.ListMonitorSecretsToRewrapRow
+	for {
+		rows, err := w.store.ListMonitorSecretsToRewrap(ctx, db.ListMonitorSecretsToRewrapParams{
+			ActiveKeyID:    active,
+			AfterMonitorID: after.MonitorID,
+			AfterName:      after.Name,
+			BatchSize:      secretsRotationBatchSize,
+		})
+		if err != nil {
+			log.Printf("❌ Failed to list secrets to rewrap: %v", err)
+			return
+		}
+
+		for _, row := range rows {
+			after = row
+			sealed, err := w.box.Rewrap(row.Ciphertext, monitor.SecretAAD(row.MonitorID, row.Name))
+			if err != nil {
+				failed++
+				log.Printf("❌ Cannot rewrap secret %q of monitor %d (key %q): %v", row.Name, row.MonitorID, row.KeyID, err)
+				continue
+			}
+
+			// A secret rewritten since it was listed already uses the active key
+			if _, err := w.store.RewrapMonitorSecret(ctx, db.RewrapMonitorSecretParams{
+				Ciphertext: sealed,
+				NewKeyID:   active,
+				MonitorID:  row.MonitorID,
+				Name:       row.Name,
+				OldKeyID:   row.KeyID,
+			}); err != nil {
+				log.Printf("❌ Failed to store rewrapped secret %q of monitor %d: %v", row.Name, row.MonitorID, err)
+				return
+			}
+			rewrapped++
+		}
+
+		if len(rows) < secretsRotationBatchSize {
+			break
+		}
+	}
+
+	if rewrapped > 0 || failed > 0 {
+		log.Printf("🔑 Rewrapped %d secrets onto key %s, %d could not be opened", rewrapped, active, failed)
+	}
+}
@@ -11,7 +11,8 @@ CREATE TYPE provider AS ENUM (
     'email',
     'google.com',
     'apple',
-    'password'
+    'password',
+    'oidc'
 );
 
 CREATE TYPE user_role AS ENUM (
@@ -31,6 +32,11 @@ CREATE TABLE users (
     fullname TEXT NOT NULL,
     provider provider NOT NULL,
     password_hash TEXT,
+    -- Local sign-ups can't sign in until they confirm their address. Accounts
+    -- from external identity providers are verified by the provider.
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    email_verification_hash TEXT UNIQUE,
+    email_verification_expires_at TIMESTAMP,
     role user_role NOT NULL DEFAULT 'USER',
     profile_status profile_status DEFAULT 'PENDING',
     created_at timestamptz DEFAULT NOW(),
@@ -48,12 +54,76 @@ CREATE TABLE user_profile (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Organizations own monitors, alert contacts and status pages; users reach
+-- them through a membership. Every user gets a personal organization on first
+-- sign-in.
+CREATE TYPE org_role AS ENUM (
+    'owner',
+    'admin',
+    'editor',
+    'viewer'
+);
+
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE org_memberships (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role org_role NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, user_id)
+);
+
+-- Pending email invitations. The token is sent by email and only accepted by a
+-- signed-in user with the invited address.
+CREATE TABLE org_invites (
+    id SERIAL PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role org_role NOT NULL,
+    token TEXT UNIQUE NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Named groups of monitors, e.g. one per service. A monitor is in at most one
+-- group; tags cover the other ways of slicing them.
+CREATE TABLE monitor_groups (
+    id SERIAL PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- How the group's status is rolled up from its monitors: 'worst' or 'percentage'
+    rollup TEXT NOT NULL DEFAULT 'worst',
+    -- With the percentage rollup, the share of monitors up for the group to be up
+    min_up_percent INTEGER NOT NULL DEFAULT 100,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- Stable id of groups managed through the config document, unique per org
+    external_id TEXT,
+    UNIQUE (org_id, name),
+    CHECK (rollup IN ('worst', 'percentage')),
+    CHECK (min_up_percent BETWEEN 1 AND 100)
+);
+
 CREATE TABLE monitors (
     id SERIAL PRIMARY KEY,
+    -- user_id is the creator, access goes through org_id
     user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
     url TEXT NOT NULL,
     method TEXT DEFAULT 'GET',
     type TEXT DEFAULT 'http',
+    -- Type-specific settings, e.g. the steps of an 'api' monitor
+    config JSONB,
     interval INTEGER NOT NULL,
     status monitor_status DEFAULT 'unknown',
     last_status monitor_status DEFAULT 'unknown',
@@ -61,12 +131,18 @@ CREATE TABLE monitors (
     is_active BOOLEAN DEFAULT TRUE,
     consecutive_failures INTEGER DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    group_id INTEGER REFERENCES monitor_groups(id) ON DELETE SET NULL,
+    -- Lowercase labels such as "env:prod", kept sorted and unique
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    external_id TEXT
 );
 
 
+-- Partitioned by month on checked_at. The monthly partitions and a default
+-- partition are created by db.Store.EnsureMonitorLogPartitions (partitions.go).
 CREATE TABLE monitor_logs (
-    id SERIAL PRIMARY KEY,
+    id SERIAL,
     monitor_id INTEGER REFERENCES monitors(id) on delete CASCADE,
     status_code INTEGER,
     response_time FLOAT,
@@ -74,16 +150,37 @@ CREATE TABLE monitor_logs (
     ssl_ok BOOLEAN,
     content_ok BOOLEAN,
     screenshot_url TEXT,
-    checked_at TIMESTAMP DEFAULT now()
-);
+    -- Artifact storage key of the screenshot, signed into a URL on read
+    screenshot_key TEXT,
+    -- Per-phase timings in ms, NULL when the phase did not happen (e.g. reused connection)
+    dns_ms FLOAT,
+    connect_ms FLOAT,
+    tls_ms FLOAT,
+    ttfb_ms FLOAT,
+    transfer_ms FLOAT,
+    -- Failure evidence, only filled in when the check was down
+    error_type TEXT,
+    error_message TEXT,
+    remote_ip TEXT,
+    response_headers JSONB,
+    response_body TEXT,
+    -- Per-step results of multi-step monitors
+    step_results JSONB,
+    -- Page timings, console errors and failed requests of browser monitors
+    browser_result JSONB,
+    checked_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (id, checked_at)
+) PARTITION BY RANGE (checked_at);
 
 CREATE TABLE alert_contacts (
     id SERIAL PRIMARY KEY,
     user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
     name TEXT NOT NULL,
     email TEXT NOT NULL,
     is_verified BOOLEAN DEFAULT FALSE,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    external_id TEXT
 );
 
 CREATE TABLE monitor_alert_configs (
@@ -111,12 +208,23 @@ CREATE TABLE alerts (
     created_at TIMESTAMP DEFAULT NOW()
 );
 
+-- An organization's paid plan, kept in sync with Stripe by its webhooks.
+-- Organizations without an active row are on the default plan.
 CREATE TABLE subscriptions(
     id SERIAL PRIMARY KEY,
     user_id UUID REFERENCES users(id),
-    stripe_plan TEXT,
-    is_active BOOLEAN DEFAULT TRUE,
-    created_at TIMESTAMP DEFAULT now()
+    org_id UUID UNIQUE NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    plan TEXT NOT NULL,
+    stripe_customer_id TEXT,
+    stripe_subscription_id TEXT UNIQUE,
+    status TEXT NOT NULL DEFAULT 'active',
+    current_period_end TIMESTAMP,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    -- When the last Stripe event applied was created. Stripe delivers events in
+    -- any order, and older ones must not undo newer ones.
+    stripe_event_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now()
 );
 
 CREATE TABLE analytics(
@@ -128,7 +236,246 @@ CREATE TABLE analytics(
     updated_at TIMESTAMP DEFAULT now()
 );
 
+-- Opened by the checker when a monitor goes down, resolved when it recovers
+CREATE TABLE incidents (
+    id SERIAL PRIMARY KEY,
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    cause TEXT NOT NULL DEFAULT '',
+    -- Failure class of the check that opened it; public pages show only this
+    error_type TEXT,
+    screenshot_key TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE TABLE status_pages (
+    id SERIAL PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    slug TEXT UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    custom_domain TEXT UNIQUE,
+    is_published BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    external_id TEXT
+);
+
+CREATE TABLE status_page_sections (
+    id SERIAL PRIMARY KEY,
+    status_page_id INTEGER NOT NULL REFERENCES status_pages(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE status_page_monitors (
+    id SERIAL PRIMARY KEY,
+    status_page_id INTEGER NOT NULL REFERENCES status_pages(id) ON DELETE CASCADE,
+    section_id INTEGER NOT NULL REFERENCES status_page_sections(id) ON DELETE CASCADE,
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    display_name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(status_page_id, monitor_id)
+);
+
+CREATE TYPE notice_kind AS ENUM ('incident', 'maintenance');
+
+-- incident: investigating -> identified -> monitoring -> resolved
+-- maintenance: scheduled -> in_progress -> completed
+CREATE TYPE notice_status AS ENUM (
+    'investigating',
+    'identified',
+    'monitoring',
+    'resolved',
+    'scheduled',
+    'in_progress',
+    'completed'
+);
+
+-- Human-written incidents and maintenance notices shown on a status page
+CREATE TABLE status_page_notices (
+    id SERIAL PRIMARY KEY,
+    status_page_id INTEGER NOT NULL REFERENCES status_pages(id) ON DELETE CASCADE,
+    kind notice_kind NOT NULL,
+    title TEXT NOT NULL,
+    status notice_status NOT NULL,
+    scheduled_start TIMESTAMP,
+    scheduled_end TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE status_page_notice_updates (
+    id SERIAL PRIMARY KEY,
+    notice_id INTEGER NOT NULL REFERENCES status_page_notices(id) ON DELETE CASCADE,
+    status notice_status NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TYPE subscriber_kind AS ENUM ('email', 'webhook');
+
+CREATE TABLE status_page_subscribers (
+    id SERIAL PRIMARY KEY,
+    status_page_id INTEGER NOT NULL REFERENCES status_pages(id) ON DELETE CASCADE,
+    kind subscriber_kind NOT NULL,
+    target TEXT NOT NULL,
+    confirm_token TEXT UNIQUE NOT NULL,
+    -- The confirmation link stops working after this unless already confirmed
+    confirm_expires_at TIMESTAMP NOT NULL,
+    unsubscribe_token TEXT UNIQUE NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(status_page_id, kind, target)
+);
+
+-- Public, revocable token used to embed a monitor's badges without exposing the API
+CREATE TABLE monitor_badges (
+    id SERIAL PRIMARY KEY,
+    monitor_id INTEGER UNIQUE NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    token TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TYPE rollup_resolution AS ENUM ('1m', '1h', '1d');
+
+-- Pre-aggregated monitor_logs used for charts and dashboard stats.
+-- Response time figures only consider checks that got a response (response_time > 0).
+CREATE TABLE monitor_rollups (
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    resolution rollup_resolution NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    check_count INTEGER NOT NULL,
+    success_count INTEGER NOT NULL,
+    response_count INTEGER NOT NULL,
+    min_response_time FLOAT,
+    avg_response_time FLOAT,
+    max_response_time FLOAT,
+    p50_response_time FLOAT,
+    p90_response_time FLOAT,
+    p95_response_time FLOAT,
+    p99_response_time FLOAT,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (monitor_id, resolution, bucket_start)
+);
+
+-- Write-only monitor secrets (auth tokens, passwords, database credentials),
+-- envelope-encrypted by common/secrets with the monitor and name as associated
+-- data. key_id names the master key the data key is wrapped with, hint is the
+-- masked value shown in the API. Checks reference them as {{secrets.<name>}}.
+CREATE TABLE monitor_secrets (
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    ciphertext BYTEA NOT NULL,
+    key_id TEXT NOT NULL,
+    hint TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (monitor_id, name)
+);
+
+-- Keys for scripts and CI. Only the SHA-256 of the key is stored; prefix is
+-- kept to tell keys apart. A key acts as its user, within org_id when set,
+-- and is limited to its scopes.
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    org_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT UNIQUE NOT NULL,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Refresh tokens of local email/password sign-in. Only the SHA-256 is stored.
+-- Each refresh rotates the token within its family; presenting a rotated token
+-- again revokes the whole family.
+CREATE TABLE refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Append-only record of configuration changes: who changed what, when and
+-- from where, with the resource before and after. org_id and the actor carry
+-- no foreign keys so entries outlive the resources and users they describe.
+CREATE TABLE audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    org_id UUID NOT NULL,
+    user_id UUID,
+    actor_email TEXT NOT NULL,
+    api_key_id INT,
+    action TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    ip TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only
+BEFORE UPDATE OR DELETE ON audit_logs
+FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+-- Responses of v2 POST requests sent with an Idempotency-Key header, replayed
+-- when a client retries with the same key. request_hash guards against the
+-- key being reused for a different request. Keys are kept for 24 hours; one
+-- whose request never finished (status_code still NULL) can be claimed again
+-- after 5 minutes.
+CREATE TABLE idempotency_keys (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    -- Who sent the request, "user:<id>" or "api_key:<id>"; keys are theirs alone
+    principal TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER,
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, principal, key)
+);
+
 -- Create indexes after all tables are created
 CREATE INDEX idx_monitors_user_id ON monitors(user_id);
 CREATE INDEX idx_monitor_logs_monitor_id ON monitor_logs(monitor_id);
-CREATE INDEX idx_monitor_logs_checked_at ON monitor_logs(checked_at);
\ No newline at end of file
+CREATE INDEX idx_monitor_logs_checked_at ON monitor_logs(checked_at);
+CREATE UNIQUE INDEX idx_incidents_open_monitor ON incidents(monitor_id) WHERE resolved_at IS NULL;
+CREATE INDEX idx_status_page_monitors_page ON status_page_monitors(status_page_id);
+CREATE INDEX idx_status_page_notices_page ON status_page_notices(status_page_id);
+CREATE INDEX idx_status_page_notice_updates_notice ON status_page_notice_updates(notice_id);
+CREATE INDEX idx_monitor_secrets_key_id ON monitor_secrets(key_id);
+CREATE INDEX idx_monitors_org_id ON monitors(org_id);
+CREATE INDEX idx_alert_contacts_org_id ON alert_contacts(org_id);
+CREATE INDEX idx_status_pages_org_id ON status_pages(org_id);
+CREATE INDEX idx_org_memberships_user ON org_memberships(user_id);
+CREATE UNIQUE INDEX idx_org_invites_pending ON org_invites(org_id, email) WHERE accepted_at IS NULL;
+CREATE INDEX idx_api_keys_user ON api_keys(user_id);
+CREATE INDEX idx_api_keys_org ON api_keys(org_id);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX idx_audit_logs_org_created ON audit_logs(org_id, created_at DESC);
+CREATE INDEX idx_audit_logs_resource ON audit_logs(org_id, resource_type, resource_id);
+CREATE INDEX idx_monitors_group ON monitors(group_id);
+CREATE INDEX idx_monitors_tags ON monitors USING GIN (tags);
+CREATE UNIQUE INDEX idx_monitor_groups_external_id ON monitor_groups(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_monitors_external_id ON monitors(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_alert_contacts_external_id ON alert_contacts(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_status_pages_external_id ON status_pages(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE INDEX idx_idempotency_keys_created ON idempotency_keys(created_at);
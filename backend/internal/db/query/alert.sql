@@ -13,7 +13,7 @@ LIMIT $2;
 SELECT a.*, m.url, m.user_id
 FROM alerts a
 JOIN monitors m ON a.monitor_id = m.id
-WHERE m.user_id = $1
+WHERE m.org_id = $1
 ORDER BY a.sent_at DESC 
 LIMIT $2;
 
@@ -1,11 +1,11 @@
 -- name: CreateAlertContact :one
-INSERT INTO alert_contacts (user_id, name, email)
-VALUES ($1, $2, $3)
+INSERT INTO alert_contacts (user_id, org_id, name, email)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
--- name: GetAlertContactsByUserID :many
+-- name: GetAlertContactsByOrg :many
 SELECT * FROM alert_contacts
-WHERE user_id = $1
+WHERE org_id = $1
 ORDER BY created_at DESC;
 
 -- name: GetAlertContactByID :one
@@ -33,3 +33,53 @@ SET alert_on_up = $2, alert_on_down = $3, alert_on_slow = $4, slow_threshold_ms
 WHERE id = $1
 RETURNING *;
 
+
+-- name: UpdateAlertContact :one
+UPDATE alert_contacts
+SET name = $3, email = $4
+WHERE id = $1 AND org_id = $2
+RETURNING *;
+
+-- name: DeleteAlertContact :exec
+DELETE FROM alert_contacts
+WHERE id = $1 AND org_id = $2;
+
+-- name: SetAlertContactExternalID :exec
+UPDATE alert_contacts SET external_id = $3
+WHERE id = $1 AND org_id = $2;
+
+-- name: GetOrgMonitorAlertConfigs :many
+SELECT mac.* FROM monitor_alert_configs mac
+JOIN monitors m ON m.id = mac.monitor_id
+WHERE m.org_id = $1 AND mac.is_active = true
+ORDER BY mac.monitor_id, mac.alert_contact_id;
+
+-- name: UpsertMonitorAlertConfig :one
+INSERT INTO monitor_alert_configs (monitor_id, alert_contact_id, alert_on_up, alert_on_down, alert_on_slow, slow_threshold_ms, is_active)
+VALUES ($1, $2, $3, $4, $5, $6, true)
+ON CONFLICT (monitor_id, alert_contact_id) DO UPDATE SET
+    alert_on_up = EXCLUDED.alert_on_up,
+    alert_on_down = EXCLUDED.alert_on_down,
+    alert_on_slow = EXCLUDED.alert_on_slow,
+    slow_threshold_ms = EXCLUDED.slow_threshold_ms,
+    is_active = true
+RETURNING *;
+
+-- name: DeleteMonitorAlertConfig :exec
+DELETE FROM monitor_alert_configs
+WHERE monitor_id = $1 AND alert_contact_id = $2;
+
+-- name: GetOrgAlertContact :one
+SELECT * FROM alert_contacts
+WHERE id = $1 AND org_id = $2;
+
+-- name: ListOrgAlertContactsPage :many
+SELECT * FROM alert_contacts
+WHERE org_id = sqlc.arg(org_id) AND id > sqlc.arg(after_id)::int
+ORDER BY id
+LIMIT sqlc.arg(page_size)::int;
+
+-- name: GetAlertConfigsForMonitors :many
+SELECT * FROM monitor_alert_configs
+WHERE monitor_id = ANY(sqlc.arg(monitor_ids)::int[]) AND is_active = true
+ORDER BY monitor_id, alert_contact_id;
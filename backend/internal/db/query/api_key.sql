@@ -0,0 +1,32 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, org_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: TouchAPIKey :exec
+-- Updated at most once a minute so busy keys don't write on every request
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: ListAPIKeys :many
+-- The user's own keys and, for admins, the org-scoped keys of their organization
+SELECT * FROM api_keys
+WHERE revoked_at IS NULL
+  AND (user_id = sqlc.arg(user_id) OR (sqlc.arg(include_org)::boolean AND org_id = sqlc.arg(org_id)))
+ORDER BY created_at DESC;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAPIKey :exec
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,21 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (
+    org_id, user_id, actor_email, api_key_id, action,
+    resource_type, resource_id, before, after, ip, user_agent
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+);
+
+-- name: ListAuditLogs :many
+-- Newest first; every filter but org_id is optional
+SELECT * FROM audit_logs
+WHERE org_id = sqlc.arg(org_id)
+  AND (sqlc.narg(resource_type)::text IS NULL OR resource_type = sqlc.narg(resource_type))
+  AND (sqlc.narg(resource_id)::text IS NULL OR resource_id = sqlc.narg(resource_id))
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(api_key_id)::int IS NULL OR api_key_id = sqlc.narg(api_key_id))
+  AND (sqlc.narg(from_time)::timestamp IS NULL OR created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamp IS NULL OR created_at <= sqlc.narg(to_time))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
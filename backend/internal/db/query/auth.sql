@@ -19,13 +19,52 @@ WHERE id = $1;
 
 
 -- name: FindOrCreateUser :one
-INSERT INTO users (id, email, provider, phone, fullname, password_hash, created_at, updated_at)
-VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, NOW(), NOW())
+-- For identities verified by their provider. An unverified local sign-up with
+-- the same email was made by whoever chose its password, so its password and
+-- sessions are dropped as the verified identity claims it.
+WITH unverified AS (
+    UPDATE refresh_tokens
+    SET revoked_at = NOW()
+    WHERE revoked_at IS NULL
+      AND user_id IN (SELECT id FROM users WHERE email = $1 AND NOT email_verified)
+)
+INSERT INTO users (id, email, provider, phone, fullname, password_hash, email_verified, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, TRUE, NOW(), NOW())
 ON CONFLICT (email)
 DO UPDATE SET
     phone = COALESCE(NULLIF(EXCLUDED.phone, ''), users.phone),
     provider = EXCLUDED.provider,
     fullname = COALESCE(NULLIF(EXCLUDED.fullname, ''), users.fullname),
+    password_hash = CASE
+        WHEN users.email_verified THEN COALESCE(EXCLUDED.password_hash, users.password_hash)
+        ELSE EXCLUDED.password_hash
+    END,
+    email_verified = TRUE,
+    email_verification_hash = NULL,
+    email_verification_expires_at = NULL,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: CreateLocalUser :one
+-- Returns no row when a verified account has the email, whichever provider it
+-- signed up with. An unverified sign-up is replaced, so whoever owns the
+-- address can always register it.
+INSERT INTO users (email, fullname, provider, password_hash, email_verification_hash, email_verification_expires_at)
+VALUES ($1, $2, 'password', $3, $4, $5)
+ON CONFLICT (email) DO UPDATE SET
+    fullname = EXCLUDED.fullname,
     password_hash = EXCLUDED.password_hash,
+    email_verification_hash = EXCLUDED.email_verification_hash,
+    email_verification_expires_at = EXCLUDED.email_verification_expires_at,
+    updated_at = NOW()
+WHERE NOT users.email_verified
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified = TRUE,
+    email_verification_hash = NULL,
+    email_verification_expires_at = NULL,
     updated_at = NOW()
-RETURNING *;
\ No newline at end of file
+WHERE email_verification_hash = $1 AND email_verification_expires_at > NOW()
+RETURNING *;
@@ -0,0 +1,31 @@
+-- name: ClaimIdempotencyKey :one
+-- Returns no row while another request holds the key; an expired key, or one
+-- whose request never finished, is taken over
+INSERT INTO idempotency_keys (org_id, principal, key, request_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (org_id, principal, key) DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+    status_code = NULL,
+    response_body = NULL,
+    created_at = CURRENT_TIMESTAMP
+WHERE idempotency_keys.created_at < NOW() - INTERVAL '24 hours'
+   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < NOW() - INTERVAL '5 minutes')
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE org_id = $1 AND principal = $2 AND key = $3;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $4, response_body = $5
+WHERE org_id = $1 AND principal = $2 AND key = $3;
+
+-- name: ReleaseIdempotencyKey :exec
+-- Frees the key of a request that failed, so it can be retried
+DELETE FROM idempotency_keys
+WHERE org_id = $1 AND principal = $2 AND key = $3 AND status_code IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < NOW() - INTERVAL '24 hours';
@@ -0,0 +1,20 @@
+-- name: OpenIncident :exec
+INSERT INTO incidents (monitor_id, cause, error_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (monitor_id) WHERE resolved_at IS NULL DO NOTHING;
+
+-- name: ResolveOpenIncident :exec
+UPDATE incidents
+SET resolved_at = NOW()
+WHERE monitor_id = $1 AND resolved_at IS NULL;
+
+-- name: GetOpenIncidentsForMonitors :many
+SELECT * FROM incidents
+WHERE monitor_id = ANY(@monitor_ids::int[])
+  AND resolved_at IS NULL
+ORDER BY started_at DESC;
+
+-- name: SetOpenIncidentScreenshot :exec
+UPDATE incidents
+SET screenshot_key = $2
+WHERE monitor_id = $1 AND resolved_at IS NULL;
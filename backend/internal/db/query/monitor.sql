@@ -1,16 +1,16 @@
 -- name: CreateMonitor :one
-INSERT INTO monitors (user_id, url, method, type, interval, status, is_active, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
+INSERT INTO monitors (user_id, org_id, url, method, type, interval, status, is_active, config, group_id, tags, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now())
 RETURNING *;
 
--- name: GetUserMonitors :many
+-- name: GetOrgMonitors :many
 SELECT * FROM monitors 
-WHERE user_id = $1 
+WHERE org_id = $1 
 ORDER BY created_at DESC;
 
 -- name: GetMonitorByID :one
 SELECT * FROM monitors 
-WHERE id = $1 AND user_id = $2;
+WHERE id = $1 AND org_id = $2;
 
 
 -- name: UpdateMonitor :one
@@ -22,21 +22,33 @@ SET
     interval = COALESCE($5, interval),
     status = COALESCE($6, status),
     is_active = COALESCE($7, is_active),
+    config = COALESCE($9, config),
+    group_id = $10,
+    tags = COALESCE($11, tags),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $8
+WHERE id = $1 AND org_id = $8
 RETURNING *;
 
 
+-- name: ListMonitorScreenshotKeys :many
+-- Stored screenshots of a monitor, read before deleting it since its logs and
+-- incidents go with it
+SELECT screenshot_key::text FROM monitor_logs
+WHERE monitor_id = @monitor_id::int AND screenshot_key IS NOT NULL
+UNION
+SELECT screenshot_key::text FROM incidents
+WHERE monitor_id = @monitor_id::int AND screenshot_key IS NOT NULL;
+
 -- name: DeleteMonitor :exec
 DELETE FROM monitors 
-WHERE id = $1 AND user_id = $2;
+WHERE id = $1 AND org_id = $2;
 
 -- name: ToggleMonitor :one
 UPDATE monitors 
 SET is_active = $3, 
     consecutive_failures = CASE WHEN $3 = true THEN 0 ELSE consecutive_failures END,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND org_id = $2
 RETURNING *;
 
 -- name: UpdateMonitorStatus :one
@@ -58,15 +70,15 @@ WHERE id = $1;
 SELECT * FROM monitors 
 WHERE is_active = true;
 
--- name: GetActiveMonitorsForUser :many
+-- name: GetActiveMonitorsForOrg :many
 SELECT * FROM monitors 
-WHERE is_active = true AND user_id = $1;
+WHERE is_active = true AND org_id = $1;
 
 
 
 -- name: GetMonitorByIdandURL :one
 SELECT * FROM monitors
-where user_id = $1 AND url = $2;
+where org_id = $1 AND url = $2;
 
 -- name: GetMonitorsByInterval :many
 SELECT * FROM monitors WHERE is_active = true AND interval = $1;
@@ -80,7 +92,7 @@ SET status = $2,
 WHERE id = $1
 RETURNING *;
 
--- name: GetUserMonitorsWithStats :many
+-- name: GetOrgMonitorsWithStats :many
 SELECT 
     m.id, 
     m.user_id,
@@ -90,11 +102,101 @@ SELECT
     m.status, 
     m.is_active, 
     COALESCE(AVG(ml.response_time), 0)::float as avg_response_time,
-    COALESCE(SUM(CASE WHEN ml.status_code >= 200 AND ml.status_code < 400 THEN 1 ELSE 0 END), 0)::bigint as successful_checks,
+    COALESCE(SUM(CASE WHEN ml.content_ok IS NOT FALSE AND ml.status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END), 0)::bigint as successful_checks,
     COUNT(ml.id)::bigint as total_checks,
-    MAX(ml.checked_at) as last_check
+    MAX(ml.checked_at) as last_check,
+    m.group_id,
+    m.tags
 FROM monitors m
 LEFT JOIN monitor_logs ml ON m.id = ml.monitor_id
-WHERE m.user_id = $1
+WHERE m.org_id = sqlc.arg(org_id)
+  AND (sqlc.narg(tags)::text[] IS NULL OR m.tags @> sqlc.narg(tags)::text[])
+  AND (sqlc.narg(status)::monitor_status IS NULL OR m.status = sqlc.narg(status)::monitor_status)
+  AND (sqlc.narg(is_active)::bool IS NULL OR m.is_active = sqlc.narg(is_active)::bool)
+  AND (sqlc.narg(group_id)::int IS NULL OR m.group_id = sqlc.narg(group_id)::int)
+  AND (sqlc.narg(search)::text IS NULL OR m.url ILIKE '%' || sqlc.narg(search)::text || '%')
 GROUP BY m.id
-ORDER BY m.created_at DESC;
\ No newline at end of file
+ORDER BY m.created_at DESC;
+
+-- name: SearchOrgMonitors :many
+-- Filters are optional: tags must all be present, search matches the URL
+-- (with LIKE wildcards already escaped)
+SELECT * FROM monitors
+WHERE org_id = sqlc.arg(org_id)
+  AND (sqlc.narg(tags)::text[] IS NULL OR tags @> sqlc.narg(tags)::text[])
+  AND (sqlc.narg(status)::monitor_status IS NULL OR status = sqlc.narg(status)::monitor_status)
+  AND (sqlc.narg(is_active)::bool IS NULL OR is_active = sqlc.narg(is_active)::bool)
+  AND (sqlc.narg(group_id)::int IS NULL OR group_id = sqlc.narg(group_id)::int)
+  AND (sqlc.narg(search)::text IS NULL OR url ILIKE '%' || sqlc.narg(search)::text || '%')
+ORDER BY created_at DESC;
+
+-- name: GetOrgMonitorsByIDs :many
+SELECT * FROM monitors
+WHERE org_id = sqlc.arg(org_id) AND id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id;
+
+-- name: BulkSetMonitorsActive :many
+UPDATE monitors
+SET is_active = sqlc.arg(is_active)::bool,
+    consecutive_failures = CASE WHEN sqlc.arg(is_active)::bool THEN 0 ELSE consecutive_failures END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = sqlc.arg(org_id) AND id = ANY(sqlc.arg(ids)::int[])
+RETURNING *;
+
+-- name: BulkSetMonitorsInterval :many
+UPDATE monitors
+SET interval = sqlc.arg(interval)::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = sqlc.arg(org_id) AND id = ANY(sqlc.arg(ids)::int[])
+RETURNING *;
+
+-- name: BulkSetMonitorsGroup :many
+UPDATE monitors
+SET group_id = sqlc.narg(group_id)::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = sqlc.arg(org_id) AND id = ANY(sqlc.arg(ids)::int[])
+RETURNING *;
+
+-- name: BulkRetagMonitors :many
+-- Adds add_tags and drops remove_tags (which wins), keeping each monitor's
+-- tags sorted and unique
+UPDATE monitors
+SET tags = ARRAY(
+        SELECT DISTINCT t
+        FROM unnest(monitors.tags || COALESCE(sqlc.arg(add_tags)::text[], '{}')) AS t
+        WHERE t <> ALL(COALESCE(sqlc.arg(remove_tags)::text[], '{}'))
+        ORDER BY t
+    ),
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = sqlc.arg(org_id) AND id = ANY(sqlc.arg(ids)::int[])
+RETURNING *;
+
+-- name: BulkDeleteMonitors :many
+DELETE FROM monitors
+WHERE org_id = sqlc.arg(org_id) AND id = ANY(sqlc.arg(ids)::int[])
+RETURNING id;
+
+-- name: ListOrgTags :many
+-- Every tag in use in the organization with how many monitors carry it
+SELECT t.tag::text AS tag, COUNT(*)::bigint AS monitors
+FROM monitors m, unnest(m.tags) AS t(tag)
+WHERE m.org_id = $1
+GROUP BY t.tag
+ORDER BY t.tag;
+-- name: SetMonitorExternalID :exec
+UPDATE monitors SET external_id = $3
+WHERE id = $1 AND org_id = $2;
+
+-- name: ListOrgMonitorsPage :many
+-- Pages through monitors by id, after the last one of the previous page, with
+-- the filters of SearchOrgMonitors
+SELECT * FROM monitors
+WHERE org_id = sqlc.arg(org_id)
+  AND id > sqlc.arg(after_id)::int
+  AND (sqlc.narg(tags)::text[] IS NULL OR tags @> sqlc.narg(tags)::text[])
+  AND (sqlc.narg(status)::monitor_status IS NULL OR status = sqlc.narg(status)::monitor_status)
+  AND (sqlc.narg(is_active)::bool IS NULL OR is_active = sqlc.narg(is_active)::bool)
+  AND (sqlc.narg(group_id)::int IS NULL OR group_id = sqlc.narg(group_id)::int)
+  AND (sqlc.narg(search)::text IS NULL OR url ILIKE '%' || sqlc.narg(search)::text || '%')
+ORDER BY id
+LIMIT sqlc.arg(page_size)::int;
@@ -0,0 +1,24 @@
+-- name: UpsertMonitorBadge :one
+INSERT INTO monitor_badges (monitor_id, token)
+VALUES ($1, $2)
+ON CONFLICT (monitor_id)
+DO UPDATE SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetMonitorBadge :one
+SELECT * FROM monitor_badges
+WHERE monitor_id = $1;
+
+-- name: DeleteMonitorBadge :exec
+DELETE FROM monitor_badges
+WHERE monitor_id = $1;
+
+-- name: GetBadgeMonitorByToken :one
+SELECT
+    m.id,
+    m.url,
+    m.status,
+    m.is_active
+FROM monitor_badges b
+JOIN monitors m ON m.id = b.monitor_id
+WHERE b.token = $1;
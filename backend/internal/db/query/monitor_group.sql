@@ -0,0 +1,52 @@
+-- name: CreateMonitorGroup :one
+INSERT INTO monitor_groups (org_id, name, description, rollup, min_up_percent)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetMonitorGroup :one
+SELECT * FROM monitor_groups
+WHERE id = $1 AND org_id = $2;
+
+-- name: GetOrgMonitorGroups :many
+SELECT * FROM monitor_groups
+WHERE org_id = $1
+ORDER BY name;
+
+-- name: UpdateMonitorGroup :one
+UPDATE monitor_groups
+SET name = $3,
+    description = $4,
+    rollup = $5,
+    min_up_percent = $6,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2
+RETURNING *;
+
+-- name: DeleteMonitorGroup :execrows
+-- Its monitors are kept, without a group
+DELETE FROM monitor_groups
+WHERE id = $1 AND org_id = $2;
+
+-- name: GetMonitorGroupCounts :many
+-- Monitors per group and state, for rolling up group status. Paused monitors
+-- are counted apart from their last status.
+SELECT
+    m.group_id::int AS group_id,
+    COUNT(*)::bigint AS total,
+    COUNT(*) FILTER (WHERE m.is_active AND m.status = 'up')::bigint AS up,
+    COUNT(*) FILTER (WHERE m.is_active AND m.status = 'down')::bigint AS down,
+    COUNT(*) FILTER (WHERE m.is_active AND (m.status IS NULL OR m.status IN ('unknown', 'pending')))::bigint AS pending,
+    COUNT(*) FILTER (WHERE m.is_active IS NOT TRUE)::bigint AS paused
+FROM monitors m
+WHERE m.org_id = $1 AND m.group_id IS NOT NULL
+GROUP BY m.group_id;
+
+-- name: SetMonitorGroupExternalID :exec
+UPDATE monitor_groups SET external_id = $3
+WHERE id = $1 AND org_id = $2;
+
+-- name: ListOrgMonitorGroupsPage :many
+SELECT * FROM monitor_groups
+WHERE org_id = sqlc.arg(org_id) AND id > sqlc.arg(after_id)::int
+ORDER BY id
+LIMIT sqlc.arg(page_size)::int;
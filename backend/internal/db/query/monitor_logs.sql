@@ -1,8 +1,11 @@
 -- name: CreateMonitorLog :one
 INSERT INTO monitor_logs (
     monitor_id, status_code, response_time, 
-    dns_ok, ssl_ok, content_ok, screenshot_url
-) VALUES ($1, $2, $3, $4, $5, $6, $7)
+    dns_ok, ssl_ok, content_ok, screenshot_url, screenshot_key,
+    dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms,
+    error_type, error_message, remote_ip, response_headers, response_body,
+    step_results, browser_result
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 RETURNING *;
 
 
@@ -20,7 +23,8 @@ ORDER BY checked_at DESC
 LIMIT 1;
 
 -- name: GetMonitorLogs :many
-SELECT id , monitor_id,status_code,response_time,dns_ok,ssl_ok,content_ok,screenshot_url,checked_at
+SELECT id , monitor_id,status_code,response_time,dns_ok,ssl_ok,content_ok,screenshot_url,
+       dns_ms,connect_ms,tls_ms,ttfb_ms,transfer_ms,error_type,error_message,checked_at
 FROM monitor_logs
 WHERE monitor_id = $1
    AND ( $2::TIMESTAMP IS NULL OR checked_at >= $2)
@@ -28,9 +32,50 @@ WHERE monitor_id = $1
 ORDER BY checked_at DESC
 LIMIT $4 OFFSET $5;
 
+-- name: GetMonitorLogByID :one
+SELECT * FROM monitor_logs
+WHERE id = $1 AND monitor_id = $2;
+
+-- name: SetMonitorLogScreenshot :exec
+UPDATE monitor_logs
+SET screenshot_key = $3
+WHERE id = $1 AND checked_at = $2;
+
 -- name: CountMonitorLogs :many  
 SELECT COUNT(*)
 FROM monitor_logs
 WHERE monitor_id = $1
  AND ( $2::TIMESTAMP IS NULL OR checked_at >= $2)
    AND ($3::timestamp IS NULL OR checked_at <= $3);
+
+-- name: DeleteExpiredMonitorLogs :many
+-- Deletes at most batch_size logs older than cutoff for monitors of
+-- organizations on plan; default_plan is the plan of organizations without an
+-- active subscription. Small batches keep locks short so the retention job
+-- can run online. Returns the screenshot key of every deleted log (NULL for
+-- most) so their artifacts can be deleted as well.
+DELETE FROM monitor_logs
+WHERE (id, checked_at) IN (
+    SELECT l.id, l.checked_at
+    FROM monitor_logs l
+    JOIN monitors m ON m.id = l.monitor_id
+    LEFT JOIN subscriptions s ON s.org_id = m.org_id AND s.is_active
+    WHERE l.checked_at < @cutoff::timestamp
+      AND COALESCE(s.plan, @default_plan::text) = @plan::text
+    LIMIT @batch_size::int
+)
+RETURNING screenshot_key;
+
+-- name: ListMonitorLogsPage :many
+-- Pages through a monitor's logs newest first, before the checked_at and id
+-- of the last log of the previous page
+SELECT id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok,
+       dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, error_type, error_message, checked_at
+FROM monitor_logs
+WHERE monitor_id = sqlc.arg(monitor_id)
+  AND (sqlc.narg(since)::timestamp IS NULL OR checked_at >= sqlc.narg(since)::timestamp)
+  AND (sqlc.narg(until)::timestamp IS NULL OR checked_at < sqlc.narg(until)::timestamp)
+  AND (sqlc.narg(before_checked_at)::timestamp IS NULL
+       OR (checked_at, id) < (sqlc.narg(before_checked_at)::timestamp, sqlc.arg(before_id)::int))
+ORDER BY checked_at DESC, id DESC
+LIMIT sqlc.arg(page_size)::int;
@@ -0,0 +1,70 @@
+-- name: RollupMonitorLogs :execrows
+-- Recomputes every bucket of the given resolution that starts in [since, until).
+-- since must be aligned to a bucket boundary, otherwise its bucket is rewritten with partial data.
+-- Safe to run repeatedly, late logs simply update their bucket.
+-- A check is up when it passed (content_ok, false for failed assertions of API
+-- and database checks that still got a 200) with a 2xx/3xx code; older logs
+-- have content_ok NULL or always TRUE, so the code decides for them.
+INSERT INTO monitor_rollups (
+    monitor_id, resolution, bucket_start,
+    check_count, success_count, response_count,
+    min_response_time, avg_response_time, max_response_time,
+    p50_response_time, p90_response_time, p95_response_time, p99_response_time
+)
+SELECT
+    l.monitor_id,
+    @resolution::rollup_resolution,
+    date_trunc(@bucket_unit::text, l.checked_at)::timestamp AS bucket,
+    COUNT(*)::int,
+    COUNT(*) FILTER (WHERE l.content_ok IS NOT FALSE AND l.status_code BETWEEN 200 AND 399)::int,
+    COUNT(*) FILTER (WHERE l.response_time > 0)::int,
+    MIN(l.response_time) FILTER (WHERE l.response_time > 0),
+    AVG(l.response_time) FILTER (WHERE l.response_time > 0),
+    MAX(l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.50) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.90) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.95) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.99) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0)
+FROM monitor_logs l
+WHERE l.monitor_id IS NOT NULL
+  AND l.checked_at >= @since::timestamp
+  AND l.checked_at < @until::timestamp
+GROUP BY l.monitor_id, bucket
+ON CONFLICT (monitor_id, resolution, bucket_start)
+DO UPDATE SET
+    check_count = EXCLUDED.check_count,
+    success_count = EXCLUDED.success_count,
+    response_count = EXCLUDED.response_count,
+    min_response_time = EXCLUDED.min_response_time,
+    avg_response_time = EXCLUDED.avg_response_time,
+    max_response_time = EXCLUDED.max_response_time,
+    p50_response_time = EXCLUDED.p50_response_time,
+    p90_response_time = EXCLUDED.p90_response_time,
+    p95_response_time = EXCLUDED.p95_response_time,
+    p99_response_time = EXCLUDED.p99_response_time,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetMonitorRollups :many
+SELECT * FROM monitor_rollups
+WHERE monitor_id = @monitor_id
+  AND resolution = @resolution
+  AND bucket_start >= @since::timestamp
+  AND bucket_start < @until::timestamp
+ORDER BY bucket_start;
+
+-- name: GetRollupTotalsForMonitors :many
+SELECT
+    monitor_id,
+    SUM(check_count)::bigint AS total_checks,
+    SUM(success_count)::bigint AS successful_checks,
+    COALESCE(SUM(avg_response_time * response_count) / NULLIF(SUM(response_count), 0), 0)::float AS avg_response_time
+FROM monitor_rollups
+WHERE monitor_id = ANY(@monitor_ids::int[])
+  AND resolution = @resolution
+  AND bucket_start >= @since::timestamp
+GROUP BY monitor_id;
+
+-- name: DeleteMonitorRollupsBefore :execrows
+DELETE FROM monitor_rollups
+WHERE resolution = @resolution
+  AND bucket_start < @before::timestamp;
@@ -0,0 +1,47 @@
+-- name: UpsertMonitorSecret :exec
+INSERT INTO monitor_secrets (monitor_id, name, ciphertext, key_id, hint)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (monitor_id, name)
+DO UPDATE SET
+    ciphertext = EXCLUDED.ciphertext,
+    key_id = EXCLUDED.key_id,
+    hint = EXCLUDED.hint,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteMonitorSecret :exec
+DELETE FROM monitor_secrets
+WHERE monitor_id = $1 AND name = $2;
+
+-- name: ListMonitorSecretHints :many
+SELECT name, hint, updated_at
+FROM monitor_secrets
+WHERE monitor_id = $1
+ORDER BY name;
+
+-- name: GetMonitorSecrets :many
+SELECT name, ciphertext
+FROM monitor_secrets
+WHERE monitor_id = $1;
+
+-- name: ListMonitorSecretsToRewrap :many
+-- Pages through secrets not sealed under the active key, after the given row
+SELECT monitor_id, name, ciphertext, key_id
+FROM monitor_secrets
+WHERE key_id <> sqlc.arg(active_key_id)::text
+  AND (monitor_id, name) > (sqlc.arg(after_monitor_id)::int, sqlc.arg(after_name)::text)
+ORDER BY monitor_id, name
+LIMIT sqlc.arg(batch_size)::int;
+
+-- name: RewrapMonitorSecret :execrows
+-- Only replaces the ciphertext if nobody rewrote the secret since it was read
+UPDATE monitor_secrets
+SET ciphertext = sqlc.arg(ciphertext), key_id = sqlc.arg(new_key_id)::text
+WHERE monitor_id = sqlc.arg(monitor_id)
+  AND name = sqlc.arg(name)
+  AND key_id = sqlc.arg(old_key_id)::text;
+
+-- name: ListSecretHintsForMonitors :many
+SELECT monitor_id, name, hint, updated_at
+FROM monitor_secrets
+WHERE monitor_id = ANY(sqlc.arg(monitor_ids)::int[])
+ORDER BY monitor_id, name;
@@ -0,0 +1,100 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (name, created_by)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetOrganization :one
+SELECT * FROM organizations
+WHERE id = $1;
+
+-- name: UpdateOrganization :one
+UPDATE organizations
+SET name = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: LockUserOrganizations :exec
+-- Serializes creating a user's personal organization within a transaction
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(user_id)::uuid::text, 0));
+
+-- name: ListUserOrganizations :many
+SELECT o.id, o.name, om.role, om.created_at AS joined_at
+FROM org_memberships om
+JOIN organizations o ON o.id = om.org_id
+WHERE om.user_id = $1
+ORDER BY om.created_at, o.id;
+
+-- name: AddOrgMember :one
+-- Existing members keep their role
+INSERT INTO org_memberships (org_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (org_id, user_id) DO UPDATE SET role = org_memberships.role
+RETURNING *;
+
+-- name: GetOrgMembership :one
+SELECT * FROM org_memberships
+WHERE org_id = $1 AND user_id = $2;
+
+-- name: GetDefaultOrgMembership :one
+SELECT * FROM org_memberships
+WHERE user_id = $1
+ORDER BY created_at, org_id
+LIMIT 1;
+
+-- name: ListOrgMembers :many
+SELECT om.user_id, u.email, u.fullname, om.role, om.created_at AS joined_at
+FROM org_memberships om
+JOIN users u ON u.id = om.user_id
+WHERE om.org_id = $1
+ORDER BY om.created_at, u.email;
+
+-- name: UpdateOrgMemberRole :one
+UPDATE org_memberships
+SET role = $3
+WHERE org_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteOrgMember :execrows
+DELETE FROM org_memberships
+WHERE org_id = $1 AND user_id = $2;
+
+-- name: LockOrganization :exec
+-- Serializes membership changes that must keep an owner within a transaction
+SELECT id FROM organizations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CountOrgOwners :one
+SELECT COUNT(*)::bigint FROM org_memberships
+WHERE org_id = $1 AND role = 'owner';
+
+-- name: CreateOrgInvite :one
+-- Re-inviting an address replaces its pending invite and token
+INSERT INTO org_invites (org_id, email, role, token, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (org_id, email) WHERE accepted_at IS NULL
+DO UPDATE SET
+    role = EXCLUDED.role,
+    token = EXCLUDED.token,
+    invited_by = EXCLUDED.invited_by,
+    expires_at = EXCLUDED.expires_at,
+    created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListPendingOrgInvites :many
+SELECT id, org_id, email, role, invited_by, expires_at, created_at FROM org_invites
+WHERE org_id = $1 AND accepted_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: DeleteOrgInvite :execrows
+DELETE FROM org_invites
+WHERE id = $1 AND org_id = $2 AND accepted_at IS NULL;
+
+-- name: GetPendingOrgInviteByToken :one
+SELECT * FROM org_invites
+WHERE token = $1 AND accepted_at IS NULL AND expires_at > NOW();
+
+-- name: MarkOrgInviteAccepted :exec
+UPDATE org_invites
+SET accepted_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,24 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetRefreshTokenByHash :one
+SELECT * FROM refresh_tokens
+WHERE token_hash = $1;
+
+-- name: RevokeRefreshToken :execrows
+-- Affects no rows when the token was already used, so concurrent refreshes
+-- with the same token can't both succeed
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: DeleteExpiredRefreshTokens :execrows
+DELETE FROM refresh_tokens
+WHERE expires_at < NOW() - INTERVAL '1 day';
@@ -0,0 +1,20 @@
+-- name: GetIncidentStartsForMonitor :many
+SELECT started_at
+FROM incidents
+WHERE monitor_id = @monitor_id
+  AND started_at >= @period_start::timestamp
+  AND started_at < @period_end::timestamp
+ORDER BY started_at;
+
+-- name: GetMaintenanceWindowsForMonitor :many
+-- Maintenance announced on any status page that shows the monitor
+SELECT DISTINCT
+    COALESCE(n.scheduled_start, n.created_at)::timestamp AS window_start,
+    COALESCE(n.resolved_at, n.scheduled_end, NOW())::timestamp AS window_end
+FROM status_page_notices n
+JOIN status_page_monitors spm ON spm.status_page_id = n.status_page_id
+WHERE spm.monitor_id = @monitor_id
+  AND n.kind = 'maintenance'
+  AND COALESCE(n.scheduled_start, n.created_at) < @period_end::timestamp
+  AND COALESCE(n.resolved_at, n.scheduled_end, NOW()) > @period_start::timestamp
+ORDER BY window_start;
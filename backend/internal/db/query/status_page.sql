@@ -0,0 +1,105 @@
+-- name: CreateStatusPage :one
+INSERT INTO status_pages (user_id, org_id, slug, title, description, custom_domain, is_published)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetStatusPagesByOrg :many
+SELECT * FROM status_pages
+WHERE org_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetStatusPageByID :one
+SELECT * FROM status_pages
+WHERE id = $1 AND org_id = $2;
+
+-- name: GetPublishedStatusPageBySlug :one
+SELECT * FROM status_pages
+WHERE slug = $1 AND is_published = true;
+
+-- name: GetPublishedStatusPageByDomain :one
+SELECT * FROM status_pages
+WHERE custom_domain = $1 AND is_published = true;
+
+-- name: UpdateStatusPage :one
+UPDATE status_pages
+SET
+    slug = $3,
+    title = $4,
+    description = $5,
+    custom_domain = $6,
+    is_published = $7,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2
+RETURNING *;
+
+-- name: DeleteStatusPage :exec
+DELETE FROM status_pages
+WHERE id = $1 AND org_id = $2;
+
+-- name: DeleteStatusPageSections :exec
+DELETE FROM status_page_sections
+WHERE status_page_id = $1;
+
+-- name: CreateStatusPageSection :one
+INSERT INTO status_page_sections (status_page_id, name, position)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: AddStatusPageMonitor :one
+INSERT INTO status_page_monitors (status_page_id, section_id, monitor_id, display_name, position)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetStatusPageSections :many
+SELECT * FROM status_page_sections
+WHERE status_page_id = $1
+ORDER BY position, id;
+
+-- name: GetStatusPageMonitors :many
+SELECT
+    spm.id,
+    spm.section_id,
+    spm.monitor_id,
+    spm.display_name,
+    spm.position,
+    m.status,
+    m.is_active
+FROM status_page_monitors spm
+JOIN monitors m ON m.id = spm.monitor_id
+WHERE spm.status_page_id = $1
+ORDER BY spm.position, spm.id;
+
+-- name: GetDailyUptimeForMonitors :many
+-- Past days come from the daily rollups, which outlive the raw logs; today is
+-- counted from the raw logs so the last bar does not lag the hourly rollup.
+SELECT
+    monitor_id,
+    bucket_start::date AS day,
+    check_count::bigint AS total_checks,
+    success_count::bigint AS successful_checks
+FROM monitor_rollups
+WHERE monitor_id = ANY(@monitor_ids::int[])
+  AND resolution = '1d'
+  AND bucket_start >= @since::timestamp
+  AND bucket_start < @today::timestamp
+UNION ALL
+SELECT
+    monitor_id,
+    date_trunc('day', checked_at)::date AS day,
+    COUNT(*)::bigint AS total_checks,
+    SUM(CASE WHEN content_ok IS NOT FALSE AND status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END)::bigint AS successful_checks
+FROM monitor_logs
+WHERE monitor_id = ANY(@monitor_ids::int[])
+  AND checked_at >= @today::timestamp
+GROUP BY monitor_id, date_trunc('day', checked_at)
+ORDER BY monitor_id, day;
+
+-- name: SetStatusPageExternalID :exec
+UPDATE status_pages SET external_id = $3
+WHERE id = $1 AND org_id = $2;
+
+-- name: ListOrgStatusPagesPage :many
+SELECT * FROM status_pages
+WHERE org_id = sqlc.arg(org_id) AND id > sqlc.arg(after_id)::int
+ORDER BY id
+LIMIT sqlc.arg(page_size)::int;
@@ -0,0 +1,38 @@
+-- name: CreateStatusPageNotice :one
+INSERT INTO status_page_notices (status_page_id, kind, title, status, scheduled_start, scheduled_end)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetStatusPageNotices :many
+SELECT * FROM status_page_notices
+WHERE status_page_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: GetStatusPageNoticeByID :one
+SELECT * FROM status_page_notices
+WHERE id = $1 AND status_page_id = $2;
+
+-- name: GetActiveStatusPageNotices :many
+SELECT * FROM status_page_notices
+WHERE status_page_id = $1 AND resolved_at IS NULL
+ORDER BY COALESCE(scheduled_start, created_at);
+
+-- name: UpdateStatusPageNoticeStatus :one
+UPDATE status_page_notices
+SET
+    status = @status,
+    resolved_at = CASE WHEN @resolved::bool THEN NOW() ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: CreateStatusPageNoticeUpdate :one
+INSERT INTO status_page_notice_updates (notice_id, status, message)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetStatusPageNoticeUpdates :many
+SELECT * FROM status_page_notice_updates
+WHERE notice_id = ANY(@notice_ids::int[])
+ORDER BY created_at DESC;
@@ -0,0 +1,28 @@
+-- name: CreateStatusPageSubscriber :one
+INSERT INTO status_page_subscribers (status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (status_page_id, kind, target)
+DO UPDATE SET
+    confirm_token = EXCLUDED.confirm_token,
+    confirm_expires_at = EXCLUDED.confirm_expires_at
+RETURNING *;
+
+-- name: ConfirmStatusPageSubscriber :one
+UPDATE status_page_subscribers
+SET confirmed_at = COALESCE(confirmed_at, NOW())
+WHERE confirm_token = $1 AND (confirmed_at IS NOT NULL OR confirm_expires_at > NOW())
+RETURNING *;
+
+-- name: DeleteStatusPageSubscriberByToken :one
+DELETE FROM status_page_subscribers
+WHERE unsubscribe_token = $1
+RETURNING *;
+
+-- name: GetStatusPageSubscribers :many
+SELECT * FROM status_page_subscribers
+WHERE status_page_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetConfirmedStatusPageSubscribers :many
+SELECT * FROM status_page_subscribers
+WHERE status_page_id = $1 AND confirmed_at IS NOT NULL;
@@ -1,23 +1,52 @@
--- name: CreateSubscription :one
-INSERT INTO subscriptions (user_id, stripe_plan, is_active)
-VALUES ($1, $2, $3)
-RETURNING *;
+-- name: GetOrgSubscription :one
+SELECT * FROM subscriptions
+WHERE org_id = $1 AND is_active;
+
+-- name: GetSubscriptionByOrg :one
+-- Includes inactive subscriptions, to reuse their Stripe customer
+SELECT * FROM subscriptions
+WHERE org_id = $1;
 
--- name: GetUserSubscription :one
-SELECT * FROM subscriptions 
-WHERE user_id = $1 
-ORDER BY created_at DESC 
-LIMIT 1;
+-- name: UpsertOrgSubscription :one
+-- Returns no row when the subscription was last updated by a newer event
+INSERT INTO subscriptions (
+    user_id, org_id, plan, stripe_customer_id, stripe_subscription_id,
+    status, current_period_end, is_active, stripe_event_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (org_id) DO UPDATE SET
+    user_id = COALESCE(EXCLUDED.user_id, subscriptions.user_id),
+    plan = EXCLUDED.plan,
+    stripe_customer_id = COALESCE(EXCLUDED.stripe_customer_id, subscriptions.stripe_customer_id),
+    stripe_subscription_id = COALESCE(EXCLUDED.stripe_subscription_id, subscriptions.stripe_subscription_id),
+    status = EXCLUDED.status,
+    current_period_end = COALESCE(EXCLUDED.current_period_end, subscriptions.current_period_end),
+    is_active = EXCLUDED.is_active,
+    stripe_event_at = EXCLUDED.stripe_event_at,
+    updated_at = now()
+WHERE subscriptions.stripe_event_at IS NULL OR subscriptions.stripe_event_at <= EXCLUDED.stripe_event_at
+RETURNING *;
 
--- name: UpdateSubscription :one
-UPDATE subscriptions 
-SET 
-    stripe_plan = COALESCE($2, stripe_plan),
-    is_active = COALESCE($3, is_active)
-WHERE user_id = $1
+-- name: UpdateSubscriptionByStripeID :one
+-- Returns no row when the subscription is unknown or was last updated by a
+-- newer event
+UPDATE subscriptions
+SET plan = $2,
+    status = $3,
+    current_period_end = $4,
+    is_active = $5,
+    stripe_event_at = $6,
+    updated_at = now()
+WHERE stripe_subscription_id = $1
+  AND (stripe_event_at IS NULL OR stripe_event_at <= $6)
 RETURNING *;
 
--- name: DeactivateSubscription :exec
-UPDATE subscriptions 
-SET is_active = false 
-WHERE user_id = $1;
\ No newline at end of file
+-- name: CountOrgMonitors :one
+SELECT COUNT(*) FROM monitors WHERE org_id = $1;
+
+-- name: CountOrgAlertContacts :one
+SELECT COUNT(*) FROM alert_contacts WHERE org_id = $1;
+
+-- name: CountOrgStatusPages :one
+SELECT COUNT(*) FROM status_pages WHERE org_id = $1;
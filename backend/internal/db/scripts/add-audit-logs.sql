@@ -0,0 +1,35 @@
+-- One-off migration of an existing database for the audit log.
+-- Fresh databases get it from migration/schema.sql directly.
+
+BEGIN;
+
+CREATE TABLE audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    org_id UUID NOT NULL,
+    user_id UUID,
+    actor_email TEXT NOT NULL,
+    api_key_id INT,
+    action TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    ip TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only
+BEFORE UPDATE OR DELETE ON audit_logs
+FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+CREATE INDEX idx_audit_logs_org_created ON audit_logs(org_id, created_at DESC);
+CREATE INDEX idx_audit_logs_resource ON audit_logs(org_id, resource_type, resource_id);
+
+COMMIT;
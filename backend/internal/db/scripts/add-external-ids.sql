@@ -0,0 +1,18 @@
+-- One-off migration of an existing database for the config document
+-- (/v1/manifest). Fresh databases get these columns from migration/schema.sql.
+--
+-- Resources keep a NULL external_id until a document adopts them.
+
+BEGIN;
+
+ALTER TABLE monitor_groups ADD COLUMN external_id TEXT;
+ALTER TABLE monitors ADD COLUMN external_id TEXT;
+ALTER TABLE alert_contacts ADD COLUMN external_id TEXT;
+ALTER TABLE status_pages ADD COLUMN external_id TEXT;
+
+CREATE UNIQUE INDEX idx_monitor_groups_external_id ON monitor_groups(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_monitors_external_id ON monitors(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_alert_contacts_external_id ON alert_contacts(org_id, external_id) WHERE external_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_status_pages_external_id ON status_pages(org_id, external_id) WHERE external_id IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- One-off migration of an existing database for monitor groups and tags.
+-- Fresh databases get them from migration/schema.sql directly.
+
+BEGIN;
+
+CREATE TABLE monitor_groups (
+    id SERIAL PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    rollup TEXT NOT NULL DEFAULT 'worst',
+    min_up_percent INTEGER NOT NULL DEFAULT 100,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (org_id, name),
+    CHECK (rollup IN ('worst', 'percentage')),
+    CHECK (min_up_percent BETWEEN 1 AND 100)
+);
+
+ALTER TABLE monitors
+    ADD COLUMN group_id INTEGER REFERENCES monitor_groups(id) ON DELETE SET NULL,
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_monitors_group ON monitors(group_id);
+CREATE INDEX idx_monitors_tags ON monitors USING GIN (tags);
+
+COMMIT;
@@ -0,0 +1,76 @@
+-- One-off migration of an existing database to organization ownership.
+-- Fresh databases get these tables and columns from migration/schema.sql directly.
+--
+-- Every user gets a personal organization they own, and their monitors, alert
+-- contacts and status pages move into it. Teammates can then be invited there,
+-- or resources recreated in a shared organization.
+
+BEGIN;
+
+CREATE TYPE org_role AS ENUM (
+    'owner',
+    'admin',
+    'editor',
+    'viewer'
+);
+
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE org_memberships (
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role org_role NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE TABLE org_invites (
+    id SERIAL PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role org_role NOT NULL,
+    token TEXT UNIQUE NOT NULL,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The organization id is derived from nothing else, so remember it per user
+CREATE TEMPORARY TABLE personal_orgs ON COMMIT DROP AS
+SELECT id AS user_id, gen_random_uuid() AS org_id, fullname
+FROM users;
+
+INSERT INTO organizations (id, name, created_by)
+SELECT org_id, fullname || '''s workspace', user_id FROM personal_orgs;
+
+INSERT INTO org_memberships (org_id, user_id, role)
+SELECT org_id, user_id, 'owner' FROM personal_orgs;
+
+ALTER TABLE monitors ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE alert_contacts ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE status_pages ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+
+UPDATE monitors m SET org_id = p.org_id FROM personal_orgs p WHERE m.user_id = p.user_id;
+UPDATE alert_contacts c SET org_id = p.org_id FROM personal_orgs p WHERE c.user_id = p.user_id;
+UPDATE status_pages s SET org_id = p.org_id FROM personal_orgs p WHERE s.user_id = p.user_id;
+
+-- Rows without a user make this fail; they were unreachable through the API,
+-- so assign or delete them and run the script again
+ALTER TABLE monitors ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE alert_contacts ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE status_pages ALTER COLUMN org_id SET NOT NULL;
+
+CREATE INDEX idx_monitors_org_id ON monitors(org_id);
+CREATE INDEX idx_alert_contacts_org_id ON alert_contacts(org_id);
+CREATE INDEX idx_status_pages_org_id ON status_pages(org_id);
+CREATE INDEX idx_org_memberships_user ON org_memberships(user_id);
+CREATE UNIQUE INDEX idx_org_invites_pending ON org_invites(org_id, email) WHERE accepted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,59 @@
+-- One-off migration of an existing database to per-organization plans.
+-- Fresh databases get these columns from migration/schema.sql directly.
+--
+-- Subscriptions move from users to their personal organization. Premium users
+-- without a subscription row get the business plan, which keeps their logs as
+-- long as premium did; everyone else falls back to DEFAULT_PLAN.
+
+BEGIN;
+
+ALTER TABLE subscriptions RENAME COLUMN stripe_plan TO plan;
+
+ALTER TABLE subscriptions
+    ADD COLUMN org_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    ADD COLUMN stripe_customer_id TEXT,
+    ADD COLUMN stripe_subscription_id TEXT UNIQUE,
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active',
+    ADD COLUMN current_period_end TIMESTAMP,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT now();
+
+-- A user's personal organization is the first one they created
+CREATE TEMPORARY TABLE personal_orgs ON COMMIT DROP AS
+SELECT DISTINCT ON (created_by) created_by AS user_id, id AS org_id
+FROM organizations
+WHERE created_by IS NOT NULL
+ORDER BY created_by, created_at, id;
+
+UPDATE subscriptions s
+SET org_id = p.org_id
+FROM personal_orgs p
+WHERE p.user_id = s.user_id;
+
+-- Keep the latest subscription of each organization
+DELETE FROM subscriptions s
+WHERE s.org_id IS NULL
+   OR EXISTS (
+       SELECT 1 FROM subscriptions newer
+       WHERE newer.org_id = s.org_id AND newer.id > s.id
+   );
+
+-- Plan names before this migration were Stripe's, and all of them premium
+UPDATE subscriptions
+SET plan = 'business'
+WHERE plan IS NULL OR plan NOT IN ('free', 'pro', 'business', 'unlimited');
+
+UPDATE subscriptions SET is_active = FALSE WHERE is_active IS NULL;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN org_id SET NOT NULL,
+    ADD CONSTRAINT subscriptions_org_id_key UNIQUE (org_id),
+    ALTER COLUMN plan SET NOT NULL,
+    ALTER COLUMN is_active SET NOT NULL;
+
+INSERT INTO subscriptions (user_id, org_id, plan, status, is_active)
+SELECT p.user_id, p.org_id, 'business', 'active', TRUE
+FROM personal_orgs p
+JOIN user_profile up ON up.user_id = p.user_id AND up.is_premium
+ON CONFLICT (org_id) DO NOTHING;
+
+COMMIT;
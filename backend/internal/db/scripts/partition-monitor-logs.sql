@@ -0,0 +1,123 @@
+-- One-off conversion of an existing, unpartitioned monitor_logs table.
+-- Fresh databases get the partitioned table from migration/schema.sql directly.
+-- Run it with psql, outside a transaction: psql -v ON_ERROR_STOP=1 -f ...
+--
+-- The old table is kept as monitor_logs_legacy and attached as the partition
+-- for everything before :legacy_bound, the start of the month after next, so
+-- no rows are copied. Rows keep landing in it until then and the retention
+-- worker creates the monthly partitions after it. Everything that scans the
+-- table (NULL backfill, unique index, constraint validation) runs first,
+-- without blocking checks; the swap itself only takes the exclusive lock for
+-- catalog changes. Once the legacy range is past every retention horizon run:
+--   SET lock_timeout = '5s';
+--   ALTER TABLE monitor_logs DETACH PARTITION monitor_logs_legacy;
+--   DROP TABLE monitor_logs_legacy;
+-- (DETACH ... CONCURRENTLY is not allowed while monitor_logs_default exists.)
+
+SELECT (date_trunc('month', now()) + interval '2 months')::timestamp AS legacy_bound \gset
+
+-- New columns are nullable without defaults, so adding them doesn't rewrite
+ALTER TABLE monitor_logs
+    ADD COLUMN IF NOT EXISTS screenshot_key TEXT,
+    ADD COLUMN IF NOT EXISTS dns_ms FLOAT,
+    ADD COLUMN IF NOT EXISTS connect_ms FLOAT,
+    ADD COLUMN IF NOT EXISTS tls_ms FLOAT,
+    ADD COLUMN IF NOT EXISTS ttfb_ms FLOAT,
+    ADD COLUMN IF NOT EXISTS transfer_ms FLOAT,
+    ADD COLUMN IF NOT EXISTS error_type TEXT,
+    ADD COLUMN IF NOT EXISTS error_message TEXT,
+    ADD COLUMN IF NOT EXISTS remote_ip TEXT,
+    ADD COLUMN IF NOT EXISTS response_headers JSONB,
+    ADD COLUMN IF NOT EXISTS response_body TEXT,
+    ADD COLUMN IF NOT EXISTS step_results JSONB,
+    ADD COLUMN IF NOT EXISTS browser_result JSONB;
+
+-- Rows without a time can't be placed by month; they are dated at the epoch
+-- so they age out with the legacy partition. checked_at defaults to now(), so
+-- no new ones appear. Batches keep each row lock short.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_monitor_logs_checked_at_null
+    ON monitor_logs(id) WHERE checked_at IS NULL;
+
+DO $$
+DECLARE
+    updated integer;
+BEGIN
+    LOOP
+        UPDATE monitor_logs SET checked_at = 'epoch'
+        WHERE id IN (SELECT id FROM monitor_logs WHERE checked_at IS NULL LIMIT 5000);
+        GET DIAGNOSTICS updated = ROW_COUNT;
+        EXIT WHEN updated = 0;
+        COMMIT;
+    END LOOP;
+END $$;
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_monitor_logs_checked_at_null;
+
+-- Backs the (id, checked_at) primary key the partition needs
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS monitor_logs_legacy_pkey
+    ON monitor_logs(id, checked_at);
+
+-- Matches the partition constraint, so neither SET NOT NULL nor ATTACH has to
+-- scan the table under the exclusive lock. VALIDATE doesn't block writes.
+ALTER TABLE monitor_logs ADD CONSTRAINT monitor_logs_legacy_range
+    CHECK (checked_at IS NOT NULL AND checked_at < :'legacy_bound') NOT VALID;
+ALTER TABLE monitor_logs VALIDATE CONSTRAINT monitor_logs_legacy_range;
+
+BEGIN;
+
+SET LOCAL lock_timeout = '5s';
+
+ALTER TABLE monitor_logs RENAME TO monitor_logs_legacy;
+ALTER SEQUENCE monitor_logs_id_seq RENAME TO monitor_logs_legacy_id_seq;
+ALTER INDEX IF EXISTS idx_monitor_logs_monitor_id RENAME TO idx_monitor_logs_legacy_monitor_id;
+ALTER INDEX IF EXISTS idx_monitor_logs_checked_at RENAME TO idx_monitor_logs_legacy_checked_at;
+
+ALTER TABLE monitor_logs_legacy ALTER COLUMN checked_at SET NOT NULL;
+ALTER TABLE monitor_logs_legacy DROP CONSTRAINT monitor_logs_pkey;
+ALTER TABLE monitor_logs_legacy ADD CONSTRAINT monitor_logs_legacy_pkey
+    PRIMARY KEY USING INDEX monitor_logs_legacy_pkey;
+
+CREATE TABLE monitor_logs (
+    id SERIAL,
+    monitor_id INTEGER REFERENCES monitors(id) on delete CASCADE,
+    status_code INTEGER,
+    response_time FLOAT,
+    dns_ok BOOLEAN,
+    ssl_ok BOOLEAN,
+    content_ok BOOLEAN,
+    screenshot_url TEXT,
+    -- Artifact storage key of the screenshot, signed into a URL on read
+    screenshot_key TEXT,
+    -- Per-phase timings in ms, NULL when the phase did not happen (e.g. reused connection)
+    dns_ms FLOAT,
+    connect_ms FLOAT,
+    tls_ms FLOAT,
+    ttfb_ms FLOAT,
+    transfer_ms FLOAT,
+    -- Failure evidence, only filled in when the check was down
+    error_type TEXT,
+    error_message TEXT,
+    remote_ip TEXT,
+    response_headers JSONB,
+    response_body TEXT,
+    -- Per-step results of multi-step monitors
+    step_results JSONB,
+    -- Page timings, console errors and failed requests of browser monitors
+    browser_result JSONB,
+    checked_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (id, checked_at)
+) PARTITION BY RANGE (checked_at);
+
+-- Keep ids unique across old and new rows; reads the primary key index
+SELECT setval('monitor_logs_id_seq', (SELECT COALESCE(MAX(id), 0) + 1 FROM monitor_logs_legacy));
+
+ALTER TABLE monitor_logs ATTACH PARTITION monitor_logs_legacy
+    FOR VALUES FROM (MINVALUE) TO (:'legacy_bound');
+
+ALTER TABLE monitor_logs_legacy DROP CONSTRAINT monitor_logs_legacy_range;
+
+-- The legacy table's matching indexes are attached rather than rebuilt
+CREATE INDEX IF NOT EXISTS idx_monitor_logs_monitor_id ON monitor_logs(monitor_id);
+CREATE INDEX IF NOT EXISTS idx_monitor_logs_checked_at ON monitor_logs(checked_at);
+
+COMMIT;
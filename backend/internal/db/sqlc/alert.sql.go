@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -82,14 +83,14 @@ const getRecentAlerts = `-- name: GetRecentAlerts :many
 SELECT a.id, a.monitor_id, a.alert_contact_id, a.alert_type, a.message, a.sent_at, a.created_at, m.url, m.user_id
 FROM alerts a
 JOIN monitors m ON a.monitor_id = m.id
-WHERE m.user_id = $1
+WHERE m.org_id = $1
 ORDER BY a.sent_at DESC 
 LIMIT $2
 `
 
 type GetRecentAlertsParams struct {
-	UserID pgtype.UUID `json:"user_id"`
-	Limit  int32       `json:"limit"`
+	OrgID uuid.UUID `json:"org_id"`
+	Limit int32     `json:"limit"`
 }
 
 type GetRecentAlertsRow struct {
@@ -105,7 +106,7 @@ type GetRecentAlertsRow struct {
 }
 
 func (q *Queries) GetRecentAlerts(ctx context.Context, arg GetRecentAlertsParams) ([]GetRecentAlertsRow, error) {
-	rows, err := q.db.Query(ctx, getRecentAlerts, arg.UserID, arg.Limit)
+	rows, err := q.db.Query(ctx, getRecentAlerts, arg.OrgID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -8,31 +8,40 @@ package db
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createAlertContact = `-- name: CreateAlertContact :one
-INSERT INTO alert_contacts (user_id, name, email)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, name, email, is_verified, created_at
+INSERT INTO alert_contacts (user_id, org_id, name, email)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, org_id, name, email, is_verified, created_at, external_id
 `
 
 type CreateAlertContactParams struct {
 	UserID pgtype.UUID `json:"user_id"`
+	OrgID  uuid.UUID   `json:"org_id"`
 	Name   string      `json:"name"`
 	Email  string      `json:"email"`
 }
 
 func (q *Queries) CreateAlertContact(ctx context.Context, arg CreateAlertContactParams) (AlertContact, error) {
-	row := q.db.QueryRow(ctx, createAlertContact, arg.UserID, arg.Name, arg.Email)
+	row := q.db.QueryRow(ctx, createAlertContact,
+		arg.UserID,
+		arg.OrgID,
+		arg.Name,
+		arg.Email,
+	)
 	var i AlertContact
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Name,
 		&i.Email,
 		&i.IsVerified,
 		&i.CreatedAt,
+		&i.ExternalID,
 	)
 	return i, err
 }
@@ -76,8 +85,74 @@ func (q *Queries) CreateMonitorAlertConfig(ctx context.Context, arg CreateMonito
 	return i, err
 }
 
+const deleteAlertContact = `-- name: DeleteAlertContact :exec
+DELETE FROM alert_contacts
+WHERE id = $1 AND org_id = $2
+`
+
+type DeleteAlertContactParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteAlertContact(ctx context.Context, arg DeleteAlertContactParams) error {
+	_, err := q.db.Exec(ctx, deleteAlertContact, arg.ID, arg.OrgID)
+	return err
+}
+
+const deleteMonitorAlertConfig = `-- name: DeleteMonitorAlertConfig :exec
+DELETE FROM monitor_alert_configs
+WHERE monitor_id = $1 AND alert_contact_id = $2
+`
+
+type DeleteMonitorAlertConfigParams struct {
+	MonitorID      pgtype.Int4 `json:"monitor_id"`
+	AlertContactID pgtype.Int4 `json:"alert_contact_id"`
+}
+
+func (q *Queries) DeleteMonitorAlertConfig(ctx context.Context, arg DeleteMonitorAlertConfigParams) error {
+	_, err := q.db.Exec(ctx, deleteMonitorAlertConfig, arg.MonitorID, arg.AlertContactID)
+	return err
+}
+
+const getAlertConfigsForMonitors = `-- name: GetAlertConfigsForMonitors :many
+SELECT id, monitor_id, alert_contact_id, alert_on_up, alert_on_down, alert_on_slow, slow_threshold_ms, is_active, created_at FROM monitor_alert_configs
+WHERE monitor_id = ANY($1::int[]) AND is_active = true
+ORDER BY monitor_id, alert_contact_id
+`
+
+func (q *Queries) GetAlertConfigsForMonitors(ctx context.Context, monitorIds []int32) ([]MonitorAlertConfig, error) {
+	rows, err := q.db.Query(ctx, getAlertConfigsForMonitors, monitorIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MonitorAlertConfig{}
+	for rows.Next() {
+		var i MonitorAlertConfig
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.AlertContactID,
+			&i.AlertOnUp,
+			&i.AlertOnDown,
+			&i.AlertOnSlow,
+			&i.SlowThresholdMs,
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAlertContactByID = `-- name: GetAlertContactByID :one
-SELECT id, user_id, name, email, is_verified, created_at FROM alert_contacts
+SELECT id, user_id, org_id, name, email, is_verified, created_at, external_id FROM alert_contacts
 WHERE id = $1
 `
 
@@ -87,16 +162,18 @@ func (q *Queries) GetAlertContactByID(ctx context.Context, id int32) (AlertConta
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Name,
 		&i.Email,
 		&i.IsVerified,
 		&i.CreatedAt,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
 const getAlertContactsByMonitor = `-- name: GetAlertContactsByMonitor :many
-SELECT ac.id, ac.user_id, ac.name, ac.email, ac.is_verified, ac.created_at FROM alert_contacts ac
+SELECT ac.id, ac.user_id, ac.org_id, ac.name, ac.email, ac.is_verified, ac.created_at, ac.external_id FROM alert_contacts ac
 JOIN monitor_alert_configs mac ON ac.id = mac.alert_contact_id
 WHERE mac.monitor_id = $1 AND mac.is_active = true
 `
@@ -113,10 +190,12 @@ func (q *Queries) GetAlertContactsByMonitor(ctx context.Context, monitorID pgtyp
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.OrgID,
 			&i.Name,
 			&i.Email,
 			&i.IsVerified,
 			&i.CreatedAt,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -128,14 +207,14 @@ func (q *Queries) GetAlertContactsByMonitor(ctx context.Context, monitorID pgtyp
 	return items, nil
 }
 
-const getAlertContactsByUserID = `-- name: GetAlertContactsByUserID :many
-SELECT id, user_id, name, email, is_verified, created_at FROM alert_contacts
-WHERE user_id = $1
+const getAlertContactsByOrg = `-- name: GetAlertContactsByOrg :many
+SELECT id, user_id, org_id, name, email, is_verified, created_at, external_id FROM alert_contacts
+WHERE org_id = $1
 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetAlertContactsByUserID(ctx context.Context, userID pgtype.UUID) ([]AlertContact, error) {
-	rows, err := q.db.Query(ctx, getAlertContactsByUserID, userID)
+func (q *Queries) GetAlertContactsByOrg(ctx context.Context, orgID uuid.UUID) ([]AlertContact, error) {
+	rows, err := q.db.Query(ctx, getAlertContactsByOrg, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -146,10 +225,12 @@ func (q *Queries) GetAlertContactsByUserID(ctx context.Context, userID pgtype.UU
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.OrgID,
 			&i.Name,
 			&i.Email,
 			&i.IsVerified,
 			&i.CreatedAt,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -196,6 +277,162 @@ func (q *Queries) GetMonitorAlertConfigs(ctx context.Context, monitorID pgtype.I
 	return items, nil
 }
 
+const getOrgAlertContact = `-- name: GetOrgAlertContact :one
+SELECT id, user_id, org_id, name, email, is_verified, created_at, external_id FROM alert_contacts
+WHERE id = $1 AND org_id = $2
+`
+
+type GetOrgAlertContactParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetOrgAlertContact(ctx context.Context, arg GetOrgAlertContactParams) (AlertContact, error) {
+	row := q.db.QueryRow(ctx, getOrgAlertContact, arg.ID, arg.OrgID)
+	var i AlertContact
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Name,
+		&i.Email,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getOrgMonitorAlertConfigs = `-- name: GetOrgMonitorAlertConfigs :many
+SELECT mac.id, mac.monitor_id, mac.alert_contact_id, mac.alert_on_up, mac.alert_on_down, mac.alert_on_slow, mac.slow_threshold_ms, mac.is_active, mac.created_at FROM monitor_alert_configs mac
+JOIN monitors m ON m.id = mac.monitor_id
+WHERE m.org_id = $1 AND mac.is_active = true
+ORDER BY mac.monitor_id, mac.alert_contact_id
+`
+
+func (q *Queries) GetOrgMonitorAlertConfigs(ctx context.Context, orgID uuid.UUID) ([]MonitorAlertConfig, error) {
+	rows, err := q.db.Query(ctx, getOrgMonitorAlertConfigs, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MonitorAlertConfig{}
+	for rows.Next() {
+		var i MonitorAlertConfig
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.AlertContactID,
+			&i.AlertOnUp,
+			&i.AlertOnDown,
+			&i.AlertOnSlow,
+			&i.SlowThresholdMs,
+			&i.IsActive,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrgAlertContactsPage = `-- name: ListOrgAlertContactsPage :many
+SELECT id, user_id, org_id, name, email, is_verified, created_at, external_id FROM alert_contacts
+WHERE org_id = $1 AND id > $2::int
+ORDER BY id
+LIMIT $3::int
+`
+
+type ListOrgAlertContactsPageParams struct {
+	OrgID    uuid.UUID `json:"org_id"`
+	AfterID  int32     `json:"after_id"`
+	PageSize int32     `json:"page_size"`
+}
+
+func (q *Queries) ListOrgAlertContactsPage(ctx context.Context, arg ListOrgAlertContactsPageParams) ([]AlertContact, error) {
+	rows, err := q.db.Query(ctx, listOrgAlertContactsPage, arg.OrgID, arg.AfterID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AlertContact{}
+	for rows.Next() {
+		var i AlertContact
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Name,
+			&i.Email,
+			&i.IsVerified,
+			&i.CreatedAt,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setAlertContactExternalID = `-- name: SetAlertContactExternalID :exec
+UPDATE alert_contacts SET external_id = $3
+WHERE id = $1 AND org_id = $2
+`
+
+type SetAlertContactExternalIDParams struct {
+	ID         int32       `json:"id"`
+	OrgID      uuid.UUID   `json:"org_id"`
+	ExternalID pgtype.Text `json:"external_id"`
+}
+
+func (q *Queries) SetAlertContactExternalID(ctx context.Context, arg SetAlertContactExternalIDParams) error {
+	_, err := q.db.Exec(ctx, setAlertContactExternalID, arg.ID, arg.OrgID, arg.ExternalID)
+	return err
+}
+
+const updateAlertContact = `-- name: UpdateAlertContact :one
+UPDATE alert_contacts
+SET name = $3, email = $4
+WHERE id = $1 AND org_id = $2
+RETURNING id, user_id, org_id, name, email, is_verified, created_at, external_id
+`
+
+type UpdateAlertContactParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) UpdateAlertContact(ctx context.Context, arg UpdateAlertContactParams) (AlertContact, error) {
+	row := q.db.QueryRow(ctx, updateAlertContact,
+		arg.ID,
+		arg.OrgID,
+		arg.Name,
+		arg.Email,
+	)
+	var i AlertContact
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Name,
+		&i.Email,
+		&i.IsVerified,
+		&i.CreatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
 const updateMonitorAlertConfig = `-- name: UpdateMonitorAlertConfig :one
 UPDATE monitor_alert_configs 
 SET alert_on_up = $2, alert_on_down = $3, alert_on_slow = $4, slow_threshold_ms = $5
@@ -233,3 +470,48 @@ func (q *Queries) UpdateMonitorAlertConfig(ctx context.Context, arg UpdateMonito
 	)
 	return i, err
 }
+
+const upsertMonitorAlertConfig = `-- name: UpsertMonitorAlertConfig :one
+INSERT INTO monitor_alert_configs (monitor_id, alert_contact_id, alert_on_up, alert_on_down, alert_on_slow, slow_threshold_ms, is_active)
+VALUES ($1, $2, $3, $4, $5, $6, true)
+ON CONFLICT (monitor_id, alert_contact_id) DO UPDATE SET
+    alert_on_up = EXCLUDED.alert_on_up,
+    alert_on_down = EXCLUDED.alert_on_down,
+    alert_on_slow = EXCLUDED.alert_on_slow,
+    slow_threshold_ms = EXCLUDED.slow_threshold_ms,
+    is_active = true
+RETURNING id, monitor_id, alert_contact_id, alert_on_up, alert_on_down, alert_on_slow, slow_threshold_ms, is_active, created_at
+`
+
+type UpsertMonitorAlertConfigParams struct {
+	MonitorID       pgtype.Int4 `json:"monitor_id"`
+	AlertContactID  pgtype.Int4 `json:"alert_contact_id"`
+	AlertOnUp       pgtype.Bool `json:"alert_on_up"`
+	AlertOnDown     pgtype.Bool `json:"alert_on_down"`
+	AlertOnSlow     pgtype.Bool `json:"alert_on_slow"`
+	SlowThresholdMs pgtype.Int4 `json:"slow_threshold_ms"`
+}
+
+func (q *Queries) UpsertMonitorAlertConfig(ctx context.Context, arg UpsertMonitorAlertConfigParams) (MonitorAlertConfig, error) {
+	row := q.db.QueryRow(ctx, upsertMonitorAlertConfig,
+		arg.MonitorID,
+		arg.AlertContactID,
+		arg.AlertOnUp,
+		arg.AlertOnDown,
+		arg.AlertOnSlow,
+		arg.SlowThresholdMs,
+	)
+	var i MonitorAlertConfig
+	err := row.Scan(
+		&i.ID,
+		&i.MonitorID,
+		&i.AlertContactID,
+		&i.AlertOnUp,
+		&i.AlertOnDown,
+		&i.AlertOnSlow,
+		&i.SlowThresholdMs,
+		&i.IsActive,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -14,9 +14,9 @@ import (
 const calculateUptimePercentage = `-- name: CalculateUptimePercentage :one
 SELECT 
     COUNT(*) as total_checks,
-    SUM(CASE WHEN status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END) as successful_checks,
+    SUM(CASE WHEN content_ok IS NOT FALSE AND status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END) as successful_checks,
     ROUND(
-        (SUM(CASE WHEN status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END) * 100.0 / COUNT(*))::numeric, 
+        (SUM(CASE WHEN content_ok IS NOT FALSE AND status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END) * 100.0 / COUNT(*))::numeric, 
     2) as uptime_percentage
 FROM monitor_logs 
 WHERE monitor_id = $1 
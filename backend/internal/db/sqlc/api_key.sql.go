@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: api_key.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, org_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, org_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at
+`
+
+type CreateAPIKeyParams struct {
+	UserID    uuid.UUID        `json:"user_id"`
+	OrgID     pgtype.UUID      `json:"org_id"`
+	Name      string           `json:"name"`
+	Prefix    string           `json:"prefix"`
+	KeyHash   string           `json:"key_hash"`
+	Scopes    []string         `json:"scopes"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.UserID,
+		arg.OrgID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAPIKey = `-- name: GetAPIKey :one
+SELECT id, user_id, org_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM api_keys
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) GetAPIKey(ctx context.Context, id int32) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getAPIKey, id)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT id, user_id, org_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW())
+`
+
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getActiveAPIKeyByHash, keyHash)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, user_id, org_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM api_keys
+WHERE revoked_at IS NULL
+  AND (user_id = $1 OR ($2::boolean AND org_id = $3))
+ORDER BY created_at DESC
+`
+
+type ListAPIKeysParams struct {
+	UserID     uuid.UUID   `json:"user_id"`
+	IncludeOrg bool        `json:"include_org"`
+	OrgID      pgtype.UUID `json:"org_id"`
+}
+
+// The user's own keys and, for admins, the org-scoped keys of their organization
+func (q *Queries) ListAPIKeys(ctx context.Context, arg ListAPIKeysParams) ([]ApiKey, error) {
+	rows, err := q.db.Query(ctx, listAPIKeys, arg.UserID, arg.IncludeOrg, arg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKey{}
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Name,
+			&i.Prefix,
+			&i.KeyHash,
+			&i.Scopes,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :exec
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, revokeAPIKey, id)
+	return err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+// Updated at most once a minute so busy keys don't write on every request
+func (q *Queries) TouchAPIKey(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, id)
+	return err
+}
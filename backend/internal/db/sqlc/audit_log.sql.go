@@ -0,0 +1,127 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: audit_log.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuditLog = `-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (
+    org_id, user_id, actor_email, api_key_id, action,
+    resource_type, resource_id, before, after, ip, user_agent
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+`
+
+type CreateAuditLogParams struct {
+	OrgID        uuid.UUID       `json:"org_id"`
+	UserID       pgtype.UUID     `json:"user_id"`
+	ActorEmail   string          `json:"actor_email"`
+	ApiKeyID     pgtype.Int4     `json:"api_key_id"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	Ip           string          `json:"ip"`
+	UserAgent    string          `json:"user_agent"`
+}
+
+func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error {
+	_, err := q.db.Exec(ctx, createAuditLog,
+		arg.OrgID,
+		arg.UserID,
+		arg.ActorEmail,
+		arg.ApiKeyID,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Before,
+		arg.After,
+		arg.Ip,
+		arg.UserAgent,
+	)
+	return err
+}
+
+const listAuditLogs = `-- name: ListAuditLogs :many
+SELECT id, org_id, user_id, actor_email, api_key_id, action, resource_type, resource_id, before, after, ip, user_agent, created_at FROM audit_logs
+WHERE org_id = $1
+  AND ($2::text IS NULL OR resource_type = $2)
+  AND ($3::text IS NULL OR resource_id = $3)
+  AND ($4::text IS NULL OR action = $4)
+  AND ($5::uuid IS NULL OR user_id = $5)
+  AND ($6::int IS NULL OR api_key_id = $6)
+  AND ($7::timestamp IS NULL OR created_at >= $7)
+  AND ($8::timestamp IS NULL OR created_at <= $8)
+ORDER BY created_at DESC, id DESC
+LIMIT $10 OFFSET $9
+`
+
+type ListAuditLogsParams struct {
+	OrgID        uuid.UUID        `json:"org_id"`
+	ResourceType pgtype.Text      `json:"resource_type"`
+	ResourceID   pgtype.Text      `json:"resource_id"`
+	Action       pgtype.Text      `json:"action"`
+	UserID       pgtype.UUID      `json:"user_id"`
+	ApiKeyID     pgtype.Int4      `json:"api_key_id"`
+	FromTime     pgtype.Timestamp `json:"from_time"`
+	ToTime       pgtype.Timestamp `json:"to_time"`
+	RowOffset    int32            `json:"row_offset"`
+	RowLimit     int32            `json:"row_limit"`
+}
+
+// Newest first; every filter but org_id is optional
+func (q *Queries) ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, listAuditLogs,
+		arg.OrgID,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Action,
+		arg.UserID,
+		arg.ApiKeyID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditLog{}
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrgID,
+			&i.UserID,
+			&i.ActorEmail,
+			&i.ApiKeyID,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Before,
+			&i.After,
+			&i.Ip,
+			&i.UserAgent,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -12,8 +12,59 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createLocalUser = `-- name: CreateLocalUser :one
+INSERT INTO users (email, fullname, provider, password_hash, email_verification_hash, email_verification_expires_at)
+VALUES ($1, $2, 'password', $3, $4, $5)
+ON CONFLICT (email) DO UPDATE SET
+    fullname = EXCLUDED.fullname,
+    password_hash = EXCLUDED.password_hash,
+    email_verification_hash = EXCLUDED.email_verification_hash,
+    email_verification_expires_at = EXCLUDED.email_verification_expires_at,
+    updated_at = NOW()
+WHERE NOT users.email_verified
+RETURNING id, phone, email, fullname, provider, password_hash, email_verified, email_verification_hash, email_verification_expires_at, role, profile_status, created_at, updated_at
+`
+
+type CreateLocalUserParams struct {
+	Email                      string           `json:"email"`
+	Fullname                   string           `json:"fullname"`
+	PasswordHash               pgtype.Text      `json:"password_hash"`
+	EmailVerificationHash      pgtype.Text      `json:"email_verification_hash"`
+	EmailVerificationExpiresAt pgtype.Timestamp `json:"email_verification_expires_at"`
+}
+
+// Returns no row when a verified account has the email, whichever provider it
+// signed up with. An unverified sign-up is replaced, so whoever owns the
+// address can always register it.
+func (q *Queries) CreateLocalUser(ctx context.Context, arg CreateLocalUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createLocalUser,
+		arg.Email,
+		arg.Fullname,
+		arg.PasswordHash,
+		arg.EmailVerificationHash,
+		arg.EmailVerificationExpiresAt,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Phone,
+		&i.Email,
+		&i.Fullname,
+		&i.Provider,
+		&i.PasswordHash,
+		&i.EmailVerified,
+		&i.EmailVerificationHash,
+		&i.EmailVerificationExpiresAt,
+		&i.Role,
+		&i.ProfileStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, phone, email, fullname, provider, password_hash, role, profile_status, created_at, updated_at
+INSERT INTO users (email, password_hash) VALUES ($1, $2) RETURNING id, phone, email, fullname, provider, password_hash, email_verified, email_verification_hash, email_verification_expires_at, role, profile_status, created_at, updated_at
 `
 
 type CreateUserParams struct {
@@ -31,6 +82,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Fullname,
 		&i.Provider,
 		&i.PasswordHash,
+		&i.EmailVerified,
+		&i.EmailVerificationHash,
+		&i.EmailVerificationExpiresAt,
 		&i.Role,
 		&i.ProfileStatus,
 		&i.CreatedAt,
@@ -40,16 +94,28 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 }
 
 const findOrCreateUser = `-- name: FindOrCreateUser :one
-INSERT INTO users (id, email, provider, phone, fullname, password_hash, created_at, updated_at)
-VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, NOW(), NOW())
+WITH unverified AS (
+    UPDATE refresh_tokens
+    SET revoked_at = NOW()
+    WHERE revoked_at IS NULL
+      AND user_id IN (SELECT id FROM users WHERE email = $1 AND NOT email_verified)
+)
+INSERT INTO users (id, email, provider, phone, fullname, password_hash, email_verified, created_at, updated_at)
+VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, TRUE, NOW(), NOW())
 ON CONFLICT (email)
 DO UPDATE SET
     phone = COALESCE(NULLIF(EXCLUDED.phone, ''), users.phone),
     provider = EXCLUDED.provider,
     fullname = COALESCE(NULLIF(EXCLUDED.fullname, ''), users.fullname),
-    password_hash = EXCLUDED.password_hash,
+    password_hash = CASE
+        WHEN users.email_verified THEN COALESCE(EXCLUDED.password_hash, users.password_hash)
+        ELSE EXCLUDED.password_hash
+    END,
+    email_verified = TRUE,
+    email_verification_hash = NULL,
+    email_verification_expires_at = NULL,
     updated_at = NOW()
-RETURNING id, phone, email, fullname, provider, password_hash, role, profile_status, created_at, updated_at
+RETURNING id, phone, email, fullname, provider, password_hash, email_verified, email_verification_hash, email_verification_expires_at, role, profile_status, created_at, updated_at
 `
 
 type FindOrCreateUserParams struct {
@@ -60,6 +126,9 @@ type FindOrCreateUserParams struct {
 	PasswordHash pgtype.Text `json:"password_hash"`
 }
 
+// For identities verified by their provider. An unverified local sign-up with
+// the same email was made by whoever chose its password, so its password and
+// sessions are dropped as the verified identity claims it.
 func (q *Queries) FindOrCreateUser(ctx context.Context, arg FindOrCreateUserParams) (User, error) {
 	row := q.db.QueryRow(ctx, findOrCreateUser,
 		arg.Email,
@@ -76,6 +145,9 @@ func (q *Queries) FindOrCreateUser(ctx context.Context, arg FindOrCreateUserPara
 		&i.Fullname,
 		&i.Provider,
 		&i.PasswordHash,
+		&i.EmailVerified,
+		&i.EmailVerificationHash,
+		&i.EmailVerificationExpiresAt,
 		&i.Role,
 		&i.ProfileStatus,
 		&i.CreatedAt,
@@ -85,7 +157,7 @@ func (q *Queries) FindOrCreateUser(ctx context.Context, arg FindOrCreateUserPara
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, phone, email, fullname, provider, password_hash, role, profile_status, created_at, updated_at FROM users WHERE email = $1
+SELECT id, phone, email, fullname, provider, password_hash, email_verified, email_verification_hash, email_verification_expires_at, role, profile_status, created_at, updated_at FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -98,6 +170,9 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Fullname,
 		&i.Provider,
 		&i.PasswordHash,
+		&i.EmailVerified,
+		&i.EmailVerificationHash,
+		&i.EmailVerificationExpiresAt,
 		&i.Role,
 		&i.ProfileStatus,
 		&i.CreatedAt,
@@ -107,31 +182,34 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT u.id, phone, email, fullname, provider, password_hash, role, profile_status, u.created_at, u.updated_at, up.id, user_id, is_premium, stripe_id, name, bio, up.created_at, up.updated_at FROM 
+SELECT u.id, phone, email, fullname, provider, password_hash, email_verified, email_verification_hash, email_verification_expires_at, role, profile_status, u.created_at, u.updated_at, up.id, user_id, is_premium, stripe_id, name, bio, up.created_at, up.updated_at FROM 
 users u
 LEFT JOIN user_profile up ON u.id = up.user_id 
 WHERE u.id = $1
 `
 
 type GetUserByIDRow struct {
-	ID            uuid.UUID          `json:"id"`
-	Phone         pgtype.Text        `json:"phone"`
-	Email         string             `json:"email"`
-	Fullname      string             `json:"fullname"`
-	Provider      Provider           `json:"provider"`
-	PasswordHash  pgtype.Text        `json:"password_hash"`
-	Role          UserRole           `json:"role"`
-	ProfileStatus NullProfileStatus  `json:"profile_status"`
-	CreatedAt     pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
-	ID_2          pgtype.Int4        `json:"id_2"`
-	UserID        pgtype.UUID        `json:"user_id"`
-	IsPremium     pgtype.Bool        `json:"is_premium"`
-	StripeID      pgtype.Text        `json:"stripe_id"`
-	Name          pgtype.Text        `json:"name"`
-	Bio           pgtype.Text        `json:"bio"`
-	CreatedAt_2   pgtype.Timestamp   `json:"created_at_2"`
-	UpdatedAt_2   pgtype.Timestamp   `json:"updated_at_2"`
+	ID                         uuid.UUID          `json:"id"`
+	Phone                      pgtype.Text        `json:"phone"`
+	Email                      string             `json:"email"`
+	Fullname                   string             `json:"fullname"`
+	Provider                   Provider           `json:"provider"`
+	PasswordHash               pgtype.Text        `json:"password_hash"`
+	EmailVerified              bool               `json:"email_verified"`
+	EmailVerificationHash      pgtype.Text        `json:"email_verification_hash"`
+	EmailVerificationExpiresAt pgtype.Timestamp   `json:"email_verification_expires_at"`
+	Role                       UserRole           `json:"role"`
+	ProfileStatus              NullProfileStatus  `json:"profile_status"`
+	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
+	ID_2                       pgtype.Int4        `json:"id_2"`
+	UserID                     pgtype.UUID        `json:"user_id"`
+	IsPremium                  pgtype.Bool        `json:"is_premium"`
+	StripeID                   pgtype.Text        `json:"stripe_id"`
+	Name                       pgtype.Text        `json:"name"`
+	Bio                        pgtype.Text        `json:"bio"`
+	CreatedAt_2                pgtype.Timestamp   `json:"created_at_2"`
+	UpdatedAt_2                pgtype.Timestamp   `json:"updated_at_2"`
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow, error) {
@@ -144,6 +222,9 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow
 		&i.Fullname,
 		&i.Provider,
 		&i.PasswordHash,
+		&i.EmailVerified,
+		&i.EmailVerificationHash,
+		&i.EmailVerificationExpiresAt,
 		&i.Role,
 		&i.ProfileStatus,
 		&i.CreatedAt,
@@ -175,3 +256,34 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
 	return err
 }
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified = TRUE,
+    email_verification_hash = NULL,
+    email_verification_expires_at = NULL,
+    updated_at = NOW()
+WHERE email_verification_hash = $1 AND email_verification_expires_at > NOW()
+RETURNING id, phone, email, fullname, provider, password_hash, email_verified, email_verification_hash, email_verification_expires_at, role, profile_status, created_at, updated_at
+`
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, emailVerificationHash pgtype.Text) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, emailVerificationHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Phone,
+		&i.Email,
+		&i.Fullname,
+		&i.Provider,
+		&i.PasswordHash,
+		&i.EmailVerified,
+		&i.EmailVerificationHash,
+		&i.EmailVerificationExpiresAt,
+		&i.Role,
+		&i.ProfileStatus,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
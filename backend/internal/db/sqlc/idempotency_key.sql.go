@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (org_id, principal, key, request_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (org_id, principal, key) DO UPDATE SET
+    request_hash = EXCLUDED.request_hash,
+    status_code = NULL,
+    response_body = NULL,
+    created_at = CURRENT_TIMESTAMP
+WHERE idempotency_keys.created_at < NOW() - INTERVAL '24 hours'
+   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < NOW() - INTERVAL '5 minutes')
+RETURNING org_id, principal, key, request_hash, status_code, response_body, created_at
+`
+
+type ClaimIdempotencyKeyParams struct {
+	OrgID       uuid.UUID `json:"org_id"`
+	Principal   string    `json:"principal"`
+	Key         string    `json:"key"`
+	RequestHash string    `json:"request_hash"`
+}
+
+// Returns no row while another request holds the key; an expired key, or one
+// whose request never finished, is taken over
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, claimIdempotencyKey,
+		arg.OrgID,
+		arg.Principal,
+		arg.Key,
+		arg.RequestHash,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.OrgID,
+		&i.Principal,
+		&i.Key,
+		&i.RequestHash,
+		&i.StatusCode,
+		&i.ResponseBody,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $4, response_body = $5
+WHERE org_id = $1 AND principal = $2 AND key = $3
+`
+
+type CompleteIdempotencyKeyParams struct {
+	OrgID        uuid.UUID   `json:"org_id"`
+	Principal    string      `json:"principal"`
+	Key          string      `json:"key"`
+	StatusCode   pgtype.Int4 `json:"status_code"`
+	ResponseBody []byte      `json:"response_body"`
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, completeIdempotencyKey,
+		arg.OrgID,
+		arg.Principal,
+		arg.Key,
+		arg.StatusCode,
+		arg.ResponseBody,
+	)
+	return err
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < NOW() - INTERVAL '24 hours'
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT org_id, principal, key, request_hash, status_code, response_body, created_at FROM idempotency_keys
+WHERE org_id = $1 AND principal = $2 AND key = $3
+`
+
+type GetIdempotencyKeyParams struct {
+	OrgID     uuid.UUID `json:"org_id"`
+	Principal string    `json:"principal"`
+	Key       string    `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.OrgID, arg.Principal, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.OrgID,
+		&i.Principal,
+		&i.Key,
+		&i.RequestHash,
+		&i.StatusCode,
+		&i.ResponseBody,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE org_id = $1 AND principal = $2 AND key = $3 AND status_code IS NULL
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	OrgID     uuid.UUID `json:"org_id"`
+	Principal string    `json:"principal"`
+	Key       string    `json:"key"`
+}
+
+// Frees the key of a request that failed, so it can be retried
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, releaseIdempotencyKey, arg.OrgID, arg.Principal, arg.Key)
+	return err
+}
@@ -0,0 +1,91 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: incident.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getOpenIncidentsForMonitors = `-- name: GetOpenIncidentsForMonitors :many
+SELECT id, monitor_id, cause, error_type, screenshot_key, started_at, resolved_at FROM incidents
+WHERE monitor_id = ANY($1::int[])
+  AND resolved_at IS NULL
+ORDER BY started_at DESC
+`
+
+func (q *Queries) GetOpenIncidentsForMonitors(ctx context.Context, monitorIds []int32) ([]Incident, error) {
+	rows, err := q.db.Query(ctx, getOpenIncidentsForMonitors, monitorIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Incident{}
+	for rows.Next() {
+		var i Incident
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.Cause,
+			&i.ErrorType,
+			&i.ScreenshotKey,
+			&i.StartedAt,
+			&i.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const openIncident = `-- name: OpenIncident :exec
+INSERT INTO incidents (monitor_id, cause, error_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (monitor_id) WHERE resolved_at IS NULL DO NOTHING
+`
+
+type OpenIncidentParams struct {
+	MonitorID int32       `json:"monitor_id"`
+	Cause     string      `json:"cause"`
+	ErrorType pgtype.Text `json:"error_type"`
+}
+
+func (q *Queries) OpenIncident(ctx context.Context, arg OpenIncidentParams) error {
+	_, err := q.db.Exec(ctx, openIncident, arg.MonitorID, arg.Cause, arg.ErrorType)
+	return err
+}
+
+const resolveOpenIncident = `-- name: ResolveOpenIncident :exec
+UPDATE incidents
+SET resolved_at = NOW()
+WHERE monitor_id = $1 AND resolved_at IS NULL
+`
+
+func (q *Queries) ResolveOpenIncident(ctx context.Context, monitorID int32) error {
+	_, err := q.db.Exec(ctx, resolveOpenIncident, monitorID)
+	return err
+}
+
+const setOpenIncidentScreenshot = `-- name: SetOpenIncidentScreenshot :exec
+UPDATE incidents
+SET screenshot_key = $2
+WHERE monitor_id = $1 AND resolved_at IS NULL
+`
+
+type SetOpenIncidentScreenshotParams struct {
+	MonitorID     int32       `json:"monitor_id"`
+	ScreenshotKey pgtype.Text `json:"screenshot_key"`
+}
+
+func (q *Queries) SetOpenIncidentScreenshot(ctx context.Context, arg SetOpenIncidentScreenshotParams) error {
+	_, err := q.db.Exec(ctx, setOpenIncidentScreenshot, arg.MonitorID, arg.ScreenshotKey)
+	return err
+}
@@ -6,6 +6,7 @@ package db
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -56,6 +57,139 @@ func (ns NullMonitorStatus) Value() (driver.Value, error) {
 	return string(ns.MonitorStatus), nil
 }
 
+type NoticeKind string
+
+const (
+	NoticeKindIncident    NoticeKind = "incident"
+	NoticeKindMaintenance NoticeKind = "maintenance"
+)
+
+func (e *NoticeKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NoticeKind(s)
+	case string:
+		*e = NoticeKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NoticeKind: %T", src)
+	}
+	return nil
+}
+
+type NullNoticeKind struct {
+	NoticeKind NoticeKind `json:"notice_kind"`
+	Valid      bool       `json:"valid"` // Valid is true if NoticeKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNoticeKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.NoticeKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NoticeKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNoticeKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NoticeKind), nil
+}
+
+type NoticeStatus string
+
+const (
+	NoticeStatusInvestigating NoticeStatus = "investigating"
+	NoticeStatusIdentified    NoticeStatus = "identified"
+	NoticeStatusMonitoring    NoticeStatus = "monitoring"
+	NoticeStatusResolved      NoticeStatus = "resolved"
+	NoticeStatusScheduled     NoticeStatus = "scheduled"
+	NoticeStatusInProgress    NoticeStatus = "in_progress"
+	NoticeStatusCompleted     NoticeStatus = "completed"
+)
+
+func (e *NoticeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NoticeStatus(s)
+	case string:
+		*e = NoticeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NoticeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNoticeStatus struct {
+	NoticeStatus NoticeStatus `json:"notice_status"`
+	Valid        bool         `json:"valid"` // Valid is true if NoticeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNoticeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NoticeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NoticeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNoticeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NoticeStatus), nil
+}
+
+type OrgRole string
+
+const (
+	OrgRoleOwner  OrgRole = "owner"
+	OrgRoleAdmin  OrgRole = "admin"
+	OrgRoleEditor OrgRole = "editor"
+	OrgRoleViewer OrgRole = "viewer"
+)
+
+func (e *OrgRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrgRole(s)
+	case string:
+		*e = OrgRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrgRole: %T", src)
+	}
+	return nil
+}
+
+type NullOrgRole struct {
+	OrgRole OrgRole `json:"org_role"`
+	Valid   bool    `json:"valid"` // Valid is true if OrgRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrgRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrgRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrgRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrgRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrgRole), nil
+}
+
 type ProfileStatus string
 
 const (
@@ -105,6 +239,7 @@ const (
 	ProviderGooglecom Provider = "google.com"
 	ProviderApple     Provider = "apple"
 	ProviderPassword  Provider = "password"
+	ProviderOidc      Provider = "oidc"
 )
 
 func (e *Provider) Scan(src interface{}) error {
@@ -142,6 +277,91 @@ func (ns NullProvider) Value() (driver.Value, error) {
 	return string(ns.Provider), nil
 }
 
+type RollupResolution string
+
+const (
+	RollupResolution1m RollupResolution = "1m"
+	RollupResolution1h RollupResolution = "1h"
+	RollupResolution1d RollupResolution = "1d"
+)
+
+func (e *RollupResolution) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RollupResolution(s)
+	case string:
+		*e = RollupResolution(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RollupResolution: %T", src)
+	}
+	return nil
+}
+
+type NullRollupResolution struct {
+	RollupResolution RollupResolution `json:"rollup_resolution"`
+	Valid            bool             `json:"valid"` // Valid is true if RollupResolution is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRollupResolution) Scan(value interface{}) error {
+	if value == nil {
+		ns.RollupResolution, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RollupResolution.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRollupResolution) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RollupResolution), nil
+}
+
+type SubscriberKind string
+
+const (
+	SubscriberKindEmail   SubscriberKind = "email"
+	SubscriberKindWebhook SubscriberKind = "webhook"
+)
+
+func (e *SubscriberKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SubscriberKind(s)
+	case string:
+		*e = SubscriberKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SubscriberKind: %T", src)
+	}
+	return nil
+}
+
+type NullSubscriberKind struct {
+	SubscriberKind SubscriberKind `json:"subscriber_kind"`
+	Valid          bool           `json:"valid"` // Valid is true if SubscriberKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSubscriberKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.SubscriberKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SubscriberKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSubscriberKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SubscriberKind), nil
+}
+
 type UserRole string
 
 const (
@@ -240,10 +460,12 @@ type Alert struct {
 type AlertContact struct {
 	ID         int32            `json:"id"`
 	UserID     pgtype.UUID      `json:"user_id"`
+	OrgID      uuid.UUID        `json:"org_id"`
 	Name       string           `json:"name"`
 	Email      string           `json:"email"`
 	IsVerified pgtype.Bool      `json:"is_verified"`
 	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ExternalID pgtype.Text      `json:"external_id"`
 }
 
 type Analytic struct {
@@ -255,12 +477,64 @@ type Analytic struct {
 	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
 }
 
+type ApiKey struct {
+	ID         int32            `json:"id"`
+	UserID     uuid.UUID        `json:"user_id"`
+	OrgID      pgtype.UUID      `json:"org_id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	KeyHash    string           `json:"key_hash"`
+	Scopes     []string         `json:"scopes"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamp `json:"revoked_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type AuditLog struct {
+	ID           int64            `json:"id"`
+	OrgID        uuid.UUID        `json:"org_id"`
+	UserID       pgtype.UUID      `json:"user_id"`
+	ActorEmail   string           `json:"actor_email"`
+	ApiKeyID     pgtype.Int4      `json:"api_key_id"`
+	Action       string           `json:"action"`
+	ResourceType string           `json:"resource_type"`
+	ResourceID   string           `json:"resource_id"`
+	Before       json.RawMessage  `json:"before"`
+	After        json.RawMessage  `json:"after"`
+	Ip           string           `json:"ip"`
+	UserAgent    string           `json:"user_agent"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	OrgID        uuid.UUID        `json:"org_id"`
+	Principal    string           `json:"principal"`
+	Key          string           `json:"key"`
+	RequestHash  string           `json:"request_hash"`
+	StatusCode   pgtype.Int4      `json:"status_code"`
+	ResponseBody []byte           `json:"response_body"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type Incident struct {
+	ID            int32            `json:"id"`
+	MonitorID     int32            `json:"monitor_id"`
+	Cause         string           `json:"cause"`
+	ErrorType     pgtype.Text      `json:"error_type"`
+	ScreenshotKey pgtype.Text      `json:"screenshot_key"`
+	StartedAt     pgtype.Timestamp `json:"started_at"`
+	ResolvedAt    pgtype.Timestamp `json:"resolved_at"`
+}
+
 type Monitor struct {
 	ID                  int32             `json:"id"`
 	UserID              pgtype.UUID       `json:"user_id"`
+	OrgID               uuid.UUID         `json:"org_id"`
 	Url                 string            `json:"url"`
 	Method              pgtype.Text       `json:"method"`
 	Type                pgtype.Text       `json:"type"`
+	Config              json.RawMessage   `json:"config"`
 	Interval            int32             `json:"interval"`
 	Status              NullMonitorStatus `json:"status"`
 	LastStatus          NullMonitorStatus `json:"last_status"`
@@ -269,6 +543,9 @@ type Monitor struct {
 	ConsecutiveFailures pgtype.Int4       `json:"consecutive_failures"`
 	CreatedAt           pgtype.Timestamp  `json:"created_at"`
 	UpdatedAt           pgtype.Timestamp  `json:"updated_at"`
+	GroupID             pgtype.Int4       `json:"group_id"`
+	Tags                []string          `json:"tags"`
+	ExternalID          pgtype.Text       `json:"external_id"`
 }
 
 type MonitorAlertConfig struct {
@@ -283,37 +560,206 @@ type MonitorAlertConfig struct {
 	CreatedAt       pgtype.Timestamp `json:"created_at"`
 }
 
+type MonitorBadge struct {
+	ID        int32            `json:"id"`
+	MonitorID int32            `json:"monitor_id"`
+	Token     string           `json:"token"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type MonitorGroup struct {
+	ID           int32            `json:"id"`
+	OrgID        uuid.UUID        `json:"org_id"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	Rollup       string           `json:"rollup"`
+	MinUpPercent int32            `json:"min_up_percent"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	ExternalID   pgtype.Text      `json:"external_id"`
+}
+
 type MonitorLog struct {
-	ID            int32            `json:"id"`
-	MonitorID     pgtype.Int4      `json:"monitor_id"`
-	StatusCode    pgtype.Int4      `json:"status_code"`
-	ResponseTime  pgtype.Float8    `json:"response_time"`
-	DnsOk         pgtype.Bool      `json:"dns_ok"`
-	SslOk         pgtype.Bool      `json:"ssl_ok"`
-	ContentOk     pgtype.Bool      `json:"content_ok"`
-	ScreenshotUrl pgtype.Text      `json:"screenshot_url"`
-	CheckedAt     pgtype.Timestamp `json:"checked_at"`
+	ID              int32            `json:"id"`
+	MonitorID       pgtype.Int4      `json:"monitor_id"`
+	StatusCode      pgtype.Int4      `json:"status_code"`
+	ResponseTime    pgtype.Float8    `json:"response_time"`
+	DnsOk           pgtype.Bool      `json:"dns_ok"`
+	SslOk           pgtype.Bool      `json:"ssl_ok"`
+	ContentOk       pgtype.Bool      `json:"content_ok"`
+	ScreenshotUrl   pgtype.Text      `json:"screenshot_url"`
+	ScreenshotKey   pgtype.Text      `json:"screenshot_key"`
+	DnsMs           pgtype.Float8    `json:"dns_ms"`
+	ConnectMs       pgtype.Float8    `json:"connect_ms"`
+	TlsMs           pgtype.Float8    `json:"tls_ms"`
+	TtfbMs          pgtype.Float8    `json:"ttfb_ms"`
+	TransferMs      pgtype.Float8    `json:"transfer_ms"`
+	ErrorType       pgtype.Text      `json:"error_type"`
+	ErrorMessage    pgtype.Text      `json:"error_message"`
+	RemoteIp        pgtype.Text      `json:"remote_ip"`
+	ResponseHeaders json.RawMessage  `json:"response_headers"`
+	ResponseBody    pgtype.Text      `json:"response_body"`
+	StepResults     json.RawMessage  `json:"step_results"`
+	BrowserResult   json.RawMessage  `json:"browser_result"`
+	CheckedAt       pgtype.Timestamp `json:"checked_at"`
 }
 
-type Subscription struct {
+type MonitorRollup struct {
+	MonitorID       int32            `json:"monitor_id"`
+	Resolution      RollupResolution `json:"resolution"`
+	BucketStart     pgtype.Timestamp `json:"bucket_start"`
+	CheckCount      int32            `json:"check_count"`
+	SuccessCount    int32            `json:"success_count"`
+	ResponseCount   int32            `json:"response_count"`
+	MinResponseTime pgtype.Float8    `json:"min_response_time"`
+	AvgResponseTime pgtype.Float8    `json:"avg_response_time"`
+	MaxResponseTime pgtype.Float8    `json:"max_response_time"`
+	P50ResponseTime pgtype.Float8    `json:"p50_response_time"`
+	P90ResponseTime pgtype.Float8    `json:"p90_response_time"`
+	P95ResponseTime pgtype.Float8    `json:"p95_response_time"`
+	P99ResponseTime pgtype.Float8    `json:"p99_response_time"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+type MonitorSecret struct {
+	MonitorID  int32            `json:"monitor_id"`
+	Name       string           `json:"name"`
+	Ciphertext []byte           `json:"ciphertext"`
+	KeyID      string           `json:"key_id"`
+	Hint       string           `json:"hint"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type OrgInvite struct {
 	ID         int32            `json:"id"`
-	UserID     pgtype.UUID      `json:"user_id"`
-	StripePlan pgtype.Text      `json:"stripe_plan"`
-	IsActive   pgtype.Bool      `json:"is_active"`
+	OrgID      uuid.UUID        `json:"org_id"`
+	Email      string           `json:"email"`
+	Role       OrgRole          `json:"role"`
+	Token      string           `json:"token"`
+	InvitedBy  pgtype.UUID      `json:"invited_by"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	AcceptedAt pgtype.Timestamp `json:"accepted_at"`
 	CreatedAt  pgtype.Timestamp `json:"created_at"`
 }
 
+type OrgMembership struct {
+	OrgID     uuid.UUID        `json:"org_id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Role      OrgRole          `json:"role"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type Organization struct {
+	ID        uuid.UUID        `json:"id"`
+	Name      string           `json:"name"`
+	CreatedBy pgtype.UUID      `json:"created_by"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type RefreshToken struct {
+	ID        int32            `json:"id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	FamilyID  uuid.UUID        `json:"family_id"`
+	TokenHash string           `json:"token_hash"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type StatusPage struct {
+	ID           int32            `json:"id"`
+	UserID       pgtype.UUID      `json:"user_id"`
+	OrgID        uuid.UUID        `json:"org_id"`
+	Slug         string           `json:"slug"`
+	Title        string           `json:"title"`
+	Description  pgtype.Text      `json:"description"`
+	CustomDomain pgtype.Text      `json:"custom_domain"`
+	IsPublished  pgtype.Bool      `json:"is_published"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	ExternalID   pgtype.Text      `json:"external_id"`
+}
+
+type StatusPageMonitor struct {
+	ID           int32  `json:"id"`
+	StatusPageID int32  `json:"status_page_id"`
+	SectionID    int32  `json:"section_id"`
+	MonitorID    int32  `json:"monitor_id"`
+	DisplayName  string `json:"display_name"`
+	Position     int32  `json:"position"`
+}
+
+type StatusPageNotice struct {
+	ID             int32            `json:"id"`
+	StatusPageID   int32            `json:"status_page_id"`
+	Kind           NoticeKind       `json:"kind"`
+	Title          string           `json:"title"`
+	Status         NoticeStatus     `json:"status"`
+	ScheduledStart pgtype.Timestamp `json:"scheduled_start"`
+	ScheduledEnd   pgtype.Timestamp `json:"scheduled_end"`
+	ResolvedAt     pgtype.Timestamp `json:"resolved_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+type StatusPageNoticeUpdate struct {
+	ID        int32            `json:"id"`
+	NoticeID  int32            `json:"notice_id"`
+	Status    NoticeStatus     `json:"status"`
+	Message   string           `json:"message"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type StatusPageSection struct {
+	ID           int32  `json:"id"`
+	StatusPageID int32  `json:"status_page_id"`
+	Name         string `json:"name"`
+	Position     int32  `json:"position"`
+}
+
+type StatusPageSubscriber struct {
+	ID               int32            `json:"id"`
+	StatusPageID     int32            `json:"status_page_id"`
+	Kind             SubscriberKind   `json:"kind"`
+	Target           string           `json:"target"`
+	ConfirmToken     string           `json:"confirm_token"`
+	ConfirmExpiresAt pgtype.Timestamp `json:"confirm_expires_at"`
+	UnsubscribeToken string           `json:"unsubscribe_token"`
+	ConfirmedAt      pgtype.Timestamp `json:"confirmed_at"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+}
+
+type Subscription struct {
+	ID                   int32            `json:"id"`
+	UserID               pgtype.UUID      `json:"user_id"`
+	OrgID                uuid.UUID        `json:"org_id"`
+	Plan                 string           `json:"plan"`
+	StripeCustomerID     pgtype.Text      `json:"stripe_customer_id"`
+	StripeSubscriptionID pgtype.Text      `json:"stripe_subscription_id"`
+	Status               string           `json:"status"`
+	CurrentPeriodEnd     pgtype.Timestamp `json:"current_period_end"`
+	IsActive             bool             `json:"is_active"`
+	StripeEventAt        pgtype.Timestamp `json:"stripe_event_at"`
+	CreatedAt            pgtype.Timestamp `json:"created_at"`
+	UpdatedAt            pgtype.Timestamp `json:"updated_at"`
+}
+
 type User struct {
-	ID            uuid.UUID          `json:"id"`
-	Phone         pgtype.Text        `json:"phone"`
-	Email         string             `json:"email"`
-	Fullname      string             `json:"fullname"`
-	Provider      Provider           `json:"provider"`
-	PasswordHash  pgtype.Text        `json:"password_hash"`
-	Role          UserRole           `json:"role"`
-	ProfileStatus NullProfileStatus  `json:"profile_status"`
-	CreatedAt     pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	ID                         uuid.UUID          `json:"id"`
+	Phone                      pgtype.Text        `json:"phone"`
+	Email                      string             `json:"email"`
+	Fullname                   string             `json:"fullname"`
+	Provider                   Provider           `json:"provider"`
+	PasswordHash               pgtype.Text        `json:"password_hash"`
+	EmailVerified              bool               `json:"email_verified"`
+	EmailVerificationHash      pgtype.Text        `json:"email_verification_hash"`
+	EmailVerificationExpiresAt pgtype.Timestamp   `json:"email_verification_expires_at"`
+	Role                       UserRole           `json:"role"`
+	ProfileStatus              NullProfileStatus  `json:"profile_status"`
+	CreatedAt                  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt                  pgtype.Timestamptz `json:"updated_at"`
 }
 
 type UserProfile struct {
@@ -7,43 +7,312 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const bulkDeleteMonitors = `-- name: BulkDeleteMonitors :many
+DELETE FROM monitors
+WHERE org_id = $1 AND id = ANY($2::int[])
+RETURNING id
+`
+
+type BulkDeleteMonitorsParams struct {
+	OrgID uuid.UUID `json:"org_id"`
+	Ids   []int32   `json:"ids"`
+}
+
+func (q *Queries) BulkDeleteMonitors(ctx context.Context, arg BulkDeleteMonitorsParams) ([]int32, error) {
+	rows, err := q.db.Query(ctx, bulkDeleteMonitors, arg.OrgID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkRetagMonitors = `-- name: BulkRetagMonitors :many
+UPDATE monitors
+SET tags = ARRAY(
+        SELECT DISTINCT t
+        FROM unnest(monitors.tags || COALESCE($1::text[], '{}')) AS t
+        WHERE t <> ALL(COALESCE($2::text[], '{}'))
+        ORDER BY t
+    ),
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = $3 AND id = ANY($4::int[])
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
+`
+
+type BulkRetagMonitorsParams struct {
+	AddTags    []string  `json:"add_tags"`
+	RemoveTags []string  `json:"remove_tags"`
+	OrgID      uuid.UUID `json:"org_id"`
+	Ids        []int32   `json:"ids"`
+}
+
+// Adds add_tags and drops remove_tags (which wins), keeping each monitor's
+// tags sorted and unique
+func (q *Queries) BulkRetagMonitors(ctx context.Context, arg BulkRetagMonitorsParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, bulkRetagMonitors,
+		arg.AddTags,
+		arg.RemoveTags,
+		arg.OrgID,
+		arg.Ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkSetMonitorsActive = `-- name: BulkSetMonitorsActive :many
+UPDATE monitors
+SET is_active = $1::bool,
+    consecutive_failures = CASE WHEN $1::bool THEN 0 ELSE consecutive_failures END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = $2 AND id = ANY($3::int[])
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
+`
+
+type BulkSetMonitorsActiveParams struct {
+	IsActive bool      `json:"is_active"`
+	OrgID    uuid.UUID `json:"org_id"`
+	Ids      []int32   `json:"ids"`
+}
+
+func (q *Queries) BulkSetMonitorsActive(ctx context.Context, arg BulkSetMonitorsActiveParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, bulkSetMonitorsActive, arg.IsActive, arg.OrgID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkSetMonitorsGroup = `-- name: BulkSetMonitorsGroup :many
+UPDATE monitors
+SET group_id = $1::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = $2 AND id = ANY($3::int[])
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
+`
+
+type BulkSetMonitorsGroupParams struct {
+	GroupID pgtype.Int4 `json:"group_id"`
+	OrgID   uuid.UUID   `json:"org_id"`
+	Ids     []int32     `json:"ids"`
+}
+
+func (q *Queries) BulkSetMonitorsGroup(ctx context.Context, arg BulkSetMonitorsGroupParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, bulkSetMonitorsGroup, arg.GroupID, arg.OrgID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const bulkSetMonitorsInterval = `-- name: BulkSetMonitorsInterval :many
+UPDATE monitors
+SET interval = $1::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE org_id = $2 AND id = ANY($3::int[])
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
+`
+
+type BulkSetMonitorsIntervalParams struct {
+	Interval int32     `json:"interval"`
+	OrgID    uuid.UUID `json:"org_id"`
+	Ids      []int32   `json:"ids"`
+}
+
+func (q *Queries) BulkSetMonitorsInterval(ctx context.Context, arg BulkSetMonitorsIntervalParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, bulkSetMonitorsInterval, arg.Interval, arg.OrgID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createMonitor = `-- name: CreateMonitor :one
-INSERT INTO monitors (user_id, url, method, type, interval, status, is_active, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
-RETURNING id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at
+INSERT INTO monitors (user_id, org_id, url, method, type, interval, status, is_active, config, group_id, tags, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now(), now())
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
 `
 
 type CreateMonitorParams struct {
 	UserID   pgtype.UUID       `json:"user_id"`
+	OrgID    uuid.UUID         `json:"org_id"`
 	Url      string            `json:"url"`
 	Method   pgtype.Text       `json:"method"`
 	Type     pgtype.Text       `json:"type"`
 	Interval int32             `json:"interval"`
 	Status   NullMonitorStatus `json:"status"`
 	IsActive pgtype.Bool       `json:"is_active"`
+	Config   json.RawMessage   `json:"config"`
+	GroupID  pgtype.Int4       `json:"group_id"`
+	Tags     []string          `json:"tags"`
 }
 
 func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (Monitor, error) {
 	row := q.db.QueryRow(ctx, createMonitor,
 		arg.UserID,
+		arg.OrgID,
 		arg.Url,
 		arg.Method,
 		arg.Type,
 		arg.Interval,
 		arg.Status,
 		arg.IsActive,
+		arg.Config,
+		arg.GroupID,
+		arg.Tags,
 	)
 	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -52,27 +321,30 @@ func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (M
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
 const deleteMonitor = `-- name: DeleteMonitor :exec
 DELETE FROM monitors 
-WHERE id = $1 AND user_id = $2
+WHERE id = $1 AND org_id = $2
 `
 
 type DeleteMonitorParams struct {
-	ID     int32       `json:"id"`
-	UserID pgtype.UUID `json:"user_id"`
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
 }
 
 func (q *Queries) DeleteMonitor(ctx context.Context, arg DeleteMonitorParams) error {
-	_, err := q.db.Exec(ctx, deleteMonitor, arg.ID, arg.UserID)
+	_, err := q.db.Exec(ctx, deleteMonitor, arg.ID, arg.OrgID)
 	return err
 }
 
 const getActiveMonitors = `-- name: GetActiveMonitors :many
-SELECT id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at FROM monitors 
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors 
 WHERE is_active = true
 `
 
@@ -88,9 +360,11 @@ func (q *Queries) GetActiveMonitors(ctx context.Context) ([]Monitor, error) {
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.OrgID,
 			&i.Url,
 			&i.Method,
 			&i.Type,
+			&i.Config,
 			&i.Interval,
 			&i.Status,
 			&i.LastStatus,
@@ -99,6 +373,9 @@ func (q *Queries) GetActiveMonitors(ctx context.Context) ([]Monitor, error) {
 			&i.ConsecutiveFailures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -110,13 +387,13 @@ func (q *Queries) GetActiveMonitors(ctx context.Context) ([]Monitor, error) {
 	return items, nil
 }
 
-const getActiveMonitorsForUser = `-- name: GetActiveMonitorsForUser :many
-SELECT id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at FROM monitors 
-WHERE is_active = true AND user_id = $1
+const getActiveMonitorsForOrg = `-- name: GetActiveMonitorsForOrg :many
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors 
+WHERE is_active = true AND org_id = $1
 `
 
-func (q *Queries) GetActiveMonitorsForUser(ctx context.Context, userID pgtype.UUID) ([]Monitor, error) {
-	rows, err := q.db.Query(ctx, getActiveMonitorsForUser, userID)
+func (q *Queries) GetActiveMonitorsForOrg(ctx context.Context, orgID uuid.UUID) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, getActiveMonitorsForOrg, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -127,9 +404,11 @@ func (q *Queries) GetActiveMonitorsForUser(ctx context.Context, userID pgtype.UU
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.OrgID,
 			&i.Url,
 			&i.Method,
 			&i.Type,
+			&i.Config,
 			&i.Interval,
 			&i.Status,
 			&i.LastStatus,
@@ -138,6 +417,9 @@ func (q *Queries) GetActiveMonitorsForUser(ctx context.Context, userID pgtype.UU
 			&i.ConsecutiveFailures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -150,24 +432,26 @@ func (q *Queries) GetActiveMonitorsForUser(ctx context.Context, userID pgtype.UU
 }
 
 const getMonitorByID = `-- name: GetMonitorByID :one
-SELECT id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at FROM monitors 
-WHERE id = $1 AND user_id = $2
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors 
+WHERE id = $1 AND org_id = $2
 `
 
 type GetMonitorByIDParams struct {
-	ID     int32       `json:"id"`
-	UserID pgtype.UUID `json:"user_id"`
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
 }
 
 func (q *Queries) GetMonitorByID(ctx context.Context, arg GetMonitorByIDParams) (Monitor, error) {
-	row := q.db.QueryRow(ctx, getMonitorByID, arg.ID, arg.UserID)
+	row := q.db.QueryRow(ctx, getMonitorByID, arg.ID, arg.OrgID)
 	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -176,29 +460,34 @@ func (q *Queries) GetMonitorByID(ctx context.Context, arg GetMonitorByIDParams)
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
 const getMonitorByIdandURL = `-- name: GetMonitorByIdandURL :one
-SELECT id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at FROM monitors
-where user_id = $1 AND url = $2
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors
+where org_id = $1 AND url = $2
 `
 
 type GetMonitorByIdandURLParams struct {
-	UserID pgtype.UUID `json:"user_id"`
-	Url    string      `json:"url"`
+	OrgID uuid.UUID `json:"org_id"`
+	Url   string    `json:"url"`
 }
 
 func (q *Queries) GetMonitorByIdandURL(ctx context.Context, arg GetMonitorByIdandURLParams) (Monitor, error) {
-	row := q.db.QueryRow(ctx, getMonitorByIdandURL, arg.UserID, arg.Url)
+	row := q.db.QueryRow(ctx, getMonitorByIdandURL, arg.OrgID, arg.Url)
 	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -207,12 +496,15 @@ func (q *Queries) GetMonitorByIdandURL(ctx context.Context, arg GetMonitorByIdan
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
 const getMonitorsByInterval = `-- name: GetMonitorsByInterval :many
-SELECT id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at FROM monitors WHERE is_active = true AND interval = $1
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors WHERE is_active = true AND interval = $1
 `
 
 func (q *Queries) GetMonitorsByInterval(ctx context.Context, interval int32) ([]Monitor, error) {
@@ -227,9 +519,11 @@ func (q *Queries) GetMonitorsByInterval(ctx context.Context, interval int32) ([]
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.OrgID,
 			&i.Url,
 			&i.Method,
 			&i.Type,
+			&i.Config,
 			&i.Interval,
 			&i.Status,
 			&i.LastStatus,
@@ -238,6 +532,9 @@ func (q *Queries) GetMonitorsByInterval(ctx context.Context, interval int32) ([]
 			&i.ConsecutiveFailures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -249,14 +546,14 @@ func (q *Queries) GetMonitorsByInterval(ctx context.Context, interval int32) ([]
 	return items, nil
 }
 
-const getUserMonitors = `-- name: GetUserMonitors :many
-SELECT id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at FROM monitors 
-WHERE user_id = $1 
+const getOrgMonitors = `-- name: GetOrgMonitors :many
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors 
+WHERE org_id = $1 
 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetUserMonitors(ctx context.Context, userID pgtype.UUID) ([]Monitor, error) {
-	rows, err := q.db.Query(ctx, getUserMonitors, userID)
+func (q *Queries) GetOrgMonitors(ctx context.Context, orgID uuid.UUID) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, getOrgMonitors, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -267,9 +564,11 @@ func (q *Queries) GetUserMonitors(ctx context.Context, userID pgtype.UUID) ([]Mo
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
+			&i.OrgID,
 			&i.Url,
 			&i.Method,
 			&i.Type,
+			&i.Config,
 			&i.Interval,
 			&i.Status,
 			&i.LastStatus,
@@ -278,6 +577,9 @@ func (q *Queries) GetUserMonitors(ctx context.Context, userID pgtype.UUID) ([]Mo
 			&i.ConsecutiveFailures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -289,7 +591,57 @@ func (q *Queries) GetUserMonitors(ctx context.Context, userID pgtype.UUID) ([]Mo
 	return items, nil
 }
 
-const getUserMonitorsWithStats = `-- name: GetUserMonitorsWithStats :many
+const getOrgMonitorsByIDs = `-- name: GetOrgMonitorsByIDs :many
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors
+WHERE org_id = $1 AND id = ANY($2::int[])
+ORDER BY id
+`
+
+type GetOrgMonitorsByIDsParams struct {
+	OrgID uuid.UUID `json:"org_id"`
+	Ids   []int32   `json:"ids"`
+}
+
+func (q *Queries) GetOrgMonitorsByIDs(ctx context.Context, arg GetOrgMonitorsByIDsParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, getOrgMonitorsByIDs, arg.OrgID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrgMonitorsWithStats = `-- name: GetOrgMonitorsWithStats :many
 SELECT 
     m.id, 
     m.user_id,
@@ -299,17 +651,33 @@ SELECT
     m.status, 
     m.is_active, 
     COALESCE(AVG(ml.response_time), 0)::float as avg_response_time,
-    COALESCE(SUM(CASE WHEN ml.status_code >= 200 AND ml.status_code < 400 THEN 1 ELSE 0 END), 0)::bigint as successful_checks,
+    COALESCE(SUM(CASE WHEN ml.content_ok IS NOT FALSE AND ml.status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END), 0)::bigint as successful_checks,
     COUNT(ml.id)::bigint as total_checks,
-    MAX(ml.checked_at) as last_check
+    MAX(ml.checked_at) as last_check,
+    m.group_id,
+    m.tags
 FROM monitors m
 LEFT JOIN monitor_logs ml ON m.id = ml.monitor_id
-WHERE m.user_id = $1
+WHERE m.org_id = $1
+  AND ($2::text[] IS NULL OR m.tags @> $2::text[])
+  AND ($3::monitor_status IS NULL OR m.status = $3::monitor_status)
+  AND ($4::bool IS NULL OR m.is_active = $4::bool)
+  AND ($5::int IS NULL OR m.group_id = $5::int)
+  AND ($6::text IS NULL OR m.url ILIKE '%' || $6::text || '%')
 GROUP BY m.id
 ORDER BY m.created_at DESC
 `
 
-type GetUserMonitorsWithStatsRow struct {
+type GetOrgMonitorsWithStatsParams struct {
+	OrgID    uuid.UUID         `json:"org_id"`
+	Tags     []string          `json:"tags"`
+	Status   NullMonitorStatus `json:"status"`
+	IsActive pgtype.Bool       `json:"is_active"`
+	GroupID  pgtype.Int4       `json:"group_id"`
+	Search   pgtype.Text       `json:"search"`
+}
+
+type GetOrgMonitorsWithStatsRow struct {
 	ID               int32             `json:"id"`
 	UserID           pgtype.UUID       `json:"user_id"`
 	Url              string            `json:"url"`
@@ -321,17 +689,26 @@ type GetUserMonitorsWithStatsRow struct {
 	SuccessfulChecks int64             `json:"successful_checks"`
 	TotalChecks      int64             `json:"total_checks"`
 	LastCheck        interface{}       `json:"last_check"`
+	GroupID          pgtype.Int4       `json:"group_id"`
+	Tags             []string          `json:"tags"`
 }
 
-func (q *Queries) GetUserMonitorsWithStats(ctx context.Context, userID pgtype.UUID) ([]GetUserMonitorsWithStatsRow, error) {
-	rows, err := q.db.Query(ctx, getUserMonitorsWithStats, userID)
+func (q *Queries) GetOrgMonitorsWithStats(ctx context.Context, arg GetOrgMonitorsWithStatsParams) ([]GetOrgMonitorsWithStatsRow, error) {
+	rows, err := q.db.Query(ctx, getOrgMonitorsWithStats,
+		arg.OrgID,
+		arg.Tags,
+		arg.Status,
+		arg.IsActive,
+		arg.GroupID,
+		arg.Search,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetUserMonitorsWithStatsRow{}
+	items := []GetOrgMonitorsWithStatsRow{}
 	for rows.Next() {
-		var i GetUserMonitorsWithStatsRow
+		var i GetOrgMonitorsWithStatsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
@@ -344,6 +721,112 @@ func (q *Queries) GetUserMonitorsWithStats(ctx context.Context, userID pgtype.UU
 			&i.SuccessfulChecks,
 			&i.TotalChecks,
 			&i.LastCheck,
+			&i.GroupID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMonitorScreenshotKeys = `-- name: ListMonitorScreenshotKeys :many
+SELECT screenshot_key::text FROM monitor_logs
+WHERE monitor_id = $1::int AND screenshot_key IS NOT NULL
+UNION
+SELECT screenshot_key::text FROM incidents
+WHERE monitor_id = $1::int AND screenshot_key IS NOT NULL
+`
+
+// Stored screenshots of a monitor, read before deleting it since its logs and
+// incidents go with it
+func (q *Queries) ListMonitorScreenshotKeys(ctx context.Context, monitorID int32) ([]string, error) {
+	rows, err := q.db.Query(ctx, listMonitorScreenshotKeys, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var screenshot_key string
+		if err := rows.Scan(&screenshot_key); err != nil {
+			return nil, err
+		}
+		items = append(items, screenshot_key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrgMonitorsPage = `-- name: ListOrgMonitorsPage :many
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors
+WHERE org_id = $1
+  AND id > $2::int
+  AND ($3::text[] IS NULL OR tags @> $3::text[])
+  AND ($4::monitor_status IS NULL OR status = $4::monitor_status)
+  AND ($5::bool IS NULL OR is_active = $5::bool)
+  AND ($6::int IS NULL OR group_id = $6::int)
+  AND ($7::text IS NULL OR url ILIKE '%' || $7::text || '%')
+ORDER BY id
+LIMIT $8::int
+`
+
+type ListOrgMonitorsPageParams struct {
+	OrgID    uuid.UUID         `json:"org_id"`
+	AfterID  int32             `json:"after_id"`
+	Tags     []string          `json:"tags"`
+	Status   NullMonitorStatus `json:"status"`
+	IsActive pgtype.Bool       `json:"is_active"`
+	GroupID  pgtype.Int4       `json:"group_id"`
+	Search   pgtype.Text       `json:"search"`
+	PageSize int32             `json:"page_size"`
+}
+
+// Pages through monitors by id, after the last one of the previous page, with
+// the filters of SearchOrgMonitors
+func (q *Queries) ListOrgMonitorsPage(ctx context.Context, arg ListOrgMonitorsPageParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, listOrgMonitorsPage,
+		arg.OrgID,
+		arg.AfterID,
+		arg.Tags,
+		arg.Status,
+		arg.IsActive,
+		arg.GroupID,
+		arg.Search,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -355,30 +838,150 @@ func (q *Queries) GetUserMonitorsWithStats(ctx context.Context, userID pgtype.UU
 	return items, nil
 }
 
+const listOrgTags = `-- name: ListOrgTags :many
+SELECT t.tag::text AS tag, COUNT(*)::bigint AS monitors
+FROM monitors m, unnest(m.tags) AS t(tag)
+WHERE m.org_id = $1
+GROUP BY t.tag
+ORDER BY t.tag
+`
+
+type ListOrgTagsRow struct {
+	Tag      string `json:"tag"`
+	Monitors int64  `json:"monitors"`
+}
+
+// Every tag in use in the organization with how many monitors carry it
+func (q *Queries) ListOrgTags(ctx context.Context, orgID uuid.UUID) ([]ListOrgTagsRow, error) {
+	rows, err := q.db.Query(ctx, listOrgTags, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOrgTagsRow{}
+	for rows.Next() {
+		var i ListOrgTagsRow
+		if err := rows.Scan(&i.Tag, &i.Monitors); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchOrgMonitors = `-- name: SearchOrgMonitors :many
+SELECT id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id FROM monitors
+WHERE org_id = $1
+  AND ($2::text[] IS NULL OR tags @> $2::text[])
+  AND ($3::monitor_status IS NULL OR status = $3::monitor_status)
+  AND ($4::bool IS NULL OR is_active = $4::bool)
+  AND ($5::int IS NULL OR group_id = $5::int)
+  AND ($6::text IS NULL OR url ILIKE '%' || $6::text || '%')
+ORDER BY created_at DESC
+`
+
+type SearchOrgMonitorsParams struct {
+	OrgID    uuid.UUID         `json:"org_id"`
+	Tags     []string          `json:"tags"`
+	Status   NullMonitorStatus `json:"status"`
+	IsActive pgtype.Bool       `json:"is_active"`
+	GroupID  pgtype.Int4       `json:"group_id"`
+	Search   pgtype.Text       `json:"search"`
+}
+
+// Filters are optional: tags must all be present, search matches the URL
+// (with LIKE wildcards already escaped)
+func (q *Queries) SearchOrgMonitors(ctx context.Context, arg SearchOrgMonitorsParams) ([]Monitor, error) {
+	rows, err := q.db.Query(ctx, searchOrgMonitors,
+		arg.OrgID,
+		arg.Tags,
+		arg.Status,
+		arg.IsActive,
+		arg.GroupID,
+		arg.Search,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Monitor{}
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Url,
+			&i.Method,
+			&i.Type,
+			&i.Config,
+			&i.Interval,
+			&i.Status,
+			&i.LastStatus,
+			&i.LastAlertSentAt,
+			&i.IsActive,
+			&i.ConsecutiveFailures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.Tags,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setMonitorExternalID = `-- name: SetMonitorExternalID :exec
+UPDATE monitors SET external_id = $3
+WHERE id = $1 AND org_id = $2
+`
+
+type SetMonitorExternalIDParams struct {
+	ID         int32       `json:"id"`
+	OrgID      uuid.UUID   `json:"org_id"`
+	ExternalID pgtype.Text `json:"external_id"`
+}
+
+func (q *Queries) SetMonitorExternalID(ctx context.Context, arg SetMonitorExternalIDParams) error {
+	_, err := q.db.Exec(ctx, setMonitorExternalID, arg.ID, arg.OrgID, arg.ExternalID)
+	return err
+}
+
 const toggleMonitor = `-- name: ToggleMonitor :one
 UPDATE monitors 
 SET is_active = $3, 
     consecutive_failures = CASE WHEN $3 = true THEN 0 ELSE consecutive_failures END,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at
+WHERE id = $1 AND org_id = $2
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
 `
 
 type ToggleMonitorParams struct {
 	ID       int32       `json:"id"`
-	UserID   pgtype.UUID `json:"user_id"`
+	OrgID    uuid.UUID   `json:"org_id"`
 	IsActive pgtype.Bool `json:"is_active"`
 }
 
 func (q *Queries) ToggleMonitor(ctx context.Context, arg ToggleMonitorParams) (Monitor, error) {
-	row := q.db.QueryRow(ctx, toggleMonitor, arg.ID, arg.UserID, arg.IsActive)
+	row := q.db.QueryRow(ctx, toggleMonitor, arg.ID, arg.OrgID, arg.IsActive)
 	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -387,6 +990,9 @@ func (q *Queries) ToggleMonitor(ctx context.Context, arg ToggleMonitorParams) (M
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
@@ -400,9 +1006,12 @@ SET
     interval = COALESCE($5, interval),
     status = COALESCE($6, status),
     is_active = COALESCE($7, is_active),
+    config = COALESCE($9, config),
+    group_id = $10,
+    tags = COALESCE($11, tags),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $8
-RETURNING id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at
+WHERE id = $1 AND org_id = $8
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
 `
 
 type UpdateMonitorParams struct {
@@ -413,7 +1022,10 @@ type UpdateMonitorParams struct {
 	Interval int32             `json:"interval"`
 	Status   NullMonitorStatus `json:"status"`
 	IsActive pgtype.Bool       `json:"is_active"`
-	UserID   pgtype.UUID       `json:"user_id"`
+	OrgID    uuid.UUID         `json:"org_id"`
+	Config   json.RawMessage   `json:"config"`
+	GroupID  pgtype.Int4       `json:"group_id"`
+	Tags     []string          `json:"tags"`
 }
 
 func (q *Queries) UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (Monitor, error) {
@@ -425,15 +1037,20 @@ func (q *Queries) UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (M
 		arg.Interval,
 		arg.Status,
 		arg.IsActive,
-		arg.UserID,
+		arg.OrgID,
+		arg.Config,
+		arg.GroupID,
+		arg.Tags,
 	)
 	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -442,6 +1059,9 @@ func (q *Queries) UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (M
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
@@ -470,7 +1090,7 @@ const updateMonitorStatus = `-- name: UpdateMonitorStatus :one
 UPDATE monitors 
 SET status = $2, updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
 `
 
 type UpdateMonitorStatusParams struct {
@@ -484,9 +1104,11 @@ func (q *Queries) UpdateMonitorStatus(ctx context.Context, arg UpdateMonitorStat
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -495,6 +1117,9 @@ func (q *Queries) UpdateMonitorStatus(ctx context.Context, arg UpdateMonitorStat
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
@@ -506,7 +1131,7 @@ SET status = $2,
     is_active = $4,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, user_id, url, method, type, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at
+RETURNING id, user_id, org_id, url, method, type, config, interval, status, last_status, last_alert_sent_at, is_active, consecutive_failures, created_at, updated_at, group_id, tags, external_id
 `
 
 type UpdateMonitorStatusAndFailuresParams struct {
@@ -527,9 +1152,11 @@ func (q *Queries) UpdateMonitorStatusAndFailures(ctx context.Context, arg Update
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
+		&i.OrgID,
 		&i.Url,
 		&i.Method,
 		&i.Type,
+		&i.Config,
 		&i.Interval,
 		&i.Status,
 		&i.LastStatus,
@@ -538,6 +1165,9 @@ func (q *Queries) UpdateMonitorStatusAndFailures(ctx context.Context, arg Update
 		&i.ConsecutiveFailures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GroupID,
+		&i.Tags,
+		&i.ExternalID,
 	)
 	return i, err
 }
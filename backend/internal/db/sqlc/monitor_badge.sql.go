@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: monitor_badge.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteMonitorBadge = `-- name: DeleteMonitorBadge :exec
+DELETE FROM monitor_badges
+WHERE monitor_id = $1
+`
+
+func (q *Queries) DeleteMonitorBadge(ctx context.Context, monitorID int32) error {
+	_, err := q.db.Exec(ctx, deleteMonitorBadge, monitorID)
+	return err
+}
+
+const getBadgeMonitorByToken = `-- name: GetBadgeMonitorByToken :one
+SELECT
+    m.id,
+    m.url,
+    m.status,
+    m.is_active
+FROM monitor_badges b
+JOIN monitors m ON m.id = b.monitor_id
+WHERE b.token = $1
+`
+
+type GetBadgeMonitorByTokenRow struct {
+	ID       int32             `json:"id"`
+	Url      string            `json:"url"`
+	Status   NullMonitorStatus `json:"status"`
+	IsActive pgtype.Bool       `json:"is_active"`
+}
+
+func (q *Queries) GetBadgeMonitorByToken(ctx context.Context, token string) (GetBadgeMonitorByTokenRow, error) {
+	row := q.db.QueryRow(ctx, getBadgeMonitorByToken, token)
+	var i GetBadgeMonitorByTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Status,
+		&i.IsActive,
+	)
+	return i, err
+}
+
+const getMonitorBadge = `-- name: GetMonitorBadge :one
+SELECT id, monitor_id, token, created_at FROM monitor_badges
+WHERE monitor_id = $1
+`
+
+func (q *Queries) GetMonitorBadge(ctx context.Context, monitorID int32) (MonitorBadge, error) {
+	row := q.db.QueryRow(ctx, getMonitorBadge, monitorID)
+	var i MonitorBadge
+	err := row.Scan(
+		&i.ID,
+		&i.MonitorID,
+		&i.Token,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertMonitorBadge = `-- name: UpsertMonitorBadge :one
+INSERT INTO monitor_badges (monitor_id, token)
+VALUES ($1, $2)
+ON CONFLICT (monitor_id)
+DO UPDATE SET token = EXCLUDED.token, created_at = CURRENT_TIMESTAMP
+RETURNING id, monitor_id, token, created_at
+`
+
+type UpsertMonitorBadgeParams struct {
+	MonitorID int32  `json:"monitor_id"`
+	Token     string `json:"token"`
+}
+
+func (q *Queries) UpsertMonitorBadge(ctx context.Context, arg UpsertMonitorBadgeParams) (MonitorBadge, error) {
+	row := q.db.QueryRow(ctx, upsertMonitorBadge, arg.MonitorID, arg.Token)
+	var i MonitorBadge
+	err := row.Scan(
+		&i.ID,
+		&i.MonitorID,
+		&i.Token,
+		&i.CreatedAt,
+	)
+	return i, err
+}
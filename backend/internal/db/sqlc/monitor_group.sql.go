@@ -0,0 +1,286 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: monitor_group.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMonitorGroup = `-- name: CreateMonitorGroup :one
+INSERT INTO monitor_groups (org_id, name, description, rollup, min_up_percent)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, org_id, name, description, rollup, min_up_percent, created_at, updated_at, external_id
+`
+
+type CreateMonitorGroupParams struct {
+	OrgID        uuid.UUID `json:"org_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Rollup       string    `json:"rollup"`
+	MinUpPercent int32     `json:"min_up_percent"`
+}
+
+func (q *Queries) CreateMonitorGroup(ctx context.Context, arg CreateMonitorGroupParams) (MonitorGroup, error) {
+	row := q.db.QueryRow(ctx, createMonitorGroup,
+		arg.OrgID,
+		arg.Name,
+		arg.Description,
+		arg.Rollup,
+		arg.MinUpPercent,
+	)
+	var i MonitorGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Name,
+		&i.Description,
+		&i.Rollup,
+		&i.MinUpPercent,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const deleteMonitorGroup = `-- name: DeleteMonitorGroup :execrows
+DELETE FROM monitor_groups
+WHERE id = $1 AND org_id = $2
+`
+
+type DeleteMonitorGroupParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+// Its monitors are kept, without a group
+func (q *Queries) DeleteMonitorGroup(ctx context.Context, arg DeleteMonitorGroupParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMonitorGroup, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getMonitorGroup = `-- name: GetMonitorGroup :one
+SELECT id, org_id, name, description, rollup, min_up_percent, created_at, updated_at, external_id FROM monitor_groups
+WHERE id = $1 AND org_id = $2
+`
+
+type GetMonitorGroupParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetMonitorGroup(ctx context.Context, arg GetMonitorGroupParams) (MonitorGroup, error) {
+	row := q.db.QueryRow(ctx, getMonitorGroup, arg.ID, arg.OrgID)
+	var i MonitorGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Name,
+		&i.Description,
+		&i.Rollup,
+		&i.MinUpPercent,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getMonitorGroupCounts = `-- name: GetMonitorGroupCounts :many
+SELECT
+    m.group_id::int AS group_id,
+    COUNT(*)::bigint AS total,
+    COUNT(*) FILTER (WHERE m.is_active AND m.status = 'up')::bigint AS up,
+    COUNT(*) FILTER (WHERE m.is_active AND m.status = 'down')::bigint AS down,
+    COUNT(*) FILTER (WHERE m.is_active AND (m.status IS NULL OR m.status IN ('unknown', 'pending')))::bigint AS pending,
+    COUNT(*) FILTER (WHERE m.is_active IS NOT TRUE)::bigint AS paused
+FROM monitors m
+WHERE m.org_id = $1 AND m.group_id IS NOT NULL
+GROUP BY m.group_id
+`
+
+type GetMonitorGroupCountsRow struct {
+	GroupID int32 `json:"group_id"`
+	Total   int64 `json:"total"`
+	Up      int64 `json:"up"`
+	Down    int64 `json:"down"`
+	Pending int64 `json:"pending"`
+	Paused  int64 `json:"paused"`
+}
+
+// Monitors per group and state, for rolling up group status. Paused monitors
+// are counted apart from their last status.
+func (q *Queries) GetMonitorGroupCounts(ctx context.Context, orgID uuid.UUID) ([]GetMonitorGroupCountsRow, error) {
+	rows, err := q.db.Query(ctx, getMonitorGroupCounts, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMonitorGroupCountsRow{}
+	for rows.Next() {
+		var i GetMonitorGroupCountsRow
+		if err := rows.Scan(
+			&i.GroupID,
+			&i.Total,
+			&i.Up,
+			&i.Down,
+			&i.Pending,
+			&i.Paused,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrgMonitorGroups = `-- name: GetOrgMonitorGroups :many
+SELECT id, org_id, name, description, rollup, min_up_percent, created_at, updated_at, external_id FROM monitor_groups
+WHERE org_id = $1
+ORDER BY name
+`
+
+func (q *Queries) GetOrgMonitorGroups(ctx context.Context, orgID uuid.UUID) ([]MonitorGroup, error) {
+	rows, err := q.db.Query(ctx, getOrgMonitorGroups, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MonitorGroup{}
+	for rows.Next() {
+		var i MonitorGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrgID,
+			&i.Name,
+			&i.Description,
+			&i.Rollup,
+			&i.MinUpPercent,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrgMonitorGroupsPage = `-- name: ListOrgMonitorGroupsPage :many
+SELECT id, org_id, name, description, rollup, min_up_percent, created_at, updated_at, external_id FROM monitor_groups
+WHERE org_id = $1 AND id > $2::int
+ORDER BY id
+LIMIT $3::int
+`
+
+type ListOrgMonitorGroupsPageParams struct {
+	OrgID    uuid.UUID `json:"org_id"`
+	AfterID  int32     `json:"after_id"`
+	PageSize int32     `json:"page_size"`
+}
+
+func (q *Queries) ListOrgMonitorGroupsPage(ctx context.Context, arg ListOrgMonitorGroupsPageParams) ([]MonitorGroup, error) {
+	rows, err := q.db.Query(ctx, listOrgMonitorGroupsPage, arg.OrgID, arg.AfterID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MonitorGroup{}
+	for rows.Next() {
+		var i MonitorGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrgID,
+			&i.Name,
+			&i.Description,
+			&i.Rollup,
+			&i.MinUpPercent,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setMonitorGroupExternalID = `-- name: SetMonitorGroupExternalID :exec
+UPDATE monitor_groups SET external_id = $3
+WHERE id = $1 AND org_id = $2
+`
+
+type SetMonitorGroupExternalIDParams struct {
+	ID         int32       `json:"id"`
+	OrgID      uuid.UUID   `json:"org_id"`
+	ExternalID pgtype.Text `json:"external_id"`
+}
+
+func (q *Queries) SetMonitorGroupExternalID(ctx context.Context, arg SetMonitorGroupExternalIDParams) error {
+	_, err := q.db.Exec(ctx, setMonitorGroupExternalID, arg.ID, arg.OrgID, arg.ExternalID)
+	return err
+}
+
+const updateMonitorGroup = `-- name: UpdateMonitorGroup :one
+UPDATE monitor_groups
+SET name = $3,
+    description = $4,
+    rollup = $5,
+    min_up_percent = $6,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2
+RETURNING id, org_id, name, description, rollup, min_up_percent, created_at, updated_at, external_id
+`
+
+type UpdateMonitorGroupParams struct {
+	ID           int32     `json:"id"`
+	OrgID        uuid.UUID `json:"org_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Rollup       string    `json:"rollup"`
+	MinUpPercent int32     `json:"min_up_percent"`
+}
+
+func (q *Queries) UpdateMonitorGroup(ctx context.Context, arg UpdateMonitorGroupParams) (MonitorGroup, error) {
+	row := q.db.QueryRow(ctx, updateMonitorGroup,
+		arg.ID,
+		arg.OrgID,
+		arg.Name,
+		arg.Description,
+		arg.Rollup,
+		arg.MinUpPercent,
+	)
+	var i MonitorGroup
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Name,
+		&i.Description,
+		&i.Rollup,
+		&i.MinUpPercent,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
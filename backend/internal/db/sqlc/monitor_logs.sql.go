@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -48,19 +49,35 @@ func (q *Queries) CountMonitorLogs(ctx context.Context, arg CountMonitorLogsPara
 const createMonitorLog = `-- name: CreateMonitorLog :one
 INSERT INTO monitor_logs (
     monitor_id, status_code, response_time, 
-    dns_ok, ssl_ok, content_ok, screenshot_url
-) VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok, screenshot_url, checked_at
+    dns_ok, ssl_ok, content_ok, screenshot_url, screenshot_key,
+    dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms,
+    error_type, error_message, remote_ip, response_headers, response_body,
+    step_results, browser_result
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+RETURNING id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok, screenshot_url, screenshot_key, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, error_type, error_message, remote_ip, response_headers, response_body, step_results, browser_result, checked_at
 `
 
 type CreateMonitorLogParams struct {
-	MonitorID     pgtype.Int4   `json:"monitor_id"`
-	StatusCode    pgtype.Int4   `json:"status_code"`
-	ResponseTime  pgtype.Float8 `json:"response_time"`
-	DnsOk         pgtype.Bool   `json:"dns_ok"`
-	SslOk         pgtype.Bool   `json:"ssl_ok"`
-	ContentOk     pgtype.Bool   `json:"content_ok"`
-	ScreenshotUrl pgtype.Text   `json:"screenshot_url"`
+	MonitorID       pgtype.Int4     `json:"monitor_id"`
+	StatusCode      pgtype.Int4     `json:"status_code"`
+	ResponseTime    pgtype.Float8   `json:"response_time"`
+	DnsOk           pgtype.Bool     `json:"dns_ok"`
+	SslOk           pgtype.Bool     `json:"ssl_ok"`
+	ContentOk       pgtype.Bool     `json:"content_ok"`
+	ScreenshotUrl   pgtype.Text     `json:"screenshot_url"`
+	ScreenshotKey   pgtype.Text     `json:"screenshot_key"`
+	DnsMs           pgtype.Float8   `json:"dns_ms"`
+	ConnectMs       pgtype.Float8   `json:"connect_ms"`
+	TlsMs           pgtype.Float8   `json:"tls_ms"`
+	TtfbMs          pgtype.Float8   `json:"ttfb_ms"`
+	TransferMs      pgtype.Float8   `json:"transfer_ms"`
+	ErrorType       pgtype.Text     `json:"error_type"`
+	ErrorMessage    pgtype.Text     `json:"error_message"`
+	RemoteIp        pgtype.Text     `json:"remote_ip"`
+	ResponseHeaders json.RawMessage `json:"response_headers"`
+	ResponseBody    pgtype.Text     `json:"response_body"`
+	StepResults     json.RawMessage `json:"step_results"`
+	BrowserResult   json.RawMessage `json:"browser_result"`
 }
 
 func (q *Queries) CreateMonitorLog(ctx context.Context, arg CreateMonitorLogParams) (MonitorLog, error) {
@@ -72,6 +89,19 @@ func (q *Queries) CreateMonitorLog(ctx context.Context, arg CreateMonitorLogPara
 		arg.SslOk,
 		arg.ContentOk,
 		arg.ScreenshotUrl,
+		arg.ScreenshotKey,
+		arg.DnsMs,
+		arg.ConnectMs,
+		arg.TlsMs,
+		arg.TtfbMs,
+		arg.TransferMs,
+		arg.ErrorType,
+		arg.ErrorMessage,
+		arg.RemoteIp,
+		arg.ResponseHeaders,
+		arg.ResponseBody,
+		arg.StepResults,
+		arg.BrowserResult,
 	)
 	var i MonitorLog
 	err := row.Scan(
@@ -83,13 +113,118 @@ func (q *Queries) CreateMonitorLog(ctx context.Context, arg CreateMonitorLogPara
 		&i.SslOk,
 		&i.ContentOk,
 		&i.ScreenshotUrl,
+		&i.ScreenshotKey,
+		&i.DnsMs,
+		&i.ConnectMs,
+		&i.TlsMs,
+		&i.TtfbMs,
+		&i.TransferMs,
+		&i.ErrorType,
+		&i.ErrorMessage,
+		&i.RemoteIp,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.StepResults,
+		&i.BrowserResult,
+		&i.CheckedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredMonitorLogs = `-- name: DeleteExpiredMonitorLogs :many
+DELETE FROM monitor_logs
+WHERE (id, checked_at) IN (
+    SELECT l.id, l.checked_at
+    FROM monitor_logs l
+    JOIN monitors m ON m.id = l.monitor_id
+    LEFT JOIN subscriptions s ON s.org_id = m.org_id AND s.is_active
+    WHERE l.checked_at < $1::timestamp
+      AND COALESCE(s.plan, $2::text) = $3::text
+    LIMIT $4::int
+)
+RETURNING screenshot_key
+`
+
+type DeleteExpiredMonitorLogsParams struct {
+	Cutoff      pgtype.Timestamp `json:"cutoff"`
+	DefaultPlan string           `json:"default_plan"`
+	Plan        string           `json:"plan"`
+	BatchSize   int32            `json:"batch_size"`
+}
+
+// Deletes at most batch_size logs older than cutoff for monitors of
+// organizations on plan; default_plan is the plan of organizations without an
+// active subscription. Small batches keep locks short so the retention job
+// can run online. Returns the screenshot key of every deleted log (NULL for
+// most) so their artifacts can be deleted as well.
+func (q *Queries) DeleteExpiredMonitorLogs(ctx context.Context, arg DeleteExpiredMonitorLogsParams) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, deleteExpiredMonitorLogs,
+		arg.Cutoff,
+		arg.DefaultPlan,
+		arg.Plan,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.Text{}
+	for rows.Next() {
+		var screenshot_key pgtype.Text
+		if err := rows.Scan(&screenshot_key); err != nil {
+			return nil, err
+		}
+		items = append(items, screenshot_key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMonitorLogByID = `-- name: GetMonitorLogByID :one
+SELECT id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok, screenshot_url, screenshot_key, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, error_type, error_message, remote_ip, response_headers, response_body, step_results, browser_result, checked_at FROM monitor_logs
+WHERE id = $1 AND monitor_id = $2
+`
+
+type GetMonitorLogByIDParams struct {
+	ID        int32       `json:"id"`
+	MonitorID pgtype.Int4 `json:"monitor_id"`
+}
+
+func (q *Queries) GetMonitorLogByID(ctx context.Context, arg GetMonitorLogByIDParams) (MonitorLog, error) {
+	row := q.db.QueryRow(ctx, getMonitorLogByID, arg.ID, arg.MonitorID)
+	var i MonitorLog
+	err := row.Scan(
+		&i.ID,
+		&i.MonitorID,
+		&i.StatusCode,
+		&i.ResponseTime,
+		&i.DnsOk,
+		&i.SslOk,
+		&i.ContentOk,
+		&i.ScreenshotUrl,
+		&i.ScreenshotKey,
+		&i.DnsMs,
+		&i.ConnectMs,
+		&i.TlsMs,
+		&i.TtfbMs,
+		&i.TransferMs,
+		&i.ErrorType,
+		&i.ErrorMessage,
+		&i.RemoteIp,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.StepResults,
+		&i.BrowserResult,
 		&i.CheckedAt,
 	)
 	return i, err
 }
 
 const getMonitorLogs = `-- name: GetMonitorLogs :many
-SELECT id , monitor_id,status_code,response_time,dns_ok,ssl_ok,content_ok,screenshot_url,checked_at
+SELECT id , monitor_id,status_code,response_time,dns_ok,ssl_ok,content_ok,screenshot_url,
+       dns_ms,connect_ms,tls_ms,ttfb_ms,transfer_ms,error_type,error_message,checked_at
 FROM monitor_logs
 WHERE monitor_id = $1
    AND ( $2::TIMESTAMP IS NULL OR checked_at >= $2)
@@ -106,7 +241,26 @@ type GetMonitorLogsParams struct {
 	Offset    int32            `json:"offset"`
 }
 
-func (q *Queries) GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams) ([]MonitorLog, error) {
+type GetMonitorLogsRow struct {
+	ID            int32            `json:"id"`
+	MonitorID     pgtype.Int4      `json:"monitor_id"`
+	StatusCode    pgtype.Int4      `json:"status_code"`
+	ResponseTime  pgtype.Float8    `json:"response_time"`
+	DnsOk         pgtype.Bool      `json:"dns_ok"`
+	SslOk         pgtype.Bool      `json:"ssl_ok"`
+	ContentOk     pgtype.Bool      `json:"content_ok"`
+	ScreenshotUrl pgtype.Text      `json:"screenshot_url"`
+	DnsMs         pgtype.Float8    `json:"dns_ms"`
+	ConnectMs     pgtype.Float8    `json:"connect_ms"`
+	TlsMs         pgtype.Float8    `json:"tls_ms"`
+	TtfbMs        pgtype.Float8    `json:"ttfb_ms"`
+	TransferMs    pgtype.Float8    `json:"transfer_ms"`
+	ErrorType     pgtype.Text      `json:"error_type"`
+	ErrorMessage  pgtype.Text      `json:"error_message"`
+	CheckedAt     pgtype.Timestamp `json:"checked_at"`
+}
+
+func (q *Queries) GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams) ([]GetMonitorLogsRow, error) {
 	rows, err := q.db.Query(ctx, getMonitorLogs,
 		arg.MonitorID,
 		arg.Column2,
@@ -118,9 +272,9 @@ func (q *Queries) GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams)
 		return nil, err
 	}
 	defer rows.Close()
-	items := []MonitorLog{}
+	items := []GetMonitorLogsRow{}
 	for rows.Next() {
-		var i MonitorLog
+		var i GetMonitorLogsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.MonitorID,
@@ -130,6 +284,13 @@ func (q *Queries) GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams)
 			&i.SslOk,
 			&i.ContentOk,
 			&i.ScreenshotUrl,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.ErrorType,
+			&i.ErrorMessage,
 			&i.CheckedAt,
 		); err != nil {
 			return nil, err
@@ -143,7 +304,7 @@ func (q *Queries) GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams)
 }
 
 const getMonitorLogsByTimeRange = `-- name: GetMonitorLogsByTimeRange :many
-SELECT id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok, screenshot_url, checked_at FROM monitor_logs 
+SELECT id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok, screenshot_url, screenshot_key, dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, error_type, error_message, remote_ip, response_headers, response_body, step_results, browser_result, checked_at FROM monitor_logs 
 WHERE monitor_id = $1 
 AND checked_at BETWEEN $2 AND $3 
 ORDER BY checked_at DESC
@@ -173,6 +334,19 @@ func (q *Queries) GetMonitorLogsByTimeRange(ctx context.Context, arg GetMonitorL
 			&i.SslOk,
 			&i.ContentOk,
 			&i.ScreenshotUrl,
+			&i.ScreenshotKey,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.ErrorType,
+			&i.ErrorMessage,
+			&i.RemoteIp,
+			&i.ResponseHeaders,
+			&i.ResponseBody,
+			&i.StepResults,
+			&i.BrowserResult,
 			&i.CheckedAt,
 		); err != nil {
 			return nil, err
@@ -205,3 +379,105 @@ func (q *Queries) GetRecentMonitorStatus(ctx context.Context, monitorID pgtype.I
 	err := row.Scan(&i.StatusCode, &i.ResponseTime, &i.CheckedAt)
 	return i, err
 }
+
+const listMonitorLogsPage = `-- name: ListMonitorLogsPage :many
+SELECT id, monitor_id, status_code, response_time, dns_ok, ssl_ok, content_ok,
+       dns_ms, connect_ms, tls_ms, ttfb_ms, transfer_ms, error_type, error_message, checked_at
+FROM monitor_logs
+WHERE monitor_id = $1
+  AND ($2::timestamp IS NULL OR checked_at >= $2::timestamp)
+  AND ($3::timestamp IS NULL OR checked_at < $3::timestamp)
+  AND ($4::timestamp IS NULL
+       OR (checked_at, id) < ($4::timestamp, $5::int))
+ORDER BY checked_at DESC, id DESC
+LIMIT $6::int
+`
+
+type ListMonitorLogsPageParams struct {
+	MonitorID       pgtype.Int4      `json:"monitor_id"`
+	Since           pgtype.Timestamp `json:"since"`
+	Until           pgtype.Timestamp `json:"until"`
+	BeforeCheckedAt pgtype.Timestamp `json:"before_checked_at"`
+	BeforeID        int32            `json:"before_id"`
+	PageSize        int32            `json:"page_size"`
+}
+
+type ListMonitorLogsPageRow struct {
+	ID           int32            `json:"id"`
+	MonitorID    pgtype.Int4      `json:"monitor_id"`
+	StatusCode   pgtype.Int4      `json:"status_code"`
+	ResponseTime pgtype.Float8    `json:"response_time"`
+	DnsOk        pgtype.Bool      `json:"dns_ok"`
+	SslOk        pgtype.Bool      `json:"ssl_ok"`
+	ContentOk    pgtype.Bool      `json:"content_ok"`
+	DnsMs        pgtype.Float8    `json:"dns_ms"`
+	ConnectMs    pgtype.Float8    `json:"connect_ms"`
+	TlsMs        pgtype.Float8    `json:"tls_ms"`
+	TtfbMs       pgtype.Float8    `json:"ttfb_ms"`
+	TransferMs   pgtype.Float8    `json:"transfer_ms"`
+	ErrorType    pgtype.Text      `json:"error_type"`
+	ErrorMessage pgtype.Text      `json:"error_message"`
+	CheckedAt    pgtype.Timestamp `json:"checked_at"`
+}
+
+// Pages through a monitor's logs newest first, before the checked_at and id
+// of the last log of the previous page
+func (q *Queries) ListMonitorLogsPage(ctx context.Context, arg ListMonitorLogsPageParams) ([]ListMonitorLogsPageRow, error) {
+	rows, err := q.db.Query(ctx, listMonitorLogsPage,
+		arg.MonitorID,
+		arg.Since,
+		arg.Until,
+		arg.BeforeCheckedAt,
+		arg.BeforeID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMonitorLogsPageRow{}
+	for rows.Next() {
+		var i ListMonitorLogsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.StatusCode,
+			&i.ResponseTime,
+			&i.DnsOk,
+			&i.SslOk,
+			&i.ContentOk,
+			&i.DnsMs,
+			&i.ConnectMs,
+			&i.TlsMs,
+			&i.TtfbMs,
+			&i.TransferMs,
+			&i.ErrorType,
+			&i.ErrorMessage,
+			&i.CheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setMonitorLogScreenshot = `-- name: SetMonitorLogScreenshot :exec
+UPDATE monitor_logs
+SET screenshot_key = $3
+WHERE id = $1 AND checked_at = $2
+`
+
+type SetMonitorLogScreenshotParams struct {
+	ID            int32            `json:"id"`
+	CheckedAt     pgtype.Timestamp `json:"checked_at"`
+	ScreenshotKey pgtype.Text      `json:"screenshot_key"`
+}
+
+func (q *Queries) SetMonitorLogScreenshot(ctx context.Context, arg SetMonitorLogScreenshotParams) error {
+	_, err := q.db.Exec(ctx, setMonitorLogScreenshot, arg.ID, arg.CheckedAt, arg.ScreenshotKey)
+	return err
+}
@@ -0,0 +1,205 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: monitor_rollup.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteMonitorRollupsBefore = `-- name: DeleteMonitorRollupsBefore :execrows
+DELETE FROM monitor_rollups
+WHERE resolution = $1
+  AND bucket_start < $2::timestamp
+`
+
+type DeleteMonitorRollupsBeforeParams struct {
+	Resolution RollupResolution `json:"resolution"`
+	Before     pgtype.Timestamp `json:"before"`
+}
+
+func (q *Queries) DeleteMonitorRollupsBefore(ctx context.Context, arg DeleteMonitorRollupsBeforeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMonitorRollupsBefore, arg.Resolution, arg.Before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getMonitorRollups = `-- name: GetMonitorRollups :many
+SELECT monitor_id, resolution, bucket_start, check_count, success_count, response_count, min_response_time, avg_response_time, max_response_time, p50_response_time, p90_response_time, p95_response_time, p99_response_time, updated_at FROM monitor_rollups
+WHERE monitor_id = $1
+  AND resolution = $2
+  AND bucket_start >= $3::timestamp
+  AND bucket_start < $4::timestamp
+ORDER BY bucket_start
+`
+
+type GetMonitorRollupsParams struct {
+	MonitorID  int32            `json:"monitor_id"`
+	Resolution RollupResolution `json:"resolution"`
+	Since      pgtype.Timestamp `json:"since"`
+	Until      pgtype.Timestamp `json:"until"`
+}
+
+func (q *Queries) GetMonitorRollups(ctx context.Context, arg GetMonitorRollupsParams) ([]MonitorRollup, error) {
+	rows, err := q.db.Query(ctx, getMonitorRollups,
+		arg.MonitorID,
+		arg.Resolution,
+		arg.Since,
+		arg.Until,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MonitorRollup{}
+	for rows.Next() {
+		var i MonitorRollup
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.Resolution,
+			&i.BucketStart,
+			&i.CheckCount,
+			&i.SuccessCount,
+			&i.ResponseCount,
+			&i.MinResponseTime,
+			&i.AvgResponseTime,
+			&i.MaxResponseTime,
+			&i.P50ResponseTime,
+			&i.P90ResponseTime,
+			&i.P95ResponseTime,
+			&i.P99ResponseTime,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRollupTotalsForMonitors = `-- name: GetRollupTotalsForMonitors :many
+SELECT
+    monitor_id,
+    SUM(check_count)::bigint AS total_checks,
+    SUM(success_count)::bigint AS successful_checks,
+    COALESCE(SUM(avg_response_time * response_count) / NULLIF(SUM(response_count), 0), 0)::float AS avg_response_time
+FROM monitor_rollups
+WHERE monitor_id = ANY($1::int[])
+  AND resolution = $2
+  AND bucket_start >= $3::timestamp
+GROUP BY monitor_id
+`
+
+type GetRollupTotalsForMonitorsParams struct {
+	MonitorIds []int32          `json:"monitor_ids"`
+	Resolution RollupResolution `json:"resolution"`
+	Since      pgtype.Timestamp `json:"since"`
+}
+
+type GetRollupTotalsForMonitorsRow struct {
+	MonitorID        int32   `json:"monitor_id"`
+	TotalChecks      int64   `json:"total_checks"`
+	SuccessfulChecks int64   `json:"successful_checks"`
+	AvgResponseTime  float64 `json:"avg_response_time"`
+}
+
+func (q *Queries) GetRollupTotalsForMonitors(ctx context.Context, arg GetRollupTotalsForMonitorsParams) ([]GetRollupTotalsForMonitorsRow, error) {
+	rows, err := q.db.Query(ctx, getRollupTotalsForMonitors, arg.MonitorIds, arg.Resolution, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRollupTotalsForMonitorsRow{}
+	for rows.Next() {
+		var i GetRollupTotalsForMonitorsRow
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.TotalChecks,
+			&i.SuccessfulChecks,
+			&i.AvgResponseTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rollupMonitorLogs = `-- name: RollupMonitorLogs :execrows
+INSERT INTO monitor_rollups (
+    monitor_id, resolution, bucket_start,
+    check_count, success_count, response_count,
+    min_response_time, avg_response_time, max_response_time,
+    p50_response_time, p90_response_time, p95_response_time, p99_response_time
+)
+SELECT
+    l.monitor_id,
+    $1::rollup_resolution,
+    date_trunc($2::text, l.checked_at)::timestamp AS bucket,
+    COUNT(*)::int,
+    COUNT(*) FILTER (WHERE l.content_ok IS NOT FALSE AND l.status_code BETWEEN 200 AND 399)::int,
+    COUNT(*) FILTER (WHERE l.response_time > 0)::int,
+    MIN(l.response_time) FILTER (WHERE l.response_time > 0),
+    AVG(l.response_time) FILTER (WHERE l.response_time > 0),
+    MAX(l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.50) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.90) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.95) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0),
+    percentile_cont(0.99) WITHIN GROUP (ORDER BY l.response_time) FILTER (WHERE l.response_time > 0)
+FROM monitor_logs l
+WHERE l.monitor_id IS NOT NULL
+  AND l.checked_at >= $3::timestamp
+  AND l.checked_at < $4::timestamp
+GROUP BY l.monitor_id, bucket
+ON CONFLICT (monitor_id, resolution, bucket_start)
+DO UPDATE SET
+    check_count = EXCLUDED.check_count,
+    success_count = EXCLUDED.success_count,
+    response_count = EXCLUDED.response_count,
+    min_response_time = EXCLUDED.min_response_time,
+    avg_response_time = EXCLUDED.avg_response_time,
+    max_response_time = EXCLUDED.max_response_time,
+    p50_response_time = EXCLUDED.p50_response_time,
+    p90_response_time = EXCLUDED.p90_response_time,
+    p95_response_time = EXCLUDED.p95_response_time,
+    p99_response_time = EXCLUDED.p99_response_time,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type RollupMonitorLogsParams struct {
+	Resolution RollupResolution `json:"resolution"`
+	BucketUnit string           `json:"bucket_unit"`
+	Since      pgtype.Timestamp `json:"since"`
+	Until      pgtype.Timestamp `json:"until"`
+}
+
+// Recomputes every bucket of the given resolution that starts in [since, until).
+// since must be aligned to a bucket boundary, otherwise its bucket is rewritten with partial data.
+// Safe to run repeatedly, late logs simply update their bucket.
+// A check is up when it passed (content_ok, false for failed assertions of API
+// and database checks that still got a 200) with a 2xx/3xx code; older logs
+// have content_ok NULL or always TRUE, so the code decides for them.
+func (q *Queries) RollupMonitorLogs(ctx context.Context, arg RollupMonitorLogsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rollupMonitorLogs,
+		arg.Resolution,
+		arg.BucketUnit,
+		arg.Since,
+		arg.Until,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: monitor_secret.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteMonitorSecret = `-- name: DeleteMonitorSecret :exec
+DELETE FROM monitor_secrets
+WHERE monitor_id = $1 AND name = $2
+`
+
+type DeleteMonitorSecretParams struct {
+	MonitorID int32  `json:"monitor_id"`
+	Name      string `json:"name"`
+}
+
+func (q *Queries) DeleteMonitorSecret(ctx context.Context, arg DeleteMonitorSecretParams) error {
+	_, err := q.db.Exec(ctx, deleteMonitorSecret, arg.MonitorID, arg.Name)
+	return err
+}
+
+const getMonitorSecrets = `-- name: GetMonitorSecrets :many
+SELECT name, ciphertext
+FROM monitor_secrets
+WHERE monitor_id = $1
+`
+
+type GetMonitorSecretsRow struct {
+	Name       string `json:"name"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (q *Queries) GetMonitorSecrets(ctx context.Context, monitorID int32) ([]GetMonitorSecretsRow, error) {
+	rows, err := q.db.Query(ctx, getMonitorSecrets, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMonitorSecretsRow{}
+	for rows.Next() {
+		var i GetMonitorSecretsRow
+		if err := rows.Scan(&i.Name, &i.Ciphertext); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMonitorSecretHints = `-- name: ListMonitorSecretHints :many
+SELECT name, hint, updated_at
+FROM monitor_secrets
+WHERE monitor_id = $1
+ORDER BY name
+`
+
+type ListMonitorSecretHintsRow struct {
+	Name      string           `json:"name"`
+	Hint      string           `json:"hint"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) ListMonitorSecretHints(ctx context.Context, monitorID int32) ([]ListMonitorSecretHintsRow, error) {
+	rows, err := q.db.Query(ctx, listMonitorSecretHints, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMonitorSecretHintsRow{}
+	for rows.Next() {
+		var i ListMonitorSecretHintsRow
+		if err := rows.Scan(&i.Name, &i.Hint, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMonitorSecretsToRewrap = `-- name: ListMonitorSecretsToRewrap :many
+SELECT monitor_id, name, ciphertext, key_id
+FROM monitor_secrets
+WHERE key_id <> $1::text
+  AND (monitor_id, name) > ($2::int, $3::text)
+ORDER BY monitor_id, name
+LIMIT $4::int
+`
+
+type ListMonitorSecretsToRewrapParams struct {
+	ActiveKeyID    string `json:"active_key_id"`
+	AfterMonitorID int32  `json:"after_monitor_id"`
+	AfterName      string `json:"after_name"`
+	BatchSize      int32  `json:"batch_size"`
+}
+
+type ListMonitorSecretsToRewrapRow struct {
+	MonitorID  int32  `json:"monitor_id"`
+	Name       string `json:"name"`
+	Ciphertext []byte `json:"ciphertext"`
+	KeyID      string `json:"key_id"`
+}
+
+// Pages through secrets not sealed under the active key, after the given row
+func (q *Queries) ListMonitorSecretsToRewrap(ctx context.Context, arg ListMonitorSecretsToRewrapParams) ([]ListMonitorSecretsToRewrapRow, error) {
+	rows, err := q.db.Query(ctx, listMonitorSecretsToRewrap,
+		arg.ActiveKeyID,
+		arg.AfterMonitorID,
+		arg.AfterName,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMonitorSecretsToRewrapRow{}
+	for rows.Next() {
+		var i ListMonitorSecretsToRewrapRow
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.Name,
+			&i.Ciphertext,
+			&i.KeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSecretHintsForMonitors = `-- name: ListSecretHintsForMonitors :many
+SELECT monitor_id, name, hint, updated_at
+FROM monitor_secrets
+WHERE monitor_id = ANY($1::int[])
+ORDER BY monitor_id, name
+`
+
+type ListSecretHintsForMonitorsRow struct {
+	MonitorID int32            `json:"monitor_id"`
+	Name      string           `json:"name"`
+	Hint      string           `json:"hint"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) ListSecretHintsForMonitors(ctx context.Context, monitorIds []int32) ([]ListSecretHintsForMonitorsRow, error) {
+	rows, err := q.db.Query(ctx, listSecretHintsForMonitors, monitorIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSecretHintsForMonitorsRow{}
+	for rows.Next() {
+		var i ListSecretHintsForMonitorsRow
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.Name,
+			&i.Hint,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rewrapMonitorSecret = `-- name: RewrapMonitorSecret :execrows
+UPDATE monitor_secrets
+SET ciphertext = $1, key_id = $2::text
+WHERE monitor_id = $3
+  AND name = $4
+  AND key_id = $5::text
+`
+
+type RewrapMonitorSecretParams struct {
+	Ciphertext []byte `json:"ciphertext"`
+	NewKeyID   string `json:"new_key_id"`
+	MonitorID  int32  `json:"monitor_id"`
+	Name       string `json:"name"`
+	OldKeyID   string `json:"old_key_id"`
+}
+
+// Only replaces the ciphertext if nobody rewrote the secret since it was read
+func (q *Queries) RewrapMonitorSecret(ctx context.Context, arg RewrapMonitorSecretParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rewrapMonitorSecret,
+		arg.Ciphertext,
+		arg.NewKeyID,
+		arg.MonitorID,
+		arg.Name,
+		arg.OldKeyID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const upsertMonitorSecret = `-- name: UpsertMonitorSecret :exec
+INSERT INTO monitor_secrets (monitor_id, name, ciphertext, key_id, hint)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (monitor_id, name)
+DO UPDATE SET
+    ciphertext = EXCLUDED.ciphertext,
+    key_id = EXCLUDED.key_id,
+    hint = EXCLUDED.hint,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertMonitorSecretParams struct {
+	MonitorID  int32  `json:"monitor_id"`
+	Name       string `json:"name"`
+	Ciphertext []byte `json:"ciphertext"`
+	KeyID      string `json:"key_id"`
+	Hint       string `json:"hint"`
+}
+
+func (q *Queries) UpsertMonitorSecret(ctx context.Context, arg UpsertMonitorSecretParams) error {
+	_, err := q.db.Exec(ctx, upsertMonitorSecret,
+		arg.MonitorID,
+		arg.Name,
+		arg.Ciphertext,
+		arg.KeyID,
+		arg.Hint,
+	)
+	return err
+}
@@ -0,0 +1,448 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: organization.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addOrgMember = `-- name: AddOrgMember :one
+INSERT INTO org_memberships (org_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (org_id, user_id) DO UPDATE SET role = org_memberships.role
+RETURNING org_id, user_id, role, created_at
+`
+
+type AddOrgMemberParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Role   OrgRole   `json:"role"`
+}
+
+// Existing members keep their role
+func (q *Queries) AddOrgMember(ctx context.Context, arg AddOrgMemberParams) (OrgMembership, error) {
+	row := q.db.QueryRow(ctx, addOrgMember, arg.OrgID, arg.UserID, arg.Role)
+	var i OrgMembership
+	err := row.Scan(
+		&i.OrgID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const countOrgOwners = `-- name: CountOrgOwners :one
+SELECT COUNT(*)::bigint FROM org_memberships
+WHERE org_id = $1 AND role = 'owner'
+`
+
+func (q *Queries) CountOrgOwners(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrgOwners, orgID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createOrgInvite = `-- name: CreateOrgInvite :one
+INSERT INTO org_invites (org_id, email, role, token, invited_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (org_id, email) WHERE accepted_at IS NULL
+DO UPDATE SET
+    role = EXCLUDED.role,
+    token = EXCLUDED.token,
+    invited_by = EXCLUDED.invited_by,
+    expires_at = EXCLUDED.expires_at,
+    created_at = CURRENT_TIMESTAMP
+RETURNING id, org_id, email, role, token, invited_by, expires_at, accepted_at, created_at
+`
+
+type CreateOrgInviteParams struct {
+	OrgID     uuid.UUID        `json:"org_id"`
+	Email     string           `json:"email"`
+	Role      OrgRole          `json:"role"`
+	Token     string           `json:"token"`
+	InvitedBy pgtype.UUID      `json:"invited_by"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+// Re-inviting an address replaces its pending invite and token
+func (q *Queries) CreateOrgInvite(ctx context.Context, arg CreateOrgInviteParams) (OrgInvite, error) {
+	row := q.db.QueryRow(ctx, createOrgInvite,
+		arg.OrgID,
+		arg.Email,
+		arg.Role,
+		arg.Token,
+		arg.InvitedBy,
+		arg.ExpiresAt,
+	)
+	var i OrgInvite
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Email,
+		&i.Role,
+		&i.Token,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createOrganization = `-- name: CreateOrganization :one
+INSERT INTO organizations (name, created_by)
+VALUES ($1, $2)
+RETURNING id, name, created_by, created_at, updated_at
+`
+
+type CreateOrganizationParams struct {
+	Name      string      `json:"name"`
+	CreatedBy pgtype.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, createOrganization, arg.Name, arg.CreatedBy)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOrgInvite = `-- name: DeleteOrgInvite :execrows
+DELETE FROM org_invites
+WHERE id = $1 AND org_id = $2 AND accepted_at IS NULL
+`
+
+type DeleteOrgInviteParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteOrgInvite(ctx context.Context, arg DeleteOrgInviteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrgInvite, arg.ID, arg.OrgID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOrgMember = `-- name: DeleteOrgMember :execrows
+DELETE FROM org_memberships
+WHERE org_id = $1 AND user_id = $2
+`
+
+type DeleteOrgMemberParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteOrgMember(ctx context.Context, arg DeleteOrgMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrgMember, arg.OrgID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getDefaultOrgMembership = `-- name: GetDefaultOrgMembership :one
+SELECT org_id, user_id, role, created_at FROM org_memberships
+WHERE user_id = $1
+ORDER BY created_at, org_id
+LIMIT 1
+`
+
+func (q *Queries) GetDefaultOrgMembership(ctx context.Context, userID uuid.UUID) (OrgMembership, error) {
+	row := q.db.QueryRow(ctx, getDefaultOrgMembership, userID)
+	var i OrgMembership
+	err := row.Scan(
+		&i.OrgID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOrgMembership = `-- name: GetOrgMembership :one
+SELECT org_id, user_id, role, created_at FROM org_memberships
+WHERE org_id = $1 AND user_id = $2
+`
+
+type GetOrgMembershipParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetOrgMembership(ctx context.Context, arg GetOrgMembershipParams) (OrgMembership, error) {
+	row := q.db.QueryRow(ctx, getOrgMembership, arg.OrgID, arg.UserID)
+	var i OrgMembership
+	err := row.Scan(
+		&i.OrgID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOrganization = `-- name: GetOrganization :one
+SELECT id, name, created_by, created_at, updated_at FROM organizations
+WHERE id = $1
+`
+
+func (q *Queries) GetOrganization(ctx context.Context, id uuid.UUID) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganization, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPendingOrgInviteByToken = `-- name: GetPendingOrgInviteByToken :one
+SELECT id, org_id, email, role, token, invited_by, expires_at, accepted_at, created_at FROM org_invites
+WHERE token = $1 AND accepted_at IS NULL AND expires_at > NOW()
+`
+
+func (q *Queries) GetPendingOrgInviteByToken(ctx context.Context, token string) (OrgInvite, error) {
+	row := q.db.QueryRow(ctx, getPendingOrgInviteByToken, token)
+	var i OrgInvite
+	err := row.Scan(
+		&i.ID,
+		&i.OrgID,
+		&i.Email,
+		&i.Role,
+		&i.Token,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listOrgMembers = `-- name: ListOrgMembers :many
+SELECT om.user_id, u.email, u.fullname, om.role, om.created_at AS joined_at
+FROM org_memberships om
+JOIN users u ON u.id = om.user_id
+WHERE om.org_id = $1
+ORDER BY om.created_at, u.email
+`
+
+type ListOrgMembersRow struct {
+	UserID   uuid.UUID        `json:"user_id"`
+	Email    string           `json:"email"`
+	Fullname string           `json:"fullname"`
+	Role     OrgRole          `json:"role"`
+	JoinedAt pgtype.Timestamp `json:"joined_at"`
+}
+
+func (q *Queries) ListOrgMembers(ctx context.Context, orgID uuid.UUID) ([]ListOrgMembersRow, error) {
+	rows, err := q.db.Query(ctx, listOrgMembers, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOrgMembersRow{}
+	for rows.Next() {
+		var i ListOrgMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Email,
+			&i.Fullname,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingOrgInvites = `-- name: ListPendingOrgInvites :many
+SELECT id, org_id, email, role, invited_by, expires_at, created_at FROM org_invites
+WHERE org_id = $1 AND accepted_at IS NULL
+ORDER BY created_at DESC
+`
+
+type ListPendingOrgInvitesRow struct {
+	ID        int32            `json:"id"`
+	OrgID     uuid.UUID        `json:"org_id"`
+	Email     string           `json:"email"`
+	Role      OrgRole          `json:"role"`
+	InvitedBy pgtype.UUID      `json:"invited_by"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+func (q *Queries) ListPendingOrgInvites(ctx context.Context, orgID uuid.UUID) ([]ListPendingOrgInvitesRow, error) {
+	rows, err := q.db.Query(ctx, listPendingOrgInvites, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPendingOrgInvitesRow{}
+	for rows.Next() {
+		var i ListPendingOrgInvitesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrgID,
+			&i.Email,
+			&i.Role,
+			&i.InvitedBy,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserOrganizations = `-- name: ListUserOrganizations :many
+SELECT o.id, o.name, om.role, om.created_at AS joined_at
+FROM org_memberships om
+JOIN organizations o ON o.id = om.org_id
+WHERE om.user_id = $1
+ORDER BY om.created_at, o.id
+`
+
+type ListUserOrganizationsRow struct {
+	ID       uuid.UUID        `json:"id"`
+	Name     string           `json:"name"`
+	Role     OrgRole          `json:"role"`
+	JoinedAt pgtype.Timestamp `json:"joined_at"`
+}
+
+func (q *Queries) ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]ListUserOrganizationsRow, error) {
+	rows, err := q.db.Query(ctx, listUserOrganizations, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserOrganizationsRow{}
+	for rows.Next() {
+		var i ListUserOrganizationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOrganization = `-- name: LockOrganization :exec
+SELECT id FROM organizations
+WHERE id = $1
+FOR UPDATE
+`
+
+// Serializes membership changes that must keep an owner within a transaction
+func (q *Queries) LockOrganization(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, lockOrganization, id)
+	return err
+}
+
+const lockUserOrganizations = `-- name: LockUserOrganizations :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::uuid::text, 0))
+`
+
+// Serializes creating a user's personal organization within a transaction
+func (q *Queries) LockUserOrganizations(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, lockUserOrganizations, userID)
+	return err
+}
+
+const markOrgInviteAccepted = `-- name: MarkOrgInviteAccepted :exec
+UPDATE org_invites
+SET accepted_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkOrgInviteAccepted(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, markOrgInviteAccepted, id)
+	return err
+}
+
+const updateOrgMemberRole = `-- name: UpdateOrgMemberRole :one
+UPDATE org_memberships
+SET role = $3
+WHERE org_id = $1 AND user_id = $2
+RETURNING org_id, user_id, role, created_at
+`
+
+type UpdateOrgMemberRoleParams struct {
+	OrgID  uuid.UUID `json:"org_id"`
+	UserID uuid.UUID `json:"user_id"`
+	Role   OrgRole   `json:"role"`
+}
+
+func (q *Queries) UpdateOrgMemberRole(ctx context.Context, arg UpdateOrgMemberRoleParams) (OrgMembership, error) {
+	row := q.db.QueryRow(ctx, updateOrgMemberRole, arg.OrgID, arg.UserID, arg.Role)
+	var i OrgMembership
+	err := row.Scan(
+		&i.OrgID,
+		&i.UserID,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateOrganization = `-- name: UpdateOrganization :one
+UPDATE organizations
+SET name = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, name, created_by, created_at, updated_at
+`
+
+type UpdateOrganizationParams struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (q *Queries) UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganization, arg.ID, arg.Name)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// monitor_logs is range partitioned by month. sqlc can't express this DDL,
+// so partitions are managed here by hand.
+
+const monitorLogPartitionPrefix = "monitor_logs_p"
+
+func monitorLogPartitionName(month time.Time) string {
+	return monitorLogPartitionPrefix + month.Format("200601")
+}
+
+func startOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// EnsureMonitorLogPartitions creates the default partition and one partition per
+// month from the month of `from` up to monthsAhead months after the current one.
+// Existing partitions are left alone, so this is safe to call repeatedly. Months
+// still covered by monitor_logs_legacy (see scripts/partition-monitor-logs.sql)
+// are skipped.
+func (store *sqlStore) EnsureMonitorLogPartitions(ctx context.Context, from time.Time, monthsAhead int) error {
+	if _, err := store.db.Exec(ctx, `CREATE TABLE IF NOT EXISTS monitor_logs_default PARTITION OF monitor_logs DEFAULT`); err != nil {
+		return fmt.Errorf("create default partition: %w", err)
+	}
+
+	last := startOfMonth(time.Now()).AddDate(0, monthsAhead, 0)
+	for month := startOfMonth(from); !month.After(last); month = month.AddDate(0, 1, 0) {
+		sql := fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s PARTITION OF monitor_logs FOR VALUES FROM ('%s') TO ('%s')`,
+			monitorLogPartitionName(month),
+			month.Format("2006-01-02"),
+			month.AddDate(0, 1, 0).Format("2006-01-02"),
+		)
+		if _, err := store.db.Exec(ctx, sql); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "42P17" {
+				// Overlaps the range of an existing partition
+				continue
+			}
+			// Fails when monitor_logs_default already holds rows for that month
+			return fmt.Errorf("create partition %s: %w", monitorLogPartitionName(month), err)
+		}
+	}
+
+	return nil
+}
+
+// DropMonitorLogPartitionsBefore removes every monthly partition that ends on or
+// before `before`. Each partition is detached and dropped in its own short
+// transaction. DETACH ... CONCURRENTLY isn't allowed while monitor_logs_default
+// exists, so the plain DETACH gives up after a lock timeout instead of queueing
+// writes to monitor_logs behind it; the next run retries. Returns the dropped names.
+func (store *sqlStore) DropMonitorLogPartitionsBefore(ctx context.Context, before time.Time) ([]string, error) {
+	rows, err := store.db.Query(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'monitor_logs'::regclass
+		ORDER BY c.relname`)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if !strings.HasPrefix(name, monitorLogPartitionPrefix) {
+			continue
+		}
+		month, err := time.Parse("200601", strings.TrimPrefix(name, monitorLogPartitionPrefix))
+		if err != nil {
+			continue
+		}
+		if !month.AddDate(0, 1, 0).After(before) {
+			expired = append(expired, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	dropped := make([]string, 0, len(expired))
+	for _, name := range expired {
+		if err := store.dropMonitorLogPartition(ctx, name); err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, name)
+	}
+
+	return dropped, nil
+}
+
+func (store *sqlStore) dropMonitorLogPartition(ctx context.Context, name string) error {
+	tx, err := store.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SET LOCAL lock_timeout = '5s'`); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE monitor_logs DETACH PARTITION %s`, name)); err != nil {
+		return fmt.Errorf("detach partition %s: %w", name, err)
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, name)); err != nil {
+		return fmt.Errorf("drop partition %s: %w", name, err)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -12,53 +12,226 @@ import (
 )
 
 type Querier interface {
+	// Existing members keep their role
+	AddOrgMember(ctx context.Context, arg AddOrgMemberParams) (OrgMembership, error)
+	AddStatusPageMonitor(ctx context.Context, arg AddStatusPageMonitorParams) (StatusPageMonitor, error)
+	BulkDeleteMonitors(ctx context.Context, arg BulkDeleteMonitorsParams) ([]int32, error)
+	// Adds add_tags and drops remove_tags (which wins), keeping each monitor's
+	// tags sorted and unique
+	BulkRetagMonitors(ctx context.Context, arg BulkRetagMonitorsParams) ([]Monitor, error)
+	BulkSetMonitorsActive(ctx context.Context, arg BulkSetMonitorsActiveParams) ([]Monitor, error)
+	BulkSetMonitorsGroup(ctx context.Context, arg BulkSetMonitorsGroupParams) ([]Monitor, error)
+	BulkSetMonitorsInterval(ctx context.Context, arg BulkSetMonitorsIntervalParams) ([]Monitor, error)
 	CalculateUptimePercentage(ctx context.Context, arg CalculateUptimePercentageParams) (CalculateUptimePercentageRow, error)
+	// Returns no row while another request holds the key; an expired key, or one
+	// whose request never finished, is taken over
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	ConfirmStatusPageSubscriber(ctx context.Context, confirmToken string) (StatusPageSubscriber, error)
 	CountMonitorLogs(ctx context.Context, arg CountMonitorLogsParams) ([]int64, error)
+	CountOrgAlertContacts(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CountOrgMonitors(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CountOrgOwners(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CountOrgStatusPages(ctx context.Context, orgID uuid.UUID) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
 	CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert, error)
 	CreateAlertContact(ctx context.Context, arg CreateAlertContactParams) (AlertContact, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
+	// Returns no row when a verified account has the email, whichever provider it
+	// signed up with. An unverified sign-up is replaced, so whoever owns the
+	// address can always register it.
+	CreateLocalUser(ctx context.Context, arg CreateLocalUserParams) (User, error)
 	CreateMonitor(ctx context.Context, arg CreateMonitorParams) (Monitor, error)
 	CreateMonitorAlertConfig(ctx context.Context, arg CreateMonitorAlertConfigParams) (MonitorAlertConfig, error)
+	CreateMonitorGroup(ctx context.Context, arg CreateMonitorGroupParams) (MonitorGroup, error)
 	CreateMonitorLog(ctx context.Context, arg CreateMonitorLogParams) (MonitorLog, error)
 	CreateOrUpdateAnalytics(ctx context.Context, arg CreateOrUpdateAnalyticsParams) (Analytic, error)
-	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	// Re-inviting an address replaces its pending invite and token
+	CreateOrgInvite(ctx context.Context, arg CreateOrgInviteParams) (OrgInvite, error)
+	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateStatusPage(ctx context.Context, arg CreateStatusPageParams) (StatusPage, error)
+	CreateStatusPageNotice(ctx context.Context, arg CreateStatusPageNoticeParams) (StatusPageNotice, error)
+	CreateStatusPageNoticeUpdate(ctx context.Context, arg CreateStatusPageNoticeUpdateParams) (StatusPageNoticeUpdate, error)
+	CreateStatusPageSection(ctx context.Context, arg CreateStatusPageSectionParams) (StatusPageSection, error)
+	CreateStatusPageSubscriber(ctx context.Context, arg CreateStatusPageSubscriberParams) (StatusPageSubscriber, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserProfile(ctx context.Context, arg CreateUserProfileParams) (UserProfile, error)
-	DeactivateSubscription(ctx context.Context, userID pgtype.UUID) error
+	DeleteAlertContact(ctx context.Context, arg DeleteAlertContactParams) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	// Deletes at most batch_size logs older than cutoff for monitors of
+	// organizations on plan; default_plan is the plan of organizations without an
+	// active subscription. Small batches keep locks short so the retention job
+	// can run online. Returns the screenshot key of every deleted log (NULL for
+	// most) so their artifacts can be deleted as well.
+	DeleteExpiredMonitorLogs(ctx context.Context, arg DeleteExpiredMonitorLogsParams) ([]pgtype.Text, error)
+	DeleteExpiredRefreshTokens(ctx context.Context) (int64, error)
 	DeleteMonitor(ctx context.Context, arg DeleteMonitorParams) error
+	DeleteMonitorAlertConfig(ctx context.Context, arg DeleteMonitorAlertConfigParams) error
+	DeleteMonitorBadge(ctx context.Context, monitorID int32) error
+	// Its monitors are kept, without a group
+	DeleteMonitorGroup(ctx context.Context, arg DeleteMonitorGroupParams) (int64, error)
+	DeleteMonitorRollupsBefore(ctx context.Context, arg DeleteMonitorRollupsBeforeParams) (int64, error)
+	DeleteMonitorSecret(ctx context.Context, arg DeleteMonitorSecretParams) error
+	DeleteOrgInvite(ctx context.Context, arg DeleteOrgInviteParams) (int64, error)
+	DeleteOrgMember(ctx context.Context, arg DeleteOrgMemberParams) (int64, error)
+	DeleteStatusPage(ctx context.Context, arg DeleteStatusPageParams) error
+	DeleteStatusPageSections(ctx context.Context, statusPageID int32) error
+	DeleteStatusPageSubscriberByToken(ctx context.Context, unsubscribeToken string) (StatusPageSubscriber, error)
+	// For identities verified by their provider. An unverified local sign-up with
+	// the same email was made by whoever chose its password, so its password and
+	// sessions are dropped as the verified identity claims it.
 	FindOrCreateUser(ctx context.Context, arg FindOrCreateUserParams) (User, error)
+	GetAPIKey(ctx context.Context, id int32) (ApiKey, error)
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
 	GetActiveMonitors(ctx context.Context) ([]Monitor, error)
-	GetActiveMonitorsForUser(ctx context.Context, userID pgtype.UUID) ([]Monitor, error)
+	GetActiveMonitorsForOrg(ctx context.Context, orgID uuid.UUID) ([]Monitor, error)
+	GetActiveStatusPageNotices(ctx context.Context, statusPageID int32) ([]StatusPageNotice, error)
+	GetAlertConfigsForMonitors(ctx context.Context, monitorIds []int32) ([]MonitorAlertConfig, error)
 	GetAlertContactByID(ctx context.Context, id int32) (AlertContact, error)
 	GetAlertContactsByMonitor(ctx context.Context, monitorID pgtype.Int4) ([]AlertContact, error)
-	GetAlertContactsByUserID(ctx context.Context, userID pgtype.UUID) ([]AlertContact, error)
+	GetAlertContactsByOrg(ctx context.Context, orgID uuid.UUID) ([]AlertContact, error)
 	GetAnalytics(ctx context.Context, monitorID pgtype.Int4) (Analytic, error)
 	GetAverageResponseTime(ctx context.Context, arg GetAverageResponseTimeParams) (pgtype.Numeric, error)
+	GetBadgeMonitorByToken(ctx context.Context, token string) (GetBadgeMonitorByTokenRow, error)
+	GetConfirmedStatusPageSubscribers(ctx context.Context, statusPageID int32) ([]StatusPageSubscriber, error)
+	// Past days come from the daily rollups, which outlive the raw logs; today is
+	// counted from the raw logs so the last bar does not lag the hourly rollup.
+	GetDailyUptimeForMonitors(ctx context.Context, arg GetDailyUptimeForMonitorsParams) ([]GetDailyUptimeForMonitorsRow, error)
+	GetDefaultOrgMembership(ctx context.Context, userID uuid.UUID) (OrgMembership, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetIncidentStartsForMonitor(ctx context.Context, arg GetIncidentStartsForMonitorParams) ([]pgtype.Timestamp, error)
+	// Maintenance announced on any status page that shows the monitor
+	GetMaintenanceWindowsForMonitor(ctx context.Context, arg GetMaintenanceWindowsForMonitorParams) ([]GetMaintenanceWindowsForMonitorRow, error)
 	GetMonitorAlertConfigs(ctx context.Context, monitorID pgtype.Int4) ([]MonitorAlertConfig, error)
 	GetMonitorAlerts(ctx context.Context, arg GetMonitorAlertsParams) ([]Alert, error)
+	GetMonitorBadge(ctx context.Context, monitorID int32) (MonitorBadge, error)
 	GetMonitorByID(ctx context.Context, arg GetMonitorByIDParams) (Monitor, error)
 	GetMonitorByIdandURL(ctx context.Context, arg GetMonitorByIdandURLParams) (Monitor, error)
-	GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams) ([]MonitorLog, error)
+	GetMonitorGroup(ctx context.Context, arg GetMonitorGroupParams) (MonitorGroup, error)
+	// Monitors per group and state, for rolling up group status. Paused monitors
+	// are counted apart from their last status.
+	GetMonitorGroupCounts(ctx context.Context, orgID uuid.UUID) ([]GetMonitorGroupCountsRow, error)
+	GetMonitorLogByID(ctx context.Context, arg GetMonitorLogByIDParams) (MonitorLog, error)
+	GetMonitorLogs(ctx context.Context, arg GetMonitorLogsParams) ([]GetMonitorLogsRow, error)
 	GetMonitorLogsByTimeRange(ctx context.Context, arg GetMonitorLogsByTimeRangeParams) ([]MonitorLog, error)
+	GetMonitorRollups(ctx context.Context, arg GetMonitorRollupsParams) ([]MonitorRollup, error)
+	GetMonitorSecrets(ctx context.Context, monitorID int32) ([]GetMonitorSecretsRow, error)
 	GetMonitorsByInterval(ctx context.Context, interval int32) ([]Monitor, error)
+	GetOpenIncidentsForMonitors(ctx context.Context, monitorIds []int32) ([]Incident, error)
+	GetOrgAlertContact(ctx context.Context, arg GetOrgAlertContactParams) (AlertContact, error)
+	GetOrgMembership(ctx context.Context, arg GetOrgMembershipParams) (OrgMembership, error)
+	GetOrgMonitorAlertConfigs(ctx context.Context, orgID uuid.UUID) ([]MonitorAlertConfig, error)
+	GetOrgMonitorGroups(ctx context.Context, orgID uuid.UUID) ([]MonitorGroup, error)
+	GetOrgMonitors(ctx context.Context, orgID uuid.UUID) ([]Monitor, error)
+	GetOrgMonitorsByIDs(ctx context.Context, arg GetOrgMonitorsByIDsParams) ([]Monitor, error)
+	GetOrgMonitorsWithStats(ctx context.Context, arg GetOrgMonitorsWithStatsParams) ([]GetOrgMonitorsWithStatsRow, error)
+	GetOrgSubscription(ctx context.Context, orgID uuid.UUID) (Subscription, error)
+	GetOrganization(ctx context.Context, id uuid.UUID) (Organization, error)
+	GetPendingOrgInviteByToken(ctx context.Context, token string) (OrgInvite, error)
+	GetPublishedStatusPageByDomain(ctx context.Context, customDomain pgtype.Text) (StatusPage, error)
+	GetPublishedStatusPageBySlug(ctx context.Context, slug string) (StatusPage, error)
 	GetRecentAlerts(ctx context.Context, arg GetRecentAlertsParams) ([]GetRecentAlertsRow, error)
 	GetRecentAlertsForContact(ctx context.Context, arg GetRecentAlertsForContactParams) ([]Alert, error)
 	GetRecentMonitorStatus(ctx context.Context, monitorID pgtype.Int4) (GetRecentMonitorStatusRow, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetRollupTotalsForMonitors(ctx context.Context, arg GetRollupTotalsForMonitorsParams) ([]GetRollupTotalsForMonitorsRow, error)
+	GetStatusPageByID(ctx context.Context, arg GetStatusPageByIDParams) (StatusPage, error)
+	GetStatusPageMonitors(ctx context.Context, statusPageID int32) ([]GetStatusPageMonitorsRow, error)
+	GetStatusPageNoticeByID(ctx context.Context, arg GetStatusPageNoticeByIDParams) (StatusPageNotice, error)
+	GetStatusPageNoticeUpdates(ctx context.Context, noticeIds []int32) ([]StatusPageNoticeUpdate, error)
+	GetStatusPageNotices(ctx context.Context, arg GetStatusPageNoticesParams) ([]StatusPageNotice, error)
+	GetStatusPageSections(ctx context.Context, statusPageID int32) ([]StatusPageSection, error)
+	GetStatusPageSubscribers(ctx context.Context, statusPageID int32) ([]StatusPageSubscriber, error)
+	GetStatusPagesByOrg(ctx context.Context, orgID uuid.UUID) ([]StatusPage, error)
+	// Includes inactive subscriptions, to reuse their Stripe customer
+	GetSubscriptionByOrg(ctx context.Context, orgID uuid.UUID) (Subscription, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow, error)
-	GetUserMonitors(ctx context.Context, userID pgtype.UUID) ([]Monitor, error)
-	GetUserMonitorsWithStats(ctx context.Context, userID pgtype.UUID) ([]GetUserMonitorsWithStatsRow, error)
 	GetUserProfile(ctx context.Context, userID pgtype.UUID) (UserProfile, error)
-	GetUserSubscription(ctx context.Context, userID pgtype.UUID) (Subscription, error)
+	// The user's own keys and, for admins, the org-scoped keys of their organization
+	ListAPIKeys(ctx context.Context, arg ListAPIKeysParams) ([]ApiKey, error)
+	// Newest first; every filter but org_id is optional
+	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]AuditLog, error)
+	// Pages through a monitor's logs newest first, before the checked_at and id
+	// of the last log of the previous page
+	ListMonitorLogsPage(ctx context.Context, arg ListMonitorLogsPageParams) ([]ListMonitorLogsPageRow, error)
+	// Stored screenshots of a monitor, read before deleting it since its logs and
+	// incidents go with it
+	ListMonitorScreenshotKeys(ctx context.Context, monitorID int32) ([]string, error)
+	ListMonitorSecretHints(ctx context.Context, monitorID int32) ([]ListMonitorSecretHintsRow, error)
+	// Pages through secrets not sealed under the active key, after the given row
+	ListMonitorSecretsToRewrap(ctx context.Context, arg ListMonitorSecretsToRewrapParams) ([]ListMonitorSecretsToRewrapRow, error)
+	ListOrgAlertContactsPage(ctx context.Context, arg ListOrgAlertContactsPageParams) ([]AlertContact, error)
+	ListOrgMembers(ctx context.Context, orgID uuid.UUID) ([]ListOrgMembersRow, error)
+	ListOrgMonitorGroupsPage(ctx context.Context, arg ListOrgMonitorGroupsPageParams) ([]MonitorGroup, error)
+	// Pages through monitors by id, after the last one of the previous page, with
+	// the filters of SearchOrgMonitors
+	ListOrgMonitorsPage(ctx context.Context, arg ListOrgMonitorsPageParams) ([]Monitor, error)
+	ListOrgStatusPagesPage(ctx context.Context, arg ListOrgStatusPagesPageParams) ([]StatusPage, error)
+	// Every tag in use in the organization with how many monitors carry it
+	ListOrgTags(ctx context.Context, orgID uuid.UUID) ([]ListOrgTagsRow, error)
+	ListPendingOrgInvites(ctx context.Context, orgID uuid.UUID) ([]ListPendingOrgInvitesRow, error)
+	ListSecretHintsForMonitors(ctx context.Context, monitorIds []int32) ([]ListSecretHintsForMonitorsRow, error)
+	ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]ListUserOrganizationsRow, error)
+	// Serializes membership changes that must keep an owner within a transaction
+	LockOrganization(ctx context.Context, id uuid.UUID) error
+	// Serializes creating a user's personal organization within a transaction
+	LockUserOrganizations(ctx context.Context, userID uuid.UUID) error
+	MarkOrgInviteAccepted(ctx context.Context, id int32) error
+	OpenIncident(ctx context.Context, arg OpenIncidentParams) error
+	// Frees the key of a request that failed, so it can be retried
+	ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error
+	ResolveOpenIncident(ctx context.Context, monitorID int32) error
+	RevokeAPIKey(ctx context.Context, id int32) error
+	// Affects no rows when the token was already used, so concurrent refreshes
+	// with the same token can't both succeed
+	RevokeRefreshToken(ctx context.Context, id int32) (int64, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	// Only replaces the ciphertext if nobody rewrote the secret since it was read
+	RewrapMonitorSecret(ctx context.Context, arg RewrapMonitorSecretParams) (int64, error)
+	// Recomputes every bucket of the given resolution that starts in [since, until).
+	// since must be aligned to a bucket boundary, otherwise its bucket is rewritten with partial data.
+	// Safe to run repeatedly, late logs simply update their bucket.
+	// A check is up when it passed (content_ok, false for failed assertions of API
+	// and database checks that still got a 200) with a 2xx/3xx code; older logs
+	// have content_ok NULL or always TRUE, so the code decides for them.
+	RollupMonitorLogs(ctx context.Context, arg RollupMonitorLogsParams) (int64, error)
+	// Filters are optional: tags must all be present, search matches the URL
+	// (with LIKE wildcards already escaped)
+	SearchOrgMonitors(ctx context.Context, arg SearchOrgMonitorsParams) ([]Monitor, error)
+	SetAlertContactExternalID(ctx context.Context, arg SetAlertContactExternalIDParams) error
+	SetMonitorExternalID(ctx context.Context, arg SetMonitorExternalIDParams) error
+	SetMonitorGroupExternalID(ctx context.Context, arg SetMonitorGroupExternalIDParams) error
+	SetMonitorLogScreenshot(ctx context.Context, arg SetMonitorLogScreenshotParams) error
+	SetOpenIncidentScreenshot(ctx context.Context, arg SetOpenIncidentScreenshotParams) error
+	SetStatusPageExternalID(ctx context.Context, arg SetStatusPageExternalIDParams) error
 	ToggleMonitor(ctx context.Context, arg ToggleMonitorParams) (Monitor, error)
+	// Updated at most once a minute so busy keys don't write on every request
+	TouchAPIKey(ctx context.Context, id int32) error
+	UpdateAlertContact(ctx context.Context, arg UpdateAlertContactParams) (AlertContact, error)
 	UpdateMonitor(ctx context.Context, arg UpdateMonitorParams) (Monitor, error)
 	UpdateMonitorAlertConfig(ctx context.Context, arg UpdateMonitorAlertConfigParams) (MonitorAlertConfig, error)
 	UpdateMonitorAlertState(ctx context.Context, arg UpdateMonitorAlertStateParams) error
+	UpdateMonitorGroup(ctx context.Context, arg UpdateMonitorGroupParams) (MonitorGroup, error)
 	UpdateMonitorStatus(ctx context.Context, arg UpdateMonitorStatusParams) (Monitor, error)
 	UpdateMonitorStatusAndFailures(ctx context.Context, arg UpdateMonitorStatusAndFailuresParams) (Monitor, error)
+	UpdateOrgMemberRole(ctx context.Context, arg UpdateOrgMemberRoleParams) (OrgMembership, error)
+	UpdateOrganization(ctx context.Context, arg UpdateOrganizationParams) (Organization, error)
 	UpdatePremiumStatus(ctx context.Context, arg UpdatePremiumStatusParams) (UserProfile, error)
-	UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error)
+	UpdateStatusPage(ctx context.Context, arg UpdateStatusPageParams) (StatusPage, error)
+	UpdateStatusPageNoticeStatus(ctx context.Context, arg UpdateStatusPageNoticeStatusParams) (StatusPageNotice, error)
+	// Returns no row when the subscription is unknown or was last updated by a
+	// newer event
+	UpdateSubscriptionByStripeID(ctx context.Context, arg UpdateSubscriptionByStripeIDParams) (Subscription, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (UserProfile, error)
+	UpsertMonitorAlertConfig(ctx context.Context, arg UpsertMonitorAlertConfigParams) (MonitorAlertConfig, error)
+	UpsertMonitorBadge(ctx context.Context, arg UpsertMonitorBadgeParams) (MonitorBadge, error)
+	UpsertMonitorSecret(ctx context.Context, arg UpsertMonitorSecretParams) error
+	// Returns no row when the subscription was last updated by a newer event
+	UpsertOrgSubscription(ctx context.Context, arg UpsertOrgSubscriptionParams) (Subscription, error)
+	VerifyUserEmail(ctx context.Context, emailVerificationHash pgtype.Text) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
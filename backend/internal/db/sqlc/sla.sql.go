@@ -0,0 +1,92 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: sla.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getIncidentStartsForMonitor = `-- name: GetIncidentStartsForMonitor :many
+SELECT started_at
+FROM incidents
+WHERE monitor_id = $1
+  AND started_at >= $2::timestamp
+  AND started_at < $3::timestamp
+ORDER BY started_at
+`
+
+type GetIncidentStartsForMonitorParams struct {
+	MonitorID   int32            `json:"monitor_id"`
+	PeriodStart pgtype.Timestamp `json:"period_start"`
+	PeriodEnd   pgtype.Timestamp `json:"period_end"`
+}
+
+func (q *Queries) GetIncidentStartsForMonitor(ctx context.Context, arg GetIncidentStartsForMonitorParams) ([]pgtype.Timestamp, error) {
+	rows, err := q.db.Query(ctx, getIncidentStartsForMonitor, arg.MonitorID, arg.PeriodStart, arg.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.Timestamp{}
+	for rows.Next() {
+		var started_at pgtype.Timestamp
+		if err := rows.Scan(&started_at); err != nil {
+			return nil, err
+		}
+		items = append(items, started_at)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMaintenanceWindowsForMonitor = `-- name: GetMaintenanceWindowsForMonitor :many
+SELECT DISTINCT
+    COALESCE(n.scheduled_start, n.created_at)::timestamp AS window_start,
+    COALESCE(n.resolved_at, n.scheduled_end, NOW())::timestamp AS window_end
+FROM status_page_notices n
+JOIN status_page_monitors spm ON spm.status_page_id = n.status_page_id
+WHERE spm.monitor_id = $1
+  AND n.kind = 'maintenance'
+  AND COALESCE(n.scheduled_start, n.created_at) < $2::timestamp
+  AND COALESCE(n.resolved_at, n.scheduled_end, NOW()) > $3::timestamp
+ORDER BY window_start
+`
+
+type GetMaintenanceWindowsForMonitorParams struct {
+	MonitorID   int32            `json:"monitor_id"`
+	PeriodEnd   pgtype.Timestamp `json:"period_end"`
+	PeriodStart pgtype.Timestamp `json:"period_start"`
+}
+
+type GetMaintenanceWindowsForMonitorRow struct {
+	WindowStart pgtype.Timestamp `json:"window_start"`
+	WindowEnd   pgtype.Timestamp `json:"window_end"`
+}
+
+// Maintenance announced on any status page that shows the monitor
+func (q *Queries) GetMaintenanceWindowsForMonitor(ctx context.Context, arg GetMaintenanceWindowsForMonitorParams) ([]GetMaintenanceWindowsForMonitorRow, error) {
+	rows, err := q.db.Query(ctx, getMaintenanceWindowsForMonitor, arg.MonitorID, arg.PeriodEnd, arg.PeriodStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMaintenanceWindowsForMonitorRow{}
+	for rows.Next() {
+		var i GetMaintenanceWindowsForMonitorRow
+		if err := rows.Scan(&i.WindowStart, &i.WindowEnd); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
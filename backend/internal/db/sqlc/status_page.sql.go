@@ -0,0 +1,513 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: status_page.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addStatusPageMonitor = `-- name: AddStatusPageMonitor :one
+INSERT INTO status_page_monitors (status_page_id, section_id, monitor_id, display_name, position)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, status_page_id, section_id, monitor_id, display_name, position
+`
+
+type AddStatusPageMonitorParams struct {
+	StatusPageID int32  `json:"status_page_id"`
+	SectionID    int32  `json:"section_id"`
+	MonitorID    int32  `json:"monitor_id"`
+	DisplayName  string `json:"display_name"`
+	Position     int32  `json:"position"`
+}
+
+func (q *Queries) AddStatusPageMonitor(ctx context.Context, arg AddStatusPageMonitorParams) (StatusPageMonitor, error) {
+	row := q.db.QueryRow(ctx, addStatusPageMonitor,
+		arg.StatusPageID,
+		arg.SectionID,
+		arg.MonitorID,
+		arg.DisplayName,
+		arg.Position,
+	)
+	var i StatusPageMonitor
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.SectionID,
+		&i.MonitorID,
+		&i.DisplayName,
+		&i.Position,
+	)
+	return i, err
+}
+
+const createStatusPage = `-- name: CreateStatusPage :one
+INSERT INTO status_pages (user_id, org_id, slug, title, description, custom_domain, is_published)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id
+`
+
+type CreateStatusPageParams struct {
+	UserID       pgtype.UUID `json:"user_id"`
+	OrgID        uuid.UUID   `json:"org_id"`
+	Slug         string      `json:"slug"`
+	Title        string      `json:"title"`
+	Description  pgtype.Text `json:"description"`
+	CustomDomain pgtype.Text `json:"custom_domain"`
+	IsPublished  pgtype.Bool `json:"is_published"`
+}
+
+func (q *Queries) CreateStatusPage(ctx context.Context, arg CreateStatusPageParams) (StatusPage, error) {
+	row := q.db.QueryRow(ctx, createStatusPage,
+		arg.UserID,
+		arg.OrgID,
+		arg.Slug,
+		arg.Title,
+		arg.Description,
+		arg.CustomDomain,
+		arg.IsPublished,
+	)
+	var i StatusPage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.CustomDomain,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const createStatusPageSection = `-- name: CreateStatusPageSection :one
+INSERT INTO status_page_sections (status_page_id, name, position)
+VALUES ($1, $2, $3)
+RETURNING id, status_page_id, name, position
+`
+
+type CreateStatusPageSectionParams struct {
+	StatusPageID int32  `json:"status_page_id"`
+	Name         string `json:"name"`
+	Position     int32  `json:"position"`
+}
+
+func (q *Queries) CreateStatusPageSection(ctx context.Context, arg CreateStatusPageSectionParams) (StatusPageSection, error) {
+	row := q.db.QueryRow(ctx, createStatusPageSection, arg.StatusPageID, arg.Name, arg.Position)
+	var i StatusPageSection
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Name,
+		&i.Position,
+	)
+	return i, err
+}
+
+const deleteStatusPage = `-- name: DeleteStatusPage :exec
+DELETE FROM status_pages
+WHERE id = $1 AND org_id = $2
+`
+
+type DeleteStatusPageParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) DeleteStatusPage(ctx context.Context, arg DeleteStatusPageParams) error {
+	_, err := q.db.Exec(ctx, deleteStatusPage, arg.ID, arg.OrgID)
+	return err
+}
+
+const deleteStatusPageSections = `-- name: DeleteStatusPageSections :exec
+DELETE FROM status_page_sections
+WHERE status_page_id = $1
+`
+
+func (q *Queries) DeleteStatusPageSections(ctx context.Context, statusPageID int32) error {
+	_, err := q.db.Exec(ctx, deleteStatusPageSections, statusPageID)
+	return err
+}
+
+const getDailyUptimeForMonitors = `-- name: GetDailyUptimeForMonitors :many
+SELECT
+    monitor_id,
+    bucket_start::date AS day,
+    check_count::bigint AS total_checks,
+    success_count::bigint AS successful_checks
+FROM monitor_rollups
+WHERE monitor_id = ANY($1::int[])
+  AND resolution = '1d'
+  AND bucket_start >= $2::timestamp
+  AND bucket_start < $3::timestamp
+UNION ALL
+SELECT
+    monitor_id,
+    date_trunc('day', checked_at)::date AS day,
+    COUNT(*)::bigint AS total_checks,
+    SUM(CASE WHEN content_ok IS NOT FALSE AND status_code BETWEEN 200 AND 399 THEN 1 ELSE 0 END)::bigint AS successful_checks
+FROM monitor_logs
+WHERE monitor_id = ANY($1::int[])
+  AND checked_at >= $3::timestamp
+GROUP BY monitor_id, date_trunc('day', checked_at)
+ORDER BY monitor_id, day
+`
+
+type GetDailyUptimeForMonitorsParams struct {
+	MonitorIds []int32          `json:"monitor_ids"`
+	Since      pgtype.Timestamp `json:"since"`
+	Today      pgtype.Timestamp `json:"today"`
+}
+
+type GetDailyUptimeForMonitorsRow struct {
+	MonitorID        int32       `json:"monitor_id"`
+	Day              pgtype.Date `json:"day"`
+	TotalChecks      int64       `json:"total_checks"`
+	SuccessfulChecks int64       `json:"successful_checks"`
+}
+
+// Past days come from the daily rollups, which outlive the raw logs; today is
+// counted from the raw logs so the last bar does not lag the hourly rollup.
+func (q *Queries) GetDailyUptimeForMonitors(ctx context.Context, arg GetDailyUptimeForMonitorsParams) ([]GetDailyUptimeForMonitorsRow, error) {
+	rows, err := q.db.Query(ctx, getDailyUptimeForMonitors, arg.MonitorIds, arg.Since, arg.Today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetDailyUptimeForMonitorsRow{}
+	for rows.Next() {
+		var i GetDailyUptimeForMonitorsRow
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.Day,
+			&i.TotalChecks,
+			&i.SuccessfulChecks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPublishedStatusPageByDomain = `-- name: GetPublishedStatusPageByDomain :one
+SELECT id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id FROM status_pages
+WHERE custom_domain = $1 AND is_published = true
+`
+
+func (q *Queries) GetPublishedStatusPageByDomain(ctx context.Context, customDomain pgtype.Text) (StatusPage, error) {
+	row := q.db.QueryRow(ctx, getPublishedStatusPageByDomain, customDomain)
+	var i StatusPage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.CustomDomain,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getPublishedStatusPageBySlug = `-- name: GetPublishedStatusPageBySlug :one
+SELECT id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id FROM status_pages
+WHERE slug = $1 AND is_published = true
+`
+
+func (q *Queries) GetPublishedStatusPageBySlug(ctx context.Context, slug string) (StatusPage, error) {
+	row := q.db.QueryRow(ctx, getPublishedStatusPageBySlug, slug)
+	var i StatusPage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.CustomDomain,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getStatusPageByID = `-- name: GetStatusPageByID :one
+SELECT id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id FROM status_pages
+WHERE id = $1 AND org_id = $2
+`
+
+type GetStatusPageByIDParams struct {
+	ID    int32     `json:"id"`
+	OrgID uuid.UUID `json:"org_id"`
+}
+
+func (q *Queries) GetStatusPageByID(ctx context.Context, arg GetStatusPageByIDParams) (StatusPage, error) {
+	row := q.db.QueryRow(ctx, getStatusPageByID, arg.ID, arg.OrgID)
+	var i StatusPage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.CustomDomain,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getStatusPageMonitors = `-- name: GetStatusPageMonitors :many
+SELECT
+    spm.id,
+    spm.section_id,
+    spm.monitor_id,
+    spm.display_name,
+    spm.position,
+    m.status,
+    m.is_active
+FROM status_page_monitors spm
+JOIN monitors m ON m.id = spm.monitor_id
+WHERE spm.status_page_id = $1
+ORDER BY spm.position, spm.id
+`
+
+type GetStatusPageMonitorsRow struct {
+	ID          int32             `json:"id"`
+	SectionID   int32             `json:"section_id"`
+	MonitorID   int32             `json:"monitor_id"`
+	DisplayName string            `json:"display_name"`
+	Position    int32             `json:"position"`
+	Status      NullMonitorStatus `json:"status"`
+	IsActive    pgtype.Bool       `json:"is_active"`
+}
+
+func (q *Queries) GetStatusPageMonitors(ctx context.Context, statusPageID int32) ([]GetStatusPageMonitorsRow, error) {
+	rows, err := q.db.Query(ctx, getStatusPageMonitors, statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetStatusPageMonitorsRow{}
+	for rows.Next() {
+		var i GetStatusPageMonitorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SectionID,
+			&i.MonitorID,
+			&i.DisplayName,
+			&i.Position,
+			&i.Status,
+			&i.IsActive,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatusPageSections = `-- name: GetStatusPageSections :many
+SELECT id, status_page_id, name, position FROM status_page_sections
+WHERE status_page_id = $1
+ORDER BY position, id
+`
+
+func (q *Queries) GetStatusPageSections(ctx context.Context, statusPageID int32) ([]StatusPageSection, error) {
+	rows, err := q.db.Query(ctx, getStatusPageSections, statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPageSection{}
+	for rows.Next() {
+		var i StatusPageSection
+		if err := rows.Scan(
+			&i.ID,
+			&i.StatusPageID,
+			&i.Name,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatusPagesByOrg = `-- name: GetStatusPagesByOrg :many
+SELECT id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id FROM status_pages
+WHERE org_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetStatusPagesByOrg(ctx context.Context, orgID uuid.UUID) ([]StatusPage, error) {
+	rows, err := q.db.Query(ctx, getStatusPagesByOrg, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPage{}
+	for rows.Next() {
+		var i StatusPage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Slug,
+			&i.Title,
+			&i.Description,
+			&i.CustomDomain,
+			&i.IsPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrgStatusPagesPage = `-- name: ListOrgStatusPagesPage :many
+SELECT id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id FROM status_pages
+WHERE org_id = $1 AND id > $2::int
+ORDER BY id
+LIMIT $3::int
+`
+
+type ListOrgStatusPagesPageParams struct {
+	OrgID    uuid.UUID `json:"org_id"`
+	AfterID  int32     `json:"after_id"`
+	PageSize int32     `json:"page_size"`
+}
+
+func (q *Queries) ListOrgStatusPagesPage(ctx context.Context, arg ListOrgStatusPagesPageParams) ([]StatusPage, error) {
+	rows, err := q.db.Query(ctx, listOrgStatusPagesPage, arg.OrgID, arg.AfterID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPage{}
+	for rows.Next() {
+		var i StatusPage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.OrgID,
+			&i.Slug,
+			&i.Title,
+			&i.Description,
+			&i.CustomDomain,
+			&i.IsPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setStatusPageExternalID = `-- name: SetStatusPageExternalID :exec
+UPDATE status_pages SET external_id = $3
+WHERE id = $1 AND org_id = $2
+`
+
+type SetStatusPageExternalIDParams struct {
+	ID         int32       `json:"id"`
+	OrgID      uuid.UUID   `json:"org_id"`
+	ExternalID pgtype.Text `json:"external_id"`
+}
+
+func (q *Queries) SetStatusPageExternalID(ctx context.Context, arg SetStatusPageExternalIDParams) error {
+	_, err := q.db.Exec(ctx, setStatusPageExternalID, arg.ID, arg.OrgID, arg.ExternalID)
+	return err
+}
+
+const updateStatusPage = `-- name: UpdateStatusPage :one
+UPDATE status_pages
+SET
+    slug = $3,
+    title = $4,
+    description = $5,
+    custom_domain = $6,
+    is_published = $7,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND org_id = $2
+RETURNING id, user_id, org_id, slug, title, description, custom_domain, is_published, created_at, updated_at, external_id
+`
+
+type UpdateStatusPageParams struct {
+	ID           int32       `json:"id"`
+	OrgID        uuid.UUID   `json:"org_id"`
+	Slug         string      `json:"slug"`
+	Title        string      `json:"title"`
+	Description  pgtype.Text `json:"description"`
+	CustomDomain pgtype.Text `json:"custom_domain"`
+	IsPublished  pgtype.Bool `json:"is_published"`
+}
+
+func (q *Queries) UpdateStatusPage(ctx context.Context, arg UpdateStatusPageParams) (StatusPage, error) {
+	row := q.db.QueryRow(ctx, updateStatusPage,
+		arg.ID,
+		arg.OrgID,
+		arg.Slug,
+		arg.Title,
+		arg.Description,
+		arg.CustomDomain,
+		arg.IsPublished,
+	)
+	var i StatusPage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Slug,
+		&i.Title,
+		&i.Description,
+		&i.CustomDomain,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExternalID,
+	)
+	return i, err
+}
@@ -0,0 +1,251 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: status_page_notice.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createStatusPageNotice = `-- name: CreateStatusPageNotice :one
+INSERT INTO status_page_notices (status_page_id, kind, title, status, scheduled_start, scheduled_end)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, status_page_id, kind, title, status, scheduled_start, scheduled_end, resolved_at, created_at, updated_at
+`
+
+type CreateStatusPageNoticeParams struct {
+	StatusPageID   int32            `json:"status_page_id"`
+	Kind           NoticeKind       `json:"kind"`
+	Title          string           `json:"title"`
+	Status         NoticeStatus     `json:"status"`
+	ScheduledStart pgtype.Timestamp `json:"scheduled_start"`
+	ScheduledEnd   pgtype.Timestamp `json:"scheduled_end"`
+}
+
+func (q *Queries) CreateStatusPageNotice(ctx context.Context, arg CreateStatusPageNoticeParams) (StatusPageNotice, error) {
+	row := q.db.QueryRow(ctx, createStatusPageNotice,
+		arg.StatusPageID,
+		arg.Kind,
+		arg.Title,
+		arg.Status,
+		arg.ScheduledStart,
+		arg.ScheduledEnd,
+	)
+	var i StatusPageNotice
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Kind,
+		&i.Title,
+		&i.Status,
+		&i.ScheduledStart,
+		&i.ScheduledEnd,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createStatusPageNoticeUpdate = `-- name: CreateStatusPageNoticeUpdate :one
+INSERT INTO status_page_notice_updates (notice_id, status, message)
+VALUES ($1, $2, $3)
+RETURNING id, notice_id, status, message, created_at
+`
+
+type CreateStatusPageNoticeUpdateParams struct {
+	NoticeID int32        `json:"notice_id"`
+	Status   NoticeStatus `json:"status"`
+	Message  string       `json:"message"`
+}
+
+func (q *Queries) CreateStatusPageNoticeUpdate(ctx context.Context, arg CreateStatusPageNoticeUpdateParams) (StatusPageNoticeUpdate, error) {
+	row := q.db.QueryRow(ctx, createStatusPageNoticeUpdate, arg.NoticeID, arg.Status, arg.Message)
+	var i StatusPageNoticeUpdate
+	err := row.Scan(
+		&i.ID,
+		&i.NoticeID,
+		&i.Status,
+		&i.Message,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getActiveStatusPageNotices = `-- name: GetActiveStatusPageNotices :many
+SELECT id, status_page_id, kind, title, status, scheduled_start, scheduled_end, resolved_at, created_at, updated_at FROM status_page_notices
+WHERE status_page_id = $1 AND resolved_at IS NULL
+ORDER BY COALESCE(scheduled_start, created_at)
+`
+
+func (q *Queries) GetActiveStatusPageNotices(ctx context.Context, statusPageID int32) ([]StatusPageNotice, error) {
+	rows, err := q.db.Query(ctx, getActiveStatusPageNotices, statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPageNotice{}
+	for rows.Next() {
+		var i StatusPageNotice
+		if err := rows.Scan(
+			&i.ID,
+			&i.StatusPageID,
+			&i.Kind,
+			&i.Title,
+			&i.Status,
+			&i.ScheduledStart,
+			&i.ScheduledEnd,
+			&i.ResolvedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatusPageNoticeByID = `-- name: GetStatusPageNoticeByID :one
+SELECT id, status_page_id, kind, title, status, scheduled_start, scheduled_end, resolved_at, created_at, updated_at FROM status_page_notices
+WHERE id = $1 AND status_page_id = $2
+`
+
+type GetStatusPageNoticeByIDParams struct {
+	ID           int32 `json:"id"`
+	StatusPageID int32 `json:"status_page_id"`
+}
+
+func (q *Queries) GetStatusPageNoticeByID(ctx context.Context, arg GetStatusPageNoticeByIDParams) (StatusPageNotice, error) {
+	row := q.db.QueryRow(ctx, getStatusPageNoticeByID, arg.ID, arg.StatusPageID)
+	var i StatusPageNotice
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Kind,
+		&i.Title,
+		&i.Status,
+		&i.ScheduledStart,
+		&i.ScheduledEnd,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStatusPageNoticeUpdates = `-- name: GetStatusPageNoticeUpdates :many
+SELECT id, notice_id, status, message, created_at FROM status_page_notice_updates
+WHERE notice_id = ANY($1::int[])
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetStatusPageNoticeUpdates(ctx context.Context, noticeIds []int32) ([]StatusPageNoticeUpdate, error) {
+	rows, err := q.db.Query(ctx, getStatusPageNoticeUpdates, noticeIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPageNoticeUpdate{}
+	for rows.Next() {
+		var i StatusPageNoticeUpdate
+		if err := rows.Scan(
+			&i.ID,
+			&i.NoticeID,
+			&i.Status,
+			&i.Message,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatusPageNotices = `-- name: GetStatusPageNotices :many
+SELECT id, status_page_id, kind, title, status, scheduled_start, scheduled_end, resolved_at, created_at, updated_at FROM status_page_notices
+WHERE status_page_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`
+
+type GetStatusPageNoticesParams struct {
+	StatusPageID int32 `json:"status_page_id"`
+	Limit        int32 `json:"limit"`
+}
+
+func (q *Queries) GetStatusPageNotices(ctx context.Context, arg GetStatusPageNoticesParams) ([]StatusPageNotice, error) {
+	rows, err := q.db.Query(ctx, getStatusPageNotices, arg.StatusPageID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPageNotice{}
+	for rows.Next() {
+		var i StatusPageNotice
+		if err := rows.Scan(
+			&i.ID,
+			&i.StatusPageID,
+			&i.Kind,
+			&i.Title,
+			&i.Status,
+			&i.ScheduledStart,
+			&i.ScheduledEnd,
+			&i.ResolvedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateStatusPageNoticeStatus = `-- name: UpdateStatusPageNoticeStatus :one
+UPDATE status_page_notices
+SET
+    status = $1,
+    resolved_at = CASE WHEN $2::bool THEN NOW() ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3
+RETURNING id, status_page_id, kind, title, status, scheduled_start, scheduled_end, resolved_at, created_at, updated_at
+`
+
+type UpdateStatusPageNoticeStatusParams struct {
+	Status   NoticeStatus `json:"status"`
+	Resolved bool         `json:"resolved"`
+	ID       int32        `json:"id"`
+}
+
+func (q *Queries) UpdateStatusPageNoticeStatus(ctx context.Context, arg UpdateStatusPageNoticeStatusParams) (StatusPageNotice, error) {
+	row := q.db.QueryRow(ctx, updateStatusPageNoticeStatus, arg.Status, arg.Resolved, arg.ID)
+	var i StatusPageNotice
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Kind,
+		&i.Title,
+		&i.Status,
+		&i.ScheduledStart,
+		&i.ScheduledEnd,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
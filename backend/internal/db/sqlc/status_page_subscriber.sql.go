@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: status_page_subscriber.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const confirmStatusPageSubscriber = `-- name: ConfirmStatusPageSubscriber :one
+UPDATE status_page_subscribers
+SET confirmed_at = COALESCE(confirmed_at, NOW())
+WHERE confirm_token = $1 AND (confirmed_at IS NOT NULL OR confirm_expires_at > NOW())
+RETURNING id, status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token, confirmed_at, created_at
+`
+
+func (q *Queries) ConfirmStatusPageSubscriber(ctx context.Context, confirmToken string) (StatusPageSubscriber, error) {
+	row := q.db.QueryRow(ctx, confirmStatusPageSubscriber, confirmToken)
+	var i StatusPageSubscriber
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Kind,
+		&i.Target,
+		&i.ConfirmToken,
+		&i.ConfirmExpiresAt,
+		&i.UnsubscribeToken,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createStatusPageSubscriber = `-- name: CreateStatusPageSubscriber :one
+INSERT INTO status_page_subscribers (status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (status_page_id, kind, target)
+DO UPDATE SET
+    confirm_token = EXCLUDED.confirm_token,
+    confirm_expires_at = EXCLUDED.confirm_expires_at
+RETURNING id, status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token, confirmed_at, created_at
+`
+
+type CreateStatusPageSubscriberParams struct {
+	StatusPageID     int32            `json:"status_page_id"`
+	Kind             SubscriberKind   `json:"kind"`
+	Target           string           `json:"target"`
+	ConfirmToken     string           `json:"confirm_token"`
+	ConfirmExpiresAt pgtype.Timestamp `json:"confirm_expires_at"`
+	UnsubscribeToken string           `json:"unsubscribe_token"`
+}
+
+func (q *Queries) CreateStatusPageSubscriber(ctx context.Context, arg CreateStatusPageSubscriberParams) (StatusPageSubscriber, error) {
+	row := q.db.QueryRow(ctx, createStatusPageSubscriber,
+		arg.StatusPageID,
+		arg.Kind,
+		arg.Target,
+		arg.ConfirmToken,
+		arg.ConfirmExpiresAt,
+		arg.UnsubscribeToken,
+	)
+	var i StatusPageSubscriber
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Kind,
+		&i.Target,
+		&i.ConfirmToken,
+		&i.ConfirmExpiresAt,
+		&i.UnsubscribeToken,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteStatusPageSubscriberByToken = `-- name: DeleteStatusPageSubscriberByToken :one
+DELETE FROM status_page_subscribers
+WHERE unsubscribe_token = $1
+RETURNING id, status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token, confirmed_at, created_at
+`
+
+func (q *Queries) DeleteStatusPageSubscriberByToken(ctx context.Context, unsubscribeToken string) (StatusPageSubscriber, error) {
+	row := q.db.QueryRow(ctx, deleteStatusPageSubscriberByToken, unsubscribeToken)
+	var i StatusPageSubscriber
+	err := row.Scan(
+		&i.ID,
+		&i.StatusPageID,
+		&i.Kind,
+		&i.Target,
+		&i.ConfirmToken,
+		&i.ConfirmExpiresAt,
+		&i.UnsubscribeToken,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getConfirmedStatusPageSubscribers = `-- name: GetConfirmedStatusPageSubscribers :many
+SELECT id, status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token, confirmed_at, created_at FROM status_page_subscribers
+WHERE status_page_id = $1 AND confirmed_at IS NOT NULL
+`
+
+func (q *Queries) GetConfirmedStatusPageSubscribers(ctx context.Context, statusPageID int32) ([]StatusPageSubscriber, error) {
+	rows, err := q.db.Query(ctx, getConfirmedStatusPageSubscribers, statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPageSubscriber{}
+	for rows.Next() {
+		var i StatusPageSubscriber
+		if err := rows.Scan(
+			&i.ID,
+			&i.StatusPageID,
+			&i.Kind,
+			&i.Target,
+			&i.ConfirmToken,
+			&i.ConfirmExpiresAt,
+			&i.UnsubscribeToken,
+			&i.ConfirmedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getStatusPageSubscribers = `-- name: GetStatusPageSubscribers :many
+SELECT id, status_page_id, kind, target, confirm_token, confirm_expires_at, unsubscribe_token, confirmed_at, created_at FROM status_page_subscribers
+WHERE status_page_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetStatusPageSubscribers(ctx context.Context, statusPageID int32) ([]StatusPageSubscriber, error) {
+	rows, err := q.db.Query(ctx, getStatusPageSubscribers, statusPageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StatusPageSubscriber{}
+	for rows.Next() {
+		var i StatusPageSubscriber
+		if err := rows.Scan(
+			&i.ID,
+			&i.StatusPageID,
+			&i.Kind,
+			&i.Target,
+			&i.ConfirmToken,
+			&i.ConfirmExpiresAt,
+			&i.UnsubscribeToken,
+			&i.ConfirmedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
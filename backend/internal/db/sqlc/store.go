@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -10,6 +11,8 @@ import (
 type Store interface {
 	Querier
 	ExecTx(ctx context.Context, fn func(*Queries) error) error
+	EnsureMonitorLogPartitions(ctx context.Context, from time.Time, monthsAhead int) error
+	DropMonitorLogPartitionsBefore(ctx context.Context, before time.Time) ([]string, error)
 }
 
 type sqlStore struct {
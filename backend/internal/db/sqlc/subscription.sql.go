@@ -8,89 +8,205 @@ package db
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createSubscription = `-- name: CreateSubscription :one
-INSERT INTO subscriptions (user_id, stripe_plan, is_active)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, stripe_plan, is_active, created_at
+const countOrgAlertContacts = `-- name: CountOrgAlertContacts :one
+SELECT COUNT(*) FROM alert_contacts WHERE org_id = $1
 `
 
-type CreateSubscriptionParams struct {
-	UserID     pgtype.UUID `json:"user_id"`
-	StripePlan pgtype.Text `json:"stripe_plan"`
-	IsActive   pgtype.Bool `json:"is_active"`
+func (q *Queries) CountOrgAlertContacts(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrgAlertContacts, orgID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error) {
-	row := q.db.QueryRow(ctx, createSubscription, arg.UserID, arg.StripePlan, arg.IsActive)
+const countOrgMonitors = `-- name: CountOrgMonitors :one
+SELECT COUNT(*) FROM monitors WHERE org_id = $1
+`
+
+func (q *Queries) CountOrgMonitors(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrgMonitors, orgID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOrgStatusPages = `-- name: CountOrgStatusPages :one
+SELECT COUNT(*) FROM status_pages WHERE org_id = $1
+`
+
+func (q *Queries) CountOrgStatusPages(ctx context.Context, orgID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrgStatusPages, orgID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getOrgSubscription = `-- name: GetOrgSubscription :one
+SELECT id, user_id, org_id, plan, stripe_customer_id, stripe_subscription_id, status, current_period_end, is_active, stripe_event_at, created_at, updated_at FROM subscriptions
+WHERE org_id = $1 AND is_active
+`
+
+func (q *Queries) GetOrgSubscription(ctx context.Context, orgID uuid.UUID) (Subscription, error) {
+	row := q.db.QueryRow(ctx, getOrgSubscription, orgID)
 	var i Subscription
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.StripePlan,
+		&i.OrgID,
+		&i.Plan,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.Status,
+		&i.CurrentPeriodEnd,
 		&i.IsActive,
+		&i.StripeEventAt,
 		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const deactivateSubscription = `-- name: DeactivateSubscription :exec
-UPDATE subscriptions 
-SET is_active = false 
-WHERE user_id = $1
+const getSubscriptionByOrg = `-- name: GetSubscriptionByOrg :one
+SELECT id, user_id, org_id, plan, stripe_customer_id, stripe_subscription_id, status, current_period_end, is_active, stripe_event_at, created_at, updated_at FROM subscriptions
+WHERE org_id = $1
 `
 
-func (q *Queries) DeactivateSubscription(ctx context.Context, userID pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deactivateSubscription, userID)
-	return err
+// Includes inactive subscriptions, to reuse their Stripe customer
+func (q *Queries) GetSubscriptionByOrg(ctx context.Context, orgID uuid.UUID) (Subscription, error) {
+	row := q.db.QueryRow(ctx, getSubscriptionByOrg, orgID)
+	var i Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.OrgID,
+		&i.Plan,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.Status,
+		&i.CurrentPeriodEnd,
+		&i.IsActive,
+		&i.StripeEventAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const getUserSubscription = `-- name: GetUserSubscription :one
-SELECT id, user_id, stripe_plan, is_active, created_at FROM subscriptions 
-WHERE user_id = $1 
-ORDER BY created_at DESC 
-LIMIT 1
+const updateSubscriptionByStripeID = `-- name: UpdateSubscriptionByStripeID :one
+UPDATE subscriptions
+SET plan = $2,
+    status = $3,
+    current_period_end = $4,
+    is_active = $5,
+    stripe_event_at = $6,
+    updated_at = now()
+WHERE stripe_subscription_id = $1
+  AND (stripe_event_at IS NULL OR stripe_event_at <= $6)
+RETURNING id, user_id, org_id, plan, stripe_customer_id, stripe_subscription_id, status, current_period_end, is_active, stripe_event_at, created_at, updated_at
 `
 
-func (q *Queries) GetUserSubscription(ctx context.Context, userID pgtype.UUID) (Subscription, error) {
-	row := q.db.QueryRow(ctx, getUserSubscription, userID)
+type UpdateSubscriptionByStripeIDParams struct {
+	StripeSubscriptionID pgtype.Text      `json:"stripe_subscription_id"`
+	Plan                 string           `json:"plan"`
+	Status               string           `json:"status"`
+	CurrentPeriodEnd     pgtype.Timestamp `json:"current_period_end"`
+	IsActive             bool             `json:"is_active"`
+	StripeEventAt        pgtype.Timestamp `json:"stripe_event_at"`
+}
+
+// Returns no row when the subscription is unknown or was last updated by a
+// newer event
+func (q *Queries) UpdateSubscriptionByStripeID(ctx context.Context, arg UpdateSubscriptionByStripeIDParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, updateSubscriptionByStripeID,
+		arg.StripeSubscriptionID,
+		arg.Plan,
+		arg.Status,
+		arg.CurrentPeriodEnd,
+		arg.IsActive,
+		arg.StripeEventAt,
+	)
 	var i Subscription
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.StripePlan,
+		&i.OrgID,
+		&i.Plan,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.Status,
+		&i.CurrentPeriodEnd,
 		&i.IsActive,
+		&i.StripeEventAt,
 		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const updateSubscription = `-- name: UpdateSubscription :one
-UPDATE subscriptions 
-SET 
-    stripe_plan = COALESCE($2, stripe_plan),
-    is_active = COALESCE($3, is_active)
-WHERE user_id = $1
-RETURNING id, user_id, stripe_plan, is_active, created_at
+const upsertOrgSubscription = `-- name: UpsertOrgSubscription :one
+INSERT INTO subscriptions (
+    user_id, org_id, plan, stripe_customer_id, stripe_subscription_id,
+    status, current_period_end, is_active, stripe_event_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+ON CONFLICT (org_id) DO UPDATE SET
+    user_id = COALESCE(EXCLUDED.user_id, subscriptions.user_id),
+    plan = EXCLUDED.plan,
+    stripe_customer_id = COALESCE(EXCLUDED.stripe_customer_id, subscriptions.stripe_customer_id),
+    stripe_subscription_id = COALESCE(EXCLUDED.stripe_subscription_id, subscriptions.stripe_subscription_id),
+    status = EXCLUDED.status,
+    current_period_end = COALESCE(EXCLUDED.current_period_end, subscriptions.current_period_end),
+    is_active = EXCLUDED.is_active,
+    stripe_event_at = EXCLUDED.stripe_event_at,
+    updated_at = now()
+WHERE subscriptions.stripe_event_at IS NULL OR subscriptions.stripe_event_at <= EXCLUDED.stripe_event_at
+RETURNING id, user_id, org_id, plan, stripe_customer_id, stripe_subscription_id, status, current_period_end, is_active, stripe_event_at, created_at, updated_at
 `
 
-type UpdateSubscriptionParams struct {
-	UserID     pgtype.UUID `json:"user_id"`
-	StripePlan pgtype.Text `json:"stripe_plan"`
-	IsActive   pgtype.Bool `json:"is_active"`
+type UpsertOrgSubscriptionParams struct {
+	UserID               pgtype.UUID      `json:"user_id"`
+	OrgID                uuid.UUID        `json:"org_id"`
+	Plan                 string           `json:"plan"`
+	StripeCustomerID     pgtype.Text      `json:"stripe_customer_id"`
+	StripeSubscriptionID pgtype.Text      `json:"stripe_subscription_id"`
+	Status               string           `json:"status"`
+	CurrentPeriodEnd     pgtype.Timestamp `json:"current_period_end"`
+	IsActive             bool             `json:"is_active"`
+	StripeEventAt        pgtype.Timestamp `json:"stripe_event_at"`
 }
 
-func (q *Queries) UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error) {
-	row := q.db.QueryRow(ctx, updateSubscription, arg.UserID, arg.StripePlan, arg.IsActive)
+// Returns no row when the subscription was last updated by a newer event
+func (q *Queries) UpsertOrgSubscription(ctx context.Context, arg UpsertOrgSubscriptionParams) (Subscription, error) {
+	row := q.db.QueryRow(ctx, upsertOrgSubscription,
+		arg.UserID,
+		arg.OrgID,
+		arg.Plan,
+		arg.StripeCustomerID,
+		arg.StripeSubscriptionID,
+		arg.Status,
+		arg.CurrentPeriodEnd,
+		arg.IsActive,
+		arg.StripeEventAt,
+	)
 	var i Subscription
 	err := row.Scan(
 		&i.ID,
 		&i.UserID,
-		&i.StripePlan,
+		&i.OrgID,
+		&i.Plan,
+		&i.StripeCustomerID,
+		&i.StripeSubscriptionID,
+		&i.Status,
+		&i.CurrentPeriodEnd,
 		&i.IsActive,
+		&i.StripeEventAt,
 		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }